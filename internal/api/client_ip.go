@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"mitsimi.dev/codeShare/internal/services"
+)
+
+// ClientIP replaces the request's RemoteAddr with the client IP determined by
+// the resolver, so logging, view tracking and rate limiting all use the same
+// address. It must run before any middleware that reads RemoteAddr.
+func ClientIP(resolver *services.ClientIPResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.RemoteAddr = resolver.Resolve(r)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
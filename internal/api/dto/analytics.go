@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type SnippetAnalyticsResponse struct {
+	SnippetID   string                   `json:"snippetId"`
+	From        string                   `json:"from"`
+	To          string                   `json:"to"`
+	Granularity string                   `json:"granularity"`
+	Totals      AnalyticsTotalsResponse  `json:"totals"`
+	Points      []AnalyticsPointResponse `json:"points"`
+}
+
+type AnalyticsTotalsResponse struct {
+	Views         int `json:"views"`
+	UniqueViewers int `json:"uniqueViewers"`
+	Likes         int `json:"likes"`
+	BotViews      int `json:"botViews"`
+}
+
+type AnalyticsPointResponse struct {
+	Date          string `json:"date"` // First day of the bucket (YYYY-MM-DD)
+	Views         int    `json:"views"`
+	UniqueViewers int    `json:"uniqueViewers"`
+	Likes         int    `json:"likes"`
+	BotViews      int    `json:"botViews"`
+}
+
+// Conversion functions
+func ToSnippetAnalyticsResponse(snippetID string, from, to time.Time, granularity string, points []domain.SnippetStatsPoint) SnippetAnalyticsResponse {
+	response := SnippetAnalyticsResponse{
+		SnippetID:   snippetID,
+		From:        from.UTC().Format(time.DateOnly),
+		To:          to.UTC().Format(time.DateOnly),
+		Granularity: granularity,
+		Points:      make([]AnalyticsPointResponse, len(points)),
+	}
+
+	for i, point := range points {
+		response.Points[i] = AnalyticsPointResponse{
+			Date:          point.Start.Format(time.DateOnly),
+			Views:         point.Views,
+			UniqueViewers: point.UniqueViewers,
+			Likes:         point.Likes,
+			BotViews:      point.BotViews,
+		}
+		response.Totals.Views += point.Views
+		response.Totals.UniqueViewers += point.UniqueViewers
+		response.Totals.Likes += point.Likes
+		response.Totals.BotViews += point.BotViews
+	}
+
+	return response
+}
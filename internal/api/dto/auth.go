@@ -17,3 +17,9 @@ type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required,min=8,max=100"`
 }
+
+// WebSocketTicketResponse is a short-lived ticket for connecting to the live update endpoints
+type WebSocketTicketResponse struct {
+	Ticket    string `json:"ticket"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
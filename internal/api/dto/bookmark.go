@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+
+// SavedSnippetResponse is a saved snippet with its private bookmark data
+type SavedSnippetResponse struct {
+	SnippetResponse
+	FolderID *string   `json:"folderId"` // null for unfiled snippets
+	Note     string    `json:"note"`
+	SavedAt  time.Time `json:"savedAt"`
+}
+
+type BookmarkFolderResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ItemCount int       `json:"itemCount"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type BulkBookmarkResponse struct {
+	Affected int `json:"affected"` // Number of saved snippets moved or unsaved
+}
+
+// Request DTOs
+type BookmarkFolderRequest struct {
+	Name string `json:"name"`
+}
+
+// UpdateBookmarkRequest changes the note or folder of one saved snippet
+type UpdateBookmarkRequest struct {
+	Note     *string `json:"note"`
+	FolderID *string `json:"folderId"` // Empty to unfile the snippet
+}
+
+// BulkBookmarkRequest moves or unsaves several saved snippets at once
+type BulkBookmarkRequest struct {
+	Action     string   `json:"action"` // "move" or "unsave"
+	SnippetIDs []string `json:"snippetIds"`
+	FolderID   string   `json:"folderId"` // Target of "move", empty to unfile
+}
+
+// Conversion functions
+func ToSavedSnippetResponse(bookmark *domain.Bookmark) SavedSnippetResponse {
+	return SavedSnippetResponse{
+		SnippetResponse: ToSnippetResponse(bookmark.Snippet),
+		FolderID:        bookmark.FolderID,
+		Note:            bookmark.Note,
+		SavedAt:         bookmark.SavedAt,
+	}
+}
+
+func ToBookmarkFolderResponse(folder *domain.BookmarkFolder) BookmarkFolderResponse {
+	return BookmarkFolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		ItemCount: folder.ItemCount,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
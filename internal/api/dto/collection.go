@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type CollectionResponse struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Visibility    string         `json:"visibility"` // "public" or "private"
+	Owner         UserResponse   `json:"owner"`
+	Collaborators []UserResponse `json:"collaborators,omitempty"` // Only set on single collections
+	ItemCount     int            `json:"itemCount"`
+	FollowerCount int            `json:"followerCount"`
+	IsFollowing   bool           `json:"isFollowing"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+}
+
+type CollectionItemResponse struct {
+	Snippet SnippetResponse `json:"snippet"`
+	AddedBy string          `json:"addedBy"`
+	AddedAt time.Time       `json:"addedAt"`
+}
+
+// CollectionDetailResponse is a collection together with its items in their manual order
+type CollectionDetailResponse struct {
+	CollectionResponse
+	Items []CollectionItemResponse `json:"items"`
+}
+
+// Request DTOs
+type CreateCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"` // "public" (default) or "private"
+}
+
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Visibility  *string `json:"visibility"`
+}
+
+type AddCollectionItemRequest struct {
+	SnippetID string `json:"snippetId"`
+}
+
+type ReorderCollectionItemsRequest struct {
+	SnippetIDs []string `json:"snippetIds"` // Every item of the collection in the new order
+}
+
+// Conversion functions
+func ToCollectionResponse(collection *domain.Collection) CollectionResponse {
+	response := CollectionResponse{
+		ID:            collection.ID,
+		Name:          collection.Name,
+		Description:   collection.Description,
+		Visibility:    string(collection.Visibility),
+		Owner:         ToUserResponse(collection.Owner),
+		ItemCount:     collection.ItemCount,
+		FollowerCount: collection.Followers,
+		IsFollowing:   collection.IsFollowing,
+		CreatedAt:     collection.CreatedAt,
+		UpdatedAt:     collection.UpdatedAt,
+	}
+	if collection.Collaborators != nil {
+		response.Collaborators = make([]UserResponse, len(collection.Collaborators))
+		for i, collaborator := range collection.Collaborators {
+			response.Collaborators[i] = ToUserResponse(collaborator)
+		}
+	}
+	return response
+}
+
+func ToCollectionDetailResponse(collection *domain.Collection, items []*domain.CollectionItem) CollectionDetailResponse {
+	response := CollectionDetailResponse{
+		CollectionResponse: ToCollectionResponse(collection),
+		Items:              make([]CollectionItemResponse, len(items)),
+	}
+	for i, item := range items {
+		response.Items[i] = CollectionItemResponse{
+			Snippet: ToSnippetResponse(item.Snippet),
+			AddedBy: item.AddedBy,
+			AddedAt: item.AddedAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/constants"
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type FeedItemResponse struct {
+	Type      string          `json:"type"` // "created" or "updated"
+	Snippet   SnippetResponse `json:"snippet"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Conversion functions
+func ToFeedItemResponse(snippet *domain.Snippet) FeedItemResponse {
+	itemType := constants.FeedItemUpdated
+	if !snippet.UpdatedAt.After(snippet.CreatedAt) {
+		itemType = constants.FeedItemCreated
+	}
+
+	return FeedItemResponse{
+		Type:      itemType,
+		Snippet:   ToSnippetResponse(snippet),
+		Timestamp: snippet.UpdatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type NotificationActorResponse struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Avatar   *string `json:"avatar"`
+}
+
+type NotificationResponse struct {
+	ID             string                    `json:"id"`
+	Type           string                    `json:"type"` // "like", "save", "follow" or "collection_item"
+	Actor          NotificationActorResponse `json:"actor"`
+	SnippetID      *string                   `json:"snippetId,omitempty"`
+	SnippetTitle   *string                   `json:"snippetTitle,omitempty"`
+	CollectionID   *string                   `json:"collectionId,omitempty"`
+	CollectionName *string                   `json:"collectionName,omitempty"`
+	Read           bool                      `json:"read"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int                    `json:"unreadCount"`
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences map[string]bool `json:"preferences"`
+}
+
+// Request DTOs
+type UpdateNotificationPreferencesRequest struct {
+	Preferences map[string]bool `json:"preferences"`
+}
+
+// Conversion functions
+func ToNotificationResponse(notification *domain.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:   notification.ID,
+		Type: string(notification.Type),
+		Actor: NotificationActorResponse{
+			ID:       notification.Actor.ID,
+			Username: notification.Actor.Username,
+			Avatar:   notification.Actor.Avatar,
+		},
+		SnippetID:      notification.SnippetID,
+		SnippetTitle:   notification.SnippetTitle,
+		CollectionID:   notification.CollectionID,
+		CollectionName: notification.CollectionName,
+		Read:           notification.ReadAt != nil,
+		CreatedAt:      notification.CreatedAt,
+	}
+}
+
+func ToNotificationPreferencesResponse(preferences []*domain.NotificationPreference) NotificationPreferencesResponse {
+	response := NotificationPreferencesResponse{Preferences: make(map[string]bool, len(preferences))}
+	for _, preference := range preferences {
+		response.Preferences[string(preference.Type)] = preference.Enabled
+	}
+	return response
+}
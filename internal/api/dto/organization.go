@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type OrganizationResponse struct {
+	ID           string    `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	MemberCount  int       `json:"memberCount"`
+	SnippetCount int       `json:"snippetCount"`
+	Role         string    `json:"role,omitempty"` // Role of the requesting user, omitted for non-members
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type OrganizationMemberResponse struct {
+	User     UserResponse `json:"user"`
+	Role     string       `json:"role"`
+	JoinedAt time.Time    `json:"joinedAt"`
+}
+
+// OrganizationProfileResponse is an organization together with the snippets the requesting user may see
+type OrganizationProfileResponse struct {
+	OrganizationResponse
+	Snippets []SnippetResponse `json:"snippets"`
+}
+
+// Request DTOs
+type CreateOrganizationRequest struct {
+	Slug        string `json:"slug"` // Lowercase letters, digits and hyphens, used in profile URLs
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type SetOrganizationMemberRequest struct {
+	Role string `json:"role"` // "owner", "maintainer" or "member"
+}
+
+// Conversion functions
+func ToOrganizationResponse(organization *domain.Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:           organization.ID,
+		Slug:         organization.Slug,
+		Name:         organization.Name,
+		Description:  organization.Description,
+		MemberCount:  organization.MemberCount,
+		SnippetCount: organization.SnippetCount,
+		Role:         string(organization.Role),
+		CreatedAt:    organization.CreatedAt,
+		UpdatedAt:    organization.UpdatedAt,
+	}
+}
+
+func ToOrganizationMemberResponse(member *domain.OrganizationMember) OrganizationMemberResponse {
+	return OrganizationMemberResponse{
+		User:     ToUserResponse(member.User),
+		Role:     string(member.Role),
+		JoinedAt: member.JoinedAt,
+	}
+}
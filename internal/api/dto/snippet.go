@@ -8,64 +8,171 @@ import (
 
 // Request DTOs
 type CreateSnippetRequest struct {
-	Title    string `json:"title" validate:"required"`
-	Content  string `json:"content" validate:"required"`
-	Language string `json:"language" validate:"required"`
+	Title          string     `json:"title" validate:"required"`
+	Content        string     `json:"content" validate:"required"`
+	Language       string     `json:"language" validate:"required"`
+	OrganizationID string     `json:"organizationId"` // Creates the snippet in an organization of the user
+	Visibility     string     `json:"visibility"`     // "public" (default), "organization" or "private"
+	ExpiresAt      *time.Time `json:"expiresAt"`      // Deletes the snippet at this time, never if omitted
+	BurnAfterRead  bool       `json:"burnAfterRead"`  // Deletes the snippet after the first view by someone other than the author
+	Password       string     `json:"password"`       // Viewers have to enter this password, unprotected if empty
 }
 
 type UpdateSnippetRequest struct {
-	Title    string `json:"title" validate:"required"`
-	Content  string `json:"content" validate:"required"`
-	Language string `json:"language" validate:"required"`
+	Title      string  `json:"title" validate:"required"`
+	Content    string  `json:"content" validate:"required"`
+	Language   string  `json:"language" validate:"required"`
+	Visibility *string `json:"visibility"` // Keeps the current visibility if omitted
+	Password   *string `json:"password"`   // Keeps the current password if omitted, removes it if empty
+}
+
+// TransferSnippetRequest moves a personal snippet into an organization
+type TransferSnippetRequest struct {
+	OrganizationID string `json:"organizationId"`
+	Visibility     string `json:"visibility"` // "public" (default), "organization" or "private"
+}
+
+type SetSnippetCollaboratorRequest struct {
+	Permission string `json:"permission"` // "view" or "edit"
+}
+
+type UnlockSnippetRequest struct {
+	Password string `json:"password" validate:"required"`
 }
 
 // Response DTOs
 type SnippetResponse struct {
-	ID        string       `json:"id"`
-	Title     string       `json:"title"`
-	Content   string       `json:"content"`
-	Language  string       `json:"language"`
-	Author    UserResponse `json:"author"`
-	CreatedAt time.Time    `json:"createdAt"`
-	UpdatedAt time.Time    `json:"updatedAt"`
-	Views     int          `json:"views"`
-	Likes     int          `json:"likes"`
-	IsLiked   bool         `json:"isLiked"`
-	IsSaved   bool         `json:"isSaved"`
+	ID                string        `json:"id"`
+	Title             string        `json:"title"`
+	Content           string        `json:"content"`
+	Language          string        `json:"language"`
+	Author            UserResponse  `json:"author"`
+	OrganizationID    *string       `json:"organizationId,omitempty"` // Set for snippets owned by an organization
+	Visibility        string        `json:"visibility"`               // "public", "organization" or "private"
+	LastEditor        *UserResponse `json:"lastEditor,omitempty"`     // User who made the latest change, if known
+	ExpiresAt         *time.Time    `json:"expiresAt,omitempty"`      // Set for snippets that expire
+	BurnAfterRead     bool          `json:"burnAfterRead"`
+	PasswordProtected bool          `json:"passwordProtected"`
+	Locked            bool          `json:"locked,omitempty"` // Title and content are withheld until the snippet is unlocked
+	CreatedAt         time.Time     `json:"createdAt"`
+	UpdatedAt         time.Time     `json:"updatedAt"`
+	Views             int           `json:"views"`
+	Likes             int           `json:"likes"`
+	IsLiked           bool          `json:"isLiked"`
+	IsSaved           bool          `json:"isSaved"`
+}
+
+// SnippetGrantResponse lets the client read a password-protected snippet, sent as the
+// X-Snippet-Grant header by clients that cannot rely on the grant cookie
+type SnippetGrantResponse struct {
+	Grant     string `json:"grant"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// TrashedSnippetResponse is a snippet in the trash with the time it will be purged
+type TrashedSnippetResponse struct {
+	SnippetResponse
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"` // Permanently deleted at this time unless restored
+}
+
+type SnippetCollaboratorResponse struct {
+	User       UserResponse `json:"user"`
+	Permission string       `json:"permission"`
+	AddedAt    time.Time    `json:"addedAt"`
 }
 
 // Conversion functions
 func ToSnippetResponse(snippet *domain.Snippet) SnippetResponse {
+	response := SnippetResponse{
+		ID:                snippet.ID,
+		Title:             snippet.Title,
+		Content:           snippet.Content,
+		Language:          snippet.Language,
+		Author:            ToUserResponse(snippet.Author),
+		OrganizationID:    snippet.OrganizationID,
+		Visibility:        string(snippet.Visibility),
+		ExpiresAt:         snippet.ExpiresAt,
+		BurnAfterRead:     snippet.BurnAfterRead,
+		PasswordProtected: snippet.IsPasswordProtected(),
+		CreatedAt:         snippet.CreatedAt,
+		UpdatedAt:         snippet.UpdatedAt,
+		Views:             snippet.Views,
+		Likes:             snippet.Likes,
+		IsLiked:           snippet.IsLiked,
+		IsSaved:           snippet.IsSaved,
+	}
+	if snippet.LastEditor != nil {
+		lastEditor := ToUserResponse(snippet.LastEditor)
+		response.LastEditor = &lastEditor
+	}
+	return response
+}
+
+// ToLockedSnippetResponse describes a snippet without revealing it, for password-protected
+// snippets that were not unlocked and burn-after-read snippets fetched by bots
+func ToLockedSnippetResponse(snippet *domain.Snippet) SnippetResponse {
 	return SnippetResponse{
-		ID:        snippet.ID,
-		Title:     snippet.Title,
-		Content:   snippet.Content,
-		Language:  snippet.Language,
-		Author:    ToUserResponse(snippet.Author),
-		CreatedAt: snippet.CreatedAt,
-		UpdatedAt: snippet.UpdatedAt,
-		Views:     snippet.Views,
-		Likes:     snippet.Likes,
-		IsLiked:   snippet.IsLiked,
-		IsSaved:   snippet.IsSaved,
+		ID:                snippet.ID,
+		Language:          snippet.Language,
+		Author:            ToUserResponse(snippet.Author),
+		OrganizationID:    snippet.OrganizationID,
+		Visibility:        string(snippet.Visibility),
+		ExpiresAt:         snippet.ExpiresAt,
+		BurnAfterRead:     snippet.BurnAfterRead,
+		PasswordProtected: snippet.IsPasswordProtected(),
+		Locked:            true,
+		CreatedAt:         snippet.CreatedAt,
+		UpdatedAt:         snippet.UpdatedAt,
+	}
+}
+
+// ToTrashedSnippetResponse describes a snippet in the trash, kept for the retention window
+func ToTrashedSnippetResponse(snippet *domain.Snippet, retention time.Duration) TrashedSnippetResponse {
+	response := TrashedSnippetResponse{SnippetResponse: ToSnippetResponse(snippet)}
+	if snippet.DeletedAt != nil {
+		response.DeletedAt = *snippet.DeletedAt
+		response.PurgeAt = snippet.DeletedAt.Add(retention)
+	}
+	return response
+}
+
+func ToSnippetCollaboratorResponse(collaborator *domain.SnippetCollaborator) SnippetCollaboratorResponse {
+	return SnippetCollaboratorResponse{
+		User:       ToUserResponse(collaborator.User),
+		Permission: string(collaborator.Permission),
+		AddedAt:    collaborator.AddedAt,
 	}
 }
 
 func ToDomainSnippet(req CreateSnippetRequest, userID string) *domain.Snippet {
-	return &domain.Snippet{
-		Title:    req.Title,
-		Content:  req.Content,
-		Language: req.Language,
+	snippet := &domain.Snippet{
+		Title:         req.Title,
+		Content:       req.Content,
+		Language:      req.Language,
+		Visibility:    domain.SnippetVisibility(req.Visibility),
+		ExpiresAt:     req.ExpiresAt,
+		BurnAfterRead: req.BurnAfterRead,
 		Author: &domain.User{
 			ID: userID,
 		},
 	}
+	if snippet.Visibility == "" {
+		snippet.Visibility = domain.SnippetPublic
+	}
+	if req.OrganizationID != "" {
+		snippet.OrganizationID = &req.OrganizationID
+	}
+	return snippet
 }
 
 func UpdateDomainSnippet(snippet *domain.Snippet, req UpdateSnippetRequest) {
 	snippet.Title = req.Title
 	snippet.Content = req.Content
 	snippet.Language = req.Language
+	if req.Visibility != nil {
+		snippet.Visibility = domain.SnippetVisibility(*req.Visibility)
+	}
 }
 
 type ToggleActionRequest struct {
@@ -3,19 +3,26 @@ package dto
 import "mitsimi.dev/codeShare/internal/domain"
 
 type UserResponse struct {
-	ID       string  `json:"id"`
-	Username string  `json:"username"`
-	Email    string  `json:"email"`
-	Avatar   *string `json:"avatar"`
+	ID             string  `json:"id"`
+	Username       string  `json:"username"`
+	Email          string  `json:"email"`
+	Avatar         *string `json:"avatar"`
+	FollowerCount  *int    `json:"followerCount,omitempty"`  // Only set on user profiles
+	FollowingCount *int    `json:"followingCount,omitempty"` // Only set on user profiles
 }
 
 func ToUserResponse(user *domain.User) UserResponse {
-	return UserResponse{
+	response := UserResponse{
 		ID:       user.ID,
 		Username: user.Username,
 		Email:    user.Email,
 		Avatar:   user.Avatar,
 	}
+	if user.FollowCounts != nil {
+		response.FollowerCount = &user.FollowCounts.Followers
+		response.FollowingCount = &user.FollowCounts.Following
+	}
+	return response
 }
 
 // UpdateUserInfoRequest represents the request body for updating a user's profile
@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// Response DTOs
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"` // Empty for all events
+	Global    bool      `json:"global"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // Only returned on creation
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"` // "pending", "succeeded" or "failed"
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"` // Only set while pending
+	ResponseStatus *int            `json:"responseStatus,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	CompletedAt    *time.Time      `json:"completedAt,omitempty"`
+}
+
+// WebhookSnippetEventData is the data of snippet webhook events
+type WebhookSnippetEventData struct {
+	Snippet SnippetResponse `json:"snippet"`
+	ActorID string          `json:"actorId"` // User who triggered the event
+}
+
+// Request DTOs
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Global bool     `json:"global"` // Admin only
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+// Conversion functions
+func ToWebhookResponse(webhook *domain.Webhook) WebhookResponse {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	return WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		Global:    webhook.Global,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func ToWebhookDeliveryResponse(delivery *domain.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		Event:          string(delivery.Event),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+		CompletedAt:    delivery.CompletedAt,
+	}
+	if delivery.Status == domain.WebhookDeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return response
+}
+
+func ToWebhookSnippetEventData(snippet *domain.Snippet, actorID string) WebhookSnippetEventData {
+	response := ToSnippetResponse(snippet)
+	// Liked and saved flags describe the acting user, not the webhook receiver
+	response.IsLiked = false
+	response.IsSaved = false
+
+	return WebhookSnippetEventData{
+		Snippet: response,
+		ActorID: actorID,
+	}
+}
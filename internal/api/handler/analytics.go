@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+	"mitsimi.dev/codeShare/internal/services"
+)
+
+// defaultAnalyticsDays is the number of days returned when no range is given
+const defaultAnalyticsDays = 30
+
+// AnalyticsHandler handles snippet analytics HTTP requests
+type AnalyticsHandler struct {
+	snippets  repository.SnippetRepository
+	analytics *services.ViewAnalytics
+	logger    *zap.Logger
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(snippets repository.SnippetRepository, analytics *services.ViewAnalytics) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		snippets:  snippets,
+		analytics: analytics,
+		logger:    logger.Log,
+	}
+}
+
+// GetSnippetAnalytics returns the view analytics time series of a snippet to its author
+func (h *AnalyticsHandler) GetSnippetAnalytics(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	query := r.URL.Query()
+	granularity, err := services.ParseGranularity(query.Get("granularity"))
+	if err != nil {
+		log.Warn("invalid granularity", zap.String("granularity", query.Get("granularity")))
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	to := time.Now().UTC()
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			log.Warn("invalid to date", zap.String("to", value))
+			api.WriteError(w, http.StatusBadRequest, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			log.Warn("invalid from date", zap.String("from", value))
+			api.WriteError(w, http.StatusBadRequest, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+
+	snippet, err := h.snippets.GetByID(r.Context(), id, userID)
+	if err != nil {
+		log.Warn("failed to get snippet for analytics",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if !snippet.IsOwner(userID) {
+		log.Warn("unauthorized analytics access attempt",
+			zap.String("snippet_author", snippet.Author.ID),
+		)
+		api.WriteError(w, http.StatusForbidden, "Only the owners of this snippet can view its analytics")
+		return
+	}
+
+	points, err := h.analytics.GetSnippetAnalytics(r.Context(), id, from, to, granularity)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsRange) {
+			log.Warn("invalid analytics range",
+				zap.Time("from", from),
+				zap.Time("to", to),
+			)
+			api.WriteError(w, http.StatusBadRequest, "from must not be after to and the range must not exceed two years")
+			return
+		}
+		log.Error("failed to get snippet analytics",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve analytics")
+		return
+	}
+
+	log.Info("retrieved snippet analytics",
+		zap.String("granularity", string(granularity)),
+		zap.Int("points", len(points)),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "Snippet analytics retrieved successfully",
+		dto.ToSnippetAnalyticsResponse(id, from, to, string(granularity), points))
+}
@@ -295,6 +295,26 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	api.WriteSuccess(w, http.StatusOK, "Token refreshed successfully", response)
 }
 
+// IssueWebSocketTicket issues a short-lived ticket for the live update endpoints, for
+// clients that cannot send the session cookie or an Authorization header with them
+func (h *AuthHandler) IssueWebSocketTicket(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	credential := api.GetCredential(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", credential.UserID))
+
+	ticket, err := auth.GenerateTicket(credential.UserID, credential.SessionID, credential.ExpiresAt, h.secretKey)
+	if err != nil {
+		log.Error("failed to generate websocket ticket", zap.Error(err))
+		api.WriteError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	api.WriteSuccess(w, http.StatusOK, "Ticket issued successfully", dto.WebSocketTicketResponse{
+		Ticket:    ticket.Token,
+		ExpiresAt: ticket.ExpiresAt,
+	})
+}
+
 func (h *AuthHandler) authenticateUser(ctx context.Context, username, password string) (string, error) {
 	user, err := h.users.GetByUsername(ctx, username)
 	if err != nil {
@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/constants"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const (
+	// maxBookmarkFolderNameLength is the maximum number of characters of a bookmark folder name
+	maxBookmarkFolderNameLength = 100
+
+	// maxBookmarkNoteLength is the maximum number of characters of a note on a saved snippet
+	maxBookmarkNoteLength = 2000
+
+	// maxBulkBookmarks is the maximum number of saved snippets changed by one bulk request
+	maxBulkBookmarks = 500
+
+	// unfiledFolder is the folder query parameter value selecting saved snippets without a folder
+	unfiledFolder = "none"
+)
+
+// BookmarkHandler handles the folders and notes of the authenticated user's saved snippets
+type BookmarkHandler struct {
+	bookmarks repository.BookmarkRepository
+	logger    *zap.Logger
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(bookmarks repository.BookmarkRepository) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarks: bookmarks,
+		logger:    logger.Log,
+	}
+}
+
+// ===== Helper methods for common logic =====
+
+// parseBookmarkFilter reads the folder ("none" for unfiled snippets) and q query parameters
+func parseBookmarkFilter(r *http.Request) repository.BookmarkFilter {
+	query := r.URL.Query()
+	filter := repository.BookmarkFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+	}
+	if folder := query.Get("folder"); folder == unfiledFolder {
+		filter.FolderID = new(string)
+	} else if folder != "" {
+		filter.FolderID = &folder
+	}
+	return filter
+}
+
+// validateBookmarkFolderName checks a trimmed bookmark folder name
+func validateBookmarkFolderName(name string) (string, bool) {
+	switch {
+	case name == "":
+		return "Name cannot be empty", false
+	case len([]rune(name)) > maxBookmarkFolderNameLength:
+		return "Name is too long", false
+	}
+	return "", true
+}
+
+// ===== Handlers =====
+
+// GetBookmarkFolders returns the bookmark folders of the authenticated user
+func (h *BookmarkHandler) GetBookmarkFolders(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	folders, err := h.bookmarks.GetFolders(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get bookmark folders",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve bookmark folders")
+		return
+	}
+
+	responses := make([]dto.BookmarkFolderResponse, len(folders))
+	for i, folder := range folders {
+		responses[i] = dto.ToBookmarkFolderResponse(folder)
+	}
+
+	log.Info("retrieved bookmark folders",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Bookmark folders retrieved successfully", responses)
+}
+
+// CreateBookmarkFolder creates an empty bookmark folder
+func (h *BookmarkHandler) CreateBookmarkFolder(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.BookmarkFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if message, ok := validateBookmarkFolderName(name); !ok {
+		log.Warn("invalid bookmark folder name", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	folder, err := h.bookmarks.CreateFolder(r.Context(), &domain.BookmarkFolder{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Name:   name,
+	})
+	if err != nil {
+		if repository.IsAlreadyExists(err) {
+			log.Warn("bookmark folder already exists")
+			api.WriteError(w, http.StatusConflict, "A folder with this name already exists")
+			return
+		}
+		log.Error("failed to create bookmark folder",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create bookmark folder")
+		return
+	}
+
+	log.Info("created bookmark folder",
+		zap.String("folder_id", folder.ID),
+	)
+	api.WriteSuccess(w, http.StatusCreated, "Bookmark folder created successfully", dto.ToBookmarkFolderResponse(folder))
+}
+
+// RenameBookmarkFolder changes the name of a bookmark folder
+func (h *BookmarkHandler) RenameBookmarkFolder(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	folderID := chi.URLParam(r, "folderId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("user_id", userID),
+		zap.String("folder_id", folderID),
+	)
+
+	var req dto.BookmarkFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if message, ok := validateBookmarkFolderName(name); !ok {
+		log.Warn("invalid bookmark folder name", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	folder, err := h.bookmarks.RenameFolder(r.Context(), userID, folderID, name)
+	if err != nil {
+		switch {
+		case repository.IsNotFound(err):
+			log.Warn("bookmark folder not found")
+			api.WriteError(w, http.StatusNotFound, "Bookmark folder not found")
+		case repository.IsAlreadyExists(err):
+			log.Warn("bookmark folder already exists")
+			api.WriteError(w, http.StatusConflict, "A folder with this name already exists")
+		default:
+			log.Error("failed to rename bookmark folder",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to rename bookmark folder")
+		}
+		return
+	}
+
+	log.Info("renamed bookmark folder")
+	api.WriteSuccess(w, http.StatusOK, "Bookmark folder renamed successfully", dto.ToBookmarkFolderResponse(folder))
+}
+
+// DeleteBookmarkFolder deletes a bookmark folder, the snippets in it stay saved without a folder
+func (h *BookmarkHandler) DeleteBookmarkFolder(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	folderID := chi.URLParam(r, "folderId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("user_id", userID),
+		zap.String("folder_id", folderID),
+	)
+
+	if err := h.bookmarks.DeleteFolder(r.Context(), userID, folderID); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("bookmark folder not found")
+			api.WriteError(w, http.StatusNotFound, "Bookmark folder not found")
+			return
+		}
+		log.Error("failed to delete bookmark folder",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to delete bookmark folder")
+		return
+	}
+
+	log.Info("deleted bookmark folder")
+	api.WriteSuccess(w, http.StatusOK, "Bookmark folder deleted successfully", nil)
+}
+
+// UpdateBookmark changes the note or folder of a saved snippet
+func (h *BookmarkHandler) UpdateBookmark(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	snippetID := chi.URLParam(r, "snippetId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("user_id", userID),
+		zap.String("snippet_id", snippetID),
+	)
+
+	var req dto.UpdateBookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Note == nil && req.FolderID == nil {
+		api.WriteError(w, http.StatusBadRequest, "Nothing to update")
+		return
+	}
+	if req.Note != nil && len([]rune(*req.Note)) > maxBookmarkNoteLength {
+		log.Warn("bookmark note too long")
+		api.WriteError(w, http.StatusBadRequest, "Note is too long")
+		return
+	}
+
+	if req.FolderID != nil {
+		var folderID *string
+		if *req.FolderID != "" {
+			folderID = req.FolderID
+		}
+
+		moved, err := h.bookmarks.Move(r.Context(), userID, folderID, []string{snippetID})
+		if err != nil {
+			if repository.IsNotFound(err) {
+				log.Warn("bookmark folder not found")
+				api.WriteError(w, http.StatusNotFound, "Bookmark folder not found")
+				return
+			}
+			log.Error("failed to move bookmark",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to update bookmark")
+			return
+		}
+		if moved == 0 {
+			log.Warn("snippet not saved")
+			api.WriteError(w, http.StatusNotFound, "Snippet is not saved")
+			return
+		}
+	}
+
+	if req.Note != nil {
+		if err := h.bookmarks.SetNote(r.Context(), userID, snippetID, strings.TrimSpace(*req.Note)); err != nil {
+			if repository.IsNotFound(err) {
+				log.Warn("snippet not saved")
+				api.WriteError(w, http.StatusNotFound, "Snippet is not saved")
+				return
+			}
+			log.Error("failed to update bookmark note",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to update bookmark")
+			return
+		}
+	}
+
+	log.Info("updated bookmark")
+	api.WriteSuccess(w, http.StatusOK, "Bookmark updated successfully", nil)
+}
+
+// BulkUpdateBookmarks moves several saved snippets to a folder or unsaves them
+func (h *BookmarkHandler) BulkUpdateBookmarks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.BulkBookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.SnippetIDs) == 0 {
+		api.WriteError(w, http.StatusBadRequest, "No snippets given")
+		return
+	}
+	if len(req.SnippetIDs) > maxBulkBookmarks {
+		log.Warn("too many snippets in bulk request", zap.Int("count", len(req.SnippetIDs)))
+		api.WriteError(w, http.StatusBadRequest, "Too many snippets")
+		return
+	}
+
+	var affected int
+	var err error
+	switch req.Action {
+	case constants.ActionMove:
+		var folderID *string
+		if req.FolderID != "" {
+			folderID = &req.FolderID
+		}
+		affected, err = h.bookmarks.Move(r.Context(), userID, folderID, req.SnippetIDs)
+	case constants.ActionUnsave:
+		affected, err = h.bookmarks.Unsave(r.Context(), userID, req.SnippetIDs)
+	default:
+		api.WriteError(w, http.StatusBadRequest, "Action must be move or unsave")
+		return
+	}
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("bookmark folder not found", zap.String("folder_id", req.FolderID))
+			api.WriteError(w, http.StatusNotFound, "Bookmark folder not found")
+			return
+		}
+		log.Error("failed to update bookmarks",
+			zap.Error(err),
+			zap.String("action", req.Action),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update bookmarks")
+		return
+	}
+
+	log.Info("updated bookmarks",
+		zap.String("action", req.Action),
+		zap.Int("affected", affected),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Bookmarks updated successfully", dto.BulkBookmarkResponse{Affected: affected})
+}
@@ -0,0 +1,587 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/auth"
+	"mitsimi.dev/codeShare/internal/constants"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+	"mitsimi.dev/codeShare/internal/services"
+)
+
+const (
+	// maxCollectionNameLength is the maximum number of characters of a collection name
+	maxCollectionNameLength = 100
+
+	// maxCollectionDescriptionLength is the maximum number of characters of a collection description
+	maxCollectionDescriptionLength = 1000
+)
+
+// collectionAccess is what a request needs to be allowed to do with a collection
+type collectionAccess int
+
+const (
+	collectionView   collectionAccess = iota // See the collection and its items
+	collectionEdit                           // Add, remove and reorder items, owner and collaborators
+	collectionManage                         // Rename, delete and manage collaborators, owner only
+)
+
+// CollectionHandler handles snippet collection HTTP requests
+type CollectionHandler struct {
+	collections repository.CollectionRepository
+	notifier    *services.Notifier
+	logger      *zap.Logger
+}
+
+// NewCollectionHandler creates a new collection handler
+func NewCollectionHandler(collections repository.CollectionRepository, notifier *services.Notifier) *CollectionHandler {
+	return &CollectionHandler{
+		collections: collections,
+		notifier:    notifier,
+		logger:      logger.Log,
+	}
+}
+
+// ===== Helper methods for common logic =====
+
+// getCollection loads the collection of the URL and checks that the user has the access.
+// Collections the user cannot view are reported as missing, admins can do everything.
+func (h *CollectionHandler) getCollection(w http.ResponseWriter, r *http.Request, log *zap.Logger, access collectionAccess) (*domain.Collection, bool) {
+	collectionID := chi.URLParam(r, "id")
+	userID := api.GetUserID(r)
+
+	collection, err := h.collections.GetByID(r.Context(), collectionID, userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("collection not found")
+			api.WriteError(w, http.StatusNotFound, "Collection not found")
+			return nil, false
+		}
+		log.Error("failed to get collection",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collection")
+		return nil, false
+	}
+
+	if auth.IsAdmin(userID) {
+		return collection, true
+	}
+
+	// Hide the existence of other users' private collections
+	if !collection.CanView(userID) {
+		log.Warn("unauthorized collection access",
+			zap.String("owner_id", collection.Owner.ID),
+		)
+		api.WriteError(w, http.StatusNotFound, "Collection not found")
+		return nil, false
+	}
+
+	allowed := true
+	switch access {
+	case collectionEdit:
+		allowed = collection.IsCollaborator(userID)
+	case collectionManage:
+		allowed = collection.Owner.ID == userID
+	}
+	if !allowed {
+		log.Warn("insufficient collection permissions",
+			zap.String("owner_id", collection.Owner.ID),
+		)
+		api.WriteError(w, http.StatusForbidden, "You are not allowed to change this collection")
+		return nil, false
+	}
+
+	return collection, true
+}
+
+// validateCollection checks the name, description and visibility of a collection
+func validateCollection(collection *domain.Collection) (string, bool) {
+	switch {
+	case collection.Name == "":
+		return "Name cannot be empty", false
+	case len([]rune(collection.Name)) > maxCollectionNameLength:
+		return "Name is too long", false
+	case len([]rune(collection.Description)) > maxCollectionDescriptionLength:
+		return "Description is too long", false
+	case !collection.Visibility.IsValid():
+		return "Visibility must be public or private", false
+	}
+	return "", true
+}
+
+// notifyFollowers tells the followers who can see the collection that a snippet was added
+func (h *CollectionHandler) notifyFollowers(r *http.Request, log *zap.Logger, collection *domain.Collection, snippetID string) {
+	if h.notifier == nil {
+		return
+	}
+
+	followerIDs, err := h.collections.GetFollowerIDs(r.Context(), collection.ID)
+	if err != nil {
+		log.Error("failed to get collection followers",
+			zap.Error(err),
+		)
+		return
+	}
+
+	actorID := api.GetUserID(r)
+	for _, followerID := range followerIDs {
+		if collection.CanView(followerID) {
+			h.notifier.NotifyCollectionItem(r.Context(), followerID, actorID, collection.ID, snippetID)
+		}
+	}
+}
+
+// writeCollection responds with the current state of a collection after a change
+func (h *CollectionHandler) writeCollection(w http.ResponseWriter, r *http.Request, log *zap.Logger, collectionID, message string) {
+	collection, err := h.collections.GetByID(r.Context(), collectionID, api.GetUserID(r))
+	if err != nil {
+		log.Error("failed to get collection",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collection")
+		return
+	}
+	api.WriteSuccess(w, http.StatusOK, message, dto.ToCollectionResponse(collection))
+}
+
+// ===== Handlers =====
+
+// GetCollections returns the collections the authenticated user owns or collaborates on
+func (h *CollectionHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	collections, err := h.collections.GetByUser(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get collections",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collections")
+		return
+	}
+
+	responses := make([]dto.CollectionResponse, len(collections))
+	for i, collection := range collections {
+		responses[i] = dto.ToCollectionResponse(collection)
+	}
+
+	log.Info("retrieved collections",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Collections retrieved successfully", responses)
+}
+
+// CreateCollection creates an empty collection owned by the authenticated user
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.CreateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Visibility == "" {
+		req.Visibility = string(domain.CollectionPublic)
+	}
+
+	collection := &domain.Collection{
+		ID:          uuid.New().String(),
+		Owner:       &domain.User{ID: userID},
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Visibility:  domain.CollectionVisibility(req.Visibility),
+	}
+	if message, ok := validateCollection(collection); !ok {
+		log.Warn("invalid collection data", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	created, err := h.collections.Create(r.Context(), collection)
+	if err != nil {
+		log.Error("failed to create collection",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create collection")
+		return
+	}
+
+	log.Info("created collection",
+		zap.String("collection_id", created.ID),
+	)
+	api.WriteSuccess(w, http.StatusCreated, "Collection created successfully", dto.ToCollectionResponse(created))
+}
+
+// GetCollection returns a collection with its items, public collections can be viewed by anyone
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	collection, ok := h.getCollection(w, r, log, collectionView)
+	if !ok {
+		return
+	}
+
+	items, err := h.collections.GetItems(r.Context(), collection.ID, userID)
+	if err != nil {
+		log.Error("failed to get collection items",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collection")
+		return
+	}
+
+	log.Info("retrieved collection",
+		zap.Int("items", len(items)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Collection retrieved successfully", dto.ToCollectionDetailResponse(collection, items))
+}
+
+// UpdateCollection changes the name, description or visibility of a collection
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.UpdateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, ok := h.getCollection(w, r, log, collectionManage)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil {
+		collection.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		collection.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Visibility != nil {
+		collection.Visibility = domain.CollectionVisibility(*req.Visibility)
+	}
+	if message, ok := validateCollection(collection); !ok {
+		log.Warn("invalid collection data", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	updated, err := h.collections.Update(r.Context(), collection)
+	if err != nil {
+		log.Error("failed to update collection",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update collection")
+		return
+	}
+
+	log.Info("updated collection")
+	api.WriteSuccess(w, http.StatusOK, "Collection updated successfully", dto.ToCollectionResponse(updated))
+}
+
+// DeleteCollection deletes a collection, the snippets in it are kept
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	collection, ok := h.getCollection(w, r, log, collectionManage)
+	if !ok {
+		return
+	}
+
+	if err := h.collections.Delete(r.Context(), collection.ID); err != nil {
+		log.Error("failed to delete collection",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to delete collection")
+		return
+	}
+
+	log.Info("deleted collection")
+	api.WriteSuccess(w, http.StatusOK, "Collection deleted successfully", nil)
+}
+
+// AddCollectionItem appends a snippet to a collection and notifies its followers
+func (h *CollectionHandler) AddCollectionItem(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.AddCollectionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SnippetID == "" {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, ok := h.getCollection(w, r, log, collectionEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.collections.AddItem(r.Context(), collection.ID, req.SnippetID, userID); err != nil {
+		switch {
+		case repository.IsNotFound(err):
+			log.Warn("snippet to add not found", zap.String("snippet_id", req.SnippetID))
+			api.WriteError(w, http.StatusNotFound, "Snippet not found")
+		case repository.IsAlreadyExists(err):
+			api.WriteError(w, http.StatusConflict, "Snippet is already in the collection")
+		default:
+			log.Error("failed to add collection item",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to add snippet to collection")
+		}
+		return
+	}
+
+	h.notifyFollowers(r, log, collection, req.SnippetID)
+
+	log.Info("added collection item",
+		zap.String("snippet_id", req.SnippetID),
+	)
+	api.WriteSuccess(w, http.StatusCreated, "Snippet added to collection", nil)
+}
+
+// RemoveCollectionItem removes a snippet from a collection
+func (h *CollectionHandler) RemoveCollectionItem(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	snippetID := chi.URLParam(r, "snippetId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("snippet_id", snippetID),
+		zap.String("user_id", userID),
+	)
+
+	collection, ok := h.getCollection(w, r, log, collectionEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.collections.RemoveItem(r.Context(), collection.ID, snippetID); err != nil {
+		if repository.IsNotFound(err) {
+			api.WriteError(w, http.StatusNotFound, "Snippet is not in the collection")
+			return
+		}
+		log.Error("failed to remove collection item",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to remove snippet from collection")
+		return
+	}
+
+	log.Info("removed collection item")
+	api.WriteSuccess(w, http.StatusOK, "Snippet removed from collection", nil)
+}
+
+// ReorderCollectionItems sets the manual order of the items of a collection
+func (h *CollectionHandler) ReorderCollectionItems(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.ReorderCollectionItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, ok := h.getCollection(w, r, log, collectionEdit)
+	if !ok {
+		return
+	}
+
+	if err := h.collections.ReorderItems(r.Context(), collection.ID, req.SnippetIDs); err != nil {
+		if repository.IsInvalidInput(err) {
+			log.Warn("invalid item order", zap.Error(err))
+			api.WriteError(w, http.StatusBadRequest, "Order must list every snippet of the collection exactly once")
+			return
+		}
+		log.Error("failed to reorder collection items",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to reorder collection")
+		return
+	}
+
+	items, err := h.collections.GetItems(r.Context(), collection.ID, userID)
+	if err != nil {
+		log.Error("failed to get collection items",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collection")
+		return
+	}
+
+	log.Info("reordered collection items",
+		zap.Int("items", len(items)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Collection reordered successfully", dto.ToCollectionDetailResponse(collection, items))
+}
+
+// AddCollaborator lets another user add, remove and reorder the items of a collection
+func (h *CollectionHandler) AddCollaborator(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	collaboratorID := chi.URLParam(r, "userId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("collaborator_id", collaboratorID),
+		zap.String("user_id", userID),
+	)
+
+	collection, ok := h.getCollection(w, r, log, collectionManage)
+	if !ok {
+		return
+	}
+
+	if collaboratorID == collection.Owner.ID {
+		api.WriteError(w, http.StatusBadRequest, "The owner cannot be a collaborator")
+		return
+	}
+
+	if err := h.collections.AddCollaborator(r.Context(), collection.ID, collaboratorID); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("collaborator not found")
+			api.WriteError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		log.Error("failed to add collaborator",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to add collaborator")
+		return
+	}
+
+	log.Info("added collaborator")
+	h.writeCollection(w, r, log, collection.ID, "Collaborator added successfully")
+}
+
+// RemoveCollaborator revokes a collaborator, collaborators may also remove themselves
+func (h *CollectionHandler) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	collaboratorID := chi.URLParam(r, "userId")
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("collaborator_id", collaboratorID),
+		zap.String("user_id", userID),
+	)
+
+	access := collectionManage
+	if collaboratorID == userID {
+		access = collectionEdit
+	}
+	collection, ok := h.getCollection(w, r, log, access)
+	if !ok {
+		return
+	}
+
+	if err := h.collections.RemoveCollaborator(r.Context(), collection.ID, collaboratorID); err != nil {
+		if repository.IsNotFound(err) {
+			api.WriteError(w, http.StatusNotFound, "User is not a collaborator")
+			return
+		}
+		log.Error("failed to remove collaborator",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to remove collaborator")
+		return
+	}
+
+	log.Info("removed collaborator")
+	api.WriteSuccess(w, http.StatusOK, "Collaborator removed successfully", nil)
+}
+
+// ToggleFollowCollection follows or unfollows a collection
+func (h *CollectionHandler) ToggleFollowCollection(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("collection_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	// Parse the action from query parameters
+	action := r.URL.Query().Get(constants.ActionQueryParam)
+	if action == "" {
+		action = constants.ActionFollow
+	}
+
+	if action != constants.ActionFollow && action != constants.ActionUnfollow {
+		log.Warn("invalid action",
+			zap.String("action", action),
+		)
+		api.WriteError(w, http.StatusBadRequest, "Invalid action")
+		return
+	}
+
+	collection, ok := h.getCollection(w, r, log, collectionView)
+	if !ok {
+		return
+	}
+
+	var err error
+	if action == constants.ActionFollow {
+		err = h.collections.Follow(r.Context(), collection.ID, userID)
+	} else {
+		err = h.collections.Unfollow(r.Context(), collection.ID, userID)
+	}
+	if err != nil {
+		log.Error("failed to toggle collection follow",
+			zap.Error(err),
+			zap.String("action", action),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update follow status")
+		return
+	}
+
+	log.Info("toggled collection follow",
+		zap.String("action", action),
+	)
+	h.writeCollection(w, r, log, collection.ID, "Follow status updated successfully")
+}
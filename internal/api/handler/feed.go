@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const (
+	// defaultFeedLimit is the number of feed items returned when no limit is given
+	defaultFeedLimit = 50
+
+	// maxFeedLimit is the maximum number of feed items per request
+	maxFeedLimit = 100
+)
+
+// FeedHandler handles activity feed HTTP requests
+type FeedHandler struct {
+	follows repository.FollowRepository
+	logger  *zap.Logger
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(follows repository.FollowRepository) *FeedHandler {
+	return &FeedHandler{
+		follows: follows,
+		logger:  logger.Log,
+	}
+}
+
+// GetFeed returns new and updated snippets of the authors the authenticated user follows
+func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	limit := defaultFeedLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxFeedLimit {
+			log.Warn("invalid feed limit", zap.String("limit", value))
+			api.WriteError(w, http.StatusBadRequest, "limit must be a number between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	snippets, err := h.follows.GetFeed(r.Context(), userID, limit)
+	if err != nil {
+		log.Error("failed to get feed",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		return
+	}
+
+	responses := make([]dto.FeedItemResponse, len(snippets))
+	for i, snippet := range snippets {
+		responses[i] = dto.ToFeedItemResponse(snippet)
+	}
+
+	log.Info("retrieved feed",
+		zap.Int("count", len(responses)),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "Feed retrieved successfully", responses)
+}
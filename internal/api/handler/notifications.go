@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const (
+	// defaultNotificationLimit is the number of notifications returned when no limit is given
+	defaultNotificationLimit = 50
+
+	// maxNotificationLimit is the maximum number of notifications per request
+	maxNotificationLimit = 100
+)
+
+// NotificationHandler handles notification inbox HTTP requests
+type NotificationHandler struct {
+	notifications repository.NotificationRepository
+	logger        *zap.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notifications repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{
+		notifications: notifications,
+		logger:        logger.Log,
+	}
+}
+
+// GetNotifications returns the most recent notifications of the authenticated user
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	limit := defaultNotificationLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxNotificationLimit {
+			log.Warn("invalid notification limit", zap.String("limit", value))
+			api.WriteError(w, http.StatusBadRequest, "limit must be a number between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	unreadOnly := false
+	if value := r.URL.Query().Get("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Warn("invalid unread filter", zap.String("unread", value))
+			api.WriteError(w, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	notifications, err := h.notifications.List(r.Context(), userID, unreadOnly, limit)
+	if err != nil {
+		log.Error("failed to get notifications",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	unread, err := h.notifications.CountUnread(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to count unread notifications",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve notifications")
+		return
+	}
+
+	response := dto.NotificationListResponse{
+		Notifications: make([]dto.NotificationResponse, len(notifications)),
+		UnreadCount:   unread,
+	}
+	for i, notification := range notifications {
+		response.Notifications[i] = dto.ToNotificationResponse(notification)
+	}
+
+	log.Info("retrieved notifications",
+		zap.Int("count", len(notifications)),
+		zap.Int("unread", unread),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "Notifications retrieved successfully", response)
+}
+
+// MarkNotificationRead marks a single notification of the authenticated user as read
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	notificationID := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("notification_id", notificationID),
+		zap.String("user_id", userID),
+	)
+
+	if err := h.notifications.MarkRead(r.Context(), userID, notificationID); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("notification not found")
+			api.WriteError(w, http.StatusNotFound, "Notification not found")
+			return
+		}
+		log.Error("failed to mark notification as read",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to mark notification as read")
+		return
+	}
+
+	log.Info("marked notification as read")
+	api.WriteSuccess(w, http.StatusOK, "Notification marked as read", nil)
+}
+
+// MarkAllNotificationsRead marks all notifications of the authenticated user as read
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	if err := h.notifications.MarkAllRead(r.Context(), userID); err != nil {
+		log.Error("failed to mark notifications as read",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to mark notifications as read")
+		return
+	}
+
+	log.Info("marked all notifications as read")
+	api.WriteSuccess(w, http.StatusOK, "Notifications marked as read", nil)
+}
+
+// GetPreferences returns which notification types the authenticated user receives
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	preferences, err := h.notifications.GetPreferences(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get notification preferences",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve notification preferences")
+		return
+	}
+
+	log.Info("retrieved notification preferences")
+	api.WriteSuccess(w, http.StatusOK, "Notification preferences retrieved successfully", dto.ToNotificationPreferencesResponse(preferences))
+}
+
+// UpdatePreferences mutes or unmutes notification types for the authenticated user
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Preferences) == 0 {
+		api.WriteError(w, http.StatusBadRequest, "At least one preference is required")
+		return
+	}
+	for notificationType := range req.Preferences {
+		if !domain.NotificationType(notificationType).IsValid() {
+			log.Warn("unknown notification type", zap.String("type", notificationType))
+			api.WriteError(w, http.StatusBadRequest, "Unknown notification type: "+notificationType)
+			return
+		}
+	}
+
+	for notificationType, enabled := range req.Preferences {
+		if err := h.notifications.SetPreference(r.Context(), userID, domain.NotificationType(notificationType), enabled); err != nil {
+			log.Error("failed to update notification preference",
+				zap.Error(err),
+				zap.String("type", notificationType),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to update notification preferences")
+			return
+		}
+	}
+
+	preferences, err := h.notifications.GetPreferences(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get notification preferences",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve notification preferences")
+		return
+	}
+
+	log.Info("updated notification preferences")
+	api.WriteSuccess(w, http.StatusOK, "Notification preferences updated successfully", dto.ToNotificationPreferencesResponse(preferences))
+}
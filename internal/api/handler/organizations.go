@@ -0,0 +1,569 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/auth"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const (
+	// maxOrganizationNameLength is the maximum number of characters of an organization name
+	maxOrganizationNameLength = 100
+
+	// maxOrganizationDescriptionLength is the maximum number of characters of an organization description
+	maxOrganizationDescriptionLength = 1000
+)
+
+// organizationSlugPattern allows 2 to 32 lowercase letters, digits and inner hyphens,
+// so a slug can never be mistaken for an organization ID
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,30}[a-z0-9]$`)
+
+// organizationAccess is what a request needs to be allowed to do with an organization
+type organizationAccess int
+
+const (
+	organizationView     organizationAccess = iota // See the profile and public snippets, anyone
+	organizationMember                             // See the members, any member
+	organizationMaintain                           // Add and remove plain members, maintainers and owners
+	organizationOwn                                // Change settings and roles and delete, owners only
+)
+
+// OrganizationHandler handles organization HTTP requests
+type OrganizationHandler struct {
+	organizations repository.OrganizationRepository
+	snippets      repository.SnippetRepository
+	logger        *zap.Logger
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(organizations repository.OrganizationRepository, snippets repository.SnippetRepository) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizations: organizations,
+		snippets:      snippets,
+		logger:        logger.Log,
+	}
+}
+
+// ===== Helper methods for common logic =====
+
+// getOrganization loads the organization of the URL, by ID or slug, and checks that the
+// user has the access. Admins can do everything.
+func (h *OrganizationHandler) getOrganization(w http.ResponseWriter, r *http.Request, log *zap.Logger, access organizationAccess) (*domain.Organization, bool) {
+	userID := api.GetUserID(r)
+
+	organization, err := h.organizations.GetByID(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("organization not found")
+			api.WriteError(w, http.StatusNotFound, "Organization not found")
+			return nil, false
+		}
+		log.Error("failed to get organization",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve organization")
+		return nil, false
+	}
+
+	if auth.IsAdmin(userID) {
+		return organization, true
+	}
+
+	allowed := true
+	switch access {
+	case organizationMember:
+		allowed = organization.Role != ""
+	case organizationMaintain:
+		allowed = organization.Role.CanManageSnippets()
+	case organizationOwn:
+		allowed = organization.Role == domain.RoleOwner
+	}
+	if !allowed {
+		log.Warn("insufficient organization permissions",
+			zap.String("role", string(organization.Role)),
+		)
+		api.WriteError(w, http.StatusForbidden, "You are not allowed to do this in this organization")
+		return nil, false
+	}
+
+	return organization, true
+}
+
+// validateOrganization checks the name and description of an organization
+func validateOrganization(organization *domain.Organization) (string, bool) {
+	switch {
+	case organization.Name == "":
+		return "Name cannot be empty", false
+	case len([]rune(organization.Name)) > maxOrganizationNameLength:
+		return "Name is too long", false
+	case len([]rune(organization.Description)) > maxOrganizationDescriptionLength:
+		return "Description is too long", false
+	}
+	return "", true
+}
+
+// isLastOwner reports whether the user is the only owner left in the organization
+func (h *OrganizationHandler) isLastOwner(r *http.Request, organizationID, userID string) (bool, error) {
+	members, err := h.organizations.GetMembers(r.Context(), organizationID)
+	if err != nil {
+		return false, err
+	}
+
+	owners := 0
+	isOwner := false
+	for _, member := range members {
+		if member.Role == domain.RoleOwner {
+			owners++
+			isOwner = isOwner || member.User.ID == userID
+		}
+	}
+	return isOwner && owners == 1, nil
+}
+
+// ===== Handlers =====
+
+// GetOrganizations returns the organizations the authenticated user is a member of
+func (h *OrganizationHandler) GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	organizations, err := h.organizations.GetByUser(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get organizations",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve organizations")
+		return
+	}
+
+	responses := make([]dto.OrganizationResponse, len(organizations))
+	for i, organization := range organizations {
+		responses[i] = dto.ToOrganizationResponse(organization)
+	}
+
+	log.Info("retrieved organizations",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Organizations retrieved successfully", responses)
+}
+
+// CreateOrganization creates an organization with the authenticated user as its owner
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	organization := &domain.Organization{
+		ID:          uuid.New().String(),
+		Slug:        strings.ToLower(strings.TrimSpace(req.Slug)),
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+	if !organizationSlugPattern.MatchString(organization.Slug) {
+		log.Warn("invalid organization slug", zap.String("slug", organization.Slug))
+		api.WriteError(w, http.StatusBadRequest, "Slug must be 2 to 32 lowercase letters, digits or hyphens")
+		return
+	}
+	if message, ok := validateOrganization(organization); !ok {
+		log.Warn("invalid organization data", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	created, err := h.organizations.Create(r.Context(), organization, userID)
+	if err != nil {
+		if repository.IsAlreadyExists(err) {
+			log.Warn("organization slug taken", zap.String("slug", organization.Slug))
+			api.WriteError(w, http.StatusConflict, "An organization with this slug already exists")
+			return
+		}
+		log.Error("failed to create organization",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create organization")
+		return
+	}
+
+	log.Info("created organization",
+		zap.String("organization_id", created.ID),
+	)
+	api.WriteSuccess(w, http.StatusCreated, "Organization created successfully", dto.ToOrganizationResponse(created))
+}
+
+// GetOrganization returns the profile of an organization with the snippets the user may see
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	organization, ok := h.getOrganization(w, r, log, organizationView)
+	if !ok {
+		return
+	}
+
+	snippets, err := h.snippets.GetAllByOrganization(r.Context(), organization.ID, userID)
+	if err != nil {
+		log.Error("failed to get organization snippets",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve organization")
+		return
+	}
+
+	response := dto.OrganizationProfileResponse{
+		OrganizationResponse: dto.ToOrganizationResponse(organization),
+		Snippets:             make([]dto.SnippetResponse, len(snippets)),
+	}
+	for i, snippet := range snippets {
+		response.Snippets[i] = dto.ToSnippetResponse(snippet)
+	}
+
+	log.Info("retrieved organization",
+		zap.Int("snippets", len(snippets)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Organization retrieved successfully", response)
+}
+
+// GetOrganizationSnippets returns the snippets of an organization the user may see, newest first
+func (h *OrganizationHandler) GetOrganizationSnippets(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	organization, ok := h.getOrganization(w, r, log, organizationView)
+	if !ok {
+		return
+	}
+
+	snippets, err := h.snippets.GetAllByOrganization(r.Context(), organization.ID, userID)
+	if err != nil {
+		log.Error("failed to get organization snippets",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippets")
+		return
+	}
+
+	responses := make([]dto.SnippetResponse, len(snippets))
+	for i, snippet := range snippets {
+		responses[i] = dto.ToSnippetResponse(snippet)
+	}
+
+	log.Info("retrieved organization snippets",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Snippets retrieved successfully", responses)
+}
+
+// UpdateOrganization changes the name or description of an organization
+func (h *OrganizationHandler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.UpdateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	organization, ok := h.getOrganization(w, r, log, organizationOwn)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil {
+		organization.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		organization.Description = strings.TrimSpace(*req.Description)
+	}
+	if message, ok := validateOrganization(organization); !ok {
+		log.Warn("invalid organization data", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+
+	if err := h.organizations.Update(r.Context(), organization); err != nil {
+		log.Error("failed to update organization",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update organization")
+		return
+	}
+
+	updated, err := h.organizations.GetByID(r.Context(), organization.ID, userID)
+	if err != nil {
+		log.Error("failed to get organization",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve organization")
+		return
+	}
+
+	log.Info("updated organization")
+	api.WriteSuccess(w, http.StatusOK, "Organization updated successfully", dto.ToOrganizationResponse(updated))
+}
+
+// DeleteOrganization deletes an organization that no longer owns any snippets
+func (h *OrganizationHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	organization, ok := h.getOrganization(w, r, log, organizationOwn)
+	if !ok {
+		return
+	}
+
+	// Snippets are never orphaned, they have to be deleted first. The repository
+	// checks inside the deletion transaction, so trashed snippets count as well.
+	if err := h.organizations.Delete(r.Context(), organization.ID); err != nil {
+		if errors.Is(err, repository.ErrOrganizationHasSnippets) {
+			log.Warn("organization still owns snippets")
+			api.WriteError(w, http.StatusConflict, "Delete the snippets of the organization first, including the trash")
+			return
+		}
+		log.Error("failed to delete organization",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to delete organization")
+		return
+	}
+
+	log.Info("deleted organization")
+	api.WriteSuccess(w, http.StatusOK, "Organization deleted successfully", nil)
+}
+
+// GetOrganizationMembers returns the members of an organization, owners first
+func (h *OrganizationHandler) GetOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	organization, ok := h.getOrganization(w, r, log, organizationMember)
+	if !ok {
+		return
+	}
+
+	members, err := h.organizations.GetMembers(r.Context(), organization.ID)
+	if err != nil {
+		log.Error("failed to get organization members",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve members")
+		return
+	}
+
+	responses := make([]dto.OrganizationMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = dto.ToOrganizationMemberResponse(member)
+	}
+
+	log.Info("retrieved organization members",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Members retrieved successfully", responses)
+}
+
+// SetOrganizationMember adds a user to an organization or changes their role. Owners may
+// grant any role, maintainers may only add plain members.
+func (h *OrganizationHandler) SetOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "userId")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("member_id", memberID),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.SetOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	role := domain.OrganizationRole(req.Role)
+	if !role.IsValid() {
+		log.Warn("invalid organization role", zap.String("role", req.Role))
+		api.WriteError(w, http.StatusBadRequest, "Role must be owner, maintainer or member")
+		return
+	}
+
+	organization, ok := h.getOrganization(w, r, log, organizationMaintain)
+	if !ok {
+		return
+	}
+
+	current, err := h.organizations.GetRole(r.Context(), organization.ID, memberID)
+	if err != nil && !repository.IsNotFound(err) {
+		log.Error("failed to get organization role",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update member")
+		return
+	}
+
+	// Maintainers cannot grant or take away the roles of maintainers and owners
+	if organization.Role != domain.RoleOwner && !auth.IsAdmin(userID) &&
+		(role != domain.RoleMember || current.CanManageSnippets()) {
+		log.Warn("maintainer tried to change a privileged role",
+			zap.String("role", req.Role),
+			zap.String("current_role", string(current)),
+		)
+		api.WriteError(w, http.StatusForbidden, "Only owners can grant or change this role")
+		return
+	}
+
+	if current == domain.RoleOwner && role != domain.RoleOwner {
+		lastOwner, err := h.isLastOwner(r, organization.ID, memberID)
+		if err != nil {
+			log.Error("failed to get organization members",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to update member")
+			return
+		}
+		if lastOwner {
+			log.Warn("tried to demote the last owner")
+			api.WriteError(w, http.StatusConflict, "An organization needs at least one owner")
+			return
+		}
+	}
+
+	if err := h.organizations.SetMember(r.Context(), organization.ID, memberID, role); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("member user not found")
+			api.WriteError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		log.Error("failed to set organization member",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update member")
+		return
+	}
+
+	log.Info("set organization member",
+		zap.String("role", req.Role),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Member updated successfully", nil)
+}
+
+// RemoveOrganizationMember removes a user from an organization. Members may leave on their
+// own, maintainers may remove plain members and owners anyone but the last owner.
+func (h *OrganizationHandler) RemoveOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	memberID := chi.URLParam(r, "userId")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("organization_id", chi.URLParam(r, "id")),
+		zap.String("member_id", memberID),
+		zap.String("user_id", userID),
+	)
+
+	access := organizationMaintain
+	if memberID == userID {
+		access = organizationMember
+	}
+	organization, ok := h.getOrganization(w, r, log, access)
+	if !ok {
+		return
+	}
+
+	current, err := h.organizations.GetRole(r.Context(), organization.ID, memberID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("member not found")
+			api.WriteError(w, http.StatusNotFound, "Member not found")
+			return
+		}
+		log.Error("failed to get organization role",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to remove member")
+		return
+	}
+
+	if memberID != userID && organization.Role != domain.RoleOwner && !auth.IsAdmin(userID) && current.CanManageSnippets() {
+		log.Warn("maintainer tried to remove a privileged member",
+			zap.String("current_role", string(current)),
+		)
+		api.WriteError(w, http.StatusForbidden, "Only owners can remove maintainers and owners")
+		return
+	}
+
+	if current == domain.RoleOwner {
+		lastOwner, err := h.isLastOwner(r, organization.ID, memberID)
+		if err != nil {
+			log.Error("failed to get organization members",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to remove member")
+			return
+		}
+		if lastOwner {
+			log.Warn("tried to remove the last owner")
+			api.WriteError(w, http.StatusConflict, "An organization needs at least one owner")
+			return
+		}
+	}
+
+	if err := h.organizations.RemoveMember(r.Context(), organization.ID, memberID); err != nil {
+		if repository.IsNotFound(err) {
+			api.WriteError(w, http.StatusNotFound, "Member not found")
+			return
+		}
+		log.Error("failed to remove organization member",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to remove member")
+		return
+	}
+
+	log.Info("removed organization member")
+	api.WriteSuccess(w, http.StatusOK, "Member removed successfully", nil)
+}
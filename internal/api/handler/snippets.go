@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"slices"
+	"strconv"
 	"time"
 
 	"mitsimi.dev/codeShare/internal/api"
 	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/auth"
 	"mitsimi.dev/codeShare/internal/constants"
+	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/services"
 	ws "mitsimi.dev/codeShare/internal/websocket"
 
@@ -24,12 +27,19 @@ import (
 
 // SnippetHandler handles snippet-related HTTP requests
 type SnippetHandler struct {
-	snippets    repository.SnippetRepository
-	likes       repository.LikeRepository
-	bookmarks   repository.BookmarkRepository
-	viewTracker *services.ViewTracker
-	wsHub       *ws.Hub
-	logger      *zap.Logger
+	snippets      repository.SnippetRepository
+	likes         repository.LikeRepository
+	bookmarks     repository.BookmarkRepository
+	follows       repository.FollowRepository
+	organizations repository.OrganizationRepository
+	viewTracker   *services.ViewTracker
+	bots          *services.BotClassifier
+	notifier      *services.Notifier
+	webhooks      *services.WebhookDispatcher
+	unlocks       *services.UnlockLimiter
+	wsHub         *ws.Hub
+	secretKey     string
+	logger        *zap.Logger
 }
 
 // NewSnippetHandler creates a new snippet handler
@@ -37,19 +47,143 @@ func NewSnippetHandler(
 	snippets repository.SnippetRepository,
 	likes repository.LikeRepository,
 	bookmarks repository.BookmarkRepository,
+	follows repository.FollowRepository,
+	organizations repository.OrganizationRepository,
 	viewTracker *services.ViewTracker,
+	bots *services.BotClassifier,
+	notifier *services.Notifier,
+	webhooks *services.WebhookDispatcher,
+	unlocks *services.UnlockLimiter,
 	wsHub *ws.Hub,
+	secretKey string,
 ) *SnippetHandler {
 	return &SnippetHandler{
-		snippets:    snippets,
-		likes:       likes,
-		bookmarks:   bookmarks,
-		viewTracker: viewTracker,
-		wsHub:       wsHub,
-		logger:      logger.Log,
+		snippets:      snippets,
+		likes:         likes,
+		bookmarks:     bookmarks,
+		follows:       follows,
+		organizations: organizations,
+		viewTracker:   viewTracker,
+		bots:          bots,
+		notifier:      notifier,
+		webhooks:      webhooks,
+		unlocks:       unlocks,
+		wsHub:         wsHub,
+		secretKey:     secretKey,
+		logger:        logger.Log,
 	}
 }
 
+// ===== Helper methods for common logic =====
+
+// getVisibleSnippet loads the snippet of the URL, hiding organization-only and private snippets
+// from users who may not see them and answering 410 Gone for expired ones
+func (h *SnippetHandler) getVisibleSnippet(w http.ResponseWriter, r *http.Request, log *zap.Logger) (*domain.Snippet, bool) {
+	snippet, err := h.snippets.GetByID(r.Context(), chi.URLParam(r, "id"), api.GetUserID(r))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("snippet not found")
+			api.WriteError(w, http.StatusNotFound, "Snippet not found")
+			return nil, false
+		}
+		log.Error("failed to get snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippet")
+		return nil, false
+	}
+
+	if !snippet.CanView(api.GetUserID(r)) {
+		log.Warn("unauthorized access to restricted snippet")
+		api.WriteError(w, http.StatusNotFound, "Snippet not found")
+		return nil, false
+	}
+
+	// Expired snippets are gone even before the purge job deletes them
+	if snippet.IsExpired(time.Now()) {
+		log.Info("snippet has expired")
+		api.WriteError(w, http.StatusGone, "Snippet has expired")
+		return nil, false
+	}
+	return snippet, true
+}
+
+// isUnlocked reports whether the request may read the snippet, either because the user
+// does not need its password or because it carries a grant from unlocking it
+func (h *SnippetHandler) isUnlocked(r *http.Request, snippet *domain.Snippet) bool {
+	if !snippet.IsLocked(api.GetUserID(r)) {
+		return true
+	}
+
+	grant := r.Header.Get(constants.SnippetGrantHeader)
+	if grant == "" {
+		if cookie, err := r.Cookie(constants.SnippetGrantCookiePrefix + snippet.ID); err == nil {
+			grant = cookie.Value
+		}
+	}
+	return grant != "" && auth.ValidateSnippetGrant(grant, snippet.ID, snippet.PasswordHash, h.secretKey) == nil
+}
+
+// getUnlockedSnippet loads the snippet of the URL like getVisibleSnippet, and additionally
+// refuses password-protected snippets the request has not unlocked
+func (h *SnippetHandler) getUnlockedSnippet(w http.ResponseWriter, r *http.Request, log *zap.Logger) (*domain.Snippet, bool) {
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return nil, false
+	}
+	if !h.isUnlocked(r, snippet) {
+		log.Warn("snippet is locked")
+		api.WriteError(w, http.StatusForbidden, "Snippet is password protected")
+		return nil, false
+	}
+	return snippet, true
+}
+
+// hashSnippetPassword hashes the viewing password of a snippet, an empty password removes protection
+func hashSnippetPassword(password string) (string, string, error) {
+	if password == "" {
+		return "", "", nil
+	}
+	// bcrypt ignores everything after 72 bytes
+	if len(password) > 72 {
+		return "", "Password must be at most 72 bytes long", nil
+	}
+	hash, err := auth.HashPassword(password)
+	return hash, "", err
+}
+
+// checkMembership makes sure the user belongs to the organization a snippet is created in or moved to
+func (h *SnippetHandler) checkMembership(w http.ResponseWriter, r *http.Request, log *zap.Logger, organizationID string) bool {
+	if _, err := h.organizations.GetRole(r.Context(), organizationID, api.GetUserID(r)); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("user is not a member of the organization",
+				zap.String("organization_id", organizationID),
+			)
+			api.WriteError(w, http.StatusForbidden, "You are not a member of this organization")
+			return false
+		}
+		log.Error("failed to get organization role",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to check organization membership")
+		return false
+	}
+	return true
+}
+
+// validateVisibility checks that only snippets of an organization are limited to its members
+func validateVisibility(snippet *domain.Snippet) (string, bool) {
+	switch {
+	case !snippet.Visibility.IsValid():
+		return "Visibility must be public, organization or private", false
+	case snippet.OrganizationID == nil && snippet.Visibility == domain.SnippetOrganization:
+		return "Only snippets of an organization can be limited to its members", false
+	}
+	return "", true
+}
+
+// ===== Handlers =====
+
 // GetSnippets returns all snippets
 func (h *SnippetHandler) GetSnippets(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetReqID(r.Context())
@@ -92,38 +226,57 @@ func (h *SnippetHandler) GetSnippet(w http.ResponseWriter, r *http.Request) {
 		zap.String("user_id", userID),
 	)
 
-	snippet, err := h.snippets.GetByID(r.Context(), id, userID)
-	if err != nil {
-		log.Warn("failed to get snippet",
-			zap.Error(err),
-		)
-		api.WriteError(w, http.StatusNotFound, err.Error())
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+
+	// Locked snippets are described without their title and content, and are not burned
+	// or counted until someone enters the password
+	if !h.isUnlocked(r, snippet) {
+		log.Info("retrieved locked snippet")
+		api.WriteSuccess(w, http.StatusOK, "Snippet is password protected", dto.ToLockedSnippetResponse(snippet))
 		return
 	}
 
-	// Track view asynchronously to avoid blocking the response
-	go func() {
-		// Create a separate context with timeout for view tracking
-		// Don't use r.Context() as it gets cancelled when the response is sent
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	if snippet.BurnsOnViewBy(userID) {
+		// Link previews and crawlers would burn the snippet before the recipient opens
+		// the link, they only get the stub
+		if bot, reason := h.bots.Classify(r); bot {
+			log.Info("served burn-after-read snippet stub to bot", zap.String("reason", reason))
+			api.WriteSuccess(w, http.StatusOK, "Snippet is deleted once read", dto.ToLockedSnippetResponse(snippet))
+			return
+		}
 
-		if err := h.viewTracker.TrackView(ctx, r, id, userID); err != nil {
-			log.Error("failed to track view",
+		// Only the view that deletes the snippet gets to read it
+		if err := h.snippets.Burn(r.Context(), id); err != nil {
+			if repository.IsNotFound(err) {
+				log.Info("snippet was already burned")
+				api.WriteError(w, http.StatusGone, "Snippet has already been read")
+				return
+			}
+			log.Error("failed to burn snippet",
 				zap.Error(err),
-				zap.String("snippet_id", id),
-				zap.String("user_id", userID),
 			)
-		} else {
-			// Broadcast view count update after successfully tracking the view
-			if h.wsHub != nil {
-				// Get the updated snippet to get the new view count
-				if updatedSnippet, err := h.snippets.GetByID(ctx, id, userID); err == nil {
-					h.wsHub.BroadcastSnippetStatsUpdate(id, &updatedSnippet.Views, &updatedSnippet.Likes)
-				}
-			}
+			api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippet")
+			return
 		}
-	}()
+
+		response := dto.ToSnippetResponse(snippet)
+		if h.wsHub != nil {
+			h.wsHub.CloseEditSession(id)
+			h.wsHub.BroadcastSnippetDeleted(response)
+		}
+
+		log.Info("burned snippet after reading",
+			zap.String("author", response.Author.ID),
+		)
+		api.WriteSuccess(w, http.StatusOK, "Snippet retrieved successfully", response)
+		return
+	}
+
+	// Buffer the view; counters are written and broadcast by the tracker's flush loop
+	h.viewTracker.TrackView(r, id, userID)
 
 	response := dto.ToSnippetResponse(snippet)
 
@@ -136,6 +289,77 @@ func (h *SnippetHandler) GetSnippet(w http.ResponseWriter, r *http.Request) {
 	api.WriteSuccess(w, http.StatusOK, "Snippet retrieved successfully", response)
 }
 
+// UnlockSnippet checks the password of a protected snippet and hands out a grant to read it.
+// The grant is set as a cookie for browsers and returned for other clients.
+func (h *SnippetHandler) UnlockSnippet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	clientIP := services.GetClientIP(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.UnlockSnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("failed to decode request body",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+	if !snippet.IsPasswordProtected() {
+		log.Warn("unlock attempt on unprotected snippet")
+		api.WriteError(w, http.StatusBadRequest, "Snippet is not password protected")
+		return
+	}
+
+	if retryAfter, ok := h.unlocks.Allow(id, clientIP); !ok {
+		log.Warn("too many wrong snippet passwords")
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		api.WriteError(w, http.StatusTooManyRequests, "Too many wrong passwords, try again later")
+		return
+	}
+	if !auth.CheckPasswordHash(req.Password, snippet.PasswordHash) {
+		log.Warn("wrong snippet password")
+		api.WriteError(w, http.StatusForbidden, "Wrong password")
+		return
+	}
+	h.unlocks.Reset(id, clientIP)
+
+	grant, err := auth.GenerateSnippetGrant(id, snippet.PasswordHash, h.secretKey)
+	if err != nil {
+		log.Error("failed to generate snippet grant",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to unlock snippet")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     constants.SnippetGrantCookiePrefix + id,
+		Value:    grant.Token,
+		Path:     "/api/snippets/" + id,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+		Expires:  time.Unix(grant.ExpiresAt, 0),
+	})
+
+	log.Info("unlocked snippet")
+	api.WriteSuccess(w, http.StatusOK, "Snippet unlocked successfully", dto.SnippetGrantResponse{
+		Grant:     grant.Token,
+		ExpiresAt: grant.ExpiresAt,
+	})
+}
+
 // CreateSnippet creates a new snippet
 func (h *SnippetHandler) CreateSnippet(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetReqID(r.Context())
@@ -176,6 +400,35 @@ func (h *SnippetHandler) CreateSnippet(w http.ResponseWriter, r *http.Request) {
 	domainSnippet.CreatedAt = time.Now()
 	domainSnippet.UpdatedAt = time.Now()
 
+	if message, ok := validateVisibility(domainSnippet); !ok {
+		log.Warn("invalid snippet visibility", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+	if domainSnippet.ExpiresAt != nil && !domainSnippet.ExpiresAt.After(time.Now()) {
+		log.Warn("snippet expiry in the past", zap.Time("expires_at", *domainSnippet.ExpiresAt))
+		api.WriteError(w, http.StatusBadRequest, "Expiry time must be in the future")
+		return
+	}
+	if domainSnippet.OrganizationID != nil && !h.checkMembership(w, r, log, *domainSnippet.OrganizationID) {
+		return
+	}
+
+	passwordHash, message, err := hashSnippetPassword(req.Password)
+	if message != "" {
+		log.Warn("invalid snippet password", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+	if err != nil {
+		log.Error("failed to hash snippet password",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create snippet")
+		return
+	}
+	domainSnippet.PasswordHash = passwordHash
+
 	log.Debug("creating new snippet",
 		zap.String("title", domainSnippet.Title),
 		zap.String("content", domainSnippet.Content),
@@ -201,6 +454,12 @@ func (h *SnippetHandler) CreateSnippet(w http.ResponseWriter, r *http.Request) {
 		zap.String("author", response.Author.ID),
 	)
 
+	if h.wsHub != nil {
+		h.wsHub.BroadcastSnippetCreated(response)
+	}
+	h.publishFeedItem(r.Context(), log, s, constants.FeedItemCreated)
+	h.publishWebhookEvent(r.Context(), domain.WebhookEventSnippetCreated, s, userID)
+
 	w.Header().Set("Location", "/snippets/"+s.ID)
 	api.WriteSuccess(w, http.StatusCreated, "Snippet created successfully", response)
 }
@@ -225,26 +484,58 @@ func (h *SnippetHandler) UpdateSnippet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	snippet, err := h.snippets.GetByID(r.Context(), id, userID)
-	if err != nil {
-		log.Warn("failed to get snippet for update",
-			zap.Error(err),
-		)
-		api.WriteError(w, http.StatusNotFound, err.Error())
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
 		return
 	}
 
-	if snippet.Author.ID != userID {
+	if !snippet.CanEdit(userID) {
 		log.Warn("unauthorized update attempt",
 			zap.String("snippet_author", snippet.Author.ID),
 			zap.String("user_id", userID),
 		)
-		api.WriteError(w, http.StatusForbidden, "Only the author can update this snippet")
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners and collaborators with edit permission can update it")
+		return
+	}
+	if req.Visibility != nil && domain.SnippetVisibility(*req.Visibility) != snippet.Visibility && !snippet.IsOwner(userID) {
+		log.Warn("unauthorized visibility change",
+			zap.String("visibility", *req.Visibility),
+		)
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners can change its visibility")
+		return
+	}
+	if req.Password != nil && !snippet.IsOwner(userID) {
+		log.Warn("unauthorized password change")
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners can change its password")
 		return
 	}
 
 	// Update domain model
+	wasListed := snippet.IsListed()
+	visibility := snippet.Visibility
+	if req.Password != nil {
+		passwordHash, message, err := hashSnippetPassword(*req.Password)
+		if message != "" {
+			log.Warn("invalid snippet password", zap.String("reason", message))
+			api.WriteError(w, http.StatusBadRequest, message)
+			return
+		}
+		if err != nil {
+			log.Error("failed to hash snippet password",
+				zap.Error(err),
+			)
+			api.WriteError(w, http.StatusInternalServerError, "Failed to update snippet")
+			return
+		}
+		snippet.PasswordHash = passwordHash
+	}
 	dto.UpdateDomainSnippet(snippet, req)
+	snippet.LastEditor = &domain.User{ID: userID}
+	if message, ok := validateVisibility(snippet); !ok {
+		log.Warn("invalid snippet visibility", zap.String("reason", message))
+		api.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
 
 	if err := h.snippets.Update(r.Context(), snippet); err != nil {
 		log.Error("failed to update snippet",
@@ -256,6 +547,11 @@ func (h *SnippetHandler) UpdateSnippet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reload to pick up the editor's name and the new timestamp
+	if updated, err := h.snippets.GetByID(r.Context(), id, userID); err == nil {
+		snippet = updated
+	}
+
 	// Convert to response DTO
 	response := dto.ToSnippetResponse(snippet)
 	log.Info("updated snippet",
@@ -265,18 +561,23 @@ func (h *SnippetHandler) UpdateSnippet(w http.ResponseWriter, r *http.Request) {
 
 	// Broadcast content update to both snippet detail and list subscribers
 	if h.wsHub != nil {
-		h.wsHub.BroadcastSnippetContentUpdate(
-			snippet.ID,
-			&snippet.Title,
-			&snippet.Content,
-			&snippet.Language,
-		)
+		h.wsHub.BroadcastSnippetUpdated(response)
+		if snippet.IsListed() != wasListed {
+			h.wsHub.BroadcastSnippetVisibilityChanged(response)
+		}
+		if snippet.Visibility != visibility || req.Password != nil {
+			h.wsHub.RevalidateSnippet(snippet.ID)
+		}
+		h.wsHub.ReplaceEditContent(snippet.ID, snippet.Content)
 	}
+	h.publishFeedItem(r.Context(), log, snippet, constants.FeedItemUpdated)
+	h.publishWebhookEvent(r.Context(), domain.WebhookEventSnippetUpdated, snippet, userID)
 
 	api.WriteSuccess(w, http.StatusOK, "Snippet updated successfully", response)
 }
 
-// DeleteSnippet deletes a snippet
+// DeleteSnippet moves a snippet to the trash, from where its owners can restore it until
+// it is purged
 func (h *SnippetHandler) DeleteSnippet(w http.ResponseWriter, r *http.Request) {
 	snippetID := chi.URLParam(r, "id")
 	requestID := middleware.GetReqID(r.Context())
@@ -287,33 +588,274 @@ func (h *SnippetHandler) DeleteSnippet(w http.ResponseWriter, r *http.Request) {
 		zap.String("user_id", userID),
 	)
 
-	snippet, err := h.snippets.GetByID(r.Context(), snippetID, userID)
-	if err != nil {
-		log.Warn("failed to get snippet",
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+	if !snippet.IsOwner(userID) {
+		log.Warn("unauthorized deletion attempt",
+			zap.String("snippet_author", snippet.Author.ID),
+			zap.String("user_id", userID),
+		)
+		api.WriteError(w, http.StatusForbidden, "Only the author or the organization's maintainers can delete this snippet")
+		return
+	}
+
+	if err := h.snippets.Trash(r.Context(), snippetID); err != nil {
+		log.Error("failed to move snippet to trash",
 			zap.Error(err),
 		)
 		api.WriteError(w, http.StatusNotFound, err.Error())
 		return
 	}
+
+	if h.wsHub != nil {
+		h.wsHub.CloseEditSession(snippetID)
+		h.wsHub.BroadcastSnippetDeleted(dto.ToSnippetResponse(snippet))
+	}
+	h.publishWebhookEvent(r.Context(), domain.WebhookEventSnippetDeleted, snippet, userID)
+
+	log.Info("moved snippet to trash")
+	api.WriteSuccess(w, http.StatusOK, "Snippet moved to trash successfully", nil)
+}
+
+// TransferSnippet moves a personal snippet of the user into one of their organizations
+func (h *SnippetHandler) TransferSnippet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.TransferSnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.OrganizationID == "" {
+		api.WriteError(w, http.StatusBadRequest, "Organization ID is required")
+		return
+	}
+	if req.Visibility == "" {
+		req.Visibility = string(domain.SnippetPublic)
+	}
+	visibility := domain.SnippetVisibility(req.Visibility)
+	if !visibility.IsValid() {
+		log.Warn("invalid snippet visibility", zap.String("visibility", req.Visibility))
+		api.WriteError(w, http.StatusBadRequest, "Visibility must be public, organization or private")
+		return
+	}
+
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+
+	if snippet.OrganizationID != nil {
+		log.Warn("snippet already belongs to an organization",
+			zap.String("organization_id", *snippet.OrganizationID),
+		)
+		api.WriteError(w, http.StatusConflict, "Snippet already belongs to an organization")
+		return
+	}
 	if snippet.Author.ID != userID {
-		log.Warn("unauthorized deletion attempt",
+		log.Warn("unauthorized transfer attempt",
 			zap.String("snippet_author", snippet.Author.ID),
-			zap.String("user_id", userID),
 		)
-		api.WriteError(w, http.StatusForbidden, "Only the author can delete this snippet")
+		api.WriteError(w, http.StatusForbidden, "Only the author can transfer this snippet")
+		return
+	}
+	if !h.checkMembership(w, r, log, req.OrganizationID) {
 		return
 	}
 
-	if err := h.snippets.Delete(r.Context(), snippetID); err != nil {
-		log.Error("failed to delete snippet",
+	if err := h.snippets.TransferToOrganization(r.Context(), id, req.OrganizationID, visibility); err != nil {
+		if repository.IsNotFound(err) {
+			api.WriteError(w, http.StatusConflict, "Snippet already belongs to an organization")
+			return
+		}
+		log.Error("failed to transfer snippet",
 			zap.Error(err),
 		)
-		api.WriteError(w, http.StatusNotFound, err.Error())
+		api.WriteError(w, http.StatusInternalServerError, "Failed to transfer snippet")
+		return
+	}
+
+	transferred, err := h.snippets.GetByID(r.Context(), id, userID)
+	if err != nil {
+		log.Error("failed to get transferred snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippet")
+		return
+	}
+
+	response := dto.ToSnippetResponse(transferred)
+	if h.wsHub != nil {
+		if !transferred.IsPublic() {
+			h.wsHub.BroadcastSnippetVisibilityChanged(response)
+		}
+		h.wsHub.RevalidateSnippet(transferred.ID)
+	}
+
+	log.Info("transferred snippet",
+		zap.String("organization_id", req.OrganizationID),
+		zap.String("visibility", req.Visibility),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Snippet transferred successfully", response)
+}
+
+// GetSnippetCollaborators returns the users invited to a snippet, editors first
+func (h *SnippetHandler) GetSnippetCollaborators(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+	if !snippet.IsOwner(userID) && snippet.Permission == "" {
+		log.Warn("unauthorized collaborator list access")
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners and collaborators can see its collaborators")
+		return
+	}
+
+	collaborators, err := h.snippets.GetCollaborators(r.Context(), id)
+	if err != nil {
+		log.Error("failed to get snippet collaborators",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve collaborators")
+		return
+	}
+
+	responses := make([]dto.SnippetCollaboratorResponse, len(collaborators))
+	for i, collaborator := range collaborators {
+		responses[i] = dto.ToSnippetCollaboratorResponse(collaborator)
+	}
+
+	log.Info("retrieved snippet collaborators",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Collaborators retrieved successfully", responses)
+}
+
+// SetSnippetCollaborator invites a user to a snippet or changes their permission
+func (h *SnippetHandler) SetSnippetCollaborator(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	collaboratorID := chi.URLParam(r, "userId")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("collaborator_id", collaboratorID),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.SetSnippetCollaboratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	permission := domain.SnippetPermission(req.Permission)
+	if !permission.IsValid() {
+		log.Warn("invalid collaborator permission", zap.String("permission", req.Permission))
+		api.WriteError(w, http.StatusBadRequest, "Permission must be view or edit")
+		return
+	}
+
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+	if !snippet.IsOwner(userID) {
+		log.Warn("unauthorized collaborator change")
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners can manage its collaborators")
+		return
+	}
+	if snippet.Author != nil && collaboratorID == snippet.Author.ID {
+		api.WriteError(w, http.StatusBadRequest, "The author cannot be added as a collaborator")
+		return
+	}
+
+	if err := h.snippets.SetCollaborator(r.Context(), id, collaboratorID, permission); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("collaborator user not found")
+			api.WriteError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		log.Error("failed to set snippet collaborator",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update collaborator")
 		return
 	}
 
-	log.Info("deleted snippet")
-	api.WriteSuccess(w, http.StatusOK, "Snippet deleted successfully", nil)
+	// Downgrading a collaborator to view permission ends their edit session
+	if h.wsHub != nil {
+		h.wsHub.RevalidateSnippet(id)
+	}
+
+	log.Info("set snippet collaborator",
+		zap.String("permission", req.Permission),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Collaborator updated successfully", nil)
+}
+
+// RemoveSnippetCollaborator revokes a user's access to a snippet. Owners may remove anyone,
+// collaborators may leave on their own.
+func (h *SnippetHandler) RemoveSnippetCollaborator(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	collaboratorID := chi.URLParam(r, "userId")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("collaborator_id", collaboratorID),
+		zap.String("user_id", userID),
+	)
+
+	snippet, ok := h.getVisibleSnippet(w, r, log)
+	if !ok {
+		return
+	}
+	if collaboratorID != userID && !snippet.IsOwner(userID) {
+		log.Warn("unauthorized collaborator removal")
+		api.WriteError(w, http.StatusForbidden, "Only the snippet's owners can manage its collaborators")
+		return
+	}
+
+	if err := h.snippets.RemoveCollaborator(r.Context(), id, collaboratorID); err != nil {
+		if repository.IsNotFound(err) {
+			api.WriteError(w, http.StatusNotFound, "Collaborator not found")
+			return
+		}
+		log.Error("failed to remove snippet collaborator",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to remove collaborator")
+		return
+	}
+
+	if h.wsHub != nil {
+		h.wsHub.RevalidateSnippet(id)
+	}
+
+	log.Info("removed snippet collaborator")
+	api.WriteSuccess(w, http.StatusOK, "Collaborator removed successfully", nil)
 }
 
 // ToggleLikeSnippet toggles the like status of a snippet
@@ -341,6 +883,10 @@ func (h *SnippetHandler) ToggleLikeSnippet(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if _, ok := h.getUnlockedSnippet(w, r, log); !ok {
+		return
+	}
+
 	if err := h.likes.ToggleLike(r.Context(), userID, id, action == constants.ActionLike); err != nil {
 		log.Warn("failed to toggle like",
 			zap.Error(err),
@@ -370,6 +916,11 @@ func (h *SnippetHandler) ToggleLikeSnippet(w http.ResponseWriter, r *http.Reques
 		h.wsHub.BroadcastSnippetStatsUpdate(id, &snippet.Views, &snippet.Likes)
 	}
 
+	if action == constants.ActionLike {
+		h.notifyAuthor(r.Context(), snippet, userID, domain.NotificationLike)
+		h.publishWebhookEvent(r.Context(), domain.WebhookEventSnippetLiked, snippet, userID)
+	}
+
 	log.Info("toggled snippet like",
 		zap.String("action", action),
 	)
@@ -401,6 +952,10 @@ func (h *SnippetHandler) ToggleSaveSnippet(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if _, ok := h.getUnlockedSnippet(w, r, log); !ok {
+		return
+	}
+
 	if err := h.bookmarks.ToggleSave(r.Context(), userID, id, action == constants.ActionSave); err != nil {
 		log.Warn("failed to toggle save",
 			zap.Error(err),
@@ -429,8 +984,54 @@ func (h *SnippetHandler) ToggleSaveSnippet(w http.ResponseWriter, r *http.Reques
 		h.wsHub.BroadcastSnippetStatsUpdate(id, &snippet.Views, &snippet.Likes)
 	}
 
+	if action == constants.ActionSave {
+		h.notifyAuthor(r.Context(), snippet, userID, domain.NotificationSave)
+		h.publishWebhookEvent(r.Context(), domain.WebhookEventSnippetSaved, snippet, userID)
+	}
+
 	log.Info("toggled snippet save",
 		zap.String("action", action),
 	)
 	api.WriteSuccess(w, http.StatusOK, "Snippet save toggled successfully", dto.ToSnippetResponse(snippet))
 }
+
+// notifyAuthor tells the snippet author that another user liked or saved their snippet
+func (h *SnippetHandler) notifyAuthor(ctx context.Context, snippet *domain.Snippet, actorID string, notificationType domain.NotificationType) {
+	if h.notifier == nil || snippet.Author == nil {
+		return
+	}
+	h.notifier.Notify(ctx, snippet.Author.ID, actorID, notificationType, &snippet.ID)
+}
+
+// publishWebhookEvent queues a snippet event for the webhooks of the author and the global webhooks.
+// Events of organization-only, private and burn-after-read snippets are not published.
+func (h *SnippetHandler) publishWebhookEvent(ctx context.Context, event domain.WebhookEvent, snippet *domain.Snippet, actorID string) {
+	if h.webhooks == nil || snippet.Author == nil || !snippet.IsListed() {
+		return
+	}
+	h.webhooks.Publish(ctx, event, snippet.Author.ID, dto.ToWebhookSnippetEventData(snippet, actorID))
+}
+
+// publishFeedItem pushes a new or updated public snippet to the live feeds of the author's followers
+func (h *SnippetHandler) publishFeedItem(ctx context.Context, log *zap.Logger, snippet *domain.Snippet, itemType string) {
+	if h.wsHub == nil || !snippet.IsListed() {
+		return
+	}
+
+	followerIDs, err := h.follows.GetFollowerIDs(ctx, snippet.Author.ID)
+	if err != nil {
+		log.Warn("failed to get followers for feed item",
+			zap.Error(err),
+		)
+		return
+	}
+
+	h.wsHub.BroadcastFeedItem(followerIDs, ws.FeedItemData{
+		Type:           itemType,
+		SnippetID:      snippet.ID,
+		AuthorID:       snippet.Author.ID,
+		AuthorUsername: snippet.Author.Username,
+		Title:          snippet.Title,
+		Language:       snippet.Language,
+	})
+}
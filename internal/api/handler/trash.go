@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+	ws "mitsimi.dev/codeShare/internal/websocket"
+)
+
+// TrashHandler handles the deleted snippets of the authenticated user until they are purged
+type TrashHandler struct {
+	snippets  repository.SnippetRepository
+	wsHub     *ws.Hub
+	retention time.Duration
+	logger    *zap.Logger
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(snippets repository.SnippetRepository, wsHub *ws.Hub, retention time.Duration) *TrashHandler {
+	return &TrashHandler{
+		snippets:  snippets,
+		wsHub:     wsHub,
+		retention: retention,
+		logger:    logger.Log,
+	}
+}
+
+// ===== Helper methods for common logic =====
+
+// getOwnedTrashedSnippet loads the snippet of the URL from the trash, hiding snippets the
+// user does not own
+func (h *TrashHandler) getOwnedTrashedSnippet(w http.ResponseWriter, r *http.Request, log *zap.Logger) (*domain.Snippet, bool) {
+	userID := api.GetUserID(r)
+	snippet, err := h.snippets.GetTrashedByID(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("trashed snippet not found")
+			api.WriteError(w, http.StatusNotFound, "Snippet not found in trash")
+			return nil, false
+		}
+		log.Error("failed to get trashed snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippet")
+		return nil, false
+	}
+
+	if !snippet.IsOwner(userID) {
+		log.Warn("unauthorized access to trashed snippet")
+		api.WriteError(w, http.StatusNotFound, "Snippet not found in trash")
+		return nil, false
+	}
+	return snippet, true
+}
+
+// ===== Handlers =====
+
+// GetMyTrash returns the snippets in the trash that the authenticated user owns
+func (h *TrashHandler) GetMyTrash(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	snippets, err := h.snippets.GetTrash(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get trash",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve trash")
+		return
+	}
+
+	responses := make([]dto.TrashedSnippetResponse, len(snippets))
+	for i, snippet := range snippets {
+		responses[i] = dto.ToTrashedSnippetResponse(snippet, h.retention)
+	}
+
+	log.Info("retrieved trash",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Trash retrieved successfully", responses)
+}
+
+// RestoreSnippet moves a snippet out of the trash
+func (h *TrashHandler) RestoreSnippet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	if _, ok := h.getOwnedTrashedSnippet(w, r, log); !ok {
+		return
+	}
+
+	if err := h.snippets.Restore(r.Context(), id); err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("snippet was already restored or purged")
+			api.WriteError(w, http.StatusNotFound, "Snippet not found in trash")
+			return
+		}
+		log.Error("failed to restore snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to restore snippet")
+		return
+	}
+
+	snippet, err := h.snippets.GetByID(r.Context(), id, userID)
+	if err != nil {
+		log.Error("failed to get restored snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve snippet")
+		return
+	}
+
+	response := dto.ToSnippetResponse(snippet)
+	if h.wsHub != nil {
+		h.wsHub.BroadcastSnippetCreated(response)
+	}
+
+	log.Info("restored snippet")
+	api.WriteSuccess(w, http.StatusOK, "Snippet restored successfully", response)
+}
+
+// DeleteTrashedSnippet permanently deletes a snippet in the trash
+func (h *TrashHandler) DeleteTrashedSnippet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("snippet_id", id),
+		zap.String("user_id", userID),
+	)
+
+	if _, ok := h.getOwnedTrashedSnippet(w, r, log); !ok {
+		return
+	}
+
+	if err := h.snippets.Delete(r.Context(), id); err != nil {
+		log.Error("failed to delete trashed snippet",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to delete snippet")
+		return
+	}
+
+	log.Info("permanently deleted snippet")
+	api.WriteSuccess(w, http.StatusOK, "Snippet permanently deleted successfully", nil)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/services"
+)
+
+// TrendingHandler handles trending snippet HTTP requests
+type TrendingHandler struct {
+	trending *services.TrendingService
+	logger   *zap.Logger
+}
+
+// NewTrendingHandler creates a new trending handler
+func NewTrendingHandler(trending *services.TrendingService) *TrendingHandler {
+	return &TrendingHandler{
+		trending: trending,
+		logger:   logger.Log,
+	}
+}
+
+// GetTrendingSnippets returns the highest ranked snippets of a window, optionally filtered by language
+func (h *TrendingHandler) GetTrendingSnippets(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	query := r.URL.Query()
+	window, err := services.ParseTrendingWindow(query.Get("window"))
+	if err != nil {
+		log.Warn("invalid trending window", zap.String("window", query.Get("window")))
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	language := strings.TrimSpace(query.Get("language"))
+
+	snippets, err := h.trending.GetTrending(r.Context(), window, language, userID)
+	if err != nil {
+		log.Error("failed to get trending snippets",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve trending snippets")
+		return
+	}
+
+	responses := make([]dto.SnippetResponse, len(snippets))
+	for i, snippet := range snippets {
+		responses[i] = dto.ToSnippetResponse(snippet)
+	}
+
+	log.Info("retrieved trending snippets",
+		zap.String("window", string(window)),
+		zap.String("language", language),
+		zap.Int("count", len(responses)),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "Trending snippets retrieved successfully", responses)
+}
@@ -10,9 +10,11 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"mitsimi.dev/codeShare/internal/api"
 	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/constants"
 	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/logger"
 	"mitsimi.dev/codeShare/internal/repository"
+	"mitsimi.dev/codeShare/internal/services"
 )
 
 type UserHandler struct {
@@ -20,18 +22,24 @@ type UserHandler struct {
 	snippets  repository.SnippetRepository
 	likes     repository.LikeRepository
 	bookmarks repository.BookmarkRepository
+	follows   repository.FollowRepository
+	notifier  *services.Notifier
 	logger    *zap.Logger
 }
 
 func NewUserHandler(users repository.UserRepository,
 	snippets repository.SnippetRepository,
 	likes repository.LikeRepository,
-	bookmarks repository.BookmarkRepository) *UserHandler {
+	bookmarks repository.BookmarkRepository,
+	follows repository.FollowRepository,
+	notifier *services.Notifier) *UserHandler {
 	return &UserHandler{
 		users:     users,
 		snippets:  snippets,
 		likes:     likes,
 		bookmarks: bookmarks,
+		follows:   follows,
+		notifier:  notifier,
 		logger:    logger.Log,
 	}
 }
@@ -55,6 +63,17 @@ func (h *UserHandler) getUserByID(w http.ResponseWriter, r *http.Request, userID
 		api.WriteError(w, http.StatusNotFound, "User not found")
 		return
 	}
+
+	counts, err := h.follows.GetCounts(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get follow counts",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve user")
+		return
+	}
+	user.FollowCounts = counts
+
 	log.Info("retrieved user",
 		zap.String("username", user.Username),
 		zap.String("email", user.Email),
@@ -125,7 +144,8 @@ func (h *UserHandler) getUserLikedSnippetsByID(w http.ResponseWriter, r *http.Re
 	api.WriteSuccess(w, http.StatusOK, "User liked snippets retrieved successfully", responses)
 }
 
-// getUserSavedSnippetsByID is a helper method that retrieves saved snippets by user ID
+// getUserSavedSnippetsByID is a helper method that retrieves saved snippets by user ID,
+// filtered by the folder and search query parameters
 func (h *UserHandler) getUserSavedSnippetsByID(w http.ResponseWriter, r *http.Request, userID string) {
 	requestID := middleware.GetReqID(r.Context())
 	log := h.logger.With(
@@ -133,7 +153,7 @@ func (h *UserHandler) getUserSavedSnippetsByID(w http.ResponseWriter, r *http.Re
 		zap.String("user_id", userID),
 	)
 
-	snippets, err := h.bookmarks.GetSavedSnippets(r.Context(), userID)
+	snippets, err := h.bookmarks.GetSavedSnippets(r.Context(), userID, parseBookmarkFilter(r))
 	if err != nil {
 		log.Warn("failed to get user saved snippets",
 			zap.Error(err),
@@ -143,9 +163,9 @@ func (h *UserHandler) getUserSavedSnippetsByID(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	responses := make([]dto.SnippetResponse, len(snippets))
+	responses := make([]dto.SavedSnippetResponse, len(snippets))
 	for i, snippet := range snippets {
-		responses[i] = dto.ToSnippetResponse(snippet)
+		responses[i] = dto.ToSavedSnippetResponse(snippet)
 	}
 
 	log.Info("retrieved user saved snippets",
@@ -250,6 +270,64 @@ func (h *UserHandler) updateUserAvatarByID(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// getUserFollowersByID is a helper method that retrieves the followers of a user
+func (h *UserHandler) getUserFollowersByID(w http.ResponseWriter, r *http.Request, userID string) {
+	requestID := middleware.GetReqID(r.Context())
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("user_id", userID),
+	)
+
+	users, err := h.follows.GetFollowers(r.Context(), userID)
+	if err != nil {
+		log.Warn("failed to get user followers",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve followers")
+		return
+	}
+
+	responses := make([]dto.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = dto.ToUserResponse(user)
+	}
+
+	log.Info("retrieved user followers",
+		zap.Int("count", len(users)),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "User followers retrieved successfully", responses)
+}
+
+// getUserFollowingByID is a helper method that retrieves the authors a user follows
+func (h *UserHandler) getUserFollowingByID(w http.ResponseWriter, r *http.Request, userID string) {
+	requestID := middleware.GetReqID(r.Context())
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("user_id", userID),
+	)
+
+	users, err := h.follows.GetFollowing(r.Context(), userID)
+	if err != nil {
+		log.Warn("failed to get followed users",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve followed users")
+		return
+	}
+
+	responses := make([]dto.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = dto.ToUserResponse(user)
+	}
+
+	log.Info("retrieved followed users",
+		zap.Int("count", len(users)),
+	)
+
+	api.WriteSuccess(w, http.StatusOK, "Followed users retrieved successfully", responses)
+}
+
 // ===== Refactored route handlers =====
 
 // GetUser returns a user by ID
@@ -336,3 +414,90 @@ func (h *UserHandler) UpdateMyAvatar(w http.ResponseWriter, r *http.Request) {
 	userID := api.GetUserID(r)
 	h.updateUserAvatarByID(w, r, userID)
 }
+
+// GetUserFollowers returns all followers of a user
+func (h *UserHandler) GetUserFollowers(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	h.getUserFollowersByID(w, r, userID)
+}
+
+// GetMyFollowers returns all followers of the authenticated user
+func (h *UserHandler) GetMyFollowers(w http.ResponseWriter, r *http.Request) {
+	userID := api.GetUserID(r)
+	h.getUserFollowersByID(w, r, userID)
+}
+
+// GetUserFollowing returns all authors a user follows
+func (h *UserHandler) GetUserFollowing(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	h.getUserFollowingByID(w, r, userID)
+}
+
+// GetMyFollowing returns all authors the authenticated user follows
+func (h *UserHandler) GetMyFollowing(w http.ResponseWriter, r *http.Request) {
+	userID := api.GetUserID(r)
+	h.getUserFollowingByID(w, r, userID)
+}
+
+// ToggleFollowUser follows or unfollows a user
+func (h *UserHandler) ToggleFollowUser(w http.ResponseWriter, r *http.Request) {
+	followeeID := chi.URLParam(r, "id")
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("followee_id", followeeID),
+		zap.String("user_id", userID),
+	)
+
+	// Parse the action from query parameters
+	action := r.URL.Query().Get(constants.ActionQueryParam)
+	if action == "" {
+		action = constants.ActionFollow
+	}
+
+	if action != constants.ActionFollow && action != constants.ActionUnfollow {
+		log.Warn("invalid action",
+			zap.String("action", action),
+		)
+		api.WriteError(w, http.StatusBadRequest, "Invalid action")
+		return
+	}
+
+	if followeeID == userID {
+		log.Warn("self follow attempt")
+		api.WriteError(w, http.StatusBadRequest, "You cannot follow yourself")
+		return
+	}
+
+	var err error
+	if action == constants.ActionFollow {
+		err = h.follows.Follow(r.Context(), userID, followeeID)
+	} else {
+		err = h.follows.Unfollow(r.Context(), userID, followeeID)
+	}
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("user to follow not found")
+			api.WriteError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		log.Error("failed to toggle follow",
+			zap.Error(err),
+			zap.String("action", action),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update follow status")
+		return
+	}
+
+	if action == constants.ActionFollow && h.notifier != nil {
+		h.notifier.Notify(r.Context(), followeeID, userID, domain.NotificationFollow, nil)
+	}
+
+	log.Info("toggled user follow",
+		zap.String("action", action),
+	)
+
+	// Respond with the followee profile including the new counts
+	h.getUserByID(w, r, followeeID)
+}
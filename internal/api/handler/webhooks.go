@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/auth"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+	"mitsimi.dev/codeShare/internal/services"
+)
+
+const (
+	// defaultWebhookDeliveryLimit is the number of deliveries returned when no limit is given
+	defaultWebhookDeliveryLimit = 50
+
+	// maxWebhookDeliveryLimit is the maximum number of deliveries per request
+	maxWebhookDeliveryLimit = 100
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	webhooks   repository.WebhookRepository
+	dispatcher *services.WebhookDispatcher
+	logger     *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhooks repository.WebhookRepository, dispatcher *services.WebhookDispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks:   webhooks,
+		dispatcher: dispatcher,
+		logger:     logger.Log,
+	}
+}
+
+// ===== Helper methods for common logic =====
+
+// getOwnedWebhook loads the webhook of the URL and checks that the user may manage it
+func (h *WebhookHandler) getOwnedWebhook(w http.ResponseWriter, r *http.Request, log *zap.Logger) (*domain.Webhook, bool) {
+	webhookID := chi.URLParam(r, "id")
+	userID := api.GetUserID(r)
+
+	webhook, err := h.webhooks.GetByID(r.Context(), webhookID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			log.Warn("webhook not found")
+			api.WriteError(w, http.StatusNotFound, "Webhook not found")
+			return nil, false
+		}
+		log.Error("failed to get webhook",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve webhook")
+		return nil, false
+	}
+
+	// Hide the existence of other users' webhooks
+	if webhook.OwnerID != userID && !auth.IsAdmin(userID) {
+		log.Warn("unauthorized webhook access",
+			zap.String("owner_id", webhook.OwnerID),
+		)
+		api.WriteError(w, http.StatusNotFound, "Webhook not found")
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+// parseWebhookEvents validates and deduplicates subscribed event types
+func parseWebhookEvents(values []string) ([]domain.WebhookEvent, string, bool) {
+	seen := make(map[domain.WebhookEvent]bool, len(values))
+	events := make([]domain.WebhookEvent, 0, len(values))
+	for _, value := range values {
+		event := domain.WebhookEvent(value)
+		if !event.IsValid() {
+			return nil, value, false
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	return events, "", true
+}
+
+// ===== Handlers =====
+
+// GetWebhooks returns the webhooks of the authenticated user
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	webhooks, err := h.webhooks.GetByOwner(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get webhooks",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve webhooks")
+		return
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = dto.ToWebhookResponse(webhook)
+	}
+
+	log.Info("retrieved webhooks",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Webhooks retrieved successfully", responses)
+}
+
+// CreateWebhook subscribes a URL to snippet events
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := services.ValidateWebhookURL(req.URL); err != nil {
+		log.Warn("invalid webhook URL", zap.String("url", req.URL))
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, invalid, ok := parseWebhookEvents(req.Events)
+	if !ok {
+		log.Warn("unknown webhook event", zap.String("event", invalid))
+		api.WriteError(w, http.StatusBadRequest, "Unknown webhook event: "+invalid)
+		return
+	}
+
+	if req.Global && !auth.IsAdmin(userID) {
+		log.Warn("non-admin tried to create a global webhook")
+		api.WriteError(w, http.StatusForbidden, "Only admins can create global webhooks")
+		return
+	}
+
+	secret, err := services.GenerateWebhookSecret()
+	if err != nil {
+		log.Error("failed to generate webhook secret",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	webhook, err := h.webhooks.Create(r.Context(), &domain.Webhook{
+		ID:      uuid.New().String(),
+		OwnerID: userID,
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  events,
+		Global:  req.Global,
+	})
+	if err != nil {
+		log.Error("failed to create webhook",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	// The secret is only ever returned once
+	response := dto.ToWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+
+	log.Info("created webhook",
+		zap.String("webhook_id", webhook.ID),
+		zap.Bool("global", webhook.Global),
+	)
+	api.WriteSuccess(w, http.StatusCreated, "Webhook created successfully", response)
+}
+
+// GetWebhook returns a single webhook
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("webhook_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	webhook, ok := h.getOwnedWebhook(w, r, log)
+	if !ok {
+		return
+	}
+
+	log.Info("retrieved webhook")
+	api.WriteSuccess(w, http.StatusOK, "Webhook retrieved successfully", dto.ToWebhookResponse(webhook))
+}
+
+// UpdateWebhook changes the URL, events or active state of a webhook
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("webhook_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	var req dto.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	webhook, ok := h.getOwnedWebhook(w, r, log)
+	if !ok {
+		return
+	}
+
+	if req.URL != nil {
+		if err := services.ValidateWebhookURL(*req.URL); err != nil {
+			log.Warn("invalid webhook URL", zap.String("url", *req.URL))
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, invalid, ok := parseWebhookEvents(*req.Events)
+		if !ok {
+			log.Warn("unknown webhook event", zap.String("event", invalid))
+			api.WriteError(w, http.StatusBadRequest, "Unknown webhook event: "+invalid)
+			return
+		}
+		webhook.Events = events
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	updated, err := h.webhooks.Update(r.Context(), webhook)
+	if err != nil {
+		log.Error("failed to update webhook",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to update webhook")
+		return
+	}
+
+	log.Info("updated webhook")
+	api.WriteSuccess(w, http.StatusOK, "Webhook updated successfully", dto.ToWebhookResponse(updated))
+}
+
+// DeleteWebhook removes a webhook together with its delivery log
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("webhook_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	webhook, ok := h.getOwnedWebhook(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Delete(r.Context(), webhook.ID); err != nil {
+		log.Error("failed to delete webhook",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+
+	log.Info("deleted webhook")
+	api.WriteSuccess(w, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+// GetWebhookDeliveries returns the most recent deliveries of a webhook
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("webhook_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	limit := defaultWebhookDeliveryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxWebhookDeliveryLimit {
+			log.Warn("invalid delivery limit", zap.String("limit", value))
+			api.WriteError(w, http.StatusBadRequest, "limit must be a number between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	webhook, ok := h.getOwnedWebhook(w, r, log)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.webhooks.GetDeliveries(r.Context(), webhook.ID, limit)
+	if err != nil {
+		log.Error("failed to get webhook deliveries",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = dto.ToWebhookDeliveryResponse(delivery)
+	}
+
+	log.Info("retrieved webhook deliveries",
+		zap.Int("count", len(responses)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Webhook deliveries retrieved successfully", responses)
+}
+
+// SendTestEvent sends a ping event to a webhook and returns the delivery result
+func (h *WebhookHandler) SendTestEvent(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetReqID(r.Context())
+	userID := api.GetUserID(r)
+	log := h.logger.With(
+		zap.String("request_id", requestID),
+		zap.String("webhook_id", chi.URLParam(r, "id")),
+		zap.String("user_id", userID),
+	)
+
+	webhook, ok := h.getOwnedWebhook(w, r, log)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.dispatcher.SendTest(r.Context(), webhook)
+	if err != nil {
+		log.Error("failed to send test event",
+			zap.Error(err),
+		)
+		api.WriteError(w, http.StatusInternalServerError, "Failed to send test event")
+		return
+	}
+
+	log.Info("sent webhook test event",
+		zap.String("delivery_id", delivery.ID),
+		zap.String("status", string(delivery.Status)),
+	)
+	api.WriteSuccess(w, http.StatusOK, "Test event sent", dto.ToWebhookDeliveryResponse(delivery))
+}
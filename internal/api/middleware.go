@@ -17,7 +17,18 @@ import (
 
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey     contextKey = "user_id"
+	credentialKey contextKey = "credential"
+)
+
+// Credential describes how a request was authenticated, so long-lived connections
+// can check it again later
+type Credential struct {
+	UserID    string
+	SessionID string // Set for session cookie authentication
+	ExpiresAt int64  // Unix time the session or token expires
+}
 
 // AuthMiddleware is a middleware that checks for valid authentication
 type AuthMiddleware struct {
@@ -42,29 +53,34 @@ func (m *AuthMiddleware) TryAttachUserID(next http.Handler) http.Handler {
 		requestID := middleware.GetReqID(r.Context())
 		log := m.logger.With(zap.String("request_id", requestID))
 
-		var userID string
+		var credential Credential
 
 		// Try to get session from cookie first
 		if cookie, err := r.Cookie("session"); err == nil {
 			if session, err := m.sessions.GetByToken(r.Context(), cookie.Value); err == nil {
 				if session.ExpiresAt > time.Now().Unix() {
-					userID = session.UserID
+					credential = Credential{UserID: session.UserID, SessionID: session.ID, ExpiresAt: session.ExpiresAt}
 				}
 			}
 		}
 
 		// If no valid session, try JWT token
-		if userID == "" {
+		if credential.UserID == "" {
 			authHeader := r.Header.Get("Authorization")
 			if token, ok := strings.CutPrefix(authHeader, "Bearer "); ok {
 				if user, err := auth.ValidateToken(token, m.secretKey); err == nil {
-					userID = user.UserID
+					credential = Credential{UserID: user.UserID}
+					if expiresAt, err := user.GetExpirationTime(); err == nil && expiresAt != nil {
+						credential.ExpiresAt = expiresAt.Unix()
+					}
 				}
 			}
 		}
+		userID := credential.UserID
 
 		// Add user ID to context (even if empty)
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		ctx = context.WithValue(ctx, credentialKey, credential)
 		log.Info("TryAuth completed", zap.String("user_id", userID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -106,6 +122,12 @@ func (m *AuthMiddleware) RequireSelfOrAdmin(next http.Handler) http.Handler {
 	})
 }
 
+// GetCredential gets the credential that authenticated the request, empty for anonymous requests
+func GetCredential(r *http.Request) Credential {
+	credential, _ := r.Context().Value(credentialKey).(Credential)
+	return credential
+}
+
 // GetUserID gets the user ID from the context
 func GetUserID(r *http.Request) string {
 	userID, _ := r.Context().Value(userIDKey).(string)
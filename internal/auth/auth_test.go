@@ -74,3 +74,83 @@ func TestGenerateRandomToken(t *testing.T) {
 
 	assert.NotEqual(t, token1, token2)
 }
+
+func TestTicketGenerationAndValidation(t *testing.T) {
+	secretKey := "test-secret-key"
+	userID := "test-user-id"
+
+	t.Run("valid ticket", func(t *testing.T) {
+		ticket, err := GenerateTicket(userID, "session-1", 1234, secretKey)
+		assert.NoError(t, err)
+
+		claims, err := ValidateTicket(ticket.Token, secretKey)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, "session-1", claims.SessionID)
+		assert.Equal(t, int64(1234), claims.ExpiresAt)
+		assert.NotEmpty(t, claims.ID)
+	})
+
+	t.Run("tickets and access tokens are not interchangeable", func(t *testing.T) {
+		ticket, err := GenerateTicket(userID, "", 0, secretKey)
+		assert.NoError(t, err)
+		_, err = ValidateToken(ticket.Token, secretKey)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		token, err := GenerateToken(userID, secretKey, false)
+		assert.NoError(t, err)
+		_, err = ValidateTicket(token.Token, secretKey)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired ticket", func(t *testing.T) {
+		originalTicketExpiration := TicketExpiration
+		TicketExpiration = -1 * time.Second
+		defer func() { TicketExpiration = originalTicketExpiration }()
+
+		ticket, err := GenerateTicket(userID, "", 0, secretKey)
+		assert.NoError(t, err)
+		_, err = ValidateTicket(ticket.Token, secretKey)
+		assert.ErrorIs(t, err, ErrExpiredToken)
+	})
+}
+
+func TestSnippetGrantGenerationAndValidation(t *testing.T) {
+	secretKey := "test-secret-key"
+	snippetID := "snippet-1"
+	passwordHash := "$2a$14$hash"
+
+	t.Run("valid grant", func(t *testing.T) {
+		grant, err := GenerateSnippetGrant(snippetID, passwordHash, secretKey)
+		assert.NoError(t, err)
+		assert.NoError(t, ValidateSnippetGrant(grant.Token, snippetID, passwordHash, secretKey))
+	})
+
+	t.Run("grant for another snippet", func(t *testing.T) {
+		grant, err := GenerateSnippetGrant(snippetID, passwordHash, secretKey)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, ValidateSnippetGrant(grant.Token, "snippet-2", passwordHash, secretKey), ErrInvalidToken)
+	})
+
+	t.Run("changing the password revokes grants", func(t *testing.T) {
+		grant, err := GenerateSnippetGrant(snippetID, passwordHash, secretKey)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, ValidateSnippetGrant(grant.Token, snippetID, "$2a$14$other", secretKey), ErrInvalidToken)
+	})
+
+	t.Run("grants and tickets are not interchangeable", func(t *testing.T) {
+		ticket, err := GenerateTicket(snippetID, "", 0, secretKey)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, ValidateSnippetGrant(ticket.Token, snippetID, passwordHash, secretKey), ErrInvalidToken)
+	})
+
+	t.Run("expired grant", func(t *testing.T) {
+		originalExpiration := SnippetGrantExpiration
+		SnippetGrantExpiration = -1 * time.Second
+		defer func() { SnippetGrantExpiration = originalExpiration }()
+
+		grant, err := GenerateSnippetGrant(snippetID, passwordHash, secretKey)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, ValidateSnippetGrant(grant.Token, snippetID, passwordHash, secretKey), ErrExpiredToken)
+	})
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SnippetGrantExpiration is how long an unlocked password-protected snippet stays readable
+var SnippetGrantExpiration = time.Hour
+
+const snippetGrantAudience = "snippet"
+
+// SnippetGrantClaims represents the claims of a snippet grant, handed out after the
+// password of a protected snippet was entered
+type SnippetGrantClaims struct {
+	SnippetID string `json:"sub"`
+	Password  string `json:"pwd"` // Fingerprint of the password hash, so changing the password revokes grants
+	jwt.RegisteredClaims
+}
+
+// snippetGrantKey derives the signing key of snippet grants, so grants cannot be used
+// in place of access tokens or tickets
+func snippetGrantKey(secretKey string) []byte {
+	return []byte(secretKey + "\x00snippet-grant")
+}
+
+// passwordFingerprint shortens a password hash to a value that is safe to put in a token
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// GenerateSnippetGrant generates a grant to read a password-protected snippet
+func GenerateSnippetGrant(snippetID, passwordHash, secretKey string) (TokenResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(SnippetGrantExpiration)
+
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return TokenResponse{}, err
+	}
+
+	claims := SnippetGrantClaims{
+		SnippetID: snippetID,
+		Password:  passwordFingerprint(passwordHash),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{snippetGrantAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        hex.EncodeToString(randomBytes),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(snippetGrantKey(secretKey))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		Token:     tokenString,
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
+
+// ValidateSnippetGrant checks that a grant was issued for the snippet and its current password
+func ValidateSnippetGrant(grant, snippetID, passwordHash, secretKey string) error {
+	var claims SnippetGrantClaims
+	token, err := jwt.ParseWithClaims(grant, &claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return snippetGrantKey(secretKey), nil
+	}, jwt.WithAudience(snippetGrantAudience))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return ErrExpiredToken
+		}
+		return ErrInvalidToken
+	}
+	if !token.Valid || claims.SnippetID != snippetID || claims.Password != passwordFingerprint(passwordHash) {
+		return ErrInvalidToken
+	}
+	return nil
+}
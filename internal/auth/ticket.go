@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TicketExpiration is how long a websocket ticket can be redeemed
+var TicketExpiration = 30 * time.Second
+
+const ticketAudience = "ws"
+
+// TicketClaims represents the claims of a websocket ticket, a short-lived token for
+// clients that cannot send cookies or headers with the connection request
+type TicketClaims struct {
+	UserID    string `json:"sub"`
+	SessionID string `json:"sid,omitempty"` // Session the ticket was issued for, checked while the connection is open
+	ExpiresAt int64  `json:"cex,omitempty"` // Unix time the credential the ticket was issued for expires
+	jwt.RegisteredClaims
+}
+
+// ticketKey derives the signing key of tickets, so tickets and access tokens
+// cannot be used in place of each other
+func ticketKey(secretKey string) []byte {
+	return []byte(secretKey + "\x00ws-ticket")
+}
+
+// GenerateTicket generates a websocket ticket for the credential of a request
+func GenerateTicket(userID, sessionID string, expiresAt int64, secretKey string) (TokenResponse, error) {
+	now := time.Now()
+	ticketExpiresAt := now.Add(TicketExpiration)
+
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return TokenResponse{}, err
+	}
+
+	claims := TicketClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		ExpiresAt: expiresAt,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{ticketAudience},
+			ExpiresAt: jwt.NewNumericDate(ticketExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        hex.EncodeToString(randomBytes),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(ticketKey(secretKey))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		Token:     tokenString,
+		ExpiresAt: ticketExpiresAt.Unix(),
+	}, nil
+}
+
+// ValidateTicket validates a websocket ticket and returns its claims
+func ValidateTicket(ticket, secretKey string) (TicketClaims, error) {
+	var claims TicketClaims
+	token, err := jwt.ParseWithClaims(ticket, &claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return ticketKey(secretKey), nil
+	}, jwt.WithAudience(ticketAudience))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return TicketClaims{}, ErrExpiredToken
+		}
+		return TicketClaims{}, ErrInvalidToken
+	}
+	if !token.Valid || claims.UserID == "" || claims.ID == "" {
+		return TicketClaims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 
@@ -17,6 +19,31 @@ type Config struct {
 	JWTSecret          string   `env:"JWT_SECRET"`
 	ServeStatic        bool     `env:"SERVE_STATIC" env-default:"false"`
 	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" env-default:"http://localhost:3000" env-separator:","`
+	TrustedProxies     []string `env:"TRUSTED_PROXIES" env-separator:","`                  // CIDRs or IPs allowed to set forwarding headers
+	TrustedProxyHeader string   `env:"TRUSTED_PROXY_HEADER" env-default:"X-Forwarded-For"` // The one forwarding header the proxies set: X-Forwarded-For, Forwarded or X-Real-IP
+
+	// View tracking privacy
+	ViewHashSecret     string `env:"VIEW_HASH_SECRET"`
+	ViewStoreIP        bool   `env:"VIEW_STORE_IP" env-default:"true"`
+	ViewIPv4PrefixBits int    `env:"VIEW_IPV4_PREFIX_BITS" env-default:"24"`
+	ViewIPv6PrefixBits int    `env:"VIEW_IPV6_PREFIX_BITS" env-default:"48"`
+
+	// View tracking bot filter
+	BotUserAgents []string `env:"BOT_USER_AGENTS" env-separator:","` // Extra user agent fragments never counted as views
+
+	// Live updates across instances
+	WSBrokerURL     string `env:"WS_BROKER_URL"` // nats://host:port or tls://host:port, empty keeps live updates on this instance
+	WSBrokerSubject string `env:"WS_BROKER_SUBJECT" env-default:"codeshare.live"`
+
+	// Live update connection limits
+	WSMaxConnectionsPerIP int `env:"WS_MAX_CONNECTIONS_PER_IP" env-default:"20"` // Open WebSocket and SSE connections per client IP, 0 for no limit
+
+	// Snippet password attempts
+	UnlockMaxFailures   int `env:"UNLOCK_MAX_FAILURES" env-default:"5"`    // Wrong passwords per client IP and snippet before it is locked out
+	UnlockWindowMinutes int `env:"UNLOCK_WINDOW_MINUTES" env-default:"15"` // Minutes the wrong passwords are counted in, and the first lockout
+
+	// Deleted snippets
+	TrashRetentionDays int `env:"TRASH_RETENTION_DAYS" env-default:"30"` // Days deleted snippets can be restored before they are purged
 }
 
 // New creates a new configuration
@@ -42,5 +69,16 @@ func New() (*Config, error) {
 		log.Printf("WARNING: Using default JWT secret in development environment. This is not secure for production use.")
 	}
 
+	if cfg.UnlockMaxFailures < 1 || cfg.UnlockWindowMinutes < 1 {
+		return nil, fmt.Errorf("UNLOCK_MAX_FAILURES and UNLOCK_WINDOW_MINUTES must be at least 1")
+	}
+
+	if cfg.ViewHashSecret == "" {
+		// Derive a separate key so the JWT signing key is never used for viewer hashes
+		sum := sha256.Sum256([]byte(cfg.JWTSecret + "\x00view-hash"))
+		cfg.ViewHashSecret = hex.EncodeToString(sum[:])
+		log.Printf("WARNING: VIEW_HASH_SECRET is not set, deriving it from JWT_SECRET. Set VIEW_HASH_SECRET so rotating JWT_SECRET does not change every viewer identifier.")
+	}
+
 	return cfg, nil
 }
@@ -7,6 +7,12 @@ const (
 
 	// CookiePath is the default path for cookies
 	CookiePath = "/"
+
+	// SnippetGrantHeader carries the grant to read a password-protected snippet
+	SnippetGrantHeader = "X-Snippet-Grant"
+
+	// SnippetGrantCookiePrefix prefixes the snippet ID in the name of grant cookies
+	SnippetGrantCookiePrefix = "snippet_grant_"
 )
 
 // Query parameter constants
@@ -28,6 +34,24 @@ const (
 
 	// ActionUnsave represents the unsave action
 	ActionUnsave = "unsave"
+
+	// ActionFollow represents the follow action
+	ActionFollow = "follow"
+
+	// ActionUnfollow represents the unfollow action
+	ActionUnfollow = "unfollow"
+
+	// ActionMove represents moving saved snippets to another folder
+	ActionMove = "move"
+)
+
+// Feed item types
+const (
+	// FeedItemCreated is a new snippet of a followed author
+	FeedItemCreated = "created"
+
+	// FeedItemUpdated is an edited snippet of a followed author
+	FeedItemUpdated = "updated"
 )
 
 // Default values
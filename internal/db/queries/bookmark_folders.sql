@@ -0,0 +1,31 @@
+-- name: CreateBookmarkFolder :exec
+INSERT INTO bookmark_folders (
+    id,
+    user_id,
+    name
+) VALUES (
+    ?, ?, ?
+);
+
+-- name: GetBookmarkFolder :one
+SELECT f.*,
+    (SELECT COUNT(*) FROM user_saves us JOIN snippets s ON s.id = us.snippet_id WHERE us.folder_id = f.id AND s.deleted_at IS NULL) AS item_count
+FROM bookmark_folders f
+WHERE f.id = ? AND f.user_id = ?;
+
+-- name: GetBookmarkFolders :many
+SELECT f.*,
+    (SELECT COUNT(*) FROM user_saves us JOIN snippets s ON s.id = us.snippet_id WHERE us.folder_id = f.id AND s.deleted_at IS NULL) AS item_count
+FROM bookmark_folders f
+WHERE f.user_id = ?
+ORDER BY f.name COLLATE NOCASE, f.id;
+
+-- name: RenameBookmarkFolder :execrows
+UPDATE bookmark_folders
+SET name = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteBookmarkFolder :execrows
+DELETE FROM bookmark_folders
+WHERE id = ? AND user_id = ?;
@@ -0,0 +1,163 @@
+-- name: CreateCollection :exec
+INSERT INTO collections (
+    id,
+    owner_id,
+    name,
+    description,
+    visibility
+) VALUES (
+    ?, ?, ?, ?, ?
+);
+
+-- name: GetCollection :one
+SELECT
+    c.*,
+    u.username AS owner_username,
+    u.email AS owner_email,
+    u.avatar AS owner_avatar,
+    (SELECT COUNT(*) FROM collection_items ci JOIN snippets s ON s.id = ci.snippet_id WHERE ci.collection_id = c.id AND s.deleted_at IS NULL) AS item_count,
+    (SELECT COUNT(*) FROM collection_follows cf WHERE cf.collection_id = c.id) AS follower_count,
+    EXISTS (SELECT 1 FROM collection_follows cf WHERE cf.collection_id = c.id AND cf.user_id = @user_id) AS is_following
+FROM collections c
+JOIN users u ON u.id = c.owner_id
+WHERE c.id = @id;
+
+-- name: GetUserCollections :many
+SELECT
+    c.*,
+    u.username AS owner_username,
+    u.email AS owner_email,
+    u.avatar AS owner_avatar,
+    (SELECT COUNT(*) FROM collection_items ci JOIN snippets s ON s.id = ci.snippet_id WHERE ci.collection_id = c.id AND s.deleted_at IS NULL) AS item_count,
+    (SELECT COUNT(*) FROM collection_follows cf WHERE cf.collection_id = c.id) AS follower_count,
+    EXISTS (SELECT 1 FROM collection_follows cf WHERE cf.collection_id = c.id AND cf.user_id = @user_id) AS is_following
+FROM collections c
+JOIN users u ON u.id = c.owner_id
+WHERE c.owner_id = @user_id
+OR c.id IN (SELECT cc.collection_id FROM collection_collaborators cc WHERE cc.user_id = @user_id)
+ORDER BY c.updated_at DESC;
+
+-- name: UpdateCollection :execrows
+UPDATE collections
+SET name = ?,
+    description = ?,
+    visibility = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: TouchCollection :exec
+UPDATE collections
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteCollection :execrows
+DELETE FROM collections
+WHERE id = ?;
+
+-- name: DeleteCollectionItems :exec
+DELETE FROM collection_items
+WHERE collection_id = ?;
+
+-- name: DeleteCollectionCollaborators :exec
+DELETE FROM collection_collaborators
+WHERE collection_id = ?;
+
+-- name: DeleteCollectionFollows :exec
+DELETE FROM collection_follows
+WHERE collection_id = ?;
+
+-- name: DeleteCollectionNotifications :exec
+DELETE FROM notifications
+WHERE collection_id = ?;
+
+-- name: GetCollectionItems :many
+SELECT
+    s.*,
+    CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
+    CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
+    u.id AS author_id,
+    u.username AS author_username,
+    u.email AS author_email,
+    u.avatar AS author_avatar,
+    ci.position,
+    ci.added_by,
+    ci.created_at AS added_at
+FROM collection_items ci
+JOIN snippets s ON s.id = ci.snippet_id
+LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
+LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
+LEFT JOIN users u ON s.author = u.id
+WHERE ci.collection_id = @collection_id
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
+ORDER BY ci.position, ci.created_at;
+
+-- name: GetCollectionItemIDs :many
+SELECT snippet_id
+FROM collection_items
+WHERE collection_id = ?
+ORDER BY position, created_at;
+
+-- name: GetNextCollectionItemPosition :one
+SELECT CAST(COALESCE(MAX(position) + 1, 0) AS INTEGER) AS position
+FROM collection_items
+WHERE collection_id = ?;
+
+-- name: AddCollectionItem :execrows
+INSERT INTO collection_items (
+    collection_id,
+    snippet_id,
+    position,
+    added_by
+) VALUES (
+    ?, ?, ?, ?
+)
+ON CONFLICT (collection_id, snippet_id) DO NOTHING;
+
+-- name: RemoveCollectionItem :execrows
+DELETE FROM collection_items
+WHERE collection_id = ? AND snippet_id = ?;
+
+-- name: SetCollectionItemPosition :exec
+UPDATE collection_items
+SET position = ?
+WHERE collection_id = ? AND snippet_id = ?;
+
+-- name: GetCollectionCollaborators :many
+SELECT u.*
+FROM collection_collaborators cc
+JOIN users u ON u.id = cc.user_id
+WHERE cc.collection_id = ?
+ORDER BY cc.created_at;
+
+-- name: AddCollectionCollaborator :exec
+INSERT INTO collection_collaborators (collection_id, user_id)
+VALUES (?, ?)
+ON CONFLICT (collection_id, user_id) DO NOTHING;
+
+-- name: RemoveCollectionCollaborator :execrows
+DELETE FROM collection_collaborators
+WHERE collection_id = ? AND user_id = ?;
+
+-- name: FollowCollection :exec
+INSERT INTO collection_follows (collection_id, user_id)
+VALUES (?, ?)
+ON CONFLICT (collection_id, user_id) DO NOTHING;
+
+-- name: UnfollowCollection :exec
+DELETE FROM collection_follows
+WHERE collection_id = ? AND user_id = ?;
+
+-- name: GetCollectionFollowerIDs :many
+SELECT user_id
+FROM collection_follows
+WHERE collection_id = ?;
@@ -0,0 +1,60 @@
+-- name: FollowUser :exec
+INSERT INTO follows (follower_id, followee_id)
+VALUES (?, ?)
+ON CONFLICT (follower_id, followee_id) DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM follows
+WHERE follower_id = ? AND followee_id = ?;
+
+-- name: GetFollowCounts :one
+SELECT
+    (SELECT COUNT(*) FROM follows WHERE followee_id = @user_id) AS followers,
+    (SELECT COUNT(*) FROM follows WHERE follower_id = @user_id) AS following;
+
+-- name: GetFollowerIDs :many
+SELECT follower_id
+FROM follows
+WHERE followee_id = ?;
+
+-- name: GetFollowers :many
+SELECT u.*
+FROM follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = ?
+ORDER BY f.created_at DESC;
+
+-- name: GetFollowing :many
+SELECT u.*
+FROM follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = ?
+ORDER BY f.created_at DESC;
+
+-- name: GetFeedSnippets :many
+SELECT 
+    s.*,
+    CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
+    CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar
+FROM snippets s
+JOIN follows f ON f.followee_id = s.author AND f.follower_id = @user_id
+LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
+LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
+LEFT JOIN users u ON s.author = u.id
+WHERE (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
+ORDER BY s.updated_at DESC
+LIMIT @limit;
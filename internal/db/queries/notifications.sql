@@ -0,0 +1,104 @@
+-- name: InsertNotification :exec
+INSERT INTO notifications (
+    id,
+    user_id,
+    actor_id,
+    type,
+    snippet_id,
+    collection_id
+) VALUES (
+    ?, ?, ?, ?, ?, ?
+);
+
+-- name: CountUnreadDuplicateNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = @user_id
+AND actor_id = @actor_id
+AND type = @type
+AND snippet_id IS @snippet_id
+AND collection_id IS @collection_id
+AND read_at IS NULL;
+
+-- name: GetNotification :one
+SELECT
+    n.*,
+    u.username AS actor_username,
+    u.avatar AS actor_avatar,
+    s.title AS snippet_title,
+    c.name AS collection_name
+FROM notifications n
+JOIN users u ON n.actor_id = u.id
+LEFT JOIN snippets s ON n.snippet_id = s.id AND s.deleted_at IS NULL
+    AND (s.visibility = 'public' OR EXISTS (
+        SELECT 1 FROM snippet_collaborators sc
+        WHERE sc.snippet_id = s.id AND sc.user_id = n.user_id
+    ) OR (s.organization_id IS NULL AND s.author = n.user_id) OR EXISTS (
+        SELECT 1 FROM organization_members om
+        WHERE om.organization_id = s.organization_id AND om.user_id = n.user_id
+        AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = n.user_id)
+    ))
+    AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = n.user_id)
+LEFT JOIN collections c ON n.collection_id = c.id
+WHERE n.id = @id;
+
+-- name: GetNotifications :many
+SELECT
+    n.*,
+    u.username AS actor_username,
+    u.avatar AS actor_avatar,
+    s.title AS snippet_title,
+    c.name AS collection_name
+FROM notifications n
+JOIN users u ON n.actor_id = u.id
+LEFT JOIN snippets s ON n.snippet_id = s.id AND s.deleted_at IS NULL
+    AND (s.visibility = 'public' OR EXISTS (
+        SELECT 1 FROM snippet_collaborators sc
+        WHERE sc.snippet_id = s.id AND sc.user_id = n.user_id
+    ) OR (s.organization_id IS NULL AND s.author = n.user_id) OR EXISTS (
+        SELECT 1 FROM organization_members om
+        WHERE om.organization_id = s.organization_id AND om.user_id = n.user_id
+        AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = n.user_id)
+    ))
+    AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = n.user_id)
+LEFT JOIN collections c ON n.collection_id = c.id
+WHERE n.user_id = @user_id
+AND (NOT CAST(@unread_only AS BOOLEAN) OR n.read_at IS NULL)
+ORDER BY n.created_at DESC
+LIMIT @limit;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = ? AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE id = ? AND user_id = ?;
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND read_at IS NULL;
+
+-- name: DeleteOldNotifications :exec
+DELETE FROM notifications
+WHERE read_at IS NOT NULL
+AND created_at < datetime('now', '-90 days');
+
+-- name: GetNotificationPreference :one
+SELECT enabled
+FROM notification_preferences
+WHERE user_id = ? AND type = ?;
+
+-- name: GetNotificationPreferences :many
+SELECT type, enabled
+FROM notification_preferences
+WHERE user_id = ?;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, type, enabled)
+VALUES (?, ?, ?)
+ON CONFLICT (user_id, type) DO UPDATE SET
+    enabled = excluded.enabled;
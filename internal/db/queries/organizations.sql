@@ -0,0 +1,70 @@
+-- name: CreateOrganization :exec
+INSERT INTO organizations (
+    id,
+    slug,
+    name,
+    description
+) VALUES (
+    ?, ?, ?, ?
+);
+
+-- name: GetOrganization :one
+-- Looks an organization up by ID or slug, with the role of the requesting user
+SELECT o.*,
+    (SELECT COUNT(*) FROM organization_members m WHERE m.organization_id = o.id) AS member_count,
+    (SELECT COUNT(*) FROM snippets s WHERE s.organization_id = o.id AND s.deleted_at IS NULL) AS snippet_count,
+    COALESCE(r.role, '') AS role
+FROM organizations o
+LEFT JOIN organization_members r ON r.organization_id = o.id AND r.user_id = @user_id
+WHERE o.id = @id OR o.slug = @id;
+
+-- name: GetUserOrganizations :many
+SELECT o.*,
+    (SELECT COUNT(*) FROM organization_members m WHERE m.organization_id = o.id) AS member_count,
+    (SELECT COUNT(*) FROM snippets s WHERE s.organization_id = o.id AND s.deleted_at IS NULL) AS snippet_count,
+    COALESCE(r.role, '') AS role
+FROM organizations o
+JOIN organization_members r ON r.organization_id = o.id AND r.user_id = @user_id
+ORDER BY o.name COLLATE NOCASE, o.id;
+
+-- name: UpdateOrganization :execrows
+UPDATE organizations
+SET name = ?,
+    description = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: CountOrganizationSnippets :one
+-- Counts trashed snippets as well, deleting the organization would orphan them
+SELECT COUNT(*)
+FROM snippets
+WHERE organization_id = ?;
+
+-- name: DeleteOrganization :execrows
+DELETE FROM organizations
+WHERE id = ?;
+
+-- name: DeleteOrganizationMembers :exec
+DELETE FROM organization_members
+WHERE organization_id = ?;
+
+-- name: GetOrganizationMembers :many
+SELECT u.id, u.username, u.email, u.avatar, m.role, m.created_at AS joined_at
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = ?
+ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'maintainer' THEN 1 ELSE 2 END, u.username;
+
+-- name: GetOrganizationMemberRole :one
+SELECT role
+FROM organization_members
+WHERE organization_id = ? AND user_id = ?;
+
+-- name: SetOrganizationMember :exec
+INSERT INTO organization_members (organization_id, user_id, role)
+VALUES (?, ?, ?)
+ON CONFLICT (organization_id, user_id) DO UPDATE SET role = excluded.role;
+
+-- name: RemoveOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE organization_id = ? AND user_id = ?;
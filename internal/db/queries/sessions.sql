@@ -13,6 +13,10 @@ INSERT INTO sessions (
 SELECT * FROM sessions
 WHERE token = ? LIMIT 1;
 
+-- name: GetSessionByID :one
+SELECT * FROM sessions
+WHERE id = ? LIMIT 1;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions
 WHERE token = ?;
@@ -21,6 +25,15 @@ WHERE token = ?;
 DELETE FROM sessions
 WHERE expires_at < unixepoch();
 
+-- name: RedeemTicket :execrows
+INSERT INTO redeemed_tickets (id, expires_at)
+VALUES (?, ?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: DeleteExpiredTickets :exec
+DELETE FROM redeemed_tickets
+WHERE expires_at < unixepoch();
+
 -- name: UpdateSessionExpiry :exec
 UPDATE sessions
 SET expires_at = ?,
@@ -0,0 +1,19 @@
+-- name: GetSnippetCollaborators :many
+SELECT u.id, u.username, u.email, u.avatar, c.permission, c.created_at AS added_at
+FROM snippet_collaborators c
+JOIN users u ON u.id = c.user_id
+WHERE c.snippet_id = ?
+ORDER BY CASE c.permission WHEN 'edit' THEN 0 ELSE 1 END, u.username;
+
+-- name: SetSnippetCollaborator :exec
+INSERT INTO snippet_collaborators (snippet_id, user_id, permission)
+VALUES (?, ?, ?)
+ON CONFLICT (snippet_id, user_id) DO UPDATE SET permission = excluded.permission;
+
+-- name: RemoveSnippetCollaborator :execrows
+DELETE FROM snippet_collaborators
+WHERE snippet_id = ? AND user_id = ?;
+
+-- name: DeleteSnippetCollaborators :exec
+DELETE FROM snippet_collaborators
+WHERE snippet_id = ?;
@@ -0,0 +1,66 @@
+-- name: UpsertViewDay :exec
+INSERT INTO snippet_view_days (
+    snippet_id,
+    viewer_identifier,
+    day,
+    hits,
+    counted_views,
+    bot_hits
+) VALUES (
+    @snippet_id,
+    @viewer_identifier,
+    @day,
+    @hits,
+    @counted_views,
+    @bot_hits
+)
+ON CONFLICT (snippet_id, day, viewer_identifier) DO UPDATE SET
+    hits = snippet_view_days.hits + excluded.hits,
+    counted_views = snippet_view_days.counted_views + excluded.counted_views,
+    bot_hits = snippet_view_days.bot_hits + excluded.bot_hits;
+
+-- name: AggregateDailyViews :exec
+INSERT INTO snippet_daily_stats (snippet_id, day, views, unique_viewers, bot_views)
+SELECT
+    snippet_id,
+    day,
+    SUM(counted_views),
+    SUM(CASE WHEN hits > 0 THEN 1 ELSE 0 END),
+    SUM(bot_hits)
+FROM snippet_view_days
+WHERE day >= date('now', '-7 days')
+GROUP BY snippet_id, day
+ON CONFLICT (snippet_id, day) DO UPDATE SET
+    views = excluded.views,
+    unique_viewers = excluded.unique_viewers,
+    bot_views = excluded.bot_views;
+
+-- name: ResetDailyLikes :exec
+-- Clears the likes of the window AggregateDailyLikes recomputes, so days whose likes
+-- were all taken back drop to zero
+UPDATE snippet_daily_stats
+SET likes = 0
+WHERE day >= date('now', '-30 days') AND likes > 0;
+
+-- name: AggregateDailyLikes :exec
+INSERT INTO snippet_daily_stats (snippet_id, day, likes)
+SELECT
+    snippet_id,
+    date(created_at),
+    COUNT(*)
+FROM user_likes
+WHERE created_at >= date('now', '-30 days')
+GROUP BY snippet_id, date(created_at)
+ON CONFLICT (snippet_id, day) DO UPDATE SET
+    likes = excluded.likes;
+
+-- name: GetDailyStats :many
+SELECT day, views, unique_viewers, likes, bot_views
+FROM snippet_daily_stats
+WHERE snippet_id = @snippet_id
+AND day BETWEEN @from_day AND @to_day
+ORDER BY day;
+
+-- name: CleanupOldViewDays :exec
+DELETE FROM snippet_view_days
+WHERE day < date('now', '-30 days');
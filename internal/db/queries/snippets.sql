@@ -11,6 +11,17 @@ FROM snippets s
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
 LEFT JOIN users u ON s.author = u.id
+WHERE (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
 ORDER BY s.created_at DESC;
 
 -- name: GetSnippetsByAuthor :many
@@ -27,9 +38,20 @@ LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
 LEFT JOIN users u ON s.author = u.id
 WHERE s.author = @author_id
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
 ORDER BY s.created_at DESC;
 
--- name: GetSnippet :one
+-- name: GetSnippetsByOrganization :many
 SELECT 
     s.*,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
@@ -39,10 +61,43 @@ SELECT
     u.email AS author_email,
     u.avatar AS author_avatar
 FROM snippets s
+LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
+LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
+LEFT JOIN users u ON s.author = u.id
+WHERE s.organization_id = @organization_id
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
+ORDER BY s.created_at DESC;
+
+-- name: GetSnippet :one
+SELECT 
+    s.*,
+    CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
+    CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar,
+    COALESCE(om.role, '') AS viewer_role,
+    COALESCE(sc.permission, '') AS viewer_permission,
+    ue.username AS updated_by_username
+FROM snippets s
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
 LEFT JOIN users u ON s.author = u.id
-WHERE s.id = @snippet_id;
+LEFT JOIN organization_members om ON om.organization_id = s.organization_id AND om.user_id = @user_id
+LEFT JOIN snippet_collaborators sc ON sc.snippet_id = s.id AND sc.user_id = @user_id
+LEFT JOIN users ue ON s.updated_by = ue.id
+WHERE s.id = @snippet_id AND s.deleted_at IS NULL;
 
 -- name: CreateSnippet :one
 INSERT INTO snippets (
@@ -50,9 +105,14 @@ INSERT INTO snippets (
     title,
     content,
     language,
-    author
+    author,
+    organization_id,
+    visibility,
+    expires_at,
+    burn_after_read,
+    password_hash
 ) VALUES (
-    ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
@@ -62,19 +122,96 @@ SET
     title = @title,
     content = @content,
     language = @language,
+    visibility = @visibility,
+    updated_by = @updated_by,
+    password_hash = @password_hash,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = @snippet_id
+WHERE id = @snippet_id AND deleted_at IS NULL
 RETURNING *;
 
+-- name: TransferSnippet :execrows
+-- Moves a personal snippet into an organization
+UPDATE snippets
+SET 
+    organization_id = @organization_id,
+    visibility = @visibility
+WHERE id = @snippet_id AND organization_id IS NULL AND deleted_at IS NULL;
+
 -- name: DeleteSnippet :exec
 DELETE FROM snippets
 WHERE id = ?;
 
+-- name: BurnSnippet :execrows
+DELETE FROM snippets
+WHERE id = ? AND burn_after_read AND deleted_at IS NULL;
+
+-- name: GetExpiredSnippets :many
+-- Trashed snippets are left to the trash purge
+SELECT id, author, language
+FROM snippets
+WHERE expires_at <= unixepoch() AND deleted_at IS NULL;
+
+-- name: TrashSnippet :execrows
+UPDATE snippets
+SET deleted_at = unixepoch()
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreSnippet :execrows
+UPDATE snippets
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: GetTrashedSnippet :one
+SELECT 
+    s.*,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar,
+    COALESCE(om.role, '') AS viewer_role
+FROM snippets s
+LEFT JOIN users u ON s.author = u.id
+LEFT JOIN organization_members om ON om.organization_id = s.organization_id AND om.user_id = @user_id
+WHERE s.id = @snippet_id AND s.deleted_at IS NOT NULL;
+
+-- name: GetTrashedSnippets :many
+-- Snippets in the trash that the user owns
+SELECT 
+    s.*,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar
+FROM snippets s
+LEFT JOIN users u ON s.author = u.id
+WHERE s.deleted_at IS NOT NULL
+AND ((s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+ORDER BY s.deleted_at DESC;
+
+-- name: GetPurgeableSnippets :many
+SELECT id, author, language
+FROM snippets
+WHERE deleted_at <= @cutoff;
+
 -- name: IncrementViews :exec
 UPDATE snippets
 SET views = views + 1
 WHERE id = @snippet_id;
 
+-- name: AddViews :exec
+UPDATE snippets
+SET views = views + @count
+WHERE id = @snippet_id;
+
+-- name: GetSnippetStats :one
+SELECT views, likes
+FROM snippets
+WHERE id = @snippet_id AND deleted_at IS NULL;
+
 -- name: CheckRecentView :one
 SELECT 
     snippet_id,
@@ -112,6 +249,25 @@ INSERT OR REPLACE INTO snippet_views (
     END
 );
 
+-- name: UpsertView :exec
+INSERT INTO snippet_views (
+    snippet_id,
+    viewer_identifier,
+    ip_address,
+    last_viewed_at,
+    view_count
+) VALUES (
+    @snippet_id,
+    @viewer_identifier,
+    @ip_address,
+    @last_viewed_at,
+    @hits
+)
+ON CONFLICT (snippet_id, viewer_identifier) DO UPDATE SET
+    ip_address = excluded.ip_address,
+    last_viewed_at = excluded.last_viewed_at,
+    view_count = COALESCE(snippet_views.view_count, 0) + excluded.view_count;
+
 -- name: CleanupOldViews :exec
 DELETE FROM snippet_views 
 WHERE last_viewed_at < datetime('now', '-30 days');
\ No newline at end of file
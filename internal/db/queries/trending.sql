@@ -0,0 +1,58 @@
+-- name: GetRecentLikeActivity :many
+SELECT
+    snippet_id,
+    CAST(strftime('%Y-%m-%d %H:00:00', created_at) AS TEXT) AS hour,
+    COUNT(*) AS count
+FROM user_likes
+WHERE created_at >= datetime('now', '-30 days')
+GROUP BY snippet_id, hour;
+
+-- name: GetRecentSaveActivity :many
+SELECT
+    snippet_id,
+    CAST(strftime('%Y-%m-%d %H:00:00', created_at) AS TEXT) AS hour,
+    COUNT(*) AS count
+FROM user_saves
+WHERE created_at >= datetime('now', '-30 days')
+GROUP BY snippet_id, hour;
+
+-- name: GetRecentViewActivity :many
+SELECT
+    snippet_id,
+    day,
+    CAST(SUM(counted_views) AS INTEGER) AS views
+FROM snippet_view_days
+WHERE day >= date('now', '-30 days')
+GROUP BY snippet_id, day
+HAVING SUM(counted_views) > 0;
+
+-- name: DeleteTrendingScores :exec
+DELETE FROM snippet_trending_scores
+WHERE period = ?;
+
+-- name: InsertTrendingScore :exec
+INSERT INTO snippet_trending_scores (period, snippet_id, score)
+VALUES (?, ?, ?);
+
+-- name: GetTrendingSnippets :many
+SELECT 
+    s.*,
+    CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
+    CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar,
+    t.score
+FROM snippet_trending_scores t
+JOIN snippets s ON s.id = t.snippet_id
+LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
+LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
+LEFT JOIN users u ON s.author = u.id
+WHERE t.period = @period
+AND (CAST(@language AS TEXT) = '' OR s.language = @language)
+AND s.visibility = 'public'
+AND s.expires_at IS NULL AND NOT s.burn_after_read AND s.password_hash IS NULL
+AND s.deleted_at IS NULL
+ORDER BY t.score DESC, s.created_at DESC
+LIMIT @limit;
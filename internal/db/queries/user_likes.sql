@@ -44,4 +44,15 @@ LEFT JOIN user_likes ul ON s.id = ul.snippet_id
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
 LEFT JOIN users u ON s.author = u.id
 WHERE ul.user_id = @user_id
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
 ORDER BY ul.created_at DESC;
\ No newline at end of file
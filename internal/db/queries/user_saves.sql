@@ -2,21 +2,58 @@
 INSERT OR IGNORE INTO user_saves (snippet_id, user_id)
 VALUES (@snippet_id, @user_id);
 
--- name: DeleteSavedSnippet :exec
+-- name: DeleteSavedSnippet :execrows
 DELETE FROM user_saves
 WHERE snippet_id = @snippet_id AND user_id = @user_id;
 
 -- name: GetSavedSnippets :many
+-- Newest saves first. Optionally restricted to one folder (NULL folder_id
+-- for unfiled saves) and to saves whose title, content or note contain the
+-- search text, which must have LIKE wildcards escaped with a backslash.
 SELECT s.*, 
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     u.id AS author_id, 
     u.username AS author_username, 
     u.email AS author_email,
-    u.avatar AS author_avatar
+    u.avatar AS author_avatar,
+    us.folder_id,
+    us.note,
+    us.created_at AS saved_at
 FROM snippets s
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = @user_id
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = @user_id
 LEFT JOIN users u ON s.author = u.id
 WHERE us.user_id = @user_id
-ORDER BY ul.created_at DESC;
\ No newline at end of file
+    AND (s.visibility = 'public' OR EXISTS (
+        SELECT 1 FROM snippet_collaborators sc
+        WHERE sc.snippet_id = s.id AND sc.user_id = @user_id
+    ) OR (s.organization_id IS NULL AND s.author = @user_id) OR EXISTS (
+        SELECT 1 FROM organization_members om
+        WHERE om.organization_id = s.organization_id AND om.user_id = @user_id
+        AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = @user_id)
+    ))
+    AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+    AND s.deleted_at IS NULL
+    AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = @user_id)
+    AND (NOT CAST(@filter_folder AS BOOLEAN) OR us.folder_id IS sqlc.narg('folder_id'))
+    AND (CAST(@search AS TEXT) = ''
+        OR s.title LIKE '%' || @search || '%' ESCAPE '\'
+        OR s.content LIKE '%' || @search || '%' ESCAPE '\'
+        OR us.note LIKE '%' || @search || '%' ESCAPE '\')
+ORDER BY us.created_at DESC, s.id;
+
+-- name: UpdateSavedSnippetNote :execrows
+UPDATE user_saves
+SET note = @note
+WHERE snippet_id = @snippet_id AND user_id = @user_id;
+
+-- name: MoveSavedSnippet :execrows
+UPDATE user_saves
+SET folder_id = sqlc.narg('folder_id')
+WHERE snippet_id = @snippet_id AND user_id = @user_id;
+
+-- name: UnfileSavedSnippets :exec
+UPDATE user_saves
+SET folder_id = NULL
+WHERE folder_id = @folder_id;
@@ -0,0 +1,89 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+    id,
+    owner_id,
+    url,
+    secret,
+    events,
+    global
+) VALUES (
+    ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = ?;
+
+-- name: GetWebhooksByOwner :many
+SELECT * FROM webhooks
+WHERE owner_id = ?
+ORDER BY created_at DESC;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = ?,
+    events = ?,
+    active = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = ?;
+
+-- name: GetSubscribedWebhooks :many
+SELECT * FROM webhooks
+WHERE active
+AND (global OR owner_id = @owner_id)
+AND (events = '' OR ',' || events || ',' LIKE '%,' || CAST(@event AS TEXT) || ',%');
+
+-- name: InsertWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    id,
+    webhook_id,
+    event,
+    payload,
+    next_attempt_at
+) VALUES (
+    ?, ?, ?, ?, ?
+);
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = ?;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = ?
+ORDER BY created_at DESC
+LIMIT ?;
+
+-- name: GetDueWebhookDeliveries :many
+SELECT
+    d.*,
+    w.url,
+    w.secret
+FROM webhook_deliveries d
+JOIN webhooks w ON d.webhook_id = w.id
+WHERE d.status = 'pending'
+AND d.next_attempt_at <= @now
+AND w.active
+ORDER BY d.next_attempt_at
+LIMIT @limit;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = ?,
+    attempts = ?,
+    next_attempt_at = ?,
+    response_status = ?,
+    last_error = ?,
+    completed_at = ?
+WHERE id = ?;
+
+-- name: DeleteOldWebhookDeliveries :exec
+DELETE FROM webhook_deliveries
+WHERE status != 'pending'
+AND created_at < datetime('now', '-30 days');
@@ -1,3 +1,10 @@
+-- Applied one-time migrations (see storage/sqlite/migrations.go)
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create snippets table
 CREATE TABLE IF NOT EXISTS snippets (
     id TEXT PRIMARY KEY,
@@ -9,6 +16,13 @@ CREATE TABLE IF NOT EXISTS snippets (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     likes INTEGER NOT NULL DEFAULT 0,
     views INTEGER NOT NULL DEFAULT 0,
+    organization_id TEXT REFERENCES organizations(id), -- NULL for personal snippets
+    visibility TEXT NOT NULL DEFAULT 'public', -- public, organization (members only) or private (owners and collaborators only)
+    updated_by TEXT REFERENCES users(id), -- Last user who changed the content, NULL until the first update
+    expires_at INTEGER, -- Unix time after which the snippet is gone, NULL for snippets that never expire
+    burn_after_read BOOLEAN NOT NULL DEFAULT FALSE, -- Deleted after the first view by someone other than the author
+    password_hash TEXT, -- bcrypt hash of the password viewers must present, NULL for unprotected snippets
+    deleted_at INTEGER, -- Unix time the snippet was moved to the trash, NULL for snippets in use
     FOREIGN KEY (author) REFERENCES users(id)
 );
 
@@ -23,6 +37,115 @@ CREATE TABLE IF NOT EXISTS users (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Create follows table for tracking who follows which author
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id TEXT NOT NULL,
+    followee_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (followee_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (follower_id != followee_id)
+);
+
+-- Create notifications table for the per-user inbox
+CREATE TABLE IF NOT EXISTS notifications (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL, -- recipient
+    actor_id TEXT NOT NULL, -- user who triggered the notification
+    type TEXT NOT NULL, -- like, save, follow or collection_item
+    snippet_id TEXT,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    collection_id TEXT REFERENCES collections(id) ON DELETE CASCADE, -- only set for collection_item notifications
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE
+);
+
+-- Notification types a user muted, missing rows are enabled
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create collections table, named sets of snippets of any author in a manual order
+CREATE TABLE IF NOT EXISTS collections (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility TEXT NOT NULL DEFAULT 'public', -- public or private
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id TEXT NOT NULL,
+    snippet_id TEXT NOT NULL,
+    position INTEGER NOT NULL, -- ascending, gaps allowed
+    added_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, snippet_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE,
+    FOREIGN KEY (added_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Users besides the owner who may add, remove and reorder items
+CREATE TABLE IF NOT EXISTS collection_collaborators (
+    collection_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, user_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Followers are notified when items are added
+CREATE TABLE IF NOT EXISTS collection_follows (
+    collection_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, user_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create webhooks table, user webhooks receive events of the owner's snippets, global webhooks of all snippets
+CREATE TABLE IF NOT EXISTS webhooks (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL, -- HMAC-SHA256 signing key
+    events TEXT NOT NULL DEFAULT '', -- comma-separated event types, empty for all events
+    global BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Webhook delivery queue and log
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    webhook_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, succeeded or failed
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL, -- Unix timestamp
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
 -- Create sessions table
 CREATE TABLE IF NOT EXISTS sessions (
     id TEXT PRIMARY KEY,
@@ -34,6 +157,12 @@ CREATE TABLE IF NOT EXISTS sessions (
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Create redeemed_tickets table, live update tickets are single use across all instances
+CREATE TABLE IF NOT EXISTS redeemed_tickets (
+    id TEXT PRIMARY KEY,
+    expires_at INTEGER NOT NULL -- Unix timestamp
+);
+
 -- Create user_likes table for tracking who liked what
 CREATE TABLE IF NOT EXISTS user_likes (
     snippet_id TEXT NOT NULL,
@@ -44,11 +173,55 @@ CREATE TABLE IF NOT EXISTS user_likes (
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Organizations owning snippets together
+CREATE TABLE IF NOT EXISTS organizations (
+    id TEXT PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member', -- owner, maintainer or member
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Users invited to view or edit a snippet besides its owners
+CREATE TABLE IF NOT EXISTS snippet_collaborators (
+    snippet_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    permission TEXT NOT NULL DEFAULT 'view', -- view or edit
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snippet_id, user_id),
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Private folders to organize saved snippets
+CREATE TABLE IF NOT EXISTS bookmark_folders (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
 -- Create user_saves table for tracking saved snippets
 CREATE TABLE IF NOT EXISTS user_saves (
     snippet_id TEXT NOT NULL,
     user_id TEXT NOT NULL,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    folder_id TEXT REFERENCES bookmark_folders(id) ON DELETE SET NULL, -- NULL for unfiled snippets
+    note TEXT NOT NULL DEFAULT '', -- private note, only visible to the user who saved the snippet
     PRIMARY KEY (snippet_id, user_id),
     FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
@@ -57,26 +230,82 @@ CREATE TABLE IF NOT EXISTS user_saves (
 -- Snippet Views Tracking Table
 CREATE TABLE IF NOT EXISTS snippet_views (
     snippet_id TEXT NOT NULL,
-    viewer_identifier TEXT NOT NULL, -- user_id for authenticated, session_token for anonymous
-    ip_address TEXT,
+    viewer_identifier TEXT NOT NULL, -- kind ("user", "session", "ip") + daily salted hash, never the raw value
+    ip_address TEXT, -- daily salted hash of the truncated IP, NULL if IP storage is disabled
     last_viewed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     view_count INTEGER DEFAULT 1,
     PRIMARY KEY (snippet_id, viewer_identifier),
     FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE
 );
 
+-- Raw daily view records per viewer, purged together with snippet_views
+CREATE TABLE IF NOT EXISTS snippet_view_days (
+    snippet_id TEXT NOT NULL,
+    viewer_identifier TEXT NOT NULL,
+    day TEXT NOT NULL, -- YYYY-MM-DD (UTC)
+    hits INTEGER NOT NULL DEFAULT 0, -- view attempts by humans
+    counted_views INTEGER NOT NULL DEFAULT 0, -- views that incremented the public counter
+    bot_hits INTEGER NOT NULL DEFAULT 0, -- view attempts by bots and crawlers, never counted
+    PRIMARY KEY (snippet_id, day, viewer_identifier),
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE
+);
+
+-- Daily aggregated analytics per snippet, kept indefinitely
+CREATE TABLE IF NOT EXISTS snippet_daily_stats (
+    snippet_id TEXT NOT NULL,
+    day TEXT NOT NULL, -- YYYY-MM-DD (UTC)
+    views INTEGER NOT NULL DEFAULT 0,
+    unique_viewers INTEGER NOT NULL DEFAULT 0,
+    likes INTEGER NOT NULL DEFAULT 0,
+    bot_views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (snippet_id, day),
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE
+);
+
+-- Trending scores per ranking window, replaced on every refresh
+CREATE TABLE IF NOT EXISTS snippet_trending_scores (
+    period TEXT NOT NULL, -- day, week or month
+    snippet_id TEXT NOT NULL,
+    score REAL NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (period, snippet_id),
+    FOREIGN KEY (snippet_id) REFERENCES snippets(id) ON DELETE CASCADE
+);
+
 -- Create index for faster lookups
 CREATE INDEX IF NOT EXISTS idx_snippets_created_at ON snippets(created_at DESC);
 
+-- idx_snippets_organization_id is created by migration 5, databases created before
+-- organizations lack the column when this file runs
+
 CREATE INDEX IF NOT EXISTS idx_user_likes_user_id ON user_likes(user_id);
 CREATE INDEX IF NOT EXISTS idx_user_likes_snippet_user ON user_likes(snippet_id, user_id);
 
 CREATE INDEX IF NOT EXISTS idx_user_saves_user_id ON user_saves(user_id);
 CREATE INDEX IF NOT EXISTS idx_user_saves_snippet_user ON user_saves(snippet_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_user_saves_user_created_at ON user_saves(user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications(user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_collections_owner_updated_at ON collections(owner_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collection_items_collection_position ON collection_items(collection_id, position);
+CREATE INDEX IF NOT EXISTS idx_collection_collaborators_user_id ON collection_collaborators(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_owner_id ON webhooks(owner_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status_next_attempt ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created_at ON webhook_deliveries(webhook_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows(followee_id);
+CREATE INDEX IF NOT EXISTS idx_snippets_author_updated_at ON snippets(author, updated_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_snippet_view_days_day ON snippet_view_days(day);
+
+CREATE INDEX IF NOT EXISTS idx_snippet_trending_scores_period_score ON snippet_trending_scores(period, score DESC);
 
 CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 
 CREATE INDEX IF NOT EXISTS idx_sessions_token ON sessions(token);
 CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
-CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+CREATE INDEX IF NOT EXISTS idx_redeemed_tickets_expires_at ON redeemed_tickets(expires_at);
\ No newline at end of file
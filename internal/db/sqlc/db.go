@@ -24,15 +24,54 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addCollectionCollaboratorStmt, err = db.PrepareContext(ctx, addCollectionCollaborator); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCollectionCollaborator: %w", err)
+	}
+	if q.addCollectionItemStmt, err = db.PrepareContext(ctx, addCollectionItem); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCollectionItem: %w", err)
+	}
+	if q.addViewsStmt, err = db.PrepareContext(ctx, addViews); err != nil {
+		return nil, fmt.Errorf("error preparing query AddViews: %w", err)
+	}
+	if q.aggregateDailyLikesStmt, err = db.PrepareContext(ctx, aggregateDailyLikes); err != nil {
+		return nil, fmt.Errorf("error preparing query AggregateDailyLikes: %w", err)
+	}
+	if q.aggregateDailyViewsStmt, err = db.PrepareContext(ctx, aggregateDailyViews); err != nil {
+		return nil, fmt.Errorf("error preparing query AggregateDailyViews: %w", err)
+	}
+	if q.burnSnippetStmt, err = db.PrepareContext(ctx, burnSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query BurnSnippet: %w", err)
+	}
 	if q.checkLikeExistsStmt, err = db.PrepareContext(ctx, checkLikeExists); err != nil {
 		return nil, fmt.Errorf("error preparing query CheckLikeExists: %w", err)
 	}
 	if q.checkRecentViewStmt, err = db.PrepareContext(ctx, checkRecentView); err != nil {
 		return nil, fmt.Errorf("error preparing query CheckRecentView: %w", err)
 	}
+	if q.cleanupOldViewDaysStmt, err = db.PrepareContext(ctx, cleanupOldViewDays); err != nil {
+		return nil, fmt.Errorf("error preparing query CleanupOldViewDays: %w", err)
+	}
 	if q.cleanupOldViewsStmt, err = db.PrepareContext(ctx, cleanupOldViews); err != nil {
 		return nil, fmt.Errorf("error preparing query CleanupOldViews: %w", err)
 	}
+	if q.countOrganizationSnippetsStmt, err = db.PrepareContext(ctx, countOrganizationSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query CountOrganizationSnippets: %w", err)
+	}
+	if q.countUnreadDuplicateNotificationsStmt, err = db.PrepareContext(ctx, countUnreadDuplicateNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUnreadDuplicateNotifications: %w", err)
+	}
+	if q.countUnreadNotificationsStmt, err = db.PrepareContext(ctx, countUnreadNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUnreadNotifications: %w", err)
+	}
+	if q.createBookmarkFolderStmt, err = db.PrepareContext(ctx, createBookmarkFolder); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBookmarkFolder: %w", err)
+	}
+	if q.createCollectionStmt, err = db.PrepareContext(ctx, createCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateCollection: %w", err)
+	}
+	if q.createOrganizationStmt, err = db.PrepareContext(ctx, createOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOrganization: %w", err)
+	}
 	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
 	}
@@ -42,15 +81,51 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createWebhookStmt, err = db.PrepareContext(ctx, createWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhook: %w", err)
+	}
 	if q.decrementLikesCountStmt, err = db.PrepareContext(ctx, decrementLikesCount); err != nil {
 		return nil, fmt.Errorf("error preparing query DecrementLikesCount: %w", err)
 	}
+	if q.deleteBookmarkFolderStmt, err = db.PrepareContext(ctx, deleteBookmarkFolder); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBookmarkFolder: %w", err)
+	}
+	if q.deleteCollectionStmt, err = db.PrepareContext(ctx, deleteCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCollection: %w", err)
+	}
+	if q.deleteCollectionCollaboratorsStmt, err = db.PrepareContext(ctx, deleteCollectionCollaborators); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCollectionCollaborators: %w", err)
+	}
+	if q.deleteCollectionFollowsStmt, err = db.PrepareContext(ctx, deleteCollectionFollows); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCollectionFollows: %w", err)
+	}
+	if q.deleteCollectionItemsStmt, err = db.PrepareContext(ctx, deleteCollectionItems); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCollectionItems: %w", err)
+	}
+	if q.deleteCollectionNotificationsStmt, err = db.PrepareContext(ctx, deleteCollectionNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCollectionNotifications: %w", err)
+	}
 	if q.deleteExpiredSessionsStmt, err = db.PrepareContext(ctx, deleteExpiredSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteExpiredSessions: %w", err)
 	}
+	if q.deleteExpiredTicketsStmt, err = db.PrepareContext(ctx, deleteExpiredTickets); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredTickets: %w", err)
+	}
 	if q.deleteLikeStmt, err = db.PrepareContext(ctx, deleteLike); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteLike: %w", err)
 	}
+	if q.deleteOldNotificationsStmt, err = db.PrepareContext(ctx, deleteOldNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOldNotifications: %w", err)
+	}
+	if q.deleteOldWebhookDeliveriesStmt, err = db.PrepareContext(ctx, deleteOldWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOldWebhookDeliveries: %w", err)
+	}
+	if q.deleteOrganizationStmt, err = db.PrepareContext(ctx, deleteOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOrganization: %w", err)
+	}
+	if q.deleteOrganizationMembersStmt, err = db.PrepareContext(ctx, deleteOrganizationMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOrganizationMembers: %w", err)
+	}
 	if q.deleteSavedSnippetStmt, err = db.PrepareContext(ctx, deleteSavedSnippet); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSavedSnippet: %w", err)
 	}
@@ -60,24 +135,144 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteSnippetStmt, err = db.PrepareContext(ctx, deleteSnippet); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSnippet: %w", err)
 	}
+	if q.deleteSnippetCollaboratorsStmt, err = db.PrepareContext(ctx, deleteSnippetCollaborators); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSnippetCollaborators: %w", err)
+	}
+	if q.deleteTrendingScoresStmt, err = db.PrepareContext(ctx, deleteTrendingScores); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTrendingScores: %w", err)
+	}
+	if q.deleteWebhookStmt, err = db.PrepareContext(ctx, deleteWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWebhook: %w", err)
+	}
+	if q.followCollectionStmt, err = db.PrepareContext(ctx, followCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query FollowCollection: %w", err)
+	}
+	if q.followUserStmt, err = db.PrepareContext(ctx, followUser); err != nil {
+		return nil, fmt.Errorf("error preparing query FollowUser: %w", err)
+	}
+	if q.getBookmarkFolderStmt, err = db.PrepareContext(ctx, getBookmarkFolder); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBookmarkFolder: %w", err)
+	}
+	if q.getBookmarkFoldersStmt, err = db.PrepareContext(ctx, getBookmarkFolders); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBookmarkFolders: %w", err)
+	}
+	if q.getCollectionStmt, err = db.PrepareContext(ctx, getCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCollection: %w", err)
+	}
+	if q.getCollectionCollaboratorsStmt, err = db.PrepareContext(ctx, getCollectionCollaborators); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCollectionCollaborators: %w", err)
+	}
+	if q.getCollectionFollowerIDsStmt, err = db.PrepareContext(ctx, getCollectionFollowerIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCollectionFollowerIDs: %w", err)
+	}
+	if q.getCollectionItemIDsStmt, err = db.PrepareContext(ctx, getCollectionItemIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCollectionItemIDs: %w", err)
+	}
+	if q.getCollectionItemsStmt, err = db.PrepareContext(ctx, getCollectionItems); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCollectionItems: %w", err)
+	}
+	if q.getDailyStatsStmt, err = db.PrepareContext(ctx, getDailyStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDailyStats: %w", err)
+	}
+	if q.getDueWebhookDeliveriesStmt, err = db.PrepareContext(ctx, getDueWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDueWebhookDeliveries: %w", err)
+	}
+	if q.getExpiredSnippetsStmt, err = db.PrepareContext(ctx, getExpiredSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetExpiredSnippets: %w", err)
+	}
+	if q.getFeedSnippetsStmt, err = db.PrepareContext(ctx, getFeedSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeedSnippets: %w", err)
+	}
+	if q.getFollowCountsStmt, err = db.PrepareContext(ctx, getFollowCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowCounts: %w", err)
+	}
+	if q.getFollowerIDsStmt, err = db.PrepareContext(ctx, getFollowerIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowerIDs: %w", err)
+	}
+	if q.getFollowersStmt, err = db.PrepareContext(ctx, getFollowers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowers: %w", err)
+	}
+	if q.getFollowingStmt, err = db.PrepareContext(ctx, getFollowing); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowing: %w", err)
+	}
 	if q.getLikedSnippetsStmt, err = db.PrepareContext(ctx, getLikedSnippets); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLikedSnippets: %w", err)
 	}
+	if q.getNextCollectionItemPositionStmt, err = db.PrepareContext(ctx, getNextCollectionItemPosition); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNextCollectionItemPosition: %w", err)
+	}
+	if q.getNotificationStmt, err = db.PrepareContext(ctx, getNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotification: %w", err)
+	}
+	if q.getNotificationPreferenceStmt, err = db.PrepareContext(ctx, getNotificationPreference); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationPreference: %w", err)
+	}
+	if q.getNotificationPreferencesStmt, err = db.PrepareContext(ctx, getNotificationPreferences); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationPreferences: %w", err)
+	}
+	if q.getNotificationsStmt, err = db.PrepareContext(ctx, getNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotifications: %w", err)
+	}
+	if q.getOrganizationStmt, err = db.PrepareContext(ctx, getOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOrganization: %w", err)
+	}
+	if q.getOrganizationMemberRoleStmt, err = db.PrepareContext(ctx, getOrganizationMemberRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOrganizationMemberRole: %w", err)
+	}
+	if q.getOrganizationMembersStmt, err = db.PrepareContext(ctx, getOrganizationMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOrganizationMembers: %w", err)
+	}
+	if q.getPurgeableSnippetsStmt, err = db.PrepareContext(ctx, getPurgeableSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPurgeableSnippets: %w", err)
+	}
+	if q.getRecentLikeActivityStmt, err = db.PrepareContext(ctx, getRecentLikeActivity); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRecentLikeActivity: %w", err)
+	}
+	if q.getRecentSaveActivityStmt, err = db.PrepareContext(ctx, getRecentSaveActivity); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRecentSaveActivity: %w", err)
+	}
+	if q.getRecentViewActivityStmt, err = db.PrepareContext(ctx, getRecentViewActivity); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRecentViewActivity: %w", err)
+	}
 	if q.getSavedSnippetsStmt, err = db.PrepareContext(ctx, getSavedSnippets); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSavedSnippets: %w", err)
 	}
 	if q.getSessionStmt, err = db.PrepareContext(ctx, getSession); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSession: %w", err)
 	}
+	if q.getSessionByIDStmt, err = db.PrepareContext(ctx, getSessionByID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSessionByID: %w", err)
+	}
 	if q.getSnippetStmt, err = db.PrepareContext(ctx, getSnippet); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSnippet: %w", err)
 	}
+	if q.getSnippetCollaboratorsStmt, err = db.PrepareContext(ctx, getSnippetCollaborators); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSnippetCollaborators: %w", err)
+	}
+	if q.getSnippetStatsStmt, err = db.PrepareContext(ctx, getSnippetStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSnippetStats: %w", err)
+	}
 	if q.getSnippetsStmt, err = db.PrepareContext(ctx, getSnippets); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSnippets: %w", err)
 	}
 	if q.getSnippetsByAuthorStmt, err = db.PrepareContext(ctx, getSnippetsByAuthor); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSnippetsByAuthor: %w", err)
 	}
+	if q.getSnippetsByOrganizationStmt, err = db.PrepareContext(ctx, getSnippetsByOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSnippetsByOrganization: %w", err)
+	}
+	if q.getSubscribedWebhooksStmt, err = db.PrepareContext(ctx, getSubscribedWebhooks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscribedWebhooks: %w", err)
+	}
+	if q.getTrashedSnippetStmt, err = db.PrepareContext(ctx, getTrashedSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrashedSnippet: %w", err)
+	}
+	if q.getTrashedSnippetsStmt, err = db.PrepareContext(ctx, getTrashedSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrashedSnippets: %w", err)
+	}
+	if q.getTrendingSnippetsStmt, err = db.PrepareContext(ctx, getTrendingSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrendingSnippets: %w", err)
+	}
 	if q.getUserStmt, err = db.PrepareContext(ctx, getUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUser: %w", err)
 	}
@@ -87,24 +282,120 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUserByUsernameStmt, err = db.PrepareContext(ctx, getUserByUsername); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByUsername: %w", err)
 	}
+	if q.getUserCollectionsStmt, err = db.PrepareContext(ctx, getUserCollections); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserCollections: %w", err)
+	}
+	if q.getUserOrganizationsStmt, err = db.PrepareContext(ctx, getUserOrganizations); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserOrganizations: %w", err)
+	}
+	if q.getWebhookStmt, err = db.PrepareContext(ctx, getWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhook: %w", err)
+	}
+	if q.getWebhookDeliveriesStmt, err = db.PrepareContext(ctx, getWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookDeliveries: %w", err)
+	}
+	if q.getWebhookDeliveryStmt, err = db.PrepareContext(ctx, getWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookDelivery: %w", err)
+	}
+	if q.getWebhooksByOwnerStmt, err = db.PrepareContext(ctx, getWebhooksByOwner); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhooksByOwner: %w", err)
+	}
 	if q.incrementLikesCountStmt, err = db.PrepareContext(ctx, incrementLikesCount); err != nil {
 		return nil, fmt.Errorf("error preparing query IncrementLikesCount: %w", err)
 	}
 	if q.incrementViewsStmt, err = db.PrepareContext(ctx, incrementViews); err != nil {
 		return nil, fmt.Errorf("error preparing query IncrementViews: %w", err)
 	}
+	if q.insertNotificationStmt, err = db.PrepareContext(ctx, insertNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertNotification: %w", err)
+	}
+	if q.insertTrendingScoreStmt, err = db.PrepareContext(ctx, insertTrendingScore); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertTrendingScore: %w", err)
+	}
+	if q.insertWebhookDeliveryStmt, err = db.PrepareContext(ctx, insertWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertWebhookDelivery: %w", err)
+	}
 	if q.likeSnippetStmt, err = db.PrepareContext(ctx, likeSnippet); err != nil {
 		return nil, fmt.Errorf("error preparing query LikeSnippet: %w", err)
 	}
+	if q.markAllNotificationsReadStmt, err = db.PrepareContext(ctx, markAllNotificationsRead); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkAllNotificationsRead: %w", err)
+	}
+	if q.markNotificationReadStmt, err = db.PrepareContext(ctx, markNotificationRead); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationRead: %w", err)
+	}
+	if q.moveSavedSnippetStmt, err = db.PrepareContext(ctx, moveSavedSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query MoveSavedSnippet: %w", err)
+	}
 	if q.recordViewStmt, err = db.PrepareContext(ctx, recordView); err != nil {
 		return nil, fmt.Errorf("error preparing query RecordView: %w", err)
 	}
+	if q.redeemTicketStmt, err = db.PrepareContext(ctx, redeemTicket); err != nil {
+		return nil, fmt.Errorf("error preparing query RedeemTicket: %w", err)
+	}
+	if q.removeCollectionCollaboratorStmt, err = db.PrepareContext(ctx, removeCollectionCollaborator); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveCollectionCollaborator: %w", err)
+	}
+	if q.removeCollectionItemStmt, err = db.PrepareContext(ctx, removeCollectionItem); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveCollectionItem: %w", err)
+	}
+	if q.removeOrganizationMemberStmt, err = db.PrepareContext(ctx, removeOrganizationMember); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveOrganizationMember: %w", err)
+	}
+	if q.removeSnippetCollaboratorStmt, err = db.PrepareContext(ctx, removeSnippetCollaborator); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveSnippetCollaborator: %w", err)
+	}
+	if q.renameBookmarkFolderStmt, err = db.PrepareContext(ctx, renameBookmarkFolder); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameBookmarkFolder: %w", err)
+	}
+	if q.resetDailyLikesStmt, err = db.PrepareContext(ctx, resetDailyLikes); err != nil {
+		return nil, fmt.Errorf("error preparing query ResetDailyLikes: %w", err)
+	}
+	if q.restoreSnippetStmt, err = db.PrepareContext(ctx, restoreSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query RestoreSnippet: %w", err)
+	}
 	if q.saveSnippetStmt, err = db.PrepareContext(ctx, saveSnippet); err != nil {
 		return nil, fmt.Errorf("error preparing query SaveSnippet: %w", err)
 	}
+	if q.setCollectionItemPositionStmt, err = db.PrepareContext(ctx, setCollectionItemPosition); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCollectionItemPosition: %w", err)
+	}
+	if q.setOrganizationMemberStmt, err = db.PrepareContext(ctx, setOrganizationMember); err != nil {
+		return nil, fmt.Errorf("error preparing query SetOrganizationMember: %w", err)
+	}
+	if q.setSnippetCollaboratorStmt, err = db.PrepareContext(ctx, setSnippetCollaborator); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSnippetCollaborator: %w", err)
+	}
+	if q.touchCollectionStmt, err = db.PrepareContext(ctx, touchCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchCollection: %w", err)
+	}
+	if q.transferSnippetStmt, err = db.PrepareContext(ctx, transferSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query TransferSnippet: %w", err)
+	}
+	if q.trashSnippetStmt, err = db.PrepareContext(ctx, trashSnippet); err != nil {
+		return nil, fmt.Errorf("error preparing query TrashSnippet: %w", err)
+	}
+	if q.unfileSavedSnippetsStmt, err = db.PrepareContext(ctx, unfileSavedSnippets); err != nil {
+		return nil, fmt.Errorf("error preparing query UnfileSavedSnippets: %w", err)
+	}
+	if q.unfollowCollectionStmt, err = db.PrepareContext(ctx, unfollowCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query UnfollowCollection: %w", err)
+	}
+	if q.unfollowUserStmt, err = db.PrepareContext(ctx, unfollowUser); err != nil {
+		return nil, fmt.Errorf("error preparing query UnfollowUser: %w", err)
+	}
+	if q.updateCollectionStmt, err = db.PrepareContext(ctx, updateCollection); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateCollection: %w", err)
+	}
 	if q.updateLikesCountStmt, err = db.PrepareContext(ctx, updateLikesCount); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateLikesCount: %w", err)
 	}
+	if q.updateOrganizationStmt, err = db.PrepareContext(ctx, updateOrganization); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateOrganization: %w", err)
+	}
+	if q.updateSavedSnippetNoteStmt, err = db.PrepareContext(ctx, updateSavedSnippetNote); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSavedSnippetNote: %w", err)
+	}
 	if q.updateSessionExpiryStmt, err = db.PrepareContext(ctx, updateSessionExpiry); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSessionExpiry: %w", err)
 	}
@@ -120,11 +411,56 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateUserPasswordStmt, err = db.PrepareContext(ctx, updateUserPassword); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserPassword: %w", err)
 	}
+	if q.updateWebhookStmt, err = db.PrepareContext(ctx, updateWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWebhook: %w", err)
+	}
+	if q.updateWebhookDeliveryStmt, err = db.PrepareContext(ctx, updateWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWebhookDelivery: %w", err)
+	}
+	if q.upsertNotificationPreferenceStmt, err = db.PrepareContext(ctx, upsertNotificationPreference); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertNotificationPreference: %w", err)
+	}
+	if q.upsertViewStmt, err = db.PrepareContext(ctx, upsertView); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertView: %w", err)
+	}
+	if q.upsertViewDayStmt, err = db.PrepareContext(ctx, upsertViewDay); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertViewDay: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addCollectionCollaboratorStmt != nil {
+		if cerr := q.addCollectionCollaboratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCollectionCollaboratorStmt: %w", cerr)
+		}
+	}
+	if q.addCollectionItemStmt != nil {
+		if cerr := q.addCollectionItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCollectionItemStmt: %w", cerr)
+		}
+	}
+	if q.addViewsStmt != nil {
+		if cerr := q.addViewsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addViewsStmt: %w", cerr)
+		}
+	}
+	if q.aggregateDailyLikesStmt != nil {
+		if cerr := q.aggregateDailyLikesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing aggregateDailyLikesStmt: %w", cerr)
+		}
+	}
+	if q.aggregateDailyViewsStmt != nil {
+		if cerr := q.aggregateDailyViewsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing aggregateDailyViewsStmt: %w", cerr)
+		}
+	}
+	if q.burnSnippetStmt != nil {
+		if cerr := q.burnSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing burnSnippetStmt: %w", cerr)
+		}
+	}
 	if q.checkLikeExistsStmt != nil {
 		if cerr := q.checkLikeExistsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing checkLikeExistsStmt: %w", cerr)
@@ -135,11 +471,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing checkRecentViewStmt: %w", cerr)
 		}
 	}
+	if q.cleanupOldViewDaysStmt != nil {
+		if cerr := q.cleanupOldViewDaysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cleanupOldViewDaysStmt: %w", cerr)
+		}
+	}
 	if q.cleanupOldViewsStmt != nil {
 		if cerr := q.cleanupOldViewsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing cleanupOldViewsStmt: %w", cerr)
 		}
 	}
+	if q.countOrganizationSnippetsStmt != nil {
+		if cerr := q.countOrganizationSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countOrganizationSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.countUnreadDuplicateNotificationsStmt != nil {
+		if cerr := q.countUnreadDuplicateNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUnreadDuplicateNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.countUnreadNotificationsStmt != nil {
+		if cerr := q.countUnreadNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUnreadNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.createBookmarkFolderStmt != nil {
+		if cerr := q.createBookmarkFolderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBookmarkFolderStmt: %w", cerr)
+		}
+	}
+	if q.createCollectionStmt != nil {
+		if cerr := q.createCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createCollectionStmt: %w", cerr)
+		}
+	}
+	if q.createOrganizationStmt != nil {
+		if cerr := q.createOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOrganizationStmt: %w", cerr)
+		}
+	}
 	if q.createSessionStmt != nil {
 		if cerr := q.createSessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
@@ -155,21 +526,81 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
 		}
 	}
+	if q.createWebhookStmt != nil {
+		if cerr := q.createWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookStmt: %w", cerr)
+		}
+	}
 	if q.decrementLikesCountStmt != nil {
 		if cerr := q.decrementLikesCountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing decrementLikesCountStmt: %w", cerr)
 		}
 	}
+	if q.deleteBookmarkFolderStmt != nil {
+		if cerr := q.deleteBookmarkFolderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBookmarkFolderStmt: %w", cerr)
+		}
+	}
+	if q.deleteCollectionStmt != nil {
+		if cerr := q.deleteCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCollectionStmt: %w", cerr)
+		}
+	}
+	if q.deleteCollectionCollaboratorsStmt != nil {
+		if cerr := q.deleteCollectionCollaboratorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCollectionCollaboratorsStmt: %w", cerr)
+		}
+	}
+	if q.deleteCollectionFollowsStmt != nil {
+		if cerr := q.deleteCollectionFollowsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCollectionFollowsStmt: %w", cerr)
+		}
+	}
+	if q.deleteCollectionItemsStmt != nil {
+		if cerr := q.deleteCollectionItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCollectionItemsStmt: %w", cerr)
+		}
+	}
+	if q.deleteCollectionNotificationsStmt != nil {
+		if cerr := q.deleteCollectionNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCollectionNotificationsStmt: %w", cerr)
+		}
+	}
 	if q.deleteExpiredSessionsStmt != nil {
 		if cerr := q.deleteExpiredSessionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteExpiredSessionsStmt: %w", cerr)
 		}
 	}
+	if q.deleteExpiredTicketsStmt != nil {
+		if cerr := q.deleteExpiredTicketsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredTicketsStmt: %w", cerr)
+		}
+	}
 	if q.deleteLikeStmt != nil {
 		if cerr := q.deleteLikeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteLikeStmt: %w", cerr)
 		}
 	}
+	if q.deleteOldNotificationsStmt != nil {
+		if cerr := q.deleteOldNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOldNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.deleteOldWebhookDeliveriesStmt != nil {
+		if cerr := q.deleteOldWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOldWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.deleteOrganizationStmt != nil {
+		if cerr := q.deleteOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOrganizationStmt: %w", cerr)
+		}
+	}
+	if q.deleteOrganizationMembersStmt != nil {
+		if cerr := q.deleteOrganizationMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOrganizationMembersStmt: %w", cerr)
+		}
+	}
 	if q.deleteSavedSnippetStmt != nil {
 		if cerr := q.deleteSavedSnippetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteSavedSnippetStmt: %w", cerr)
@@ -185,11 +616,171 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteSnippetStmt: %w", cerr)
 		}
 	}
+	if q.deleteSnippetCollaboratorsStmt != nil {
+		if cerr := q.deleteSnippetCollaboratorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSnippetCollaboratorsStmt: %w", cerr)
+		}
+	}
+	if q.deleteTrendingScoresStmt != nil {
+		if cerr := q.deleteTrendingScoresStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTrendingScoresStmt: %w", cerr)
+		}
+	}
+	if q.deleteWebhookStmt != nil {
+		if cerr := q.deleteWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWebhookStmt: %w", cerr)
+		}
+	}
+	if q.followCollectionStmt != nil {
+		if cerr := q.followCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing followCollectionStmt: %w", cerr)
+		}
+	}
+	if q.followUserStmt != nil {
+		if cerr := q.followUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing followUserStmt: %w", cerr)
+		}
+	}
+	if q.getBookmarkFolderStmt != nil {
+		if cerr := q.getBookmarkFolderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBookmarkFolderStmt: %w", cerr)
+		}
+	}
+	if q.getBookmarkFoldersStmt != nil {
+		if cerr := q.getBookmarkFoldersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBookmarkFoldersStmt: %w", cerr)
+		}
+	}
+	if q.getCollectionStmt != nil {
+		if cerr := q.getCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCollectionStmt: %w", cerr)
+		}
+	}
+	if q.getCollectionCollaboratorsStmt != nil {
+		if cerr := q.getCollectionCollaboratorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCollectionCollaboratorsStmt: %w", cerr)
+		}
+	}
+	if q.getCollectionFollowerIDsStmt != nil {
+		if cerr := q.getCollectionFollowerIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCollectionFollowerIDsStmt: %w", cerr)
+		}
+	}
+	if q.getCollectionItemIDsStmt != nil {
+		if cerr := q.getCollectionItemIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCollectionItemIDsStmt: %w", cerr)
+		}
+	}
+	if q.getCollectionItemsStmt != nil {
+		if cerr := q.getCollectionItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCollectionItemsStmt: %w", cerr)
+		}
+	}
+	if q.getDailyStatsStmt != nil {
+		if cerr := q.getDailyStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDailyStatsStmt: %w", cerr)
+		}
+	}
+	if q.getDueWebhookDeliveriesStmt != nil {
+		if cerr := q.getDueWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDueWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.getExpiredSnippetsStmt != nil {
+		if cerr := q.getExpiredSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getExpiredSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.getFeedSnippetsStmt != nil {
+		if cerr := q.getFeedSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeedSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.getFollowCountsStmt != nil {
+		if cerr := q.getFollowCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowCountsStmt: %w", cerr)
+		}
+	}
+	if q.getFollowerIDsStmt != nil {
+		if cerr := q.getFollowerIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowerIDsStmt: %w", cerr)
+		}
+	}
+	if q.getFollowersStmt != nil {
+		if cerr := q.getFollowersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowersStmt: %w", cerr)
+		}
+	}
+	if q.getFollowingStmt != nil {
+		if cerr := q.getFollowingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowingStmt: %w", cerr)
+		}
+	}
 	if q.getLikedSnippetsStmt != nil {
 		if cerr := q.getLikedSnippetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLikedSnippetsStmt: %w", cerr)
 		}
 	}
+	if q.getNextCollectionItemPositionStmt != nil {
+		if cerr := q.getNextCollectionItemPositionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNextCollectionItemPositionStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationStmt != nil {
+		if cerr := q.getNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationPreferenceStmt != nil {
+		if cerr := q.getNotificationPreferenceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationPreferenceStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationPreferencesStmt != nil {
+		if cerr := q.getNotificationPreferencesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationPreferencesStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationsStmt != nil {
+		if cerr := q.getNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.getOrganizationStmt != nil {
+		if cerr := q.getOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOrganizationStmt: %w", cerr)
+		}
+	}
+	if q.getOrganizationMemberRoleStmt != nil {
+		if cerr := q.getOrganizationMemberRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOrganizationMemberRoleStmt: %w", cerr)
+		}
+	}
+	if q.getOrganizationMembersStmt != nil {
+		if cerr := q.getOrganizationMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOrganizationMembersStmt: %w", cerr)
+		}
+	}
+	if q.getPurgeableSnippetsStmt != nil {
+		if cerr := q.getPurgeableSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPurgeableSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.getRecentLikeActivityStmt != nil {
+		if cerr := q.getRecentLikeActivityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRecentLikeActivityStmt: %w", cerr)
+		}
+	}
+	if q.getRecentSaveActivityStmt != nil {
+		if cerr := q.getRecentSaveActivityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRecentSaveActivityStmt: %w", cerr)
+		}
+	}
+	if q.getRecentViewActivityStmt != nil {
+		if cerr := q.getRecentViewActivityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRecentViewActivityStmt: %w", cerr)
+		}
+	}
 	if q.getSavedSnippetsStmt != nil {
 		if cerr := q.getSavedSnippetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSavedSnippetsStmt: %w", cerr)
@@ -200,11 +791,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getSessionStmt: %w", cerr)
 		}
 	}
+	if q.getSessionByIDStmt != nil {
+		if cerr := q.getSessionByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionByIDStmt: %w", cerr)
+		}
+	}
 	if q.getSnippetStmt != nil {
 		if cerr := q.getSnippetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSnippetStmt: %w", cerr)
 		}
 	}
+	if q.getSnippetCollaboratorsStmt != nil {
+		if cerr := q.getSnippetCollaboratorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSnippetCollaboratorsStmt: %w", cerr)
+		}
+	}
+	if q.getSnippetStatsStmt != nil {
+		if cerr := q.getSnippetStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSnippetStatsStmt: %w", cerr)
+		}
+	}
 	if q.getSnippetsStmt != nil {
 		if cerr := q.getSnippetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSnippetsStmt: %w", cerr)
@@ -215,6 +821,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getSnippetsByAuthorStmt: %w", cerr)
 		}
 	}
+	if q.getSnippetsByOrganizationStmt != nil {
+		if cerr := q.getSnippetsByOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSnippetsByOrganizationStmt: %w", cerr)
+		}
+	}
+	if q.getSubscribedWebhooksStmt != nil {
+		if cerr := q.getSubscribedWebhooksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscribedWebhooksStmt: %w", cerr)
+		}
+	}
+	if q.getTrashedSnippetStmt != nil {
+		if cerr := q.getTrashedSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrashedSnippetStmt: %w", cerr)
+		}
+	}
+	if q.getTrashedSnippetsStmt != nil {
+		if cerr := q.getTrashedSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrashedSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.getTrendingSnippetsStmt != nil {
+		if cerr := q.getTrendingSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrendingSnippetsStmt: %w", cerr)
+		}
+	}
 	if q.getUserStmt != nil {
 		if cerr := q.getUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
@@ -230,6 +861,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
 		}
 	}
+	if q.getUserCollectionsStmt != nil {
+		if cerr := q.getUserCollectionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserCollectionsStmt: %w", cerr)
+		}
+	}
+	if q.getUserOrganizationsStmt != nil {
+		if cerr := q.getUserOrganizationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserOrganizationsStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookStmt != nil {
+		if cerr := q.getWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookDeliveriesStmt != nil {
+		if cerr := q.getWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookDeliveryStmt != nil {
+		if cerr := q.getWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.getWebhooksByOwnerStmt != nil {
+		if cerr := q.getWebhooksByOwnerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhooksByOwnerStmt: %w", cerr)
+		}
+	}
 	if q.incrementLikesCountStmt != nil {
 		if cerr := q.incrementLikesCountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing incrementLikesCountStmt: %w", cerr)
@@ -240,26 +901,156 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing incrementViewsStmt: %w", cerr)
 		}
 	}
+	if q.insertNotificationStmt != nil {
+		if cerr := q.insertNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertNotificationStmt: %w", cerr)
+		}
+	}
+	if q.insertTrendingScoreStmt != nil {
+		if cerr := q.insertTrendingScoreStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertTrendingScoreStmt: %w", cerr)
+		}
+	}
+	if q.insertWebhookDeliveryStmt != nil {
+		if cerr := q.insertWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertWebhookDeliveryStmt: %w", cerr)
+		}
+	}
 	if q.likeSnippetStmt != nil {
 		if cerr := q.likeSnippetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing likeSnippetStmt: %w", cerr)
 		}
 	}
+	if q.markAllNotificationsReadStmt != nil {
+		if cerr := q.markAllNotificationsReadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markAllNotificationsReadStmt: %w", cerr)
+		}
+	}
+	if q.markNotificationReadStmt != nil {
+		if cerr := q.markNotificationReadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationReadStmt: %w", cerr)
+		}
+	}
+	if q.moveSavedSnippetStmt != nil {
+		if cerr := q.moveSavedSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing moveSavedSnippetStmt: %w", cerr)
+		}
+	}
 	if q.recordViewStmt != nil {
 		if cerr := q.recordViewStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing recordViewStmt: %w", cerr)
 		}
 	}
+	if q.redeemTicketStmt != nil {
+		if cerr := q.redeemTicketStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing redeemTicketStmt: %w", cerr)
+		}
+	}
+	if q.removeCollectionCollaboratorStmt != nil {
+		if cerr := q.removeCollectionCollaboratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeCollectionCollaboratorStmt: %w", cerr)
+		}
+	}
+	if q.removeCollectionItemStmt != nil {
+		if cerr := q.removeCollectionItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeCollectionItemStmt: %w", cerr)
+		}
+	}
+	if q.removeOrganizationMemberStmt != nil {
+		if cerr := q.removeOrganizationMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeOrganizationMemberStmt: %w", cerr)
+		}
+	}
+	if q.removeSnippetCollaboratorStmt != nil {
+		if cerr := q.removeSnippetCollaboratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeSnippetCollaboratorStmt: %w", cerr)
+		}
+	}
+	if q.renameBookmarkFolderStmt != nil {
+		if cerr := q.renameBookmarkFolderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameBookmarkFolderStmt: %w", cerr)
+		}
+	}
+	if q.resetDailyLikesStmt != nil {
+		if cerr := q.resetDailyLikesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resetDailyLikesStmt: %w", cerr)
+		}
+	}
+	if q.restoreSnippetStmt != nil {
+		if cerr := q.restoreSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing restoreSnippetStmt: %w", cerr)
+		}
+	}
 	if q.saveSnippetStmt != nil {
 		if cerr := q.saveSnippetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing saveSnippetStmt: %w", cerr)
 		}
 	}
+	if q.setCollectionItemPositionStmt != nil {
+		if cerr := q.setCollectionItemPositionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCollectionItemPositionStmt: %w", cerr)
+		}
+	}
+	if q.setOrganizationMemberStmt != nil {
+		if cerr := q.setOrganizationMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setOrganizationMemberStmt: %w", cerr)
+		}
+	}
+	if q.setSnippetCollaboratorStmt != nil {
+		if cerr := q.setSnippetCollaboratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSnippetCollaboratorStmt: %w", cerr)
+		}
+	}
+	if q.touchCollectionStmt != nil {
+		if cerr := q.touchCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchCollectionStmt: %w", cerr)
+		}
+	}
+	if q.transferSnippetStmt != nil {
+		if cerr := q.transferSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing transferSnippetStmt: %w", cerr)
+		}
+	}
+	if q.trashSnippetStmt != nil {
+		if cerr := q.trashSnippetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing trashSnippetStmt: %w", cerr)
+		}
+	}
+	if q.unfileSavedSnippetsStmt != nil {
+		if cerr := q.unfileSavedSnippetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unfileSavedSnippetsStmt: %w", cerr)
+		}
+	}
+	if q.unfollowCollectionStmt != nil {
+		if cerr := q.unfollowCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unfollowCollectionStmt: %w", cerr)
+		}
+	}
+	if q.unfollowUserStmt != nil {
+		if cerr := q.unfollowUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing unfollowUserStmt: %w", cerr)
+		}
+	}
+	if q.updateCollectionStmt != nil {
+		if cerr := q.updateCollectionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateCollectionStmt: %w", cerr)
+		}
+	}
 	if q.updateLikesCountStmt != nil {
 		if cerr := q.updateLikesCountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateLikesCountStmt: %w", cerr)
 		}
 	}
+	if q.updateOrganizationStmt != nil {
+		if cerr := q.updateOrganizationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateOrganizationStmt: %w", cerr)
+		}
+	}
+	if q.updateSavedSnippetNoteStmt != nil {
+		if cerr := q.updateSavedSnippetNoteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSavedSnippetNoteStmt: %w", cerr)
+		}
+	}
 	if q.updateSessionExpiryStmt != nil {
 		if cerr := q.updateSessionExpiryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateSessionExpiryStmt: %w", cerr)
@@ -285,6 +1076,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserPasswordStmt: %w", cerr)
 		}
 	}
+	if q.updateWebhookStmt != nil {
+		if cerr := q.updateWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWebhookStmt: %w", cerr)
+		}
+	}
+	if q.updateWebhookDeliveryStmt != nil {
+		if cerr := q.updateWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWebhookDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.upsertNotificationPreferenceStmt != nil {
+		if cerr := q.upsertNotificationPreferenceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertNotificationPreferenceStmt: %w", cerr)
+		}
+	}
+	if q.upsertViewStmt != nil {
+		if cerr := q.upsertViewStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertViewStmt: %w", cerr)
+		}
+	}
+	if q.upsertViewDayStmt != nil {
+		if cerr := q.upsertViewDayStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertViewDayStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -322,77 +1138,281 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                        DBTX
-	tx                        *sql.Tx
-	checkLikeExistsStmt       *sql.Stmt
-	checkRecentViewStmt       *sql.Stmt
-	cleanupOldViewsStmt       *sql.Stmt
-	createSessionStmt         *sql.Stmt
-	createSnippetStmt         *sql.Stmt
-	createUserStmt            *sql.Stmt
-	decrementLikesCountStmt   *sql.Stmt
-	deleteExpiredSessionsStmt *sql.Stmt
-	deleteLikeStmt            *sql.Stmt
-	deleteSavedSnippetStmt    *sql.Stmt
-	deleteSessionStmt         *sql.Stmt
-	deleteSnippetStmt         *sql.Stmt
-	getLikedSnippetsStmt      *sql.Stmt
-	getSavedSnippetsStmt      *sql.Stmt
-	getSessionStmt            *sql.Stmt
-	getSnippetStmt            *sql.Stmt
-	getSnippetsStmt           *sql.Stmt
-	getSnippetsByAuthorStmt   *sql.Stmt
-	getUserStmt               *sql.Stmt
-	getUserByEmailStmt        *sql.Stmt
-	getUserByUsernameStmt     *sql.Stmt
-	incrementLikesCountStmt   *sql.Stmt
-	incrementViewsStmt        *sql.Stmt
-	likeSnippetStmt           *sql.Stmt
-	recordViewStmt            *sql.Stmt
-	saveSnippetStmt           *sql.Stmt
-	updateLikesCountStmt      *sql.Stmt
-	updateSessionExpiryStmt   *sql.Stmt
-	updateSnippetStmt         *sql.Stmt
-	updateUserAvatarStmt      *sql.Stmt
-	updateUserInfoStmt        *sql.Stmt
-	updateUserPasswordStmt    *sql.Stmt
+	db                                    DBTX
+	tx                                    *sql.Tx
+	addCollectionCollaboratorStmt         *sql.Stmt
+	addCollectionItemStmt                 *sql.Stmt
+	addViewsStmt                          *sql.Stmt
+	aggregateDailyLikesStmt               *sql.Stmt
+	aggregateDailyViewsStmt               *sql.Stmt
+	burnSnippetStmt                       *sql.Stmt
+	checkLikeExistsStmt                   *sql.Stmt
+	checkRecentViewStmt                   *sql.Stmt
+	cleanupOldViewDaysStmt                *sql.Stmt
+	cleanupOldViewsStmt                   *sql.Stmt
+	countOrganizationSnippetsStmt         *sql.Stmt
+	countUnreadDuplicateNotificationsStmt *sql.Stmt
+	countUnreadNotificationsStmt          *sql.Stmt
+	createBookmarkFolderStmt              *sql.Stmt
+	createCollectionStmt                  *sql.Stmt
+	createOrganizationStmt                *sql.Stmt
+	createSessionStmt                     *sql.Stmt
+	createSnippetStmt                     *sql.Stmt
+	createUserStmt                        *sql.Stmt
+	createWebhookStmt                     *sql.Stmt
+	decrementLikesCountStmt               *sql.Stmt
+	deleteBookmarkFolderStmt              *sql.Stmt
+	deleteCollectionStmt                  *sql.Stmt
+	deleteCollectionCollaboratorsStmt     *sql.Stmt
+	deleteCollectionFollowsStmt           *sql.Stmt
+	deleteCollectionItemsStmt             *sql.Stmt
+	deleteCollectionNotificationsStmt     *sql.Stmt
+	deleteExpiredSessionsStmt             *sql.Stmt
+	deleteExpiredTicketsStmt              *sql.Stmt
+	deleteLikeStmt                        *sql.Stmt
+	deleteOldNotificationsStmt            *sql.Stmt
+	deleteOldWebhookDeliveriesStmt        *sql.Stmt
+	deleteOrganizationStmt                *sql.Stmt
+	deleteOrganizationMembersStmt         *sql.Stmt
+	deleteSavedSnippetStmt                *sql.Stmt
+	deleteSessionStmt                     *sql.Stmt
+	deleteSnippetStmt                     *sql.Stmt
+	deleteSnippetCollaboratorsStmt        *sql.Stmt
+	deleteTrendingScoresStmt              *sql.Stmt
+	deleteWebhookStmt                     *sql.Stmt
+	followCollectionStmt                  *sql.Stmt
+	followUserStmt                        *sql.Stmt
+	getBookmarkFolderStmt                 *sql.Stmt
+	getBookmarkFoldersStmt                *sql.Stmt
+	getCollectionStmt                     *sql.Stmt
+	getCollectionCollaboratorsStmt        *sql.Stmt
+	getCollectionFollowerIDsStmt          *sql.Stmt
+	getCollectionItemIDsStmt              *sql.Stmt
+	getCollectionItemsStmt                *sql.Stmt
+	getDailyStatsStmt                     *sql.Stmt
+	getDueWebhookDeliveriesStmt           *sql.Stmt
+	getExpiredSnippetsStmt                *sql.Stmt
+	getFeedSnippetsStmt                   *sql.Stmt
+	getFollowCountsStmt                   *sql.Stmt
+	getFollowerIDsStmt                    *sql.Stmt
+	getFollowersStmt                      *sql.Stmt
+	getFollowingStmt                      *sql.Stmt
+	getLikedSnippetsStmt                  *sql.Stmt
+	getNextCollectionItemPositionStmt     *sql.Stmt
+	getNotificationStmt                   *sql.Stmt
+	getNotificationPreferenceStmt         *sql.Stmt
+	getNotificationPreferencesStmt        *sql.Stmt
+	getNotificationsStmt                  *sql.Stmt
+	getOrganizationStmt                   *sql.Stmt
+	getOrganizationMemberRoleStmt         *sql.Stmt
+	getOrganizationMembersStmt            *sql.Stmt
+	getPurgeableSnippetsStmt              *sql.Stmt
+	getRecentLikeActivityStmt             *sql.Stmt
+	getRecentSaveActivityStmt             *sql.Stmt
+	getRecentViewActivityStmt             *sql.Stmt
+	getSavedSnippetsStmt                  *sql.Stmt
+	getSessionStmt                        *sql.Stmt
+	getSessionByIDStmt                    *sql.Stmt
+	getSnippetStmt                        *sql.Stmt
+	getSnippetCollaboratorsStmt           *sql.Stmt
+	getSnippetStatsStmt                   *sql.Stmt
+	getSnippetsStmt                       *sql.Stmt
+	getSnippetsByAuthorStmt               *sql.Stmt
+	getSnippetsByOrganizationStmt         *sql.Stmt
+	getSubscribedWebhooksStmt             *sql.Stmt
+	getTrashedSnippetStmt                 *sql.Stmt
+	getTrashedSnippetsStmt                *sql.Stmt
+	getTrendingSnippetsStmt               *sql.Stmt
+	getUserStmt                           *sql.Stmt
+	getUserByEmailStmt                    *sql.Stmt
+	getUserByUsernameStmt                 *sql.Stmt
+	getUserCollectionsStmt                *sql.Stmt
+	getUserOrganizationsStmt              *sql.Stmt
+	getWebhookStmt                        *sql.Stmt
+	getWebhookDeliveriesStmt              *sql.Stmt
+	getWebhookDeliveryStmt                *sql.Stmt
+	getWebhooksByOwnerStmt                *sql.Stmt
+	incrementLikesCountStmt               *sql.Stmt
+	incrementViewsStmt                    *sql.Stmt
+	insertNotificationStmt                *sql.Stmt
+	insertTrendingScoreStmt               *sql.Stmt
+	insertWebhookDeliveryStmt             *sql.Stmt
+	likeSnippetStmt                       *sql.Stmt
+	markAllNotificationsReadStmt          *sql.Stmt
+	markNotificationReadStmt              *sql.Stmt
+	moveSavedSnippetStmt                  *sql.Stmt
+	recordViewStmt                        *sql.Stmt
+	redeemTicketStmt                      *sql.Stmt
+	removeCollectionCollaboratorStmt      *sql.Stmt
+	removeCollectionItemStmt              *sql.Stmt
+	removeOrganizationMemberStmt          *sql.Stmt
+	removeSnippetCollaboratorStmt         *sql.Stmt
+	renameBookmarkFolderStmt              *sql.Stmt
+	resetDailyLikesStmt                   *sql.Stmt
+	restoreSnippetStmt                    *sql.Stmt
+	saveSnippetStmt                       *sql.Stmt
+	setCollectionItemPositionStmt         *sql.Stmt
+	setOrganizationMemberStmt             *sql.Stmt
+	setSnippetCollaboratorStmt            *sql.Stmt
+	touchCollectionStmt                   *sql.Stmt
+	transferSnippetStmt                   *sql.Stmt
+	trashSnippetStmt                      *sql.Stmt
+	unfileSavedSnippetsStmt               *sql.Stmt
+	unfollowCollectionStmt                *sql.Stmt
+	unfollowUserStmt                      *sql.Stmt
+	updateCollectionStmt                  *sql.Stmt
+	updateLikesCountStmt                  *sql.Stmt
+	updateOrganizationStmt                *sql.Stmt
+	updateSavedSnippetNoteStmt            *sql.Stmt
+	updateSessionExpiryStmt               *sql.Stmt
+	updateSnippetStmt                     *sql.Stmt
+	updateUserAvatarStmt                  *sql.Stmt
+	updateUserInfoStmt                    *sql.Stmt
+	updateUserPasswordStmt                *sql.Stmt
+	updateWebhookStmt                     *sql.Stmt
+	updateWebhookDeliveryStmt             *sql.Stmt
+	upsertNotificationPreferenceStmt      *sql.Stmt
+	upsertViewStmt                        *sql.Stmt
+	upsertViewDayStmt                     *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                        tx,
-		tx:                        tx,
-		checkLikeExistsStmt:       q.checkLikeExistsStmt,
-		checkRecentViewStmt:       q.checkRecentViewStmt,
-		cleanupOldViewsStmt:       q.cleanupOldViewsStmt,
-		createSessionStmt:         q.createSessionStmt,
-		createSnippetStmt:         q.createSnippetStmt,
-		createUserStmt:            q.createUserStmt,
-		decrementLikesCountStmt:   q.decrementLikesCountStmt,
-		deleteExpiredSessionsStmt: q.deleteExpiredSessionsStmt,
-		deleteLikeStmt:            q.deleteLikeStmt,
-		deleteSavedSnippetStmt:    q.deleteSavedSnippetStmt,
-		deleteSessionStmt:         q.deleteSessionStmt,
-		deleteSnippetStmt:         q.deleteSnippetStmt,
-		getLikedSnippetsStmt:      q.getLikedSnippetsStmt,
-		getSavedSnippetsStmt:      q.getSavedSnippetsStmt,
-		getSessionStmt:            q.getSessionStmt,
-		getSnippetStmt:            q.getSnippetStmt,
-		getSnippetsStmt:           q.getSnippetsStmt,
-		getSnippetsByAuthorStmt:   q.getSnippetsByAuthorStmt,
-		getUserStmt:               q.getUserStmt,
-		getUserByEmailStmt:        q.getUserByEmailStmt,
-		getUserByUsernameStmt:     q.getUserByUsernameStmt,
-		incrementLikesCountStmt:   q.incrementLikesCountStmt,
-		incrementViewsStmt:        q.incrementViewsStmt,
-		likeSnippetStmt:           q.likeSnippetStmt,
-		recordViewStmt:            q.recordViewStmt,
-		saveSnippetStmt:           q.saveSnippetStmt,
-		updateLikesCountStmt:      q.updateLikesCountStmt,
-		updateSessionExpiryStmt:   q.updateSessionExpiryStmt,
-		updateSnippetStmt:         q.updateSnippetStmt,
-		updateUserAvatarStmt:      q.updateUserAvatarStmt,
-		updateUserInfoStmt:        q.updateUserInfoStmt,
-		updateUserPasswordStmt:    q.updateUserPasswordStmt,
+		db:                                    tx,
+		tx:                                    tx,
+		addCollectionCollaboratorStmt:         q.addCollectionCollaboratorStmt,
+		addCollectionItemStmt:                 q.addCollectionItemStmt,
+		addViewsStmt:                          q.addViewsStmt,
+		aggregateDailyLikesStmt:               q.aggregateDailyLikesStmt,
+		aggregateDailyViewsStmt:               q.aggregateDailyViewsStmt,
+		burnSnippetStmt:                       q.burnSnippetStmt,
+		checkLikeExistsStmt:                   q.checkLikeExistsStmt,
+		checkRecentViewStmt:                   q.checkRecentViewStmt,
+		cleanupOldViewDaysStmt:                q.cleanupOldViewDaysStmt,
+		cleanupOldViewsStmt:                   q.cleanupOldViewsStmt,
+		countOrganizationSnippetsStmt:         q.countOrganizationSnippetsStmt,
+		countUnreadDuplicateNotificationsStmt: q.countUnreadDuplicateNotificationsStmt,
+		countUnreadNotificationsStmt:          q.countUnreadNotificationsStmt,
+		createBookmarkFolderStmt:              q.createBookmarkFolderStmt,
+		createCollectionStmt:                  q.createCollectionStmt,
+		createOrganizationStmt:                q.createOrganizationStmt,
+		createSessionStmt:                     q.createSessionStmt,
+		createSnippetStmt:                     q.createSnippetStmt,
+		createUserStmt:                        q.createUserStmt,
+		createWebhookStmt:                     q.createWebhookStmt,
+		decrementLikesCountStmt:               q.decrementLikesCountStmt,
+		deleteBookmarkFolderStmt:              q.deleteBookmarkFolderStmt,
+		deleteCollectionStmt:                  q.deleteCollectionStmt,
+		deleteCollectionCollaboratorsStmt:     q.deleteCollectionCollaboratorsStmt,
+		deleteCollectionFollowsStmt:           q.deleteCollectionFollowsStmt,
+		deleteCollectionItemsStmt:             q.deleteCollectionItemsStmt,
+		deleteCollectionNotificationsStmt:     q.deleteCollectionNotificationsStmt,
+		deleteExpiredSessionsStmt:             q.deleteExpiredSessionsStmt,
+		deleteExpiredTicketsStmt:              q.deleteExpiredTicketsStmt,
+		deleteLikeStmt:                        q.deleteLikeStmt,
+		deleteOldNotificationsStmt:            q.deleteOldNotificationsStmt,
+		deleteOldWebhookDeliveriesStmt:        q.deleteOldWebhookDeliveriesStmt,
+		deleteOrganizationStmt:                q.deleteOrganizationStmt,
+		deleteOrganizationMembersStmt:         q.deleteOrganizationMembersStmt,
+		deleteSavedSnippetStmt:                q.deleteSavedSnippetStmt,
+		deleteSessionStmt:                     q.deleteSessionStmt,
+		deleteSnippetStmt:                     q.deleteSnippetStmt,
+		deleteSnippetCollaboratorsStmt:        q.deleteSnippetCollaboratorsStmt,
+		deleteTrendingScoresStmt:              q.deleteTrendingScoresStmt,
+		deleteWebhookStmt:                     q.deleteWebhookStmt,
+		followCollectionStmt:                  q.followCollectionStmt,
+		followUserStmt:                        q.followUserStmt,
+		getBookmarkFolderStmt:                 q.getBookmarkFolderStmt,
+		getBookmarkFoldersStmt:                q.getBookmarkFoldersStmt,
+		getCollectionStmt:                     q.getCollectionStmt,
+		getCollectionCollaboratorsStmt:        q.getCollectionCollaboratorsStmt,
+		getCollectionFollowerIDsStmt:          q.getCollectionFollowerIDsStmt,
+		getCollectionItemIDsStmt:              q.getCollectionItemIDsStmt,
+		getCollectionItemsStmt:                q.getCollectionItemsStmt,
+		getDailyStatsStmt:                     q.getDailyStatsStmt,
+		getDueWebhookDeliveriesStmt:           q.getDueWebhookDeliveriesStmt,
+		getExpiredSnippetsStmt:                q.getExpiredSnippetsStmt,
+		getFeedSnippetsStmt:                   q.getFeedSnippetsStmt,
+		getFollowCountsStmt:                   q.getFollowCountsStmt,
+		getFollowerIDsStmt:                    q.getFollowerIDsStmt,
+		getFollowersStmt:                      q.getFollowersStmt,
+		getFollowingStmt:                      q.getFollowingStmt,
+		getLikedSnippetsStmt:                  q.getLikedSnippetsStmt,
+		getNextCollectionItemPositionStmt:     q.getNextCollectionItemPositionStmt,
+		getNotificationStmt:                   q.getNotificationStmt,
+		getNotificationPreferenceStmt:         q.getNotificationPreferenceStmt,
+		getNotificationPreferencesStmt:        q.getNotificationPreferencesStmt,
+		getNotificationsStmt:                  q.getNotificationsStmt,
+		getOrganizationStmt:                   q.getOrganizationStmt,
+		getOrganizationMemberRoleStmt:         q.getOrganizationMemberRoleStmt,
+		getOrganizationMembersStmt:            q.getOrganizationMembersStmt,
+		getPurgeableSnippetsStmt:              q.getPurgeableSnippetsStmt,
+		getRecentLikeActivityStmt:             q.getRecentLikeActivityStmt,
+		getRecentSaveActivityStmt:             q.getRecentSaveActivityStmt,
+		getRecentViewActivityStmt:             q.getRecentViewActivityStmt,
+		getSavedSnippetsStmt:                  q.getSavedSnippetsStmt,
+		getSessionStmt:                        q.getSessionStmt,
+		getSessionByIDStmt:                    q.getSessionByIDStmt,
+		getSnippetStmt:                        q.getSnippetStmt,
+		getSnippetCollaboratorsStmt:           q.getSnippetCollaboratorsStmt,
+		getSnippetStatsStmt:                   q.getSnippetStatsStmt,
+		getSnippetsStmt:                       q.getSnippetsStmt,
+		getSnippetsByAuthorStmt:               q.getSnippetsByAuthorStmt,
+		getSnippetsByOrganizationStmt:         q.getSnippetsByOrganizationStmt,
+		getSubscribedWebhooksStmt:             q.getSubscribedWebhooksStmt,
+		getTrashedSnippetStmt:                 q.getTrashedSnippetStmt,
+		getTrashedSnippetsStmt:                q.getTrashedSnippetsStmt,
+		getTrendingSnippetsStmt:               q.getTrendingSnippetsStmt,
+		getUserStmt:                           q.getUserStmt,
+		getUserByEmailStmt:                    q.getUserByEmailStmt,
+		getUserByUsernameStmt:                 q.getUserByUsernameStmt,
+		getUserCollectionsStmt:                q.getUserCollectionsStmt,
+		getUserOrganizationsStmt:              q.getUserOrganizationsStmt,
+		getWebhookStmt:                        q.getWebhookStmt,
+		getWebhookDeliveriesStmt:              q.getWebhookDeliveriesStmt,
+		getWebhookDeliveryStmt:                q.getWebhookDeliveryStmt,
+		getWebhooksByOwnerStmt:                q.getWebhooksByOwnerStmt,
+		incrementLikesCountStmt:               q.incrementLikesCountStmt,
+		incrementViewsStmt:                    q.incrementViewsStmt,
+		insertNotificationStmt:                q.insertNotificationStmt,
+		insertTrendingScoreStmt:               q.insertTrendingScoreStmt,
+		insertWebhookDeliveryStmt:             q.insertWebhookDeliveryStmt,
+		likeSnippetStmt:                       q.likeSnippetStmt,
+		markAllNotificationsReadStmt:          q.markAllNotificationsReadStmt,
+		markNotificationReadStmt:              q.markNotificationReadStmt,
+		moveSavedSnippetStmt:                  q.moveSavedSnippetStmt,
+		recordViewStmt:                        q.recordViewStmt,
+		redeemTicketStmt:                      q.redeemTicketStmt,
+		removeCollectionCollaboratorStmt:      q.removeCollectionCollaboratorStmt,
+		removeCollectionItemStmt:              q.removeCollectionItemStmt,
+		removeOrganizationMemberStmt:          q.removeOrganizationMemberStmt,
+		removeSnippetCollaboratorStmt:         q.removeSnippetCollaboratorStmt,
+		renameBookmarkFolderStmt:              q.renameBookmarkFolderStmt,
+		resetDailyLikesStmt:                   q.resetDailyLikesStmt,
+		restoreSnippetStmt:                    q.restoreSnippetStmt,
+		saveSnippetStmt:                       q.saveSnippetStmt,
+		setCollectionItemPositionStmt:         q.setCollectionItemPositionStmt,
+		setOrganizationMemberStmt:             q.setOrganizationMemberStmt,
+		setSnippetCollaboratorStmt:            q.setSnippetCollaboratorStmt,
+		touchCollectionStmt:                   q.touchCollectionStmt,
+		transferSnippetStmt:                   q.transferSnippetStmt,
+		trashSnippetStmt:                      q.trashSnippetStmt,
+		unfileSavedSnippetsStmt:               q.unfileSavedSnippetsStmt,
+		unfollowCollectionStmt:                q.unfollowCollectionStmt,
+		unfollowUserStmt:                      q.unfollowUserStmt,
+		updateCollectionStmt:                  q.updateCollectionStmt,
+		updateLikesCountStmt:                  q.updateLikesCountStmt,
+		updateOrganizationStmt:                q.updateOrganizationStmt,
+		updateSavedSnippetNoteStmt:            q.updateSavedSnippetNoteStmt,
+		updateSessionExpiryStmt:               q.updateSessionExpiryStmt,
+		updateSnippetStmt:                     q.updateSnippetStmt,
+		updateUserAvatarStmt:                  q.updateUserAvatarStmt,
+		updateUserInfoStmt:                    q.updateUserInfoStmt,
+		updateUserPasswordStmt:                q.updateUserPasswordStmt,
+		updateWebhookStmt:                     q.updateWebhookStmt,
+		updateWebhookDeliveryStmt:             q.updateWebhookDeliveryStmt,
+		upsertNotificationPreferenceStmt:      q.upsertNotificationPreferenceStmt,
+		upsertViewStmt:                        q.upsertViewStmt,
+		upsertViewDayStmt:                     q.upsertViewDayStmt,
 	}
 }
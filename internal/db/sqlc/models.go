@@ -9,6 +9,94 @@ import (
 	"time"
 )
 
+type BookmarkFolder struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Collection struct {
+	ID          string    `json:"id"`
+	OwnerID     string    `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CollectionCollaborator struct {
+	CollectionID string    `json:"collection_id"`
+	UserID       string    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CollectionFollow struct {
+	CollectionID string    `json:"collection_id"`
+	UserID       string    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CollectionItem struct {
+	CollectionID string    `json:"collection_id"`
+	SnippetID    string    `json:"snippet_id"`
+	Position     int64     `json:"position"`
+	AddedBy      string    `json:"added_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Follow struct {
+	FollowerID string    `json:"follower_id"`
+	FolloweeID string    `json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Notification struct {
+	ID           string         `json:"id"`
+	UserID       string         `json:"user_id"`
+	ActorID      string         `json:"actor_id"`
+	Type         string         `json:"type"`
+	SnippetID    sql.NullString `json:"snippet_id"`
+	ReadAt       sql.NullTime   `json:"read_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	CollectionID sql.NullString `json:"collection_id"`
+}
+
+type NotificationPreference struct {
+	UserID  string `json:"user_id"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+type Organization struct {
+	ID          string    `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RedeemedTicket struct {
+	ID        string `json:"id"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type SchemaMigration struct {
+	Version     int64     `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
 type Session struct {
 	ID           string    `json:"id"`
 	UserID       string    `json:"user_id"`
@@ -19,15 +107,45 @@ type Session struct {
 }
 
 type Snippet struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Language  string    `json:"language"`
-	Content   string    `json:"content"`
-	Author    string    `json:"author"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Likes     int64     `json:"likes"`
-	Views     int64     `json:"views"`
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Language       string         `json:"language"`
+	Content        string         `json:"content"`
+	Author         string         `json:"author"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Likes          int64          `json:"likes"`
+	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
+}
+
+type SnippetCollaborator struct {
+	SnippetID  string    `json:"snippet_id"`
+	UserID     string    `json:"user_id"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type SnippetDailyStat struct {
+	SnippetID     string `json:"snippet_id"`
+	Day           string `json:"day"`
+	Views         int64  `json:"views"`
+	UniqueViewers int64  `json:"unique_viewers"`
+	Likes         int64  `json:"likes"`
+	BotViews      int64  `json:"bot_views"`
+}
+
+type SnippetTrendingScore struct {
+	Period     string    `json:"period"`
+	SnippetID  string    `json:"snippet_id"`
+	Score      float64   `json:"score"`
+	ComputedAt time.Time `json:"computed_at"`
 }
 
 type SnippetView struct {
@@ -38,6 +156,15 @@ type SnippetView struct {
 	ViewCount        sql.NullInt64  `json:"view_count"`
 }
 
+type SnippetViewDay struct {
+	SnippetID        string `json:"snippet_id"`
+	ViewerIdentifier string `json:"viewer_identifier"`
+	Day              string `json:"day"`
+	Hits             int64  `json:"hits"`
+	CountedViews     int64  `json:"counted_views"`
+	BotHits          int64  `json:"bot_hits"`
+}
+
 type User struct {
 	ID           string         `json:"id"`
 	Username     string         `json:"username"`
@@ -55,7 +182,35 @@ type UserLike struct {
 }
 
 type UserSafe struct {
-	SnippetID string    `json:"snippet_id"`
-	UserID    string    `json:"user_id"`
+	SnippetID string         `json:"snippet_id"`
+	UserID    string         `json:"user_id"`
+	CreatedAt time.Time      `json:"created_at"`
+	FolderID  sql.NullString `json:"folder_id"`
+	Note      string         `json:"note"`
+}
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"owner_id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    string    `json:"events"`
+	Global    bool      `json:"global"`
+	Active    bool      `json:"active"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string         `json:"id"`
+	WebhookID      string         `json:"webhook_id"`
+	Event          string         `json:"event"`
+	Payload        string         `json:"payload"`
+	Status         string         `json:"status"`
+	Attempts       int64          `json:"attempts"`
+	NextAttemptAt  int64          `json:"next_attempt_at"`
+	ResponseStatus sql.NullInt64  `json:"response_status"`
+	LastError      sql.NullString `json:"last_error"`
+	CreatedAt      time.Time      `json:"created_at"`
+	CompletedAt    sql.NullTime   `json:"completed_at"`
 }
@@ -6,41 +6,154 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	AddCollectionCollaborator(ctx context.Context, arg AddCollectionCollaboratorParams) error
+	AddCollectionItem(ctx context.Context, arg AddCollectionItemParams) (int64, error)
+	AddViews(ctx context.Context, arg AddViewsParams) error
+	AggregateDailyLikes(ctx context.Context) error
+	AggregateDailyViews(ctx context.Context) error
+	BurnSnippet(ctx context.Context, id string) (int64, error)
 	CheckLikeExists(ctx context.Context, arg CheckLikeExistsParams) (int64, error)
 	CheckRecentView(ctx context.Context, arg CheckRecentViewParams) (CheckRecentViewRow, error)
+	CleanupOldViewDays(ctx context.Context) error
 	CleanupOldViews(ctx context.Context) error
+	// Counts trashed snippets as well, deleting the organization would orphan them
+	CountOrganizationSnippets(ctx context.Context, organizationID sql.NullString) (int64, error)
+	CountUnreadDuplicateNotifications(ctx context.Context, arg CountUnreadDuplicateNotificationsParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int64, error)
+	CreateBookmarkFolder(ctx context.Context, arg CreateBookmarkFolderParams) error
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) error
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateSnippet(ctx context.Context, arg CreateSnippetParams) (Snippet, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
 	DecrementLikesCount(ctx context.Context, id string) error
+	DeleteBookmarkFolder(ctx context.Context, arg DeleteBookmarkFolderParams) (int64, error)
+	DeleteCollection(ctx context.Context, id string) (int64, error)
+	DeleteCollectionCollaborators(ctx context.Context, collectionID string) error
+	DeleteCollectionFollows(ctx context.Context, collectionID string) error
+	DeleteCollectionItems(ctx context.Context, collectionID string) error
+	DeleteCollectionNotifications(ctx context.Context, collectionID sql.NullString) error
 	DeleteExpiredSessions(ctx context.Context) error
+	DeleteExpiredTickets(ctx context.Context) error
 	DeleteLike(ctx context.Context, arg DeleteLikeParams) error
-	DeleteSavedSnippet(ctx context.Context, arg DeleteSavedSnippetParams) error
+	DeleteOldNotifications(ctx context.Context) error
+	DeleteOldWebhookDeliveries(ctx context.Context) error
+	DeleteOrganization(ctx context.Context, id string) (int64, error)
+	DeleteOrganizationMembers(ctx context.Context, organizationID string) error
+	DeleteSavedSnippet(ctx context.Context, arg DeleteSavedSnippetParams) (int64, error)
 	DeleteSession(ctx context.Context, token string) error
 	DeleteSnippet(ctx context.Context, id string) error
+	DeleteSnippetCollaborators(ctx context.Context, snippetID string) error
+	DeleteTrendingScores(ctx context.Context, period string) error
+	DeleteWebhook(ctx context.Context, id string) error
+	FollowCollection(ctx context.Context, arg FollowCollectionParams) error
+	FollowUser(ctx context.Context, arg FollowUserParams) error
+	GetBookmarkFolder(ctx context.Context, arg GetBookmarkFolderParams) (GetBookmarkFolderRow, error)
+	GetBookmarkFolders(ctx context.Context, userID string) ([]GetBookmarkFoldersRow, error)
+	GetCollection(ctx context.Context, arg GetCollectionParams) (GetCollectionRow, error)
+	GetCollectionCollaborators(ctx context.Context, collectionID string) ([]User, error)
+	GetCollectionFollowerIDs(ctx context.Context, collectionID string) ([]string, error)
+	GetCollectionItemIDs(ctx context.Context, collectionID string) ([]string, error)
+	GetCollectionItems(ctx context.Context, arg GetCollectionItemsParams) ([]GetCollectionItemsRow, error)
+	GetDailyStats(ctx context.Context, arg GetDailyStatsParams) ([]GetDailyStatsRow, error)
+	GetDueWebhookDeliveries(ctx context.Context, arg GetDueWebhookDeliveriesParams) ([]GetDueWebhookDeliveriesRow, error)
+	// Trashed snippets are left to the trash purge
+	GetExpiredSnippets(ctx context.Context) ([]GetExpiredSnippetsRow, error)
+	GetFeedSnippets(ctx context.Context, arg GetFeedSnippetsParams) ([]GetFeedSnippetsRow, error)
+	GetFollowCounts(ctx context.Context, userID string) (GetFollowCountsRow, error)
+	GetFollowerIDs(ctx context.Context, followeeID string) ([]string, error)
+	GetFollowers(ctx context.Context, followeeID string) ([]User, error)
+	GetFollowing(ctx context.Context, followerID string) ([]User, error)
 	GetLikedSnippets(ctx context.Context, userID string) ([]GetLikedSnippetsRow, error)
-	GetSavedSnippets(ctx context.Context, userID string) ([]GetSavedSnippetsRow, error)
+	GetNextCollectionItemPosition(ctx context.Context, collectionID string) (int64, error)
+	GetNotification(ctx context.Context, id string) (GetNotificationRow, error)
+	GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (bool, error)
+	GetNotificationPreferences(ctx context.Context, userID string) ([]GetNotificationPreferencesRow, error)
+	GetNotifications(ctx context.Context, arg GetNotificationsParams) ([]GetNotificationsRow, error)
+	// Looks an organization up by ID or slug, with the role of the requesting user
+	GetOrganization(ctx context.Context, arg GetOrganizationParams) (GetOrganizationRow, error)
+	GetOrganizationMemberRole(ctx context.Context, arg GetOrganizationMemberRoleParams) (string, error)
+	GetOrganizationMembers(ctx context.Context, organizationID string) ([]GetOrganizationMembersRow, error)
+	GetPurgeableSnippets(ctx context.Context, cutoff sql.NullInt64) ([]GetPurgeableSnippetsRow, error)
+	GetRecentLikeActivity(ctx context.Context) ([]GetRecentLikeActivityRow, error)
+	GetRecentSaveActivity(ctx context.Context) ([]GetRecentSaveActivityRow, error)
+	GetRecentViewActivity(ctx context.Context) ([]GetRecentViewActivityRow, error)
+	// Newest saves first. Optionally restricted to one folder (NULL folder_id
+	// for unfiled saves) and to saves whose title, content or note contain the
+	// search text, which must have LIKE wildcards escaped with a backslash.
+	GetSavedSnippets(ctx context.Context, arg GetSavedSnippetsParams) ([]GetSavedSnippetsRow, error)
 	GetSession(ctx context.Context, token string) (Session, error)
+	GetSessionByID(ctx context.Context, id string) (Session, error)
 	GetSnippet(ctx context.Context, arg GetSnippetParams) (GetSnippetRow, error)
+	GetSnippetCollaborators(ctx context.Context, snippetID string) ([]GetSnippetCollaboratorsRow, error)
+	GetSnippetStats(ctx context.Context, snippetID string) (GetSnippetStatsRow, error)
 	GetSnippets(ctx context.Context, userID string) ([]GetSnippetsRow, error)
 	GetSnippetsByAuthor(ctx context.Context, arg GetSnippetsByAuthorParams) ([]GetSnippetsByAuthorRow, error)
+	GetSnippetsByOrganization(ctx context.Context, arg GetSnippetsByOrganizationParams) ([]GetSnippetsByOrganizationRow, error)
+	GetSubscribedWebhooks(ctx context.Context, arg GetSubscribedWebhooksParams) ([]Webhook, error)
+	GetTrashedSnippet(ctx context.Context, arg GetTrashedSnippetParams) (GetTrashedSnippetRow, error)
+	// Snippets in the trash that the user owns
+	GetTrashedSnippets(ctx context.Context, userID string) ([]GetTrashedSnippetsRow, error)
+	GetTrendingSnippets(ctx context.Context, arg GetTrendingSnippetsParams) ([]GetTrendingSnippetsRow, error)
 	GetUser(ctx context.Context, id string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetUserCollections(ctx context.Context, userID string) ([]GetUserCollectionsRow, error)
+	GetUserOrganizations(ctx context.Context, userID string) ([]GetUserOrganizationsRow, error)
+	GetWebhook(ctx context.Context, id string) (Webhook, error)
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id string) (WebhookDelivery, error)
+	GetWebhooksByOwner(ctx context.Context, ownerID string) ([]Webhook, error)
 	IncrementLikesCount(ctx context.Context, id string) error
 	IncrementViews(ctx context.Context, snippetID string) error
+	InsertNotification(ctx context.Context, arg InsertNotificationParams) error
+	InsertTrendingScore(ctx context.Context, arg InsertTrendingScoreParams) error
+	InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) error
 	LikeSnippet(ctx context.Context, arg LikeSnippetParams) error
+	MarkAllNotificationsRead(ctx context.Context, userID string) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MoveSavedSnippet(ctx context.Context, arg MoveSavedSnippetParams) (int64, error)
 	RecordView(ctx context.Context, arg RecordViewParams) error
+	RedeemTicket(ctx context.Context, arg RedeemTicketParams) (int64, error)
+	RemoveCollectionCollaborator(ctx context.Context, arg RemoveCollectionCollaboratorParams) (int64, error)
+	RemoveCollectionItem(ctx context.Context, arg RemoveCollectionItemParams) (int64, error)
+	RemoveOrganizationMember(ctx context.Context, arg RemoveOrganizationMemberParams) (int64, error)
+	RemoveSnippetCollaborator(ctx context.Context, arg RemoveSnippetCollaboratorParams) (int64, error)
+	RenameBookmarkFolder(ctx context.Context, arg RenameBookmarkFolderParams) (int64, error)
+	// Clears the likes of the window AggregateDailyLikes recomputes, so days whose likes
+	// were all taken back drop to zero
+	ResetDailyLikes(ctx context.Context) error
+	RestoreSnippet(ctx context.Context, id string) (int64, error)
 	SaveSnippet(ctx context.Context, arg SaveSnippetParams) error
+	SetCollectionItemPosition(ctx context.Context, arg SetCollectionItemPositionParams) error
+	SetOrganizationMember(ctx context.Context, arg SetOrganizationMemberParams) error
+	SetSnippetCollaborator(ctx context.Context, arg SetSnippetCollaboratorParams) error
+	TouchCollection(ctx context.Context, id string) error
+	// Moves a personal snippet into an organization
+	TransferSnippet(ctx context.Context, arg TransferSnippetParams) (int64, error)
+	TrashSnippet(ctx context.Context, id string) (int64, error)
+	UnfileSavedSnippets(ctx context.Context, folderID sql.NullString) error
+	UnfollowCollection(ctx context.Context, arg UnfollowCollectionParams) error
+	UnfollowUser(ctx context.Context, arg UnfollowUserParams) error
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (int64, error)
 	UpdateLikesCount(ctx context.Context, arg UpdateLikesCountParams) error
+	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (int64, error)
+	UpdateSavedSnippetNote(ctx context.Context, arg UpdateSavedSnippetNoteParams) (int64, error)
 	UpdateSessionExpiry(ctx context.Context, arg UpdateSessionExpiryParams) error
 	UpdateSnippet(ctx context.Context, arg UpdateSnippetParams) (Snippet, error)
 	UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (User, error)
 	UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) error
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) error
+	UpsertView(ctx context.Context, arg UpsertViewParams) error
+	UpsertViewDay(ctx context.Context, arg UpsertViewDayParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -59,6 +59,16 @@ func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
 	return err
 }
 
+const deleteExpiredTickets = `-- name: DeleteExpiredTickets :exec
+DELETE FROM redeemed_tickets
+WHERE expires_at < unixepoch()
+`
+
+func (q *Queries) DeleteExpiredTickets(ctx context.Context) error {
+	_, err := q.exec(ctx, q.deleteExpiredTicketsStmt, deleteExpiredTickets)
+	return err
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM sessions
 WHERE token = ?
@@ -88,6 +98,44 @@ func (q *Queries) GetSession(ctx context.Context, token string) (Session, error)
 	return i, err
 }
 
+const getSessionByID = `-- name: GetSessionByID :one
+SELECT id, user_id, token, refresh_token, expires_at, created_at FROM sessions
+WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error) {
+	row := q.queryRow(ctx, q.getSessionByIDStmt, getSessionByID, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.RefreshToken,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const redeemTicket = `-- name: RedeemTicket :execrows
+INSERT INTO redeemed_tickets (id, expires_at)
+VALUES (?, ?)
+ON CONFLICT (id) DO NOTHING
+`
+
+type RedeemTicketParams struct {
+	ID        string `json:"id"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func (q *Queries) RedeemTicket(ctx context.Context, arg RedeemTicketParams) (int64, error) {
+	result, err := q.exec(ctx, q.redeemTicketStmt, redeemTicket, arg.ID, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateSessionExpiry = `-- name: UpdateSessionExpiry :exec
 UPDATE sessions
 SET expires_at = ?,
@@ -11,6 +11,35 @@ import (
 	"time"
 )
 
+const addViews = `-- name: AddViews :exec
+UPDATE snippets
+SET views = views + ?1
+WHERE id = ?2
+`
+
+type AddViewsParams struct {
+	Count     int64  `json:"count"`
+	SnippetID string `json:"snippet_id"`
+}
+
+func (q *Queries) AddViews(ctx context.Context, arg AddViewsParams) error {
+	_, err := q.exec(ctx, q.addViewsStmt, addViews, arg.Count, arg.SnippetID)
+	return err
+}
+
+const burnSnippet = `-- name: BurnSnippet :execrows
+DELETE FROM snippets
+WHERE id = ? AND burn_after_read AND deleted_at IS NULL
+`
+
+func (q *Queries) BurnSnippet(ctx context.Context, id string) (int64, error) {
+	result, err := q.exec(ctx, q.burnSnippetStmt, burnSnippet, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const checkRecentView = `-- name: CheckRecentView :one
 SELECT 
     snippet_id,
@@ -63,19 +92,29 @@ INSERT INTO snippets (
     title,
     content,
     language,
-    author
+    author,
+    organization_id,
+    visibility,
+    expires_at,
+    burn_after_read,
+    password_hash
 ) VALUES (
-    ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, title, language, content, author, created_at, updated_at, likes, views
+RETURNING id, title, language, content, author, created_at, updated_at, likes, views, organization_id, visibility, updated_by, expires_at, burn_after_read, password_hash, deleted_at
 `
 
 type CreateSnippetParams struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	Language string `json:"language"`
-	Author   string `json:"author"`
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Content        string         `json:"content"`
+	Language       string         `json:"language"`
+	Author         string         `json:"author"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
 }
 
 func (q *Queries) CreateSnippet(ctx context.Context, arg CreateSnippetParams) (Snippet, error) {
@@ -85,6 +124,11 @@ func (q *Queries) CreateSnippet(ctx context.Context, arg CreateSnippetParams) (S
 		arg.Content,
 		arg.Language,
 		arg.Author,
+		arg.OrganizationID,
+		arg.Visibility,
+		arg.ExpiresAt,
+		arg.BurnAfterRead,
+		arg.PasswordHash,
 	)
 	var i Snippet
 	err := row.Scan(
@@ -97,6 +141,13 @@ func (q *Queries) CreateSnippet(ctx context.Context, arg CreateSnippetParams) (S
 		&i.UpdatedAt,
 		&i.Likes,
 		&i.Views,
+		&i.OrganizationID,
+		&i.Visibility,
+		&i.UpdatedBy,
+		&i.ExpiresAt,
+		&i.BurnAfterRead,
+		&i.PasswordHash,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -111,20 +162,97 @@ func (q *Queries) DeleteSnippet(ctx context.Context, id string) error {
 	return err
 }
 
+const getExpiredSnippets = `-- name: GetExpiredSnippets :many
+SELECT id, author, language
+FROM snippets
+WHERE expires_at <= unixepoch() AND deleted_at IS NULL
+`
+
+type GetExpiredSnippetsRow struct {
+	ID       string `json:"id"`
+	Author   string `json:"author"`
+	Language string `json:"language"`
+}
+
+// Trashed snippets are left to the trash purge
+func (q *Queries) GetExpiredSnippets(ctx context.Context) ([]GetExpiredSnippetsRow, error) {
+	rows, err := q.query(ctx, q.getExpiredSnippetsStmt, getExpiredSnippets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetExpiredSnippetsRow{}
+	for rows.Next() {
+		var i GetExpiredSnippetsRow
+		if err := rows.Scan(&i.ID, &i.Author, &i.Language); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPurgeableSnippets = `-- name: GetPurgeableSnippets :many
+SELECT id, author, language
+FROM snippets
+WHERE deleted_at <= ?1
+`
+
+type GetPurgeableSnippetsRow struct {
+	ID       string `json:"id"`
+	Author   string `json:"author"`
+	Language string `json:"language"`
+}
+
+func (q *Queries) GetPurgeableSnippets(ctx context.Context, cutoff sql.NullInt64) ([]GetPurgeableSnippetsRow, error) {
+	rows, err := q.query(ctx, q.getPurgeableSnippetsStmt, getPurgeableSnippets, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPurgeableSnippetsRow{}
+	for rows.Next() {
+		var i GetPurgeableSnippetsRow
+		if err := rows.Scan(&i.ID, &i.Author, &i.Language); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSnippet = `-- name: GetSnippet :one
 SELECT 
-    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views,
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     u.id AS author_id, 
     u.username AS author_username, 
     u.email AS author_email,
-    u.avatar AS author_avatar
+    u.avatar AS author_avatar,
+    COALESCE(om.role, '') AS viewer_role,
+    COALESCE(sc.permission, '') AS viewer_permission,
+    ue.username AS updated_by_username
 FROM snippets s
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = ?1
 LEFT JOIN users u ON s.author = u.id
-WHERE s.id = ?2
+LEFT JOIN organization_members om ON om.organization_id = s.organization_id AND om.user_id = ?1
+LEFT JOIN snippet_collaborators sc ON sc.snippet_id = s.id AND sc.user_id = ?1
+LEFT JOIN users ue ON s.updated_by = ue.id
+WHERE s.id = ?2 AND s.deleted_at IS NULL
 `
 
 type GetSnippetParams struct {
@@ -133,21 +261,31 @@ type GetSnippetParams struct {
 }
 
 type GetSnippetRow struct {
-	ID             string         `json:"id"`
-	Title          string         `json:"title"`
-	Language       string         `json:"language"`
-	Content        string         `json:"content"`
-	Author         string         `json:"author"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	Likes          int64          `json:"likes"`
-	Views          int64          `json:"views"`
-	IsSaved        int64          `json:"is_saved"`
-	IsLiked        int64          `json:"is_liked"`
-	AuthorID       sql.NullString `json:"author_id"`
-	AuthorUsername sql.NullString `json:"author_username"`
-	AuthorEmail    sql.NullString `json:"author_email"`
-	AuthorAvatar   sql.NullString `json:"author_avatar"`
+	ID                string         `json:"id"`
+	Title             string         `json:"title"`
+	Language          string         `json:"language"`
+	Content           string         `json:"content"`
+	Author            string         `json:"author"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	Likes             int64          `json:"likes"`
+	Views             int64          `json:"views"`
+	OrganizationID    sql.NullString `json:"organization_id"`
+	Visibility        string         `json:"visibility"`
+	UpdatedBy         sql.NullString `json:"updated_by"`
+	ExpiresAt         sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead     bool           `json:"burn_after_read"`
+	PasswordHash      sql.NullString `json:"password_hash"`
+	DeletedAt         sql.NullInt64  `json:"deleted_at"`
+	IsSaved           int64          `json:"is_saved"`
+	IsLiked           int64          `json:"is_liked"`
+	AuthorID          sql.NullString `json:"author_id"`
+	AuthorUsername    sql.NullString `json:"author_username"`
+	AuthorEmail       sql.NullString `json:"author_email"`
+	AuthorAvatar      sql.NullString `json:"author_avatar"`
+	ViewerRole        string         `json:"viewer_role"`
+	ViewerPermission  string         `json:"viewer_permission"`
+	UpdatedByUsername sql.NullString `json:"updated_by_username"`
 }
 
 func (q *Queries) GetSnippet(ctx context.Context, arg GetSnippetParams) (GetSnippetRow, error) {
@@ -163,19 +301,47 @@ func (q *Queries) GetSnippet(ctx context.Context, arg GetSnippetParams) (GetSnip
 		&i.UpdatedAt,
 		&i.Likes,
 		&i.Views,
+		&i.OrganizationID,
+		&i.Visibility,
+		&i.UpdatedBy,
+		&i.ExpiresAt,
+		&i.BurnAfterRead,
+		&i.PasswordHash,
+		&i.DeletedAt,
 		&i.IsSaved,
 		&i.IsLiked,
 		&i.AuthorID,
 		&i.AuthorUsername,
 		&i.AuthorEmail,
 		&i.AuthorAvatar,
+		&i.ViewerRole,
+		&i.ViewerPermission,
+		&i.UpdatedByUsername,
 	)
 	return i, err
 }
 
+const getSnippetStats = `-- name: GetSnippetStats :one
+SELECT views, likes
+FROM snippets
+WHERE id = ?1 AND deleted_at IS NULL
+`
+
+type GetSnippetStatsRow struct {
+	Views int64 `json:"views"`
+	Likes int64 `json:"likes"`
+}
+
+func (q *Queries) GetSnippetStats(ctx context.Context, snippetID string) (GetSnippetStatsRow, error) {
+	row := q.queryRow(ctx, q.getSnippetStatsStmt, getSnippetStats, snippetID)
+	var i GetSnippetStatsRow
+	err := row.Scan(&i.Views, &i.Likes)
+	return i, err
+}
+
 const getSnippets = `-- name: GetSnippets :many
 SELECT 
-    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views,
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     u.id AS author_id, 
@@ -186,6 +352,17 @@ FROM snippets s
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = ?1
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
 LEFT JOIN users u ON s.author = u.id
+WHERE (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = ?1
+) OR (s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = ?1)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = ?1)
 ORDER BY s.created_at DESC
 `
 
@@ -199,6 +376,13 @@ type GetSnippetsRow struct {
 	UpdatedAt      time.Time      `json:"updated_at"`
 	Likes          int64          `json:"likes"`
 	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
 	IsSaved        int64          `json:"is_saved"`
 	IsLiked        int64          `json:"is_liked"`
 	AuthorID       sql.NullString `json:"author_id"`
@@ -226,6 +410,13 @@ func (q *Queries) GetSnippets(ctx context.Context, userID string) ([]GetSnippets
 			&i.UpdatedAt,
 			&i.Likes,
 			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
 			&i.IsSaved,
 			&i.IsLiked,
 			&i.AuthorID,
@@ -248,7 +439,7 @@ func (q *Queries) GetSnippets(ctx context.Context, userID string) ([]GetSnippets
 
 const getSnippetsByAuthor = `-- name: GetSnippetsByAuthor :many
 SELECT 
-    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views,
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     u.id AS author_id, 
@@ -260,6 +451,17 @@ LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = ?1
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
 LEFT JOIN users u ON s.author = u.id
 WHERE s.author = ?2
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = ?1
+) OR (s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = ?1)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = ?1)
 ORDER BY s.created_at DESC
 `
 
@@ -278,6 +480,13 @@ type GetSnippetsByAuthorRow struct {
 	UpdatedAt      time.Time      `json:"updated_at"`
 	Likes          int64          `json:"likes"`
 	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
 	IsSaved        int64          `json:"is_saved"`
 	IsLiked        int64          `json:"is_liked"`
 	AuthorID       sql.NullString `json:"author_id"`
@@ -305,6 +514,13 @@ func (q *Queries) GetSnippetsByAuthor(ctx context.Context, arg GetSnippetsByAuth
 			&i.UpdatedAt,
 			&i.Likes,
 			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
 			&i.IsSaved,
 			&i.IsLiked,
 			&i.AuthorID,
@@ -325,6 +541,268 @@ func (q *Queries) GetSnippetsByAuthor(ctx context.Context, arg GetSnippetsByAuth
 	return items, nil
 }
 
+const getSnippetsByOrganization = `-- name: GetSnippetsByOrganization :many
+SELECT 
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
+    CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
+    CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar
+FROM snippets s
+LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = ?1
+LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
+LEFT JOIN users u ON s.author = u.id
+WHERE s.organization_id = ?2
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = ?1
+) OR (s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = ?1)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = ?1)
+ORDER BY s.created_at DESC
+`
+
+type GetSnippetsByOrganizationParams struct {
+	UserID         string         `json:"user_id"`
+	OrganizationID sql.NullString `json:"organization_id"`
+}
+
+type GetSnippetsByOrganizationRow struct {
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Language       string         `json:"language"`
+	Content        string         `json:"content"`
+	Author         string         `json:"author"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Likes          int64          `json:"likes"`
+	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
+	IsSaved        int64          `json:"is_saved"`
+	IsLiked        int64          `json:"is_liked"`
+	AuthorID       sql.NullString `json:"author_id"`
+	AuthorUsername sql.NullString `json:"author_username"`
+	AuthorEmail    sql.NullString `json:"author_email"`
+	AuthorAvatar   sql.NullString `json:"author_avatar"`
+}
+
+func (q *Queries) GetSnippetsByOrganization(ctx context.Context, arg GetSnippetsByOrganizationParams) ([]GetSnippetsByOrganizationRow, error) {
+	rows, err := q.query(ctx, q.getSnippetsByOrganizationStmt, getSnippetsByOrganization, arg.UserID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSnippetsByOrganizationRow{}
+	for rows.Next() {
+		var i GetSnippetsByOrganizationRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Language,
+			&i.Content,
+			&i.Author,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Likes,
+			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
+			&i.IsSaved,
+			&i.IsLiked,
+			&i.AuthorID,
+			&i.AuthorUsername,
+			&i.AuthorEmail,
+			&i.AuthorAvatar,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrashedSnippet = `-- name: GetTrashedSnippet :one
+SELECT 
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar,
+    COALESCE(om.role, '') AS viewer_role
+FROM snippets s
+LEFT JOIN users u ON s.author = u.id
+LEFT JOIN organization_members om ON om.organization_id = s.organization_id AND om.user_id = ?1
+WHERE s.id = ?2 AND s.deleted_at IS NOT NULL
+`
+
+type GetTrashedSnippetParams struct {
+	UserID    string `json:"user_id"`
+	SnippetID string `json:"snippet_id"`
+}
+
+type GetTrashedSnippetRow struct {
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Language       string         `json:"language"`
+	Content        string         `json:"content"`
+	Author         string         `json:"author"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Likes          int64          `json:"likes"`
+	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
+	AuthorID       sql.NullString `json:"author_id"`
+	AuthorUsername sql.NullString `json:"author_username"`
+	AuthorEmail    sql.NullString `json:"author_email"`
+	AuthorAvatar   sql.NullString `json:"author_avatar"`
+	ViewerRole     string         `json:"viewer_role"`
+}
+
+func (q *Queries) GetTrashedSnippet(ctx context.Context, arg GetTrashedSnippetParams) (GetTrashedSnippetRow, error) {
+	row := q.queryRow(ctx, q.getTrashedSnippetStmt, getTrashedSnippet, arg.UserID, arg.SnippetID)
+	var i GetTrashedSnippetRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Language,
+		&i.Content,
+		&i.Author,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Likes,
+		&i.Views,
+		&i.OrganizationID,
+		&i.Visibility,
+		&i.UpdatedBy,
+		&i.ExpiresAt,
+		&i.BurnAfterRead,
+		&i.PasswordHash,
+		&i.DeletedAt,
+		&i.AuthorID,
+		&i.AuthorUsername,
+		&i.AuthorEmail,
+		&i.AuthorAvatar,
+		&i.ViewerRole,
+	)
+	return i, err
+}
+
+const getTrashedSnippets = `-- name: GetTrashedSnippets :many
+SELECT 
+    s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at,
+    u.id AS author_id, 
+    u.username AS author_username, 
+    u.email AS author_email,
+    u.avatar AS author_avatar
+FROM snippets s
+LEFT JOIN users u ON s.author = u.id
+WHERE s.deleted_at IS NOT NULL
+AND ((s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+    AND (om.role IN ('owner', 'maintainer') OR s.author = ?1)
+))
+ORDER BY s.deleted_at DESC
+`
+
+type GetTrashedSnippetsRow struct {
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Language       string         `json:"language"`
+	Content        string         `json:"content"`
+	Author         string         `json:"author"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Likes          int64          `json:"likes"`
+	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
+	AuthorID       sql.NullString `json:"author_id"`
+	AuthorUsername sql.NullString `json:"author_username"`
+	AuthorEmail    sql.NullString `json:"author_email"`
+	AuthorAvatar   sql.NullString `json:"author_avatar"`
+}
+
+// Snippets in the trash that the user owns
+func (q *Queries) GetTrashedSnippets(ctx context.Context, userID string) ([]GetTrashedSnippetsRow, error) {
+	rows, err := q.query(ctx, q.getTrashedSnippetsStmt, getTrashedSnippets, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTrashedSnippetsRow{}
+	for rows.Next() {
+		var i GetTrashedSnippetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Language,
+			&i.Content,
+			&i.Author,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Likes,
+			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
+			&i.AuthorID,
+			&i.AuthorUsername,
+			&i.AuthorEmail,
+			&i.AuthorAvatar,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const incrementViews = `-- name: IncrementViews :exec
 UPDATE snippets
 SET views = views + 1
@@ -374,22 +852,79 @@ func (q *Queries) RecordView(ctx context.Context, arg RecordViewParams) error {
 	return err
 }
 
+const restoreSnippet = `-- name: RestoreSnippet :execrows
+UPDATE snippets
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreSnippet(ctx context.Context, id string) (int64, error) {
+	result, err := q.exec(ctx, q.restoreSnippetStmt, restoreSnippet, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const transferSnippet = `-- name: TransferSnippet :execrows
+UPDATE snippets
+SET 
+    organization_id = ?1,
+    visibility = ?2
+WHERE id = ?3 AND organization_id IS NULL AND deleted_at IS NULL
+`
+
+type TransferSnippetParams struct {
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	SnippetID      string         `json:"snippet_id"`
+}
+
+// Moves a personal snippet into an organization
+func (q *Queries) TransferSnippet(ctx context.Context, arg TransferSnippetParams) (int64, error) {
+	result, err := q.exec(ctx, q.transferSnippetStmt, transferSnippet, arg.OrganizationID, arg.Visibility, arg.SnippetID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashSnippet = `-- name: TrashSnippet :execrows
+UPDATE snippets
+SET deleted_at = unixepoch()
+WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashSnippet(ctx context.Context, id string) (int64, error) {
+	result, err := q.exec(ctx, q.trashSnippetStmt, trashSnippet, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateSnippet = `-- name: UpdateSnippet :one
 UPDATE snippets
 SET 
     title = ?1,
     content = ?2,
     language = ?3,
+    visibility = ?4,
+    updated_by = ?5,
+    password_hash = ?6,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = ?4
-RETURNING id, title, language, content, author, created_at, updated_at, likes, views
+WHERE id = ?7 AND deleted_at IS NULL
+RETURNING id, title, language, content, author, created_at, updated_at, likes, views, organization_id, visibility, updated_by, expires_at, burn_after_read, password_hash, deleted_at
 `
 
 type UpdateSnippetParams struct {
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Language  string `json:"language"`
-	SnippetID string `json:"snippet_id"`
+	Title        string         `json:"title"`
+	Content      string         `json:"content"`
+	Language     string         `json:"language"`
+	Visibility   string         `json:"visibility"`
+	UpdatedBy    sql.NullString `json:"updated_by"`
+	PasswordHash sql.NullString `json:"password_hash"`
+	SnippetID    string         `json:"snippet_id"`
 }
 
 func (q *Queries) UpdateSnippet(ctx context.Context, arg UpdateSnippetParams) (Snippet, error) {
@@ -397,6 +932,9 @@ func (q *Queries) UpdateSnippet(ctx context.Context, arg UpdateSnippetParams) (S
 		arg.Title,
 		arg.Content,
 		arg.Language,
+		arg.Visibility,
+		arg.UpdatedBy,
+		arg.PasswordHash,
 		arg.SnippetID,
 	)
 	var i Snippet
@@ -410,6 +948,52 @@ func (q *Queries) UpdateSnippet(ctx context.Context, arg UpdateSnippetParams) (S
 		&i.UpdatedAt,
 		&i.Likes,
 		&i.Views,
+		&i.OrganizationID,
+		&i.Visibility,
+		&i.UpdatedBy,
+		&i.ExpiresAt,
+		&i.BurnAfterRead,
+		&i.PasswordHash,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const upsertView = `-- name: UpsertView :exec
+INSERT INTO snippet_views (
+    snippet_id,
+    viewer_identifier,
+    ip_address,
+    last_viewed_at,
+    view_count
+) VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5
+)
+ON CONFLICT (snippet_id, viewer_identifier) DO UPDATE SET
+    ip_address = excluded.ip_address,
+    last_viewed_at = excluded.last_viewed_at,
+    view_count = COALESCE(snippet_views.view_count, 0) + excluded.view_count
+`
+
+type UpsertViewParams struct {
+	SnippetID        string         `json:"snippet_id"`
+	ViewerIdentifier string         `json:"viewer_identifier"`
+	IpAddress        sql.NullString `json:"ip_address"`
+	LastViewedAt     sql.NullTime   `json:"last_viewed_at"`
+	Hits             sql.NullInt64  `json:"hits"`
+}
+
+func (q *Queries) UpsertView(ctx context.Context, arg UpsertViewParams) error {
+	_, err := q.exec(ctx, q.upsertViewStmt, upsertView,
+		arg.SnippetID,
+		arg.ViewerIdentifier,
+		arg.IpAddress,
+		arg.LastViewedAt,
+		arg.Hits,
+	)
+	return err
+}
@@ -57,7 +57,7 @@ func (q *Queries) DeleteLike(ctx context.Context, arg DeleteLikeParams) error {
 }
 
 const getLikedSnippets = `-- name: GetLikedSnippets :many
-SELECT s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, 
+SELECT s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at, 
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     u.id AS author_id, 
@@ -69,6 +69,17 @@ LEFT JOIN user_likes ul ON s.id = ul.snippet_id
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
 LEFT JOIN users u ON s.author = u.id
 WHERE ul.user_id = ?1
+AND (s.visibility = 'public' OR EXISTS (
+    SELECT 1 FROM snippet_collaborators sc
+    WHERE sc.snippet_id = s.id AND sc.user_id = ?1
+) OR (s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+    SELECT 1 FROM organization_members om
+    WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+    AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = ?1)
+))
+AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+AND s.deleted_at IS NULL
+AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = ?1)
 ORDER BY ul.created_at DESC
 `
 
@@ -82,6 +93,13 @@ type GetLikedSnippetsRow struct {
 	UpdatedAt      time.Time      `json:"updated_at"`
 	Likes          int64          `json:"likes"`
 	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
 	IsSaved        int64          `json:"is_saved"`
 	IsLiked        int64          `json:"is_liked"`
 	AuthorID       sql.NullString `json:"author_id"`
@@ -109,6 +127,13 @@ func (q *Queries) GetLikedSnippets(ctx context.Context, userID string) ([]GetLik
 			&i.UpdatedAt,
 			&i.Likes,
 			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
 			&i.IsSaved,
 			&i.IsLiked,
 			&i.AuthorID,
@@ -11,7 +11,7 @@ import (
 	"time"
 )
 
-const deleteSavedSnippet = `-- name: DeleteSavedSnippet :exec
+const deleteSavedSnippet = `-- name: DeleteSavedSnippet :execrows
 DELETE FROM user_saves
 WHERE snippet_id = ?1 AND user_id = ?2
 `
@@ -21,27 +21,57 @@ type DeleteSavedSnippetParams struct {
 	UserID    string `json:"user_id"`
 }
 
-func (q *Queries) DeleteSavedSnippet(ctx context.Context, arg DeleteSavedSnippetParams) error {
-	_, err := q.exec(ctx, q.deleteSavedSnippetStmt, deleteSavedSnippet, arg.SnippetID, arg.UserID)
-	return err
+func (q *Queries) DeleteSavedSnippet(ctx context.Context, arg DeleteSavedSnippetParams) (int64, error) {
+	result, err := q.exec(ctx, q.deleteSavedSnippetStmt, deleteSavedSnippet, arg.SnippetID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getSavedSnippets = `-- name: GetSavedSnippets :many
-SELECT s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, 
+SELECT s.id, s.title, s.language, s.content, s.author, s.created_at, s.updated_at, s.likes, s.views, s.organization_id, s.visibility, s.updated_by, s.expires_at, s.burn_after_read, s.password_hash, s.deleted_at, 
     CASE WHEN ul.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked,
     CASE WHEN us.user_id IS NOT NULL THEN 1 ELSE 0 END as is_saved,
     u.id AS author_id, 
     u.username AS author_username, 
     u.email AS author_email,
-    u.avatar AS author_avatar
+    u.avatar AS author_avatar,
+    us.folder_id,
+    us.note,
+    us.created_at AS saved_at
 FROM snippets s
 LEFT JOIN user_likes ul ON s.id = ul.snippet_id AND ul.user_id = ?1
 LEFT JOIN user_saves us ON s.id = us.snippet_id AND us.user_id = ?1
 LEFT JOIN users u ON s.author = u.id
 WHERE us.user_id = ?1
-ORDER BY ul.created_at DESC
+    AND (s.visibility = 'public' OR EXISTS (
+        SELECT 1 FROM snippet_collaborators sc
+        WHERE sc.snippet_id = s.id AND sc.user_id = ?1
+    ) OR (s.organization_id IS NULL AND s.author = ?1) OR EXISTS (
+        SELECT 1 FROM organization_members om
+        WHERE om.organization_id = s.organization_id AND om.user_id = ?1
+        AND (s.visibility = 'organization' OR om.role IN ('owner', 'maintainer') OR s.author = ?1)
+    ))
+    AND (s.expires_at IS NULL OR s.expires_at > unixepoch())
+    AND s.deleted_at IS NULL
+AND s.deleted_at IS NULL
+    AND ((NOT s.burn_after_read AND s.password_hash IS NULL) OR s.author = ?1)
+    AND (NOT CAST(?2 AS BOOLEAN) OR us.folder_id IS ?3)
+    AND (CAST(?4 AS TEXT) = ''
+        OR s.title LIKE '%' || ?4 || '%' ESCAPE '\'
+        OR s.content LIKE '%' || ?4 || '%' ESCAPE '\'
+        OR us.note LIKE '%' || ?4 || '%' ESCAPE '\')
+ORDER BY us.created_at DESC, s.id
 `
 
+type GetSavedSnippetsParams struct {
+	UserID       string         `json:"user_id"`
+	FilterFolder bool           `json:"filter_folder"`
+	FolderID     sql.NullString `json:"folder_id"`
+	Search       string         `json:"search"`
+}
+
 type GetSavedSnippetsRow struct {
 	ID             string         `json:"id"`
 	Title          string         `json:"title"`
@@ -52,16 +82,34 @@ type GetSavedSnippetsRow struct {
 	UpdatedAt      time.Time      `json:"updated_at"`
 	Likes          int64          `json:"likes"`
 	Views          int64          `json:"views"`
+	OrganizationID sql.NullString `json:"organization_id"`
+	Visibility     string         `json:"visibility"`
+	UpdatedBy      sql.NullString `json:"updated_by"`
+	ExpiresAt      sql.NullInt64  `json:"expires_at"`
+	BurnAfterRead  bool           `json:"burn_after_read"`
+	PasswordHash   sql.NullString `json:"password_hash"`
+	DeletedAt      sql.NullInt64  `json:"deleted_at"`
 	IsLiked        int64          `json:"is_liked"`
 	IsSaved        int64          `json:"is_saved"`
 	AuthorID       sql.NullString `json:"author_id"`
 	AuthorUsername sql.NullString `json:"author_username"`
 	AuthorEmail    sql.NullString `json:"author_email"`
 	AuthorAvatar   sql.NullString `json:"author_avatar"`
+	FolderID       sql.NullString `json:"folder_id"`
+	Note           sql.NullString `json:"note"`
+	SavedAt        sql.NullTime   `json:"saved_at"`
 }
 
-func (q *Queries) GetSavedSnippets(ctx context.Context, userID string) ([]GetSavedSnippetsRow, error) {
-	rows, err := q.query(ctx, q.getSavedSnippetsStmt, getSavedSnippets, userID)
+// Newest saves first. Optionally restricted to one folder (NULL folder_id
+// for unfiled saves) and to saves whose title, content or note contain the
+// search text, which must have LIKE wildcards escaped with a backslash.
+func (q *Queries) GetSavedSnippets(ctx context.Context, arg GetSavedSnippetsParams) ([]GetSavedSnippetsRow, error) {
+	rows, err := q.query(ctx, q.getSavedSnippetsStmt, getSavedSnippets,
+		arg.UserID,
+		arg.FilterFolder,
+		arg.FolderID,
+		arg.Search,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -79,12 +127,22 @@ func (q *Queries) GetSavedSnippets(ctx context.Context, userID string) ([]GetSav
 			&i.UpdatedAt,
 			&i.Likes,
 			&i.Views,
+			&i.OrganizationID,
+			&i.Visibility,
+			&i.UpdatedBy,
+			&i.ExpiresAt,
+			&i.BurnAfterRead,
+			&i.PasswordHash,
+			&i.DeletedAt,
 			&i.IsLiked,
 			&i.IsSaved,
 			&i.AuthorID,
 			&i.AuthorUsername,
 			&i.AuthorEmail,
 			&i.AuthorAvatar,
+			&i.FolderID,
+			&i.Note,
+			&i.SavedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -99,6 +157,26 @@ func (q *Queries) GetSavedSnippets(ctx context.Context, userID string) ([]GetSav
 	return items, nil
 }
 
+const moveSavedSnippet = `-- name: MoveSavedSnippet :execrows
+UPDATE user_saves
+SET folder_id = ?1
+WHERE snippet_id = ?2 AND user_id = ?3
+`
+
+type MoveSavedSnippetParams struct {
+	FolderID  sql.NullString `json:"folder_id"`
+	SnippetID string         `json:"snippet_id"`
+	UserID    string         `json:"user_id"`
+}
+
+func (q *Queries) MoveSavedSnippet(ctx context.Context, arg MoveSavedSnippetParams) (int64, error) {
+	result, err := q.exec(ctx, q.moveSavedSnippetStmt, moveSavedSnippet, arg.FolderID, arg.SnippetID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const saveSnippet = `-- name: SaveSnippet :exec
 INSERT OR IGNORE INTO user_saves (snippet_id, user_id)
 VALUES (?1, ?2)
@@ -113,3 +191,34 @@ func (q *Queries) SaveSnippet(ctx context.Context, arg SaveSnippetParams) error
 	_, err := q.exec(ctx, q.saveSnippetStmt, saveSnippet, arg.SnippetID, arg.UserID)
 	return err
 }
+
+const unfileSavedSnippets = `-- name: UnfileSavedSnippets :exec
+UPDATE user_saves
+SET folder_id = NULL
+WHERE folder_id = ?1
+`
+
+func (q *Queries) UnfileSavedSnippets(ctx context.Context, folderID sql.NullString) error {
+	_, err := q.exec(ctx, q.unfileSavedSnippetsStmt, unfileSavedSnippets, folderID)
+	return err
+}
+
+const updateSavedSnippetNote = `-- name: UpdateSavedSnippetNote :execrows
+UPDATE user_saves
+SET note = ?1
+WHERE snippet_id = ?2 AND user_id = ?3
+`
+
+type UpdateSavedSnippetNoteParams struct {
+	Note      string `json:"note"`
+	SnippetID string `json:"snippet_id"`
+	UserID    string `json:"user_id"`
+}
+
+func (q *Queries) UpdateSavedSnippetNote(ctx context.Context, arg UpdateSavedSnippetNoteParams) (int64, error) {
+	result, err := q.exec(ctx, q.updateSavedSnippetNoteStmt, updateSavedSnippetNote, arg.Note, arg.SnippetID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// SnippetStatsPoint holds aggregated analytics of a snippet for one time bucket
+type SnippetStatsPoint struct {
+	Start         time.Time // First day of the bucket (UTC)
+	Views         int
+	UniqueViewers int
+	Likes         int
+	BotViews      int // View attempts by bots and crawlers, never counted publicly
+}
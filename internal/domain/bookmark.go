@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Bookmark is a snippet saved by a user, together with how they organized it
+type Bookmark struct {
+	Snippet  *Snippet
+	FolderID *string // nil for unfiled bookmarks
+	Note     string  // Private, only visible to the user who saved the snippet
+	SavedAt  time.Time
+}
+
+// BookmarkFolder is a private folder to organize saved snippets
+type BookmarkFolder struct {
+	ID        string
+	UserID    string
+	Name      string
+	ItemCount int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type CollectionVisibility string
+
+const (
+	CollectionPublic  CollectionVisibility = "public"  // Anyone with the link can view it
+	CollectionPrivate CollectionVisibility = "private" // Only the owner and collaborators can view it
+)
+
+// IsValid reports whether v is a known collection visibility
+func (v CollectionVisibility) IsValid() bool {
+	return v == CollectionPublic || v == CollectionPrivate
+}
+
+// Collection is a named set of snippets of any author, curated by its owner and collaborators
+type Collection struct {
+	ID            string
+	Owner         *User
+	Name          string
+	Description   string
+	Visibility    CollectionVisibility
+	Collaborators []*User // Only loaded for single collections
+	ItemCount     int
+	Followers     int
+	IsFollowing   bool // Whether the requesting user follows the collection
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// IsCollaborator reports whether the user may add, remove and reorder items
+func (c *Collection) IsCollaborator(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if c.Owner.ID == userID {
+		return true
+	}
+	for _, collaborator := range c.Collaborators {
+		if collaborator.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanView reports whether the user may see the collection and its items
+func (c *Collection) CanView(userID string) bool {
+	return c.Visibility == CollectionPublic || c.IsCollaborator(userID)
+}
+
+// CollectionItem is a snippet in a collection
+type CollectionItem struct {
+	Snippet  *Snippet
+	Position int
+	AddedBy  string
+	AddedAt  time.Time
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationLike   NotificationType = "like"
+	NotificationSave   NotificationType = "save"
+	NotificationFollow NotificationType = "follow"
+
+	// NotificationCollectionItem tells followers of a collection that a snippet was added
+	NotificationCollectionItem NotificationType = "collection_item"
+)
+
+// NotificationTypes lists all notification types a user can mute
+var NotificationTypes = []NotificationType{
+	NotificationLike,
+	NotificationSave,
+	NotificationFollow,
+	NotificationCollectionItem,
+}
+
+// IsValid reports whether t is a known notification type
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Notification struct {
+	ID             string
+	UserID         string // Recipient
+	Type           NotificationType
+	Actor          *User
+	SnippetID      *string // Not set for follow notifications
+	SnippetTitle   *string
+	CollectionID   *string // Only set for collection item notifications
+	CollectionName *string
+	ReadAt         *time.Time
+	CreatedAt      time.Time
+}
+
+type NotificationPreference struct {
+	Type    NotificationType
+	Enabled bool
+}
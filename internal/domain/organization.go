@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+type OrganizationRole string
+
+const (
+	RoleOwner      OrganizationRole = "owner"      // Manages members, settings and every snippet
+	RoleMaintainer OrganizationRole = "maintainer" // Manages plain members and every snippet
+	RoleMember     OrganizationRole = "member"     // Sees org-only snippets and edits their own
+)
+
+// IsValid reports whether r is a known organization role
+func (r OrganizationRole) IsValid() bool {
+	return r == RoleOwner || r == RoleMaintainer || r == RoleMember
+}
+
+// CanManageSnippets reports whether the role may edit and delete any snippet of the organization
+func (r OrganizationRole) CanManageSnippets() bool {
+	return r == RoleOwner || r == RoleMaintainer
+}
+
+// Organization is a team of users that owns snippets together
+type Organization struct {
+	ID           string
+	Slug         string
+	Name         string
+	Description  string
+	MemberCount  int
+	SnippetCount int
+	Role         OrganizationRole // Role of the requesting user, empty if they are not a member
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// OrganizationMember is a user with their role in an organization
+type OrganizationMember struct {
+	User     *User
+	Role     OrganizationRole
+	JoinedAt time.Time
+}
@@ -4,16 +4,121 @@ import (
 	"time"
 )
 
+type SnippetVisibility string
+
+const (
+	SnippetPublic       SnippetVisibility = "public"       // Anyone can view it
+	SnippetOrganization SnippetVisibility = "organization" // Only members of the owning organization can view it
+	SnippetPrivate      SnippetVisibility = "private"      // Only its owners and invited collaborators can view it
+)
+
+// IsValid reports whether v is a known snippet visibility
+func (v SnippetVisibility) IsValid() bool {
+	return v == SnippetPublic || v == SnippetOrganization || v == SnippetPrivate
+}
+
+type SnippetPermission string
+
+const (
+	PermissionView SnippetPermission = "view" // Collaborator may read the snippet
+	PermissionEdit SnippetPermission = "edit" // Collaborator may also change its title, content and language
+)
+
+// IsValid reports whether p is a known collaborator permission
+func (p SnippetPermission) IsValid() bool {
+	return p == PermissionView || p == PermissionEdit
+}
+
+// SnippetCollaborator is a user invited to a single snippet
+type SnippetCollaborator struct {
+	User       *User
+	Permission SnippetPermission
+	AddedAt    time.Time
+}
+
 type Snippet struct {
-	ID        string
-	Title     string
-	Content   string
-	Language  string
-	Author    *User
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Views     int
-	Likes     int
-	IsLiked   bool
-	IsSaved   bool
+	ID             string
+	Title          string
+	Content        string
+	Language       string
+	Author         *User
+	OrganizationID *string // nil for personal snippets
+	Visibility     SnippetVisibility
+	ViewerRole     OrganizationRole  // Role of the requesting user in the owning organization, only loaded by GetByID
+	Permission     SnippetPermission // Collaborator permission of the requesting user, only loaded by GetByID
+	LastEditor     *User             // User who last changed the snippet, only loaded by GetByID
+	ExpiresAt      *time.Time        // nil for snippets that never expire
+	BurnAfterRead  bool              // Deleted after the first view by someone other than the author
+	PasswordHash   string            // bcrypt hash of the viewing password, empty for unprotected snippets
+	DeletedAt      *time.Time        // Time the snippet was moved to the trash, only loaded by the trash queries
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Views          int
+	Likes          int
+	IsLiked        bool
+	IsSaved        bool
+}
+
+// IsPublic reports whether everyone may see the snippet
+func (s *Snippet) IsPublic() bool {
+	return s.Visibility == SnippetPublic || s.Visibility == ""
+}
+
+// IsListed reports whether the snippet may show up in lists, feeds and events shared
+// with everyone. Burn-after-read and password-protected snippets are only handed out
+// by their link.
+func (s *Snippet) IsListed() bool {
+	return s.IsPublic() && !s.BurnAfterRead && !s.IsPasswordProtected()
+}
+
+// IsPasswordProtected reports whether viewers need a password to read the snippet
+func (s *Snippet) IsPasswordProtected() bool {
+	return s.PasswordHash != ""
+}
+
+// IsLocked reports whether the user needs the password to read the snippet. Owners and
+// collaborators never do.
+func (s *Snippet) IsLocked(userID string) bool {
+	return s.IsPasswordProtected() && !s.IsOwner(userID) && s.Permission == ""
+}
+
+// IsExpired reports whether the snippet's expiry time has passed
+func (s *Snippet) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// BurnsOnViewBy reports whether viewing the snippet deletes it
+func (s *Snippet) BurnsOnViewBy(userID string) bool {
+	return s.BurnAfterRead && (s.Author == nil || s.Author.ID != userID)
+}
+
+// CanView reports whether the user, whose role and permission were loaded into ViewerRole
+// and Permission, may see the snippet
+func (s *Snippet) CanView(userID string) bool {
+	if s.IsPublic() || s.Permission != "" || s.IsOwner(userID) {
+		return true
+	}
+	return s.Visibility == SnippetOrganization && s.ViewerRole != ""
+}
+
+// IsOwner reports whether the user may delete the snippet and manage who has access to it.
+// Personal snippets belong to their author alone, organization snippets to owners and
+// maintainers and to their author for as long as they remain a member.
+func (s *Snippet) IsOwner(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if s.OrganizationID == nil {
+		return s.Author != nil && s.Author.ID == userID
+	}
+	if s.ViewerRole.CanManageSnippets() {
+		return true
+	}
+	return s.ViewerRole != "" && s.Author != nil && s.Author.ID == userID
+}
+
+// CanEdit reports whether the user may change the snippet, either as one of its owners
+// or as a collaborator with edit permission
+func (s *Snippet) CanEdit(userID string) bool {
+	return s.IsOwner(userID) || (userID != "" && s.Permission == PermissionEdit)
 }
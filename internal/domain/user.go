@@ -14,6 +14,13 @@ type User struct {
 	PasswordHash string // This should be kept private and not exposed in the User struct
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	FollowCounts *FollowCounts // Only loaded for user profiles
+}
+
+// FollowCounts holds the number of followers and followed authors of a user
+type FollowCounts struct {
+	Followers int
+	Following int
 }
 
 type UserCreation struct {
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+type WebhookEvent string
+
+const (
+	WebhookEventSnippetCreated WebhookEvent = "snippet.created"
+	WebhookEventSnippetUpdated WebhookEvent = "snippet.updated"
+	WebhookEventSnippetDeleted WebhookEvent = "snippet.deleted"
+	WebhookEventSnippetLiked   WebhookEvent = "snippet.liked"
+	WebhookEventSnippetSaved   WebhookEvent = "snippet.saved"
+
+	// WebhookEventPing is only sent by the test endpoint and cannot be subscribed to
+	WebhookEventPing WebhookEvent = "ping"
+)
+
+// WebhookEvents lists all events a webhook can subscribe to
+var WebhookEvents = []WebhookEvent{
+	WebhookEventSnippetCreated,
+	WebhookEventSnippetUpdated,
+	WebhookEventSnippetDeleted,
+	WebhookEventSnippetLiked,
+	WebhookEventSnippetSaved,
+}
+
+// IsValid reports whether e is an event webhooks can subscribe to
+func (e WebhookEvent) IsValid() bool {
+	for _, known := range WebhookEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	ID        string
+	OwnerID   string
+	URL       string
+	Secret    string
+	Events    []WebhookEvent // Empty subscribes to all events
+	Global    bool           // Receives events of all snippets instead of only the owner's, admin only
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	Event          WebhookEvent
+	Payload        string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus *int
+	LastError      *string
+	CreatedAt      time.Time
+	CompletedAt    *time.Time
+}
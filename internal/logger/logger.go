@@ -97,6 +97,11 @@ func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return hijacker.Hijack()
 }
 
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController, needed to flush Server-Sent Events
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Debug logs a debug message
 func Debug(msg string, fields ...zap.Field) {
 	Log.Debug(msg, fields...)
@@ -6,7 +6,38 @@ import (
 	"mitsimi.dev/codeShare/internal/domain"
 )
 
+// BookmarkFilter restricts the saved snippets returned by GetSavedSnippets
+type BookmarkFilter struct {
+	FolderID *string // nil for all bookmarks, empty for unfiled bookmarks only
+	Query    string  // Matched case-insensitively against title, content and note
+}
+
 type BookmarkRepository interface {
 	ToggleSave(ctx context.Context, userID, snippetID string, isSave bool) error
-	GetSavedSnippets(ctx context.Context, userID string) ([]*domain.Snippet, error)
+
+	// GetSavedSnippets returns the bookmarks of a user matching the filter, recently saved first
+	GetSavedSnippets(ctx context.Context, userID string, filter BookmarkFilter) ([]*domain.Bookmark, error)
+
+	// SetNote replaces the private note of a bookmark. It returns ErrNotFound if
+	// the user has not saved the snippet.
+	SetNote(ctx context.Context, userID, snippetID, note string) error
+
+	// Move files bookmarks into a folder of the user, or unfiles them if folderID is nil.
+	// It returns ErrNotFound if the folder does not exist, and the number of bookmarks
+	// moved, snippets the user has not saved are skipped.
+	Move(ctx context.Context, userID string, folderID *string, snippetIDs []string) (int, error)
+
+	// Unsave removes bookmarks and returns how many were removed
+	Unsave(ctx context.Context, userID string, snippetIDs []string) (int, error)
+
+	// GetFolders returns the folders of a user ordered by name
+	GetFolders(ctx context.Context, userID string) ([]*domain.BookmarkFolder, error)
+	GetFolder(ctx context.Context, userID, folderID string) (*domain.BookmarkFolder, error)
+
+	// CreateFolder returns ErrAlreadyExists if the user already has a folder with that name
+	CreateFolder(ctx context.Context, folder *domain.BookmarkFolder) (*domain.BookmarkFolder, error)
+	RenameFolder(ctx context.Context, userID, folderID, name string) (*domain.BookmarkFolder, error)
+
+	// DeleteFolder removes a folder, its bookmarks are kept unfiled
+	DeleteFolder(ctx context.Context, userID, folderID string) error
 }
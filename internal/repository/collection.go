@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+type CollectionRepository interface {
+	Create(ctx context.Context, collection *domain.Collection) (*domain.Collection, error)
+
+	// GetByID returns a collection with its collaborators. userID is the requesting user,
+	// empty for anonymous requests.
+	GetByID(ctx context.Context, collectionID, userID string) (*domain.Collection, error)
+
+	// GetByUser returns the collections a user owns or collaborates on, recently updated first
+	GetByUser(ctx context.Context, userID string) ([]*domain.Collection, error)
+	Update(ctx context.Context, collection *domain.Collection) (*domain.Collection, error)
+	Delete(ctx context.Context, collectionID string) error
+
+	// GetItems returns the snippets of a collection in their manual order
+	GetItems(ctx context.Context, collectionID, userID string) ([]*domain.CollectionItem, error)
+
+	// AddItem appends a snippet to a collection. It returns ErrNotFound if the snippet
+	// does not exist or the user cannot read it, and ErrAlreadyExists if it is already
+	// in the collection.
+	AddItem(ctx context.Context, collectionID, snippetID, userID string) error
+	RemoveItem(ctx context.Context, collectionID, snippetID string) error
+
+	// ReorderItems sets the order of the items, snippetIDs must list every item exactly once
+	ReorderItems(ctx context.Context, collectionID string, snippetIDs []string) error
+
+	// AddCollaborator lets a user edit the items of a collection, adding twice is a no-op
+	AddCollaborator(ctx context.Context, collectionID, userID string) error
+	RemoveCollaborator(ctx context.Context, collectionID, userID string) error
+
+	// Follow makes a user follow a collection, following twice is a no-op
+	Follow(ctx context.Context, collectionID, userID string) error
+	Unfollow(ctx context.Context, collectionID, userID string) error
+	GetFollowerIDs(ctx context.Context, collectionID string) ([]string, error)
+}
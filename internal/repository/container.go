@@ -2,12 +2,18 @@ package repository
 
 // Container holds all repository instances for dependency injection
 type Container struct {
-	Snippets  SnippetRepository
-	Likes     LikeRepository
-	Bookmarks BookmarkRepository
-	Users     UserRepository
-	Sessions  SessionRepository
-	Views     ViewRepository
+	Snippets      SnippetRepository
+	Likes         LikeRepository
+	Bookmarks     BookmarkRepository
+	Users         UserRepository
+	Sessions      SessionRepository
+	Views         ViewRepository
+	Trending      TrendingRepository
+	Follows       FollowRepository
+	Notifications NotificationRepository
+	Webhooks      WebhookRepository
+	Collections   CollectionRepository
+	Organizations OrganizationRepository
 }
 
 // NewContainer creates a new repository container with all repositories
@@ -18,13 +24,25 @@ func NewContainer(
 	users UserRepository,
 	sessions SessionRepository,
 	views ViewRepository,
+	trending TrendingRepository,
+	follows FollowRepository,
+	notifications NotificationRepository,
+	webhooks WebhookRepository,
+	collections CollectionRepository,
+	organizations OrganizationRepository,
 ) *Container {
 	return &Container{
-		Snippets:  snippets,
-		Likes:     likes,
-		Bookmarks: bookmarks,
-		Users:     users,
-		Sessions:  sessions,
-		Views:     views,
+		Snippets:      snippets,
+		Likes:         likes,
+		Bookmarks:     bookmarks,
+		Users:         users,
+		Sessions:      sessions,
+		Views:         views,
+		Trending:      trending,
+		Follows:       follows,
+		Notifications: notifications,
+		Webhooks:      webhooks,
+		Collections:   collections,
+		Organizations: organizations,
 	}
 }
@@ -11,6 +11,7 @@ var (
 	ErrAlreadyExists = errors.New("resource already exists")
 	ErrInvalidInput  = errors.New("invalid input")
 	ErrInternal      = errors.New("internal repository error")
+	ErrUnavailable   = errors.New("repository temporarily unavailable")
 )
 
 // IsNotFound returns true if the error is a not found error
@@ -28,6 +29,12 @@ func IsInvalidInput(err error) bool {
 	return errors.Is(err, ErrInvalidInput)
 }
 
+// IsUnavailable returns true if the error is temporary, e.g. a busy database, and
+// the operation can be retried as is
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
 // WrapError wraps an error with additional context
 func WrapError(err error, msg string) error {
 	return fmt.Errorf("%s: %w", msg, err)
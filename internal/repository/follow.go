@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+type FollowRepository interface {
+	// Follow makes a user follow an author, following twice is a no-op
+	Follow(ctx context.Context, followerID, followeeID string) error
+	Unfollow(ctx context.Context, followerID, followeeID string) error
+	GetCounts(ctx context.Context, userID string) (*domain.FollowCounts, error)
+	GetFollowerIDs(ctx context.Context, userID string) ([]string, error)
+	GetFollowers(ctx context.Context, userID string) ([]*domain.User, error)
+	GetFollowing(ctx context.Context, userID string) ([]*domain.User, error)
+
+	// GetFeed returns the most recently created or updated snippets of the authors a user follows
+	GetFeed(ctx context.Context, userID string, limit int) ([]*domain.Snippet, error)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// NewNotification describes a notification to be stored
+type NewNotification struct {
+	ID           string
+	UserID       string
+	ActorID      string
+	Type         domain.NotificationType
+	SnippetID    *string
+	CollectionID *string
+}
+
+type NotificationRepository interface {
+	// Create stores a notification and returns it. It returns nil without an error
+	// if the recipient muted the type or an identical notification is still unread.
+	Create(ctx context.Context, notification *NewNotification) (*domain.Notification, error)
+	List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	MarkAllRead(ctx context.Context, userID string) error
+
+	// GetPreferences returns the preference of every notification type, enabled by default
+	GetPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error)
+	SetPreference(ctx context.Context, userID string, notificationType domain.NotificationType, enabled bool) error
+
+	// DeleteOld removes read notifications older than 90 days
+	DeleteOld(ctx context.Context) error
+}
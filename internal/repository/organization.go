@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// ErrOrganizationHasSnippets is returned when deleting an organization that still
+// owns snippets, trashed ones included
+var ErrOrganizationHasSnippets = errors.New("organization still owns snippets")
+
+type OrganizationRepository interface {
+	// Create stores an organization with ownerID as its first owner. It returns
+	// ErrAlreadyExists if the slug is taken.
+	Create(ctx context.Context, organization *domain.Organization, ownerID string) (*domain.Organization, error)
+
+	// GetByID looks an organization up by ID or slug. userID is the requesting user,
+	// empty for anonymous requests.
+	GetByID(ctx context.Context, idOrSlug, userID string) (*domain.Organization, error)
+
+	// GetByUser returns the organizations a user is a member of, sorted by name
+	GetByUser(ctx context.Context, userID string) ([]*domain.Organization, error)
+	Update(ctx context.Context, organization *domain.Organization) error
+
+	// Delete removes an organization and its memberships. It returns
+	// ErrOrganizationHasSnippets while any snippet, trashed or not, belongs to it.
+	Delete(ctx context.Context, organizationID string) error
+
+	GetMembers(ctx context.Context, organizationID string) ([]*domain.OrganizationMember, error)
+
+	// GetRole returns the role of a user, ErrNotFound if they are not a member
+	GetRole(ctx context.Context, organizationID, userID string) (domain.OrganizationRole, error)
+
+	// SetMember adds a user to an organization or changes their role. It returns
+	// ErrNotFound if the user does not exist.
+	SetMember(ctx context.Context, organizationID, userID string, role domain.OrganizationRole) error
+	RemoveMember(ctx context.Context, organizationID, userID string) error
+}
@@ -9,7 +9,14 @@ import (
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
 	GetByToken(ctx context.Context, token string) (*domain.Session, error)
+	GetByID(ctx context.Context, id string) (*domain.Session, error)
 	Delete(ctx context.Context, token string) error
+
+	// DeleteExpired removes expired sessions and redeemed tickets
 	DeleteExpired(ctx context.Context) error
 	UpdateExpiry(ctx context.Context, sessionID string, expiresAt domain.UnixTime, refreshToken string) error
+
+	// RedeemTicket marks a live update ticket as used until it expires. It returns
+	// ErrAlreadyExists if the ticket was redeemed before, on any instance.
+	RedeemTicket(ctx context.Context, ticketID string, expiresAt domain.UnixTime) error
 }
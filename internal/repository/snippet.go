@@ -2,15 +2,64 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"mitsimi.dev/codeShare/internal/domain"
 )
 
 type SnippetRepository interface {
 	Create(ctx context.Context, snippet *domain.Snippet) error
+
+	// GetByID returns a snippet regardless of its visibility, together with the role of
+	// userID in the owning organization and their collaborator permission. Callers check
+	// Snippet.CanView.
 	GetByID(ctx context.Context, id string, userID string) (*domain.Snippet, error)
+
+	// GetAll, GetAllByAuthor and GetAllByOrganization only return organization-only
+	// snippets to members of the owning organization and private snippets to their
+	// owners and collaborators
 	GetAll(ctx context.Context, userID string) ([]*domain.Snippet, error)
 	GetAllByAuthor(ctx context.Context, authorID string, userID string) ([]*domain.Snippet, error)
+	GetAllByOrganization(ctx context.Context, organizationID string, userID string) ([]*domain.Snippet, error)
+	// Update records snippet.LastEditor, when set, as the user who made the change
 	Update(ctx context.Context, snippet *domain.Snippet) error
+
+	// Trash moves a snippet to the trash, after which it is hidden from every other query
+	// until it is restored or purged. It returns ErrNotFound if the snippet is already
+	// in the trash.
+	Trash(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+
+	// GetTrashedByID returns a snippet in the trash together with the role of userID in
+	// the owning organization, so callers can check Snippet.IsOwner
+	GetTrashedByID(ctx context.Context, id string, userID string) (*domain.Snippet, error)
+
+	// GetTrash returns the snippets in the trash that userID owns, most recently deleted first
+	GetTrash(ctx context.Context, userID string) ([]*domain.Snippet, error)
+
+	// PurgeTrash permanently deletes the snippets moved to the trash before the cutoff
+	// and returns them with their ID, author and language
+	PurgeTrash(ctx context.Context, cutoff time.Time) ([]*domain.Snippet, error)
+
+	// Delete permanently deletes a snippet, whether or not it is in the trash
 	Delete(ctx context.Context, id string) error
+
+	// Burn deletes a burn-after-read snippet after its first view. It returns ErrNotFound
+	// if the snippet was already burned by another view.
+	Burn(ctx context.Context, id string) error
+
+	// DeleteExpired deletes the snippets outside the trash whose expiry time has passed
+	// and returns them with their ID, author and language
+	DeleteExpired(ctx context.Context) ([]*domain.Snippet, error)
+
+	// TransferToOrganization moves a personal snippet into an organization. It returns
+	// ErrNotFound if the snippet does not exist or already belongs to an organization.
+	TransferToOrganization(ctx context.Context, snippetID, organizationID string, visibility domain.SnippetVisibility) error
+
+	GetCollaborators(ctx context.Context, snippetID string) ([]*domain.SnippetCollaborator, error)
+
+	// SetCollaborator invites a user or changes their permission. It returns ErrNotFound
+	// if the user does not exist.
+	SetCollaborator(ctx context.Context, snippetID, userID string, permission domain.SnippetPermission) error
+	RemoveCollaborator(ctx context.Context, snippetID, userID string) error
 }
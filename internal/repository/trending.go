@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// ActivityKind is the kind of interaction that contributes to a trending score
+type ActivityKind string
+
+const (
+	ActivityView ActivityKind = "view"
+	ActivityLike ActivityKind = "like"
+	ActivitySave ActivityKind = "save"
+)
+
+// SnippetActivity is the number of interactions of one kind with a snippet in a time bucket
+type SnippetActivity struct {
+	SnippetID string
+	Kind      ActivityKind
+	At        time.Time // Start of the bucket (UTC); hourly for likes and saves, daily for views
+	Count     int
+}
+
+// TrendingRepository defines the interface for trending score operations
+type TrendingRepository interface {
+	// GetRecentActivity returns views, likes and saves of the last 30 days
+	GetRecentActivity(ctx context.Context) ([]SnippetActivity, error)
+
+	// ReplaceScores replaces all trending scores of a window (snippetID -> score) in one transaction
+	ReplaceScores(ctx context.Context, window string, scores map[string]float64) error
+
+	// GetTrending returns the highest scored snippets of a window, optionally filtered by language
+	GetTrending(ctx context.Context, window, language, userID string, limit int) ([]*domain.Snippet, error)
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
 )
 
 // ViewRecord represents a view tracking record
@@ -14,6 +16,23 @@ type ViewRecord struct {
 	SecondsSinceLastView int64
 }
 
+// BufferedView represents view attempts of one viewer on a snippet aggregated in memory
+type BufferedView struct {
+	SnippetID        string
+	ViewerIdentifier string
+	IPAddress        string
+	LastViewedAt     time.Time
+	Hits             int  // Number of view attempts since the last flush
+	Counted          int  // Number of those attempts that incremented the public counter
+	Bot              bool // Attempts by a bot or crawler, which are never counted
+}
+
+// SnippetStats holds the public counters of a snippet
+type SnippetStats struct {
+	Views int
+	Likes int
+}
+
 // ViewRepository defines the interface for view tracking operations
 type ViewRepository interface {
 	// CheckRecentView returns the last view record for a snippet by a viewer
@@ -25,6 +44,21 @@ type ViewRepository interface {
 	// IncrementViewCount increments the public view counter for a snippet
 	IncrementViewCount(ctx context.Context, snippetID string) error
 
+	// FlushViews persists buffered view records and view counter increments (snippetID -> count) in one transaction.
+	// Views that cannot be written are left out along with their share of the increments and
+	// returned; views of deleted snippets are skipped. It returns ErrUnavailable while the
+	// database is busy.
+	FlushViews(ctx context.Context, views []BufferedView, increments map[string]int) ([]BufferedView, error)
+
+	// GetSnippetStats returns the current public counters of a snippet
+	GetSnippetStats(ctx context.Context, snippetID string) (*SnippetStats, error)
+
+	// AggregateDailyStats rolls raw daily view records and likes up into per-snippet daily stats
+	AggregateDailyStats(ctx context.Context) error
+
+	// GetDailyStats returns the aggregated daily stats of a snippet between two days (inclusive)
+	GetDailyStats(ctx context.Context, snippetID string, from, to time.Time) ([]*domain.SnippetStatsPoint, error)
+
 	// CleanupOldViews removes old view tracking records
 	CleanupOldViews(ctx context.Context) error
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+// DueWebhookDelivery is a queued delivery together with its target
+type DueWebhookDelivery struct {
+	Delivery *domain.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	GetByID(ctx context.Context, webhookID string) (*domain.Webhook, error)
+	GetByOwner(ctx context.Context, ownerID string) ([]*domain.Webhook, error)
+	Update(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	Delete(ctx context.Context, webhookID string) error
+
+	// GetSubscribed returns the active webhooks that receive an event of a snippet owned by ownerID
+	GetSubscribed(ctx context.Context, ownerID string, event domain.WebhookEvent) ([]*domain.Webhook, error)
+
+	// Enqueue stores deliveries to be sent by the dispatcher
+	Enqueue(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*DueWebhookDelivery, error)
+	GetDelivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, webhookID string, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// DeleteOldDeliveries removes finished deliveries older than 30 days
+	DeleteOldDeliveries(ctx context.Context) error
+}
@@ -24,16 +24,22 @@ func (s *Server) setupAPIRoutes(r chi.Router, authMiddleware *api.AuthMiddleware
 			r.Post("/login", handler.Login)
 			r.Post("/logout", handler.Logout)
 			r.Post("/refresh", handler.RefreshToken)
+			r.With(authMiddleware.RequireAuth).Post("/ws-ticket", handler.IssueWebSocketTicket) // Ticket for the live update endpoints
 		})
 
 		// User routes
 		r.Route("/users", func(r chi.Router) {
-			handler := handler.NewUserHandler(s.repos.Users, s.repos.Snippets, s.repos.Likes, s.repos.Bookmarks)
+			bookmarkHandler := handler.NewBookmarkHandler(s.repos.Bookmarks)
+			trashHandler := handler.NewTrashHandler(s.repos.Snippets, s.wsHub, s.trashRetention)
+			handler := handler.NewUserHandler(s.repos.Users, s.repos.Snippets, s.repos.Likes, s.repos.Bookmarks, s.repos.Follows, s.notifier)
 			r.Use(authMiddleware.RequireAuth) // Protect user routes
 
 			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", handler.GetUser)                 // Get user by ID
-				r.Get("/snippets", handler.GetUserSnippets) // Get user's snippets
+				r.Get("/", handler.GetUser)                   // Get user by ID
+				r.Get("/snippets", handler.GetUserSnippets)   // Get user's snippets
+				r.Get("/followers", handler.GetUserFollowers) // Get user's followers
+				r.Get("/following", handler.GetUserFollowing) // Get authors the user follows
+				r.Patch("/follow", handler.ToggleFollowUser)  // Follow or unfollow user
 
 				r.Group(func(r chi.Router) {
 					r.Use(authMiddleware.RequireSelfOrAdmin)      // Require self or admin access
@@ -47,27 +53,121 @@ func (s *Server) setupAPIRoutes(r chi.Router, authMiddleware *api.AuthMiddleware
 
 			// /me routes - automatically use authenticated user's ID
 			r.Route("/me", func(r chi.Router) {
-				r.Get("/", handler.GetMe)                 // Get current user's profile
-				r.Get("/snippets", handler.GetMySnippets) // Get current user's snippets
+				r.Get("/", handler.GetMe)                   // Get current user's profile
+				r.Get("/snippets", handler.GetMySnippets)   // Get current user's snippets
+				r.Get("/followers", handler.GetMyFollowers) // Get current user's followers
+				r.Get("/following", handler.GetMyFollowing) // Get authors the current user follows
 
 				r.Group(func(r chi.Router) {
 					r.Get("/liked", handler.GetMyLikedSnippets)    // Get current user's liked snippets
-					r.Get("/saved", handler.GetMySavedSnippets)    // Get current user's saved snippets
+					r.Get("/saved", handler.GetMySavedSnippets)    // Get current user's saved snippets, ?folder=<id>|none&q=
 					r.Patch("/", handler.UpdateMyProfile)          // Update current user's profile
 					r.Patch("/password", handler.UpdateMyPassword) // Update current user's password
 					r.Patch("/avatar", handler.UpdateMyAvatar)     // Update current user's avatar
 				})
+
+				// Bookmark organization
+				r.Post("/saved/bulk", bookmarkHandler.BulkUpdateBookmarks)     // Move or unsave several snippets
+				r.Patch("/saved/{snippetId}", bookmarkHandler.UpdateBookmark)  // Set note or folder
+				r.Get("/bookmark-folders", bookmarkHandler.GetBookmarkFolders) // List folders
+				r.Post("/bookmark-folders", bookmarkHandler.CreateBookmarkFolder)
+				r.Patch("/bookmark-folders/{folderId}", bookmarkHandler.RenameBookmarkFolder)
+				r.Delete("/bookmark-folders/{folderId}", bookmarkHandler.DeleteBookmarkFolder) // Snippets in it stay saved
+
+				// Deleted snippets
+				r.Get("/trash", trashHandler.GetMyTrash)                   // Snippets the user owns, until they are purged
+				r.Post("/trash/{id}/restore", trashHandler.RestoreSnippet) // Owners only
+				r.Delete("/trash/{id}", trashHandler.DeleteTrashedSnippet) // Delete permanently, owners only
+			})
+		})
+
+		// Feed routes
+		r.Route("/feed", func(r chi.Router) {
+			handler := handler.NewFeedHandler(s.repos.Follows)
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", handler.GetFeed)
+		})
+
+		// Notification routes
+		r.Route("/notifications", func(r chi.Router) {
+			handler := handler.NewNotificationHandler(s.repos.Notifications)
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", handler.GetNotifications)                // Get current user's notifications
+			r.Patch("/read", handler.MarkAllNotificationsRead)  // Mark all notifications as read
+			r.Get("/preferences", handler.GetPreferences)       // Get notification preferences
+			r.Patch("/preferences", handler.UpdatePreferences)  // Mute or unmute notification types
+			r.Patch("/{id}/read", handler.MarkNotificationRead) // Mark one notification as read
+		})
+
+		// Webhook routes
+		r.Route("/webhooks", func(r chi.Router) {
+			handler := handler.NewWebhookHandler(s.repos.Webhooks, s.webhooks)
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", handler.GetWebhooks)
+			r.Post("/", handler.CreateWebhook)
+			r.Get("/{id}", handler.GetWebhook)
+			r.Patch("/{id}", handler.UpdateWebhook)
+			r.Delete("/{id}", handler.DeleteWebhook)
+			r.Get("/{id}/deliveries", handler.GetWebhookDeliveries) // Delivery log
+			r.Post("/{id}/test", handler.SendTestEvent)             // Send a ping event
+		})
+
+		// Collection routes
+		r.Route("/collections", func(r chi.Router) {
+			handler := handler.NewCollectionHandler(s.repos.Collections, s.notifier)
+
+			// Public routes, private collections are only visible to their owner and collaborators
+			r.Get("/{id}", handler.GetCollection)
+
+			// Protected routes
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Get("/", handler.GetCollections) // Collections the current user owns or collaborates on
+				r.Post("/", handler.CreateCollection)
+				r.Patch("/{id}", handler.UpdateCollection)
+				r.Delete("/{id}", handler.DeleteCollection)
+				r.Patch("/{id}/follow", handler.ToggleFollowCollection)              // Follow or unfollow collection
+				r.Post("/{id}/items", handler.AddCollectionItem)                     // Append a snippet
+				r.Put("/{id}/items", handler.ReorderCollectionItems)                 // Set the manual order
+				r.Delete("/{id}/items/{snippetId}", handler.RemoveCollectionItem)    // Remove a snippet
+				r.Put("/{id}/collaborators/{userId}", handler.AddCollaborator)       // Owner only
+				r.Delete("/{id}/collaborators/{userId}", handler.RemoveCollaborator) // Owner, or collaborators leaving
+			})
+		})
+
+		// Organization routes, {id} is the ID or the slug
+		r.Route("/organizations", func(r chi.Router) {
+			handler := handler.NewOrganizationHandler(s.repos.Organizations, s.repos.Snippets)
+
+			// Public routes, organization-only snippets are only listed for members
+			r.Get("/{id}", handler.GetOrganization)
+			r.Get("/{id}/snippets", handler.GetOrganizationSnippets)
+
+			// Protected routes
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Get("/", handler.GetOrganizations) // Organizations the current user is a member of
+				r.Post("/", handler.CreateOrganization)
+				r.Patch("/{id}", handler.UpdateOrganization)                         // Owners only
+				r.Delete("/{id}", handler.DeleteOrganization)                        // Owners only, once it owns no snippets
+				r.Get("/{id}/members", handler.GetOrganizationMembers)               // Members only
+				r.Put("/{id}/members/{userId}", handler.SetOrganizationMember)       // Add a member or change their role
+				r.Delete("/{id}/members/{userId}", handler.RemoveOrganizationMember) // Maintainers, or members leaving
 			})
 		})
 
 		// Snippet routes
 		r.Route("/snippets", func(r chi.Router) {
-			handler := handler.NewSnippetHandler(s.repos.Snippets, s.repos.Likes, s.repos.Bookmarks, s.viewTracker, s.wsHub)
+			analyticsHandler := handler.NewAnalyticsHandler(s.repos.Snippets, s.viewAnalytics)
+			trendingHandler := handler.NewTrendingHandler(s.trending)
+			handler := handler.NewSnippetHandler(s.repos.Snippets, s.repos.Likes, s.repos.Bookmarks, s.repos.Follows, s.repos.Organizations, s.viewTracker, s.bots, s.notifier, s.webhooks, s.unlocks, s.wsHub, s.secretKey)
 
 			// Public routes
 			r.Group(func(r chi.Router) {
 				r.Get("/", handler.GetSnippets)
+				r.Get("/trending", trendingHandler.GetTrendingSnippets)
 				r.Get("/{id}", handler.GetSnippet)
+				r.Post("/{id}/unlock", handler.UnlockSnippet) // {"password": "..."}, sets a grant cookie and returns the grant
 			})
 
 			// Protected routes
@@ -77,8 +177,13 @@ func (s *Server) setupAPIRoutes(r chi.Router, authMiddleware *api.AuthMiddleware
 
 				r.Put("/{id}", handler.UpdateSnippet)
 				r.Delete("/{id}", handler.DeleteSnippet)
+				r.Post("/{id}/transfer", handler.TransferSnippet) // Move a personal snippet into an organization
+				r.Get("/{id}/collaborators", handler.GetSnippetCollaborators)
+				r.Put("/{id}/collaborators/{userId}", handler.SetSnippetCollaborator)       // Owners only, {"permission": "view"|"edit"}
+				r.Delete("/{id}/collaborators/{userId}", handler.RemoveSnippetCollaborator) // Owners, or collaborators leaving
 				r.Patch("/{id}/like", handler.ToggleLikeSnippet)
 				r.Patch("/{id}/save", handler.ToggleSaveSnippet)
+				r.Get("/{id}/analytics", analyticsHandler.GetSnippetAnalytics)
 			})
 		})
 	})
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"mitsimi.dev/codeShare/internal/api/dto"
+
 	"go.uber.org/zap"
 )
 
@@ -23,6 +25,51 @@ func (s *Server) startSessionCleanup() {
 	}()
 }
 
+// startSnippetExpiry starts a background goroutine to periodically delete expired snippets
+// and tell their viewers and the list views that they are gone
+func (s *Server) startSnippetExpiry() {
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute) // Run purge every minute
+		defer ticker.Stop()
+
+		for range ticker.C {
+			snippets, err := s.repos.Snippets.DeleteExpired(context.Background())
+			if err != nil {
+				s.logger.Error("Failed to delete expired snippets", zap.Error(err))
+				continue
+			}
+
+			for _, snippet := range snippets {
+				s.wsHub.CloseEditSession(snippet.ID)
+				s.wsHub.BroadcastSnippetDeleted(dto.ToSnippetResponse(snippet))
+			}
+			if len(snippets) > 0 {
+				s.logger.Debug("Successfully deleted expired snippets", zap.Int("count", len(snippets)))
+			}
+		}
+	}()
+}
+
+// startTrashPurge starts a background goroutine to periodically delete the snippets that
+// were in the trash for longer than the retention window
+func (s *Server) startTrashPurge() {
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour) // Run purge hourly
+		defer ticker.Stop()
+
+		for range ticker.C {
+			snippets, err := s.repos.Snippets.PurgeTrash(context.Background(), time.Now().Add(-s.trashRetention))
+			if err != nil {
+				s.logger.Error("Failed to purge trashed snippets", zap.Error(err))
+				continue
+			}
+			if len(snippets) > 0 {
+				s.logger.Debug("Successfully purged trashed snippets", zap.Int("count", len(snippets)))
+			}
+		}
+	}()
+}
+
 // startViewCleanup starts a background goroutine to periodically clean up old view tracking records
 func (s *Server) startViewCleanup() {
 	go func() {
@@ -30,6 +77,12 @@ func (s *Server) startViewCleanup() {
 		defer ticker.Stop()
 
 		for range ticker.C {
+			// Roll raw view records up before they are deleted
+			if err := s.viewAnalytics.Aggregate(context.Background()); err != nil {
+				s.logger.Error("Failed to aggregate view analytics, skipping cleanup", zap.Error(err))
+				continue
+			}
+
 			if err := s.viewTracker.CleanupOldViews(context.Background()); err != nil {
 				s.logger.Error("Failed to clean up old view tracking records", zap.Error(err))
 			} else {
@@ -38,3 +91,86 @@ func (s *Server) startViewCleanup() {
 		}
 	}()
 }
+
+// startNotificationCleanup starts a background goroutine to periodically delete old read notifications
+func (s *Server) startNotificationCleanup() {
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour) // Run cleanup daily
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.repos.Notifications.DeleteOld(context.Background()); err != nil {
+				s.logger.Error("Failed to delete old notifications", zap.Error(err))
+			} else {
+				s.logger.Debug("Successfully cleaned up old notifications")
+			}
+		}
+	}()
+}
+
+// startWebhookCleanup starts a background goroutine to periodically delete old webhook delivery logs
+func (s *Server) startWebhookCleanup() {
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour) // Run cleanup daily
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.webhooks.CleanupOldDeliveries(context.Background()); err != nil {
+				s.logger.Error("Failed to delete old webhook deliveries", zap.Error(err))
+			} else {
+				s.logger.Debug("Successfully cleaned up old webhook deliveries")
+			}
+		}
+	}()
+}
+
+// startUnlockPrune starts a background goroutine to periodically forget the password
+// attempts of clients that stopped guessing
+func (s *Server) startUnlockPrune() {
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute) // Run prune every minute
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.unlocks.Prune()
+		}
+	}()
+}
+
+// startViewAggregation starts a background goroutine to periodically roll view records up into daily analytics
+func (s *Server) startViewAggregation() {
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour) // Run aggregation every hour
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.viewAnalytics.Aggregate(context.Background()); err != nil {
+				s.logger.Error("Failed to aggregate view analytics", zap.Error(err))
+			} else {
+				s.logger.Debug("Successfully aggregated view analytics")
+			}
+		}
+	}()
+}
+
+// startTrendingRefresh starts a background goroutine to periodically recompute trending scores
+func (s *Server) startTrendingRefresh() {
+	refresh := func() {
+		if err := s.trending.Refresh(context.Background()); err != nil {
+			s.logger.Error("Failed to refresh trending scores", zap.Error(err))
+		} else {
+			s.logger.Debug("Successfully refreshed trending scores")
+		}
+	}
+
+	go func() {
+		refresh() // Scores are not kept up to date while the server is down
+
+		ticker := time.NewTicker(15 * time.Minute) // Run refresh every 15 minutes
+		defer ticker.Stop()
+
+		for range ticker.C {
+			refresh()
+		}
+	}()
+}
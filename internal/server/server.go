@@ -10,6 +10,7 @@ import (
 
 	"mitsimi.dev/codeShare/frontend"
 	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/constants"
 	"mitsimi.dev/codeShare/internal/logger"
 	"mitsimi.dev/codeShare/internal/repository"
 	"mitsimi.dev/codeShare/internal/services"
@@ -27,38 +28,85 @@ type Server struct {
 	httpServer         *http.Server
 	repos              *repository.Container
 	viewTracker        *services.ViewTracker
+	viewAnalytics      *services.ViewAnalytics
+	trending           *services.TrendingService
+	notifier           *services.Notifier
+	webhooks           *services.WebhookDispatcher
+	unlocks            *services.UnlockLimiter
+	bots               *services.BotClassifier
+	clientIPResolver   *services.ClientIPResolver
 	wsHub              *ws.Hub
+	wsGuard            *ws.Guard
 	logger             *zap.Logger
 	secretKey          string
+	trashRetention     time.Duration
 	serveStatic        bool
 	corsAllowedOrigins []string
 	devProxy           *DevProxy
 }
 
+// Options configures a server
+type Options struct {
+	SecretKey             string
+	ServeStatic           bool
+	CORSAllowedOrigins    []string
+	ViewPrivacy           services.ViewPrivacyConfig
+	TrustedProxies        []string // CIDRs or IPs allowed to set forwarding headers
+	TrustedProxyHeader    string
+	BotUserAgents         []string  // Extra user agent fragments never counted as views
+	WSBroker              ws.Broker // Shares live updates with other instances, nil keeps them on this instance
+	WSMaxConnectionsPerIP int
+	UnlockLimits          services.UnlockLimiterConfig
+	TrashRetention        time.Duration
+}
+
 // New creates a new server instance
-func New(
-	repos *repository.Container,
-	secretKey string,
-	serveStatic bool,
-	corsAllowedOrigins []string,
-) *Server {
+func New(repos *repository.Container, opts Options) *Server {
+	wsHub := ws.NewHub(opts.WSBroker, services.NewSnippetEditStore(repos.Snippets), services.NewSnippetAccess(repos.Snippets))
+	wsGuard := ws.NewGuard(wsHub, ws.GuardConfig{
+		AllowedOrigins:      opts.CORSAllowedOrigins,
+		MaxConnectionsPerIP: opts.WSMaxConnectionsPerIP,
+		SecretKey:           opts.SecretKey,
+		Users:               repos.Users,
+		Sessions:            repos.Sessions,
+		Tickets:             repos.Sessions,
+	})
+
 	// Create view tracker
-	viewTracker := services.NewViewTracker(repos.Views)
-	wsHub := ws.NewHub()
+	bots := services.NewBotClassifier(opts.BotUserAgents)
+	viewTracker := services.NewViewTracker(repos.Views, wsHub, opts.ViewPrivacy, bots)
+	viewAnalytics := services.NewViewAnalytics(repos.Views)
+	trending := services.NewTrendingService(repos.Trending)
+	notifier := services.NewNotifier(repos.Notifications, wsHub)
+	webhooks := services.NewWebhookDispatcher(repos.Webhooks)
 
 	s := &Server{
 		router:             chi.NewRouter(),
 		repos:              repos,
 		viewTracker:        viewTracker,
+		viewAnalytics:      viewAnalytics,
+		trending:           trending,
+		notifier:           notifier,
+		webhooks:           webhooks,
+		unlocks:            services.NewUnlockLimiter(opts.UnlockLimits),
+		bots:               bots,
 		wsHub:              wsHub,
+		wsGuard:            wsGuard,
 		logger:             logger.Log,
-		secretKey:          secretKey,
-		serveStatic:        serveStatic,
-		corsAllowedOrigins: corsAllowedOrigins,
+		secretKey:          opts.SecretKey,
+		trashRetention:     opts.TrashRetention,
+		serveStatic:        opts.ServeStatic,
+		corsAllowedOrigins: opts.CORSAllowedOrigins,
+	}
+
+	clientIPResolver, err := services.NewClientIPResolver(opts.TrustedProxies, opts.TrustedProxyHeader)
+	if err != nil {
+		s.logger.Fatal("Failed to parse trusted proxies", zap.Error(err))
 	}
+	s.clientIPResolver = clientIPResolver
 
 	// Setup Vite dev server proxy if not serving static files
-	if !serveStatic {
+	if !opts.ServeStatic {
 		if err := s.setupDevProxy(); err != nil {
 			s.logger.Fatal("Failed to setup development proxy", zap.Error(err))
 		}
@@ -67,10 +115,20 @@ func New(
 	s.setupMiddleware()
 	s.setupRoutes()
 	s.startSessionCleanup()
+	s.startSnippetExpiry()
+	s.startTrashPurge()
+	s.startViewAggregation()
 	s.startViewCleanup()
+	s.startTrendingRefresh()
+	s.startNotificationCleanup()
+	s.startWebhookCleanup()
+	s.startUnlockPrune()
+	s.viewTracker.Start()
+	s.webhooks.Start()
 
 	// Start the WebSocket hub
 	go wsHub.Run()
+	wsGuard.Start()
 	s.logger.Info("WebSocket hub started")
 
 	return s
@@ -111,14 +169,40 @@ func (s *Server) Start(port, env string) error {
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down server...")
 
-	if s.httpServer == nil {
-		return nil
+	var shutdownErr error
+	if s.httpServer != nil {
+		// Shutdown the HTTP server gracefully
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			s.logger.Error("server shutdown failed", zap.Error(err))
+			shutdownErr = err
+		}
 	}
 
-	// Shutdown the HTTP server gracefully
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		s.logger.Error("server shutdown failed", zap.Error(err))
-		return err
+	// Write buffered views even if the HTTP server did not stop cleanly
+	if err := s.viewTracker.Stop(ctx); err != nil {
+		s.logger.Error("failed to flush buffered views", zap.Error(err))
+		if shutdownErr == nil {
+			shutdownErr = err
+		}
+	}
+
+	// Queued webhook deliveries are kept in the database and sent after the next start
+	if err := s.webhooks.Stop(ctx); err != nil {
+		s.logger.Error("failed to stop webhook dispatcher", zap.Error(err))
+		if shutdownErr == nil {
+			shutdownErr = err
+		}
+	}
+
+	if err := s.wsHub.Close(); err != nil {
+		s.logger.Error("failed to close websocket broker", zap.Error(err))
+		if shutdownErr == nil {
+			shutdownErr = err
+		}
+	}
+
+	if shutdownErr != nil {
+		return shutdownErr
 	}
 
 	s.logger.Info("server shutdown completed successfully")
@@ -130,12 +214,14 @@ func (s *Server) setupMiddleware() {
 	// Add request ID to context
 	s.router.Use(middleware.RequestID)
 
+	// Resolve the client IP once, honoring forwarding headers only from trusted proxies
+	s.router.Use(api.ClientIP(s.clientIPResolver))
+
 	// Use our structured logger
 	s.router.Use(logger.RequestLogger)
 
 	// Other middleware
 	s.router.Use(middleware.Recoverer)
-	s.router.Use(middleware.RealIP)
 	s.router.Use(middleware.CleanPath)
 	s.router.Use(middleware.GetHead)
 }
@@ -149,7 +235,7 @@ func (s *Server) setupRoutes() {
 	s.router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   s.corsAllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", constants.SnippetGrantHeader},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
@@ -7,6 +7,7 @@ import (
 
 // setupWebSocketRoutes configures WebSocket routes
 func (s *Server) setupWebSocketRoutes(r chi.Router) {
-	r.HandleFunc("/", ws.HandleWebSocket(s.wsHub))
+	r.HandleFunc("/", ws.HandleWebSocket(s.wsHub, s.wsGuard))
 	r.HandleFunc("/stats", ws.HandleStats(s.wsHub))
+	r.Get("/events", ws.HandleSSE(s.wsHub, s.wsGuard)) // Server-Sent Events fallback for networks that block upgrades
 }
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultBotPatterns are lowercase user agent fragments of crawlers, link
+// preview fetchers, monitoring tools and scripted HTTP clients
+var defaultBotPatterns = []string{
+	"bot", // Googlebot, bingbot, Slackbot, Twitterbot, Discordbot, TelegramBot, LinkedInBot, ...
+	"crawl",
+	"spider",
+	"slurp",
+	"facebookexternalhit",
+	"facebookcatalog",
+	"whatsapp",
+	"skypeuripreview",
+	"embedly",
+	"preview",
+	"headless",
+	"lighthouse",
+	"pingdom",
+	"uptime",
+	"curl/",
+	"wget/",
+	"python-requests",
+	"python-urllib",
+	"go-http-client",
+	"okhttp",
+	"java/",
+	"libwww",
+	"httpclient",
+	"axios/",
+	"node-fetch",
+	"scrapy",
+}
+
+// BotClassifier detects requests from bots and crawlers so their views can
+// be kept out of the public view counter
+type BotClassifier struct {
+	patterns []string
+}
+
+// NewBotClassifier creates a classifier using the default patterns plus the
+// given user agent fragments (matched case-insensitively)
+func NewBotClassifier(denyList []string) *BotClassifier {
+	patterns := make([]string, 0, len(defaultBotPatterns)+len(denyList))
+	patterns = append(patterns, defaultBotPatterns...)
+	for _, pattern := range denyList {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return &BotClassifier{patterns: patterns}
+}
+
+// Classify reports whether the request comes from a bot, and why
+func (c *BotClassifier) Classify(r *http.Request) (bool, string) {
+	// Link unfurlers probe with HEAD, which middleware.GetHead routes to GET handlers
+	if r.Method == http.MethodHead {
+		return true, "head request"
+	}
+
+	userAgent := strings.ToLower(r.UserAgent())
+	if userAgent == "" {
+		return true, "missing user agent"
+	}
+
+	for _, pattern := range c.patterns {
+		if strings.Contains(userAgent, pattern) {
+			return true, "user agent matches " + pattern
+		}
+	}
+
+	// Browsers always send Accept-Language, even for fetch requests
+	if r.Header.Get("Accept-Language") == "" {
+		return true, "missing accept-language"
+	}
+
+	return false, ""
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotClassifier_Classify(t *testing.T) {
+	classifier := NewBotClassifier([]string{" InternalScanner "})
+
+	tests := []struct {
+		name           string
+		method         string
+		userAgent      string
+		acceptLanguage string
+		expected       bool
+	}{
+		{name: "browser", method: "GET", userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36", acceptLanguage: "de-DE,de;q=0.9", expected: false},
+		{name: "crawler", method: "GET", userAgent: "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", acceptLanguage: "en", expected: true},
+		{name: "slack link preview", method: "GET", userAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", acceptLanguage: "en", expected: true},
+		{name: "link preview", method: "GET", userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", acceptLanguage: "en", expected: true},
+		{name: "scripted client", method: "GET", userAgent: "curl/8.5.0", acceptLanguage: "en", expected: true},
+		{name: "deny list", method: "GET", userAgent: "Mozilla/5.0 internalscanner/1.0", acceptLanguage: "en", expected: true},
+		{name: "missing user agent", method: "GET", userAgent: "", acceptLanguage: "en", expected: true},
+		{name: "missing accept-language", method: "GET", userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0", acceptLanguage: "", expected: true},
+		{name: "head request", method: "HEAD", userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0", acceptLanguage: "en", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newBrowserRequest(tt.method, "/api/snippets/snippet-1")
+			r.Header.Set("User-Agent", tt.userAgent)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			bot, reason := classifier.Classify(r)
+			assert.Equal(t, tt.expected, bot)
+			if bot {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
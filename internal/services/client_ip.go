@@ -0,0 +1,198 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Forwarding headers a ClientIPResolver can read
+const (
+	HeaderForwarded     = "Forwarded"
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+// ClientIPResolver determines the real client IP of a request.
+// Only the one forwarding header the proxies set is read, so clients cannot slip
+// in another header the proxies pass through untouched. It is only honored when the
+// request comes from a trusted proxy, and is walked right to left until the first
+// untrusted hop.
+type ClientIPResolver struct {
+	trustedProxies []netip.Prefix
+	header         string
+}
+
+// NewClientIPResolver creates a resolver trusting the given CIDRs or single IPs to set
+// header, one of Forwarded, X-Forwarded-For (the default if empty) and X-Real-IP
+func NewClientIPResolver(trustedProxies []string, header string) (*ClientIPResolver, error) {
+	switch http.CanonicalHeaderKey(header) {
+	case "":
+		header = HeaderXForwardedFor
+	case http.CanonicalHeaderKey(HeaderForwarded):
+		header = HeaderForwarded
+	case http.CanonicalHeaderKey(HeaderXForwardedFor):
+		header = HeaderXForwardedFor
+	case http.CanonicalHeaderKey(HeaderXRealIP):
+		header = HeaderXRealIP
+	default:
+		return nil, fmt.Errorf("unsupported trusted proxy header %q", header)
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, value := range trustedProxies {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy CIDR %q: %w", value, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy IP %q: %w", value, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return &ClientIPResolver{trustedProxies: prefixes, header: header}, nil
+}
+
+// Resolve returns the client IP of the request
+func (res *ClientIPResolver) Resolve(r *http.Request) string {
+	remote := cleanIPAddress(r.RemoteAddr)
+	addr, err := parseHop(remote)
+	if err != nil {
+		return remote
+	}
+	if !res.isTrusted(addr) {
+		return addr.String()
+	}
+
+	// Walk the proxy chain from the closest hop to the client
+	client := addr
+	hops := res.forwardedHops(r)
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := parseHop(hops[i])
+		if err != nil {
+			// Obfuscated or unknown hop, the last known proxy is the best we have
+			break
+		}
+		client = hop
+		if !res.isTrusted(hop) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+// isTrusted reports whether addr belongs to a trusted proxy
+func (res *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range res.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedHops returns the addresses the proxy chain reported in the configured
+// header, closest hop last
+func (res *ClientIPResolver) forwardedHops(r *http.Request) []string {
+	switch res.header {
+	case HeaderForwarded:
+		var hops []string
+		for _, element := range splitOutsideQuotes(strings.Join(r.Header.Values(HeaderForwarded), ","), ',') {
+			for _, pair := range splitOutsideQuotes(element, ';') {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(key, "for") {
+					hops = append(hops, strings.Trim(value, `"`))
+				}
+			}
+		}
+		return hops
+
+	case HeaderXRealIP:
+		if xri := r.Header.Get(HeaderXRealIP); xri != "" {
+			return []string{xri}
+		}
+		return nil
+
+	default:
+		if values := r.Header.Values(HeaderXForwardedFor); len(values) > 0 {
+			return strings.Split(strings.Join(values, ","), ",")
+		}
+		return nil
+	}
+}
+
+// splitOutsideQuotes splits s at every sep that is not inside a quoted string
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++ // Skip the escaped character
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseHop parses a hop address with optional port and IPv6 brackets
+func parseHop(value string) (netip.Addr, error) {
+	value = cleanIPAddress(strings.TrimSpace(value))
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap().WithZone(""), nil
+}
+
+// GetClientIP returns the client IP of a request.
+// The ClientIP middleware replaces RemoteAddr with the resolved client IP,
+// so no forwarding headers are read here.
+func GetClientIP(r *http.Request) string {
+	return cleanIPAddress(r.RemoteAddr)
+}
+
+// cleanIPAddress removes port from IP address if present
+func cleanIPAddress(addr string) string {
+	// Handle IPv6 addresses like [::1]:64011
+	if strings.HasPrefix(addr, "[") {
+		if idx := strings.LastIndex(addr, "]:"); idx != -1 {
+			return addr[1:idx] // Extract IP without brackets and port
+		}
+		return addr // Return as-is if no port found
+	}
+
+	// Bare IPv6 addresses contain several colons and no port
+	if strings.Count(addr, ":") > 1 {
+		return addr
+	}
+
+	// Handle IPv4 addresses like 192.168.1.1:8080
+	if idx := strings.LastIndex(addr, ":"); idx != -1 {
+		return addr[:idx] // Extract IP without port
+	}
+
+	return addr // Return as-is if no port found
+}
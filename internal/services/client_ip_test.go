@@ -0,0 +1,142 @@
+package services
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string // Forwarding header the proxies set, X-Forwarded-For if empty
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "direct connection",
+			remoteAddr: "203.0.113.7:51234",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer cannot spoof",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.20"},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "spoofed leftmost value is ignored",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.20, 10.0.0.9"},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"X-Forwarded-For": "10.1.1.1, 10.0.0.9"},
+			expected:   "10.1.1.1",
+		},
+		{
+			name:       "garbage hop stops at last proxy",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.20, garbage"},
+			expected:   "10.0.0.5",
+		},
+		{
+			name:       "x-real-ip from trusted proxy",
+			header:     HeaderXRealIP,
+			remoteAddr: "192.0.2.1:443",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.20"},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "forwarded header",
+			header:     HeaderForwarded,
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"Forwarded": `for=1.2.3.4, for="[2001:db8:cafe::17]:4711";proto=https, For=198.51.100.20;by=10.0.0.5`},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "forwarded header with trusted ipv6 hop",
+			header:     HeaderForwarded,
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"Forwarded": `for=198.51.100.20, for="[2001:db8:cafe::17]:4711"`},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "spoofed forwarded header next to proxy x-forwarded-for",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"Forwarded": "for=1.2.3.4", "X-Forwarded-For": "198.51.100.20"},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "spoofed x-forwarded-for next to proxy forwarded header",
+			header:     HeaderForwarded,
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.20", "X-Forwarded-For": "1.2.3.4"},
+			expected:   "198.51.100.20",
+		},
+		{
+			name:       "other headers are not a fallback",
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"X-Real-IP": "1.2.3.4"},
+			expected:   "10.0.0.5",
+		},
+		{
+			name:       "obfuscated forwarded hop",
+			header:     HeaderForwarded,
+			remoteAddr: "10.0.0.5:443",
+			headers:    map[string]string{"Forwarded": "for=_hidden"},
+			expected:   "10.0.0.5",
+		},
+		{
+			name:       "ipv6 peer",
+			remoteAddr: "[2001:db9::1]:8080",
+			expected:   "2001:db9::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"}, tt.header)
+			assert.NoError(t, err)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.expected, resolver.Resolve(r))
+		})
+	}
+}
+
+func TestNewClientIPResolver_Invalid(t *testing.T) {
+	_, err := NewClientIPResolver([]string{"10.0.0.0/33"}, "")
+	assert.Error(t, err)
+
+	_, err = NewClientIPResolver([]string{"proxy.local"}, "")
+	assert.Error(t, err)
+
+	_, err = NewClientIPResolver([]string{"10.0.0.0/8"}, "True-Client-IP")
+	assert.Error(t, err)
+}
+
+func TestGetClientIP(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+	r.RemoteAddr = "203.0.113.7:51234"
+	assert.Equal(t, "203.0.113.7", GetClientIP(r))
+
+	r.RemoteAddr = "2001:db8::1"
+	assert.Equal(t, "2001:db8::1", GetClientIP(r))
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// NotificationBroadcaster delivers new notifications to the recipient's connected devices
+type NotificationBroadcaster interface {
+	BroadcastNotification(userID string, notification *domain.Notification, unreadCount int)
+}
+
+// Notifier stores notifications in the recipient's inbox and pushes them live
+type Notifier struct {
+	repo        repository.NotificationRepository
+	broadcaster NotificationBroadcaster
+	logger      *zap.Logger
+}
+
+func NewNotifier(repo repository.NotificationRepository, broadcaster NotificationBroadcaster) *Notifier {
+	return &Notifier{
+		repo:        repo,
+		broadcaster: broadcaster,
+		logger:      logger.Log,
+	}
+}
+
+// Notify records that actorID did something that concerns recipientID.
+// Notifications are best effort, failures are logged and never fail the triggering action.
+func (n *Notifier) Notify(ctx context.Context, recipientID, actorID string, notificationType domain.NotificationType, snippetID *string) {
+	n.notify(ctx, &repository.NewNotification{
+		UserID:    recipientID,
+		ActorID:   actorID,
+		Type:      notificationType,
+		SnippetID: snippetID,
+	})
+}
+
+// NotifyCollectionItem tells a follower of a collection that actorID added a snippet to it
+func (n *Notifier) NotifyCollectionItem(ctx context.Context, recipientID, actorID, collectionID, snippetID string) {
+	n.notify(ctx, &repository.NewNotification{
+		UserID:       recipientID,
+		ActorID:      actorID,
+		Type:         domain.NotificationCollectionItem,
+		SnippetID:    &snippetID,
+		CollectionID: &collectionID,
+	})
+}
+
+func (n *Notifier) notify(ctx context.Context, newNotification *repository.NewNotification) {
+	recipientID := newNotification.UserID
+	if recipientID == "" || recipientID == newNotification.ActorID {
+		return
+	}
+
+	log := n.logger.With(
+		zap.String("recipient_id", recipientID),
+		zap.String("actor_id", newNotification.ActorID),
+		zap.String("type", string(newNotification.Type)),
+	)
+
+	newNotification.ID = uuid.New().String()
+	notification, err := n.repo.Create(ctx, newNotification)
+	if err != nil {
+		log.Error("failed to create notification", zap.Error(err))
+		return
+	}
+	if notification == nil {
+		log.Debug("notification muted or already pending")
+		return
+	}
+
+	unread, err := n.repo.CountUnread(ctx, recipientID)
+	if err != nil {
+		log.Error("failed to count unread notifications", zap.Error(err))
+		return
+	}
+
+	if n.broadcaster != nil {
+		n.broadcaster.BroadcastNotification(recipientID, notification, unread)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// fakeNotificationRepository stores created notifications in memory
+type fakeNotificationRepository struct {
+	repository.NotificationRepository
+	created []*repository.NewNotification
+	muted   bool
+}
+
+func (r *fakeNotificationRepository) Create(ctx context.Context, notification *repository.NewNotification) (*domain.Notification, error) {
+	if r.muted {
+		return nil, nil
+	}
+	r.created = append(r.created, notification)
+	return &domain.Notification{
+		ID:     notification.ID,
+		UserID: notification.UserID,
+		Type:   notification.Type,
+		Actor:  &domain.User{ID: notification.ActorID},
+	}, nil
+}
+
+func (r *fakeNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	return len(r.created), nil
+}
+
+// fakeNotificationBroadcaster records delivered notifications
+type fakeNotificationBroadcaster struct {
+	delivered []int
+}
+
+func (b *fakeNotificationBroadcaster) BroadcastNotification(userID string, notification *domain.Notification, unreadCount int) {
+	b.delivered = append(b.delivered, unreadCount)
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	setupTestLogger(t)
+
+	snippetID := "snippet-1"
+
+	t.Run("delivers", func(t *testing.T) {
+		repo := &fakeNotificationRepository{}
+		broadcaster := &fakeNotificationBroadcaster{}
+		notifier := NewNotifier(repo, broadcaster)
+
+		notifier.Notify(context.Background(), "author", "fan", domain.NotificationLike, &snippetID)
+		assert.Len(t, repo.created, 1)
+		assert.NotEmpty(t, repo.created[0].ID)
+		assert.Equal(t, []int{1}, broadcaster.delivered)
+	})
+
+	t.Run("skips self actions", func(t *testing.T) {
+		repo := &fakeNotificationRepository{}
+		broadcaster := &fakeNotificationBroadcaster{}
+		notifier := NewNotifier(repo, broadcaster)
+
+		notifier.Notify(context.Background(), "author", "author", domain.NotificationSave, &snippetID)
+		assert.Empty(t, repo.created)
+		assert.Empty(t, broadcaster.delivered)
+	})
+
+	t.Run("muted is not delivered", func(t *testing.T) {
+		repo := &fakeNotificationRepository{muted: true}
+		broadcaster := &fakeNotificationBroadcaster{}
+		notifier := NewNotifier(repo, broadcaster)
+
+		notifier.Notify(context.Background(), "author", "fan", domain.NotificationFollow, nil)
+		assert.Empty(t, broadcaster.delivered)
+	})
+
+	t.Run("collection items", func(t *testing.T) {
+		repo := &fakeNotificationRepository{}
+		broadcaster := &fakeNotificationBroadcaster{}
+		notifier := NewNotifier(repo, broadcaster)
+
+		notifier.NotifyCollectionItem(context.Background(), "follower", "curator", "collection-1", snippetID)
+		notifier.NotifyCollectionItem(context.Background(), "curator", "curator", "collection-1", snippetID)
+		assert.Len(t, repo.created, 1)
+		assert.Equal(t, domain.NotificationCollectionItem, repo.created[0].Type)
+		assert.Equal(t, "collection-1", *repo.created[0].CollectionID)
+		assert.Equal(t, snippetID, *repo.created[0].SnippetID)
+		assert.Equal(t, []int{1}, broadcaster.delivered)
+	})
+}
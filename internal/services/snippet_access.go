@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// SnippetAccess checks who may follow a snippet's live updates, with the same rules
+// as viewing it over the API
+type SnippetAccess struct {
+	snippets repository.SnippetRepository
+}
+
+func NewSnippetAccess(snippets repository.SnippetRepository) *SnippetAccess {
+	return &SnippetAccess{snippets: snippets}
+}
+
+// CanView returns repository.ErrNotFound for snippets that do not exist or that the
+// user may not see, so restricted snippets cannot be told apart from missing ones.
+// Password-protected snippets only stream to their owners and collaborators, and
+// burn-after-read snippets only to their author, since following one would reveal
+// its content without ever burning it. Expired snippets do not stream at all.
+func (a *SnippetAccess) CanView(ctx context.Context, snippetID, userID string) error {
+	snippet, err := a.snippets.GetByID(ctx, snippetID, userID)
+	if err != nil {
+		return err
+	}
+	if !snippet.CanView(userID) || snippet.IsLocked(userID) ||
+		snippet.BurnsOnViewBy(userID) || snippet.IsExpired(time.Now()) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func TestSnippetAccess(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	snippets := &fakeSnippetRepository{snippets: map[string]*domain.Snippet{
+		"public":  {ID: "public", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPublic},
+		"private": {ID: "private", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPrivate},
+		"locked":  {ID: "locked", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPublic, PasswordHash: "hash"},
+		"burning": {ID: "burning", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPublic, BurnAfterRead: true},
+		"expired": {ID: "expired", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPublic, ExpiresAt: &expired},
+	}}
+	access := NewSnippetAccess(snippets)
+	ctx := context.Background()
+
+	assert.NoError(t, access.CanView(ctx, "public", ""))
+	assert.NoError(t, access.CanView(ctx, "private", "user-1"))
+	assert.ErrorIs(t, access.CanView(ctx, "private", "user-2"), repository.ErrNotFound)
+	assert.ErrorIs(t, access.CanView(ctx, "private", ""), repository.ErrNotFound)
+	assert.ErrorIs(t, access.CanView(ctx, "missing", "user-1"), repository.ErrNotFound)
+	assert.NoError(t, access.CanView(ctx, "locked", "user-1"))
+	assert.ErrorIs(t, access.CanView(ctx, "locked", "user-2"), repository.ErrNotFound)
+	assert.NoError(t, access.CanView(ctx, "burning", "user-1"))
+	assert.ErrorIs(t, access.CanView(ctx, "burning", "user-2"), repository.ErrNotFound)
+	assert.ErrorIs(t, access.CanView(ctx, "burning", ""), repository.ErrNotFound)
+	assert.ErrorIs(t, access.CanView(ctx, "expired", "user-1"), repository.ErrNotFound)
+	assert.ErrorIs(t, access.CanView(ctx, "expired", "user-2"), repository.ErrNotFound)
+}
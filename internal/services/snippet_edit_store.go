@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// ErrEditForbidden is returned when a user may not edit a snippet
+var ErrEditForbidden = errors.New("not allowed to edit this snippet")
+
+// SnippetEditStore loads and persists the documents of collaborative edit sessions,
+// with the same permissions as regular snippet updates
+type SnippetEditStore struct {
+	snippets repository.SnippetRepository
+}
+
+func NewSnippetEditStore(snippets repository.SnippetRepository) *SnippetEditStore {
+	return &SnippetEditStore{snippets: snippets}
+}
+
+// Load returns the content of a snippet the user may edit
+func (s *SnippetEditStore) Load(ctx context.Context, snippetID, userID string) (string, error) {
+	snippet, err := s.snippets.GetByID(ctx, snippetID, userID)
+	if err != nil {
+		return "", err
+	}
+	if !snippet.CanEdit(userID) {
+		return "", ErrEditForbidden
+	}
+	return snippet.Content, nil
+}
+
+// Save replaces the content of a snippet, keeping its other fields, and records the editor.
+// Editors who lost access since they joined cannot save.
+func (s *SnippetEditStore) Save(ctx context.Context, snippetID, content, editorID string) (*domain.Snippet, error) {
+	snippet, err := s.snippets.GetByID(ctx, snippetID, editorID)
+	if err != nil {
+		return nil, err
+	}
+	if !snippet.CanEdit(editorID) {
+		return nil, ErrEditForbidden
+	}
+
+	snippet.Content = content
+	snippet.LastEditor = &domain.User{ID: editorID}
+	if err := s.snippets.Update(ctx, snippet); err != nil {
+		return nil, err
+	}
+	return s.snippets.GetByID(ctx, snippetID, "")
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// fakeSnippetRepository keeps snippets in memory
+type fakeSnippetRepository struct {
+	repository.SnippetRepository
+	snippets map[string]*domain.Snippet
+}
+
+func (r *fakeSnippetRepository) GetByID(ctx context.Context, id string, userID string) (*domain.Snippet, error) {
+	snippet, ok := r.snippets[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *snippet
+	return &copied, nil
+}
+
+func (r *fakeSnippetRepository) Update(ctx context.Context, snippet *domain.Snippet) error {
+	r.snippets[snippet.ID] = snippet
+	return nil
+}
+
+func TestSnippetEditStore(t *testing.T) {
+	orgID := "org-1"
+	snippets := &fakeSnippetRepository{snippets: map[string]*domain.Snippet{
+		"snippet-1": {ID: "snippet-1", Title: "Title", Content: "hello", Language: "go", Author: &domain.User{ID: "user-1"}},
+		"snippet-2": {ID: "snippet-2", Content: "org", Author: &domain.User{ID: "user-1"}, OrganizationID: &orgID, ViewerRole: domain.RoleMaintainer},
+		"snippet-3": {ID: "snippet-3", Content: "shared", Author: &domain.User{ID: "user-1"}, Visibility: domain.SnippetPrivate, Permission: domain.PermissionEdit},
+	}}
+	store := NewSnippetEditStore(snippets)
+	ctx := context.Background()
+
+	content, err := store.Load(ctx, "snippet-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", content)
+
+	_, err = store.Load(ctx, "snippet-1", "user-2")
+	assert.ErrorIs(t, err, ErrEditForbidden)
+
+	// Maintainers of the owning organization may edit snippets of other members
+	content, err = store.Load(ctx, "snippet-2", "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, "org", content)
+
+	// Collaborators with edit permission may edit private snippets
+	content, err = store.Load(ctx, "snippet-3", "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, "shared", content)
+
+	_, err = store.Load(ctx, "missing", "user-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	saved, err := store.Save(ctx, "snippet-3", "shared world", "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, saved, snippets.snippets["snippet-3"])
+	assert.Equal(t, "shared world", saved.Content)
+	assert.Equal(t, domain.SnippetPrivate, saved.Visibility)
+	require.NotNil(t, saved.LastEditor)
+	assert.Equal(t, "user-2", saved.LastEditor.ID)
+
+	// Editors who lost access since they joined cannot save
+	_, err = store.Save(ctx, "snippet-1", "hello world", "user-2")
+	assert.ErrorIs(t, err, ErrEditForbidden)
+	assert.Equal(t, "hello", snippets.snippets["snippet-1"].Content)
+}
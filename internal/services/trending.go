@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// TrendingWindow is the time span a trending ranking covers
+type TrendingWindow string
+
+const (
+	TrendingDay   TrendingWindow = "day"
+	TrendingWeek  TrendingWindow = "week"
+	TrendingMonth TrendingWindow = "month"
+)
+
+// TrendingLimit is the maximum number of snippets in a trending ranking
+const TrendingLimit = 50
+
+// trendingDecay configures how fast interactions lose weight within a window
+type trendingDecay struct {
+	lookback time.Duration // Interactions older than this are ignored
+	halfLife time.Duration // Age at which an interaction counts half
+}
+
+var trendingDecays = map[TrendingWindow]trendingDecay{
+	TrendingDay:   {lookback: 24 * time.Hour, halfLife: 6 * time.Hour},
+	TrendingWeek:  {lookback: 7 * 24 * time.Hour, halfLife: 36 * time.Hour},
+	TrendingMonth: {lookback: 30 * 24 * time.Hour, halfLife: 7 * 24 * time.Hour},
+}
+
+// trendingWeights is the score of a single interaction of each kind
+var trendingWeights = map[repository.ActivityKind]float64{
+	repository.ActivityView: 1,
+	repository.ActivityLike: 4,
+	repository.ActivitySave: 6,
+}
+
+// ParseTrendingWindow parses a window query value, defaulting to a week
+func ParseTrendingWindow(value string) (TrendingWindow, error) {
+	switch TrendingWindow(value) {
+	case "", TrendingWeek:
+		return TrendingWeek, nil
+	case TrendingDay, TrendingMonth:
+		return TrendingWindow(value), nil
+	default:
+		return "", errors.New("window must be one of day, week or month")
+	}
+}
+
+// TrendingService computes and serves time-decayed snippet rankings
+type TrendingService struct {
+	repo   repository.TrendingRepository
+	logger *zap.Logger
+}
+
+// NewTrendingService creates a new trending service
+func NewTrendingService(repo repository.TrendingRepository) *TrendingService {
+	return &TrendingService{
+		repo:   repo,
+		logger: logger.Log,
+	}
+}
+
+// Refresh recomputes and persists the scores of all windows
+func (t *TrendingService) Refresh(ctx context.Context) error {
+	activity, err := t.repo.GetRecentActivity(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, window := range []TrendingWindow{TrendingDay, TrendingWeek, TrendingMonth} {
+		scores := computeTrendingScores(activity, window, now)
+		if err := t.repo.ReplaceScores(ctx, string(window), scores); err != nil {
+			return err
+		}
+
+		t.logger.Debug("refreshed trending scores",
+			zap.String("window", string(window)),
+			zap.Int("snippets", len(scores)),
+		)
+	}
+
+	return nil
+}
+
+// GetTrending returns the highest ranked snippets of a window as of the last refresh
+func (t *TrendingService) GetTrending(ctx context.Context, window TrendingWindow, language, userID string) ([]*domain.Snippet, error) {
+	return t.repo.GetTrending(ctx, string(window), language, userID, TrendingLimit)
+}
+
+// computeTrendingScores sums the weighted interactions of each snippet within
+// the window, halving their weight every half-life. Snippets without any
+// interaction in the window are left out.
+func computeTrendingScores(activity []repository.SnippetActivity, window TrendingWindow, now time.Time) map[string]float64 {
+	decay := trendingDecays[window]
+	scores := make(map[string]float64)
+
+	for _, a := range activity {
+		// Score buckets at their midpoint, but never in the future
+		at := a.At.Add(30 * time.Minute)
+		if a.Kind == repository.ActivityView {
+			at = a.At.Add(12 * time.Hour)
+		}
+		age := max(now.Sub(at), 0)
+		if age > decay.lookback || a.Count <= 0 {
+			continue
+		}
+
+		scores[a.SnippetID] += trendingWeights[a.Kind] * float64(a.Count) * math.Exp2(-age.Hours()/decay.halfLife.Hours())
+	}
+
+	return scores
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func TestParseTrendingWindow(t *testing.T) {
+	window, err := ParseTrendingWindow("")
+	assert.NoError(t, err)
+	assert.Equal(t, TrendingWeek, window)
+
+	window, err = ParseTrendingWindow("day")
+	assert.NoError(t, err)
+	assert.Equal(t, TrendingDay, window)
+
+	_, err = ParseTrendingWindow("year")
+	assert.Error(t, err)
+}
+
+func TestComputeTrendingScores(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	hour := func(hoursAgo int) time.Time {
+		return now.Add(-time.Duration(hoursAgo)*time.Hour - 30*time.Minute)
+	}
+
+	activity := []repository.SnippetActivity{
+		// Fresh likes beat a larger number of older likes
+		{SnippetID: "fresh", Kind: repository.ActivityLike, At: hour(0), Count: 2},
+		{SnippetID: "old", Kind: repository.ActivityLike, At: hour(12), Count: 3},
+		// Saves weigh more than likes
+		{SnippetID: "saved", Kind: repository.ActivitySave, At: hour(0), Count: 2},
+		// Views are bucketed by day and scored at noon
+		{SnippetID: "viewed", Kind: repository.ActivityView, At: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Count: 5},
+		// Outside of the day window
+		{SnippetID: "stale", Kind: repository.ActivityLike, At: hour(48), Count: 100},
+	}
+
+	t.Run("day", func(t *testing.T) {
+		scores := computeTrendingScores(activity, TrendingDay, now)
+
+		assert.InDelta(t, 8, scores["fresh"], 0.001)
+		assert.InDelta(t, 3, scores["old"], 0.001) // Two half-lives
+		assert.Greater(t, scores["fresh"], scores["old"])
+		assert.Greater(t, scores["saved"], scores["fresh"])
+		assert.InDelta(t, 5, scores["viewed"], 0.001)
+		assert.NotContains(t, scores, "stale")
+	})
+
+	t.Run("week", func(t *testing.T) {
+		scores := computeTrendingScores(activity, TrendingWeek, now)
+
+		assert.Contains(t, scores, "stale")
+		assert.Greater(t, scores["stale"], scores["fresh"])
+	})
+}
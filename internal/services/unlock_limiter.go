@@ -0,0 +1,124 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// maxUnlockLockout caps how long repeated lockouts keep a client out of a snippet
+const maxUnlockLockout = 24 * time.Hour
+
+// UnlockLimiterConfig controls how many wrong snippet passwords a client may enter
+type UnlockLimiterConfig struct {
+	MaxFailures int           // Wrong passwords per client and snippet within Window
+	Window      time.Duration // Window the wrong passwords are counted in, and the first lockout
+}
+
+// unlockKey identifies a client guessing the password of a specific snippet
+type unlockKey struct {
+	snippetID string
+	clientIP  string
+}
+
+// unlockAttempts counts the password attempts of a client since the window started
+type unlockAttempts struct {
+	failures    int
+	since       time.Time
+	lockouts    int // Lockouts in a row, each one twice as long as the last
+	lockedUntil time.Time
+}
+
+// UnlockLimiter slows down password guessing on protected snippets by locking a client
+// out of a snippet after too many wrong passwords within a window. Every further lockout
+// of the client lasts twice as long, up to a day, until it stays quiet for a window.
+// Other clients are never locked out, so nobody can keep the legitimate viewers of a
+// snippet from unlocking it. Attempts are counted as failures when they are allowed and
+// refunded when the password was right, so concurrent requests cannot get past the limit.
+type UnlockLimiter struct {
+	maxFailures int
+	window      time.Duration
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	attempts map[unlockKey]*unlockAttempts
+}
+
+func NewUnlockLimiter(config UnlockLimiterConfig) *UnlockLimiter {
+	return &UnlockLimiter{
+		maxFailures: config.MaxFailures,
+		window:      config.Window,
+		now:         time.Now,
+		attempts:    make(map[unlockKey]*unlockAttempts),
+	}
+}
+
+// Allow reserves an attempt for the client and reports whether it may try another
+// password, and otherwise how long it has to wait. The attempt counts as a wrong
+// password until Reset is called.
+func (l *UnlockLimiter) Allow(snippetID, clientIP string) (time.Duration, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	key := unlockKey{snippetID, clientIP}
+	attempts, ok := l.attempts[key]
+	if !ok {
+		attempts = &unlockAttempts{since: now}
+		l.attempts[key] = attempts
+	}
+
+	if now.Before(attempts.lockedUntil) {
+		return attempts.lockedUntil.Sub(now), false
+	}
+	if now.Sub(attempts.since) >= l.window {
+		attempts.failures = 0
+		attempts.since = now
+	}
+	if attempts.failures >= l.maxFailures {
+		attempts.lockedUntil = attempts.since.Add(l.lockout(attempts.lockouts))
+		attempts.lockouts++
+		return attempts.lockedUntil.Sub(now), false
+	}
+
+	attempts.failures++
+	return 0, true
+}
+
+// Reset forgets the wrong passwords and lockouts of a client after it unlocked the
+// snippet, which also refunds the attempt that succeeded
+func (l *UnlockLimiter) Reset(snippetID, clientIP string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.attempts, unlockKey{snippetID, clientIP})
+}
+
+// Prune forgets the clients that stayed quiet for a window after their last attempt or
+// lockout, so the limiter does not grow with every client
+func (l *UnlockLimiter) Prune() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	for key, attempts := range l.attempts {
+		end := attempts.since
+		if attempts.lockedUntil.After(end) {
+			end = attempts.lockedUntil
+		}
+		if now.Sub(end) >= l.window {
+			delete(l.attempts, key)
+		}
+	}
+}
+
+// lockout returns how long the lockout after the given number of earlier lockouts lasts
+func (l *UnlockLimiter) lockout(lockouts int) time.Duration {
+	lockout := l.window
+	for range lockouts {
+		if lockout >= maxUnlockLockout/2 {
+			return maxUnlockLockout
+		}
+		lockout *= 2
+	}
+	return min(lockout, maxUnlockLockout)
+}
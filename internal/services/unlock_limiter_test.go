@@ -0,0 +1,128 @@
+package services
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockLimiter(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewUnlockLimiter(UnlockLimiterConfig{MaxFailures: 3, Window: 15 * time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	for range 3 {
+		_, ok := limiter.Allow("snippet-1", "10.0.0.1")
+		assert.True(t, ok)
+	}
+
+	retryAfter, ok := limiter.Allow("snippet-1", "10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, 15*time.Minute, retryAfter)
+
+	// Other clients and other snippets are not affected
+	_, ok = limiter.Allow("snippet-1", "10.0.0.2")
+	assert.True(t, ok)
+	_, ok = limiter.Allow("snippet-2", "10.0.0.1")
+	assert.True(t, ok)
+
+	now = now.Add(5 * time.Minute)
+	retryAfter, ok = limiter.Allow("snippet-1", "10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, 10*time.Minute, retryAfter)
+
+	now = now.Add(10 * time.Minute)
+	_, ok = limiter.Allow("snippet-1", "10.0.0.1")
+	assert.True(t, ok)
+
+	t.Run("reset after unlocking", func(t *testing.T) {
+		for range 3 {
+			limiter.Allow("snippet-3", "10.0.0.1")
+		}
+		limiter.Reset("snippet-3", "10.0.0.1")
+		_, ok := limiter.Allow("snippet-3", "10.0.0.1")
+		assert.True(t, ok)
+	})
+
+	t.Run("many clients do not lock out others", func(t *testing.T) {
+		for i := range 100 {
+			for range 4 {
+				limiter.Allow("snippet-4", "10.0.1."+strconv.Itoa(i))
+			}
+		}
+
+		_, ok := limiter.Allow("snippet-4", "10.0.2.1")
+		assert.True(t, ok)
+	})
+}
+
+func TestUnlockLimiter_Backoff(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewUnlockLimiter(UnlockLimiterConfig{MaxFailures: 3, Window: time.Hour})
+	limiter.now = func() time.Time { return now }
+
+	// Every lockout in a row lasts twice as long, up to a day
+	for _, lockout := range []time.Duration{time.Hour, 2 * time.Hour, 4 * time.Hour, 8 * time.Hour, 16 * time.Hour, 24 * time.Hour, 24 * time.Hour} {
+		for range 3 {
+			_, ok := limiter.Allow("snippet-1", "10.0.0.1")
+			assert.True(t, ok)
+		}
+		retryAfter, ok := limiter.Allow("snippet-1", "10.0.0.1")
+		assert.False(t, ok)
+		assert.Equal(t, lockout, retryAfter)
+		now = now.Add(retryAfter)
+	}
+
+	// Staying quiet for a window after the lockout starts over
+	now = now.Add(time.Hour)
+	limiter.Prune()
+	for range 3 {
+		limiter.Allow("snippet-1", "10.0.0.1")
+	}
+	retryAfter, ok := limiter.Allow("snippet-1", "10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Hour, retryAfter)
+}
+
+func TestUnlockLimiter_Prune(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewUnlockLimiter(UnlockLimiterConfig{MaxFailures: 3, Window: 15 * time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("snippet-1", "10.0.0.1")
+	for range 4 {
+		limiter.Allow("snippet-1", "10.0.0.2")
+	}
+
+	// Lockouts are kept for a window after they end
+	now = now.Add(15 * time.Minute)
+	limiter.Prune()
+	assert.Len(t, limiter.attempts, 1)
+
+	now = now.Add(15 * time.Minute)
+	limiter.Prune()
+	assert.Empty(t, limiter.attempts)
+}
+
+func TestUnlockLimiter_Concurrent(t *testing.T) {
+	limiter := NewUnlockLimiter(UnlockLimiterConfig{MaxFailures: 5, Window: 15 * time.Minute})
+
+	// Concurrent guesses from one client get exactly as many attempts as sequential ones
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := limiter.Allow("snippet-1", "10.0.0.1"); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(5), allowed.Load())
+}
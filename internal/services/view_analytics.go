@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// Granularity is the size of the time buckets of an analytics series
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+// MaxAnalyticsRange is the longest time span a single analytics query may cover
+const MaxAnalyticsRange = 2 * 366 * 24 * time.Hour
+
+// ErrInvalidAnalyticsRange is returned for empty, inverted or too long ranges
+var ErrInvalidAnalyticsRange = errors.New("invalid analytics range")
+
+// ParseGranularity parses a granularity query value, defaulting to days
+func ParseGranularity(value string) (Granularity, error) {
+	switch Granularity(value) {
+	case "", GranularityDay:
+		return GranularityDay, nil
+	case GranularityWeek, GranularityMonth:
+		return Granularity(value), nil
+	default:
+		return "", errors.New("granularity must be one of day, week or month")
+	}
+}
+
+// ViewAnalytics serves per-snippet view analytics time series
+type ViewAnalytics struct {
+	viewRepo repository.ViewRepository
+	logger   *zap.Logger
+}
+
+// NewViewAnalytics creates a new view analytics service
+func NewViewAnalytics(viewRepo repository.ViewRepository) *ViewAnalytics {
+	return &ViewAnalytics{
+		viewRepo: viewRepo,
+		logger:   logger.Log,
+	}
+}
+
+// Aggregate rolls raw view records up into daily stats.
+// It is idempotent and must run before old view records are cleaned up.
+func (a *ViewAnalytics) Aggregate(ctx context.Context) error {
+	return a.viewRepo.AggregateDailyStats(ctx)
+}
+
+// GetSnippetAnalytics returns one point per bucket between from and to (inclusive).
+// Buckets without activity are included with zero values. Unique viewers of
+// week and month buckets are the sum of daily unique viewers.
+func (a *ViewAnalytics) GetSnippetAnalytics(ctx context.Context, snippetID string, from, to time.Time, granularity Granularity) ([]domain.SnippetStatsPoint, error) {
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) || to.Sub(from) > MaxAnalyticsRange {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	days, err := a.viewRepo.GetDailyStats(ctx, snippetID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create every bucket up front so gaps are reported as zero
+	points := make([]domain.SnippetStatsPoint, 0)
+	index := make(map[time.Time]int)
+	for start := bucketStart(from, granularity); !start.After(to); start = nextBucket(start, granularity) {
+		index[start] = len(points)
+		points = append(points, domain.SnippetStatsPoint{Start: start})
+	}
+
+	for _, day := range days {
+		i, ok := index[bucketStart(day.Start, granularity)]
+		if !ok {
+			continue
+		}
+		points[i].Views += day.Views
+		points[i].UniqueViewers += day.UniqueViewers
+		points[i].Likes += day.Likes
+		points[i].BotViews += day.BotViews
+	}
+
+	a.logger.Debug("computed snippet analytics",
+		zap.String("snippet_id", snippetID),
+		zap.String("granularity", string(granularity)),
+		zap.Int("days", len(days)),
+		zap.Int("points", len(points)),
+	)
+
+	return points, nil
+}
+
+// truncateDay returns midnight UTC of the given time's day
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// bucketStart returns the first day of the bucket containing day.
+// Weeks start on Monday.
+func bucketStart(day time.Time, granularity Granularity) time.Time {
+	day = truncateDay(day)
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextBucket returns the start of the bucket following start
+func nextBucket(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// fakeStatsRepository returns fixed daily stats
+type fakeStatsRepository struct {
+	repository.ViewRepository
+	days []*domain.SnippetStatsPoint
+}
+
+func (r *fakeStatsRepository) GetDailyStats(ctx context.Context, snippetID string, from, to time.Time) ([]*domain.SnippetStatsPoint, error) {
+	return r.days, nil
+}
+
+func day(value string) time.Time {
+	t, _ := time.Parse(time.DateOnly, value)
+	return t
+}
+
+func TestViewAnalytics_GetSnippetAnalytics(t *testing.T) {
+	setupTestLogger(t)
+
+	repo := &fakeStatsRepository{days: []*domain.SnippetStatsPoint{
+		{Start: day("2025-03-03"), Views: 2, UniqueViewers: 2, Likes: 1}, // Monday
+		{Start: day("2025-03-05"), Views: 3, UniqueViewers: 1},
+		{Start: day("2025-03-12"), Views: 1, UniqueViewers: 1},
+	}}
+	analytics := NewViewAnalytics(repo)
+
+	t.Run("day fills gaps", func(t *testing.T) {
+		points, err := analytics.GetSnippetAnalytics(context.Background(), "snippet-1", day("2025-03-03"), day("2025-03-06"), GranularityDay)
+		assert.NoError(t, err)
+		assert.Len(t, points, 4)
+		assert.Equal(t, 2, points[0].Views)
+		assert.Equal(t, 0, points[1].Views)
+		assert.Equal(t, 3, points[2].Views)
+		assert.Equal(t, 0, points[3].Views)
+	})
+
+	t.Run("week", func(t *testing.T) {
+		points, err := analytics.GetSnippetAnalytics(context.Background(), "snippet-1", day("2025-03-04"), day("2025-03-12"), GranularityWeek)
+		assert.NoError(t, err)
+		assert.Len(t, points, 2)
+		assert.Equal(t, day("2025-03-03"), points[0].Start)
+		assert.Equal(t, 5, points[0].Views)
+		assert.Equal(t, 3, points[0].UniqueViewers)
+		assert.Equal(t, 1, points[0].Likes)
+		assert.Equal(t, day("2025-03-10"), points[1].Start)
+		assert.Equal(t, 1, points[1].Views)
+	})
+
+	t.Run("month", func(t *testing.T) {
+		points, err := analytics.GetSnippetAnalytics(context.Background(), "snippet-1", day("2025-02-15"), day("2025-03-31"), GranularityMonth)
+		assert.NoError(t, err)
+		assert.Len(t, points, 2)
+		assert.Equal(t, 0, points[0].Views)
+		assert.Equal(t, 6, points[1].Views)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := analytics.GetSnippetAnalytics(context.Background(), "snippet-1", day("2025-03-12"), day("2025-03-03"), GranularityDay)
+		assert.ErrorIs(t, err, ErrInvalidAnalyticsRange)
+
+		_, err = analytics.GetSnippetAnalytics(context.Background(), "snippet-1", day("2020-01-01"), day("2025-03-03"), GranularityDay)
+		assert.ErrorIs(t, err, ErrInvalidAnalyticsRange)
+	})
+}
+
+func TestParseGranularity(t *testing.T) {
+	granularity, err := ParseGranularity("")
+	assert.NoError(t, err)
+	assert.Equal(t, GranularityDay, granularity)
+
+	granularity, err = ParseGranularity("month")
+	assert.NoError(t, err)
+	assert.Equal(t, GranularityMonth, granularity)
+
+	_, err = ParseGranularity("year")
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// ViewPrivacyConfig controls how viewer identifiers and IP addresses are stored
+type ViewPrivacyConfig struct {
+	HashSecret     string // Key for the viewer identifier and IP address hashes
+	StoreIP        bool   // Whether to store hashed IP addresses at all
+	IPv4PrefixBits int    // Leading bits of IPv4 addresses kept before hashing
+	IPv6PrefixBits int    // Leading bits of IPv6 addresses kept before hashing
+}
+
+// viewerHasher pseudonymizes viewer identifiers and IP addresses with a keyed hash.
+// The salt is derived from the secret and the UTC day, so restarts and replicas hash a
+// viewer to the same identifier within a day, while hashes from different days cannot be
+// linked to each other without the secret.
+type viewerHasher struct {
+	config ViewPrivacyConfig
+	now    func() time.Time
+
+	mutex sync.Mutex
+	day   string
+	salt  []byte
+}
+
+func newViewerHasher(config ViewPrivacyConfig) *viewerHasher {
+	return &viewerHasher{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// currentSalt returns the salt of the current UTC day, deriving it again if the day changed
+func (h *viewerHasher) currentSalt() []byte {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	day := h.now().UTC().Format(time.DateOnly)
+	if day != h.day {
+		mac := hmac.New(sha256.New, []byte(h.config.HashSecret))
+		mac.Write([]byte("daily-salt\x00" + day))
+		h.day = day
+		h.salt = mac.Sum(nil)
+	}
+	return h.salt
+}
+
+// hash returns the hex encoded keyed hash of value within the given domain
+func (h *viewerHasher) hash(domain, value string) string {
+	mac := hmac.New(sha256.New, []byte(h.config.HashSecret))
+	mac.Write(h.currentSalt())
+	mac.Write([]byte(domain))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Identifier returns the pseudonymous viewer identifier for a raw value of the given kind
+func (h *viewerHasher) Identifier(kind, value string) string {
+	return kind + ":" + h.hash(kind, value)
+}
+
+// IPAddress returns the value stored for a client IP, or "" if IPs are not stored
+func (h *viewerHasher) IPAddress(ip string) string {
+	if !h.config.StoreIP || ip == "" {
+		return ""
+	}
+	return h.hash("ip_address", truncateIP(ip, h.config.IPv4PrefixBits, h.config.IPv6PrefixBits))
+}
+
+// truncateIP zeroes all but the leading prefix bits of an IP address.
+// Values that are not IP addresses are returned unchanged.
+func truncateIP(ip string, ipv4Bits, ipv6Bits int) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+	addr = addr.Unmap().WithZone("")
+
+	bits := ipv6Bits
+	if addr.Is4() {
+		bits = ipv4Bits
+	}
+	bits = max(0, min(bits, addr.BitLen()))
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ip
+	}
+	return prefix.Addr().String()
+}
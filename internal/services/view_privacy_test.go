@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewerHasher_Identifier(t *testing.T) {
+	now := time.Date(2025, 3, 3, 23, 59, 0, 0, time.UTC)
+	hasher := newViewerHasher(ViewPrivacyConfig{HashSecret: "test-secret"})
+	hasher.now = func() time.Time { return now }
+
+	identifier := hasher.Identifier("session", "raw-session-token")
+	assert.True(t, strings.HasPrefix(identifier, "session:"))
+	assert.NotContains(t, identifier, "raw-session-token")
+
+	t.Run("stable within a day", func(t *testing.T) {
+		assert.Equal(t, identifier, hasher.Identifier("session", "raw-session-token"))
+		assert.NotEqual(t, identifier, hasher.Identifier("session", "other-session-token"))
+	})
+
+	t.Run("stable across restarts and replicas", func(t *testing.T) {
+		restarted := newViewerHasher(ViewPrivacyConfig{HashSecret: "test-secret"})
+		restarted.now = hasher.now
+		assert.Equal(t, identifier, restarted.Identifier("session", "raw-session-token"))
+	})
+
+	t.Run("rotates with the day", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		assert.NotEqual(t, identifier, hasher.Identifier("session", "raw-session-token"))
+	})
+
+	t.Run("depends on the secret", func(t *testing.T) {
+		other := newViewerHasher(ViewPrivacyConfig{HashSecret: "other-secret"})
+		other.now = hasher.now
+		assert.NotEqual(t, hasher.Identifier("user", "user-1"), other.Identifier("user", "user-1"))
+	})
+}
+
+func TestViewerHasher_IPAddress(t *testing.T) {
+	t.Run("truncated before hashing", func(t *testing.T) {
+		hasher := newViewerHasher(ViewPrivacyConfig{HashSecret: "test-secret", StoreIP: true, IPv4PrefixBits: 24, IPv6PrefixBits: 48})
+
+		hashed := hasher.IPAddress("192.168.1.77")
+		assert.NotContains(t, hashed, "192.168")
+		assert.Equal(t, hashed, hasher.IPAddress("192.168.1.10"))
+		assert.NotEqual(t, hashed, hasher.IPAddress("192.168.2.77"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		hasher := newViewerHasher(ViewPrivacyConfig{HashSecret: "test-secret", StoreIP: false})
+		assert.Empty(t, hasher.IPAddress("192.168.1.77"))
+	})
+}
+
+func TestTruncateIP(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"192.168.1.77", "192.168.1.0"},
+		{"::ffff:192.168.1.77", "192.168.1.0"},
+		{"2001:db8:abcd:12:34::1", "2001:db8:abcd::"},
+		{"fe80::1%eth0", "fe80::"},
+		{"not-an-ip", "not-an-ip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.expected, truncateIP(tt.ip, 24, 48))
+		})
+	}
+
+	t.Run("full length keeps address", func(t *testing.T) {
+		assert.Equal(t, "192.168.1.77", truncateIP("192.168.1.77", 64, 128))
+	})
+}
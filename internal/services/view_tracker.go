@@ -3,177 +3,333 @@ package services
 import (
 	"context"
 	"net/http"
-	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"mitsimi.dev/codeShare/internal/logger"
 	"mitsimi.dev/codeShare/internal/repository"
 )
 
-// ViewTracker handles view counting with debouncing logic
+// StatsBroadcaster publishes updated snippet counters to live subscribers
+type StatsBroadcaster interface {
+	BroadcastSnippetStatsUpdate(snippetID string, viewCount, likeCount *int)
+}
+
+// viewKey identifies a viewer of a specific snippet
+type viewKey struct {
+	snippetID        string
+	viewerIdentifier string
+	bot              bool
+}
+
+// ViewTracker handles view counting with debouncing logic.
+// Views are deduplicated in memory and written to the database in batches.
 type ViewTracker struct {
-	viewRepo repository.ViewRepository
-	logger   *zap.Logger
+	viewRepo    repository.ViewRepository
+	broadcaster StatsBroadcaster
+	hasher      *viewerHasher
+	bots        *BotClassifier
+	logger      *zap.Logger
 
 	// Configuration
-	ViewCooldownMinutes int // Time before counting another view from same viewer
+	ViewCooldownMinutes int           // Time before counting another view from same viewer
+	FlushInterval       time.Duration // Time between writes of buffered views to the database
+	MaxFlushAttempts    int           // Failed flushes of a buffered view before it is dropped, not counting a busy database
+
+	mutex       sync.Mutex
+	lastCounted map[viewKey]time.Time                // Last time a view was counted per viewer
+	pending     map[viewKey]*repository.BufferedView // View attempts not yet written
+	increments  map[string]int                       // snippetID -> views not yet added to the public counter
+	failures    map[viewKey]int                      // Failed flushes of views still in pending
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 // NewViewTracker creates a new view tracker with default settings
-func NewViewTracker(viewRepo repository.ViewRepository) *ViewTracker {
+func NewViewTracker(viewRepo repository.ViewRepository, broadcaster StatsBroadcaster, privacy ViewPrivacyConfig, bots *BotClassifier) *ViewTracker {
 	return &ViewTracker{
 		viewRepo:            viewRepo,
+		broadcaster:         broadcaster,
+		hasher:              newViewerHasher(privacy),
+		bots:                bots,
 		logger:              logger.Log,
 		ViewCooldownMinutes: 10,
+		FlushInterval:       10 * time.Second,
+		MaxFlushAttempts:    5,
+		lastCounted:         make(map[viewKey]time.Time),
+		pending:             make(map[viewKey]*repository.BufferedView),
+		increments:          make(map[string]int),
+		failures:            make(map[viewKey]int),
+		stop:                make(chan struct{}),
 	}
 }
 
-// ViewerIdentifier extracts a unique identifier for the viewer
+// ViewerIdentifier extracts a pseudonymous identifier for the viewer.
+// Raw user IDs, session tokens and IPs are never returned, only their daily salted hashes.
 func (vt *ViewTracker) ViewerIdentifier(r *http.Request, userID string) string {
 	// Use userID if authenticated, otherwise use session cookie or create temporary identifier
 	if userID != "" {
-		return "user:" + userID
+		return vt.hasher.Identifier("user", userID)
 	}
 
 	// Try to get session cookie
 	if cookie, err := r.Cookie("session"); err == nil {
-		return "session:" + cookie.Value
+		return vt.hasher.Identifier("session", cookie.Value)
 	}
 
 	// Fallback to IP address (less reliable but better than nothing)
-	return "ip:" + GetClientIP(r)
+	return vt.hasher.Identifier("ip", GetClientIP(r))
 }
 
-// GetClientIP extracts the real client IP from request
-func GetClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header first (for proxies)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return cleanIPAddress(xff)
-	}
-
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return cleanIPAddress(xri)
+// shouldCountView determines if a view should be counted based on debouncing rules.
+// Must be called with the mutex held.
+func (vt *ViewTracker) shouldCountView(key viewKey, now time.Time) bool {
+	lastCounted, ok := vt.lastCounted[key]
+	if !ok {
+		return true
 	}
+	return now.Sub(lastCounted) >= vt.cooldown()
+}
 
-	// Fallback to RemoteAddr (remove port if present)
-	return cleanIPAddress(r.RemoteAddr)
+func (vt *ViewTracker) cooldown() time.Duration {
+	return time.Duration(vt.ViewCooldownMinutes) * time.Minute
 }
 
-// cleanIPAddress removes port from IP address if present
-func cleanIPAddress(addr string) string {
-	// Handle IPv6 addresses like [::1]:64011
-	if strings.HasPrefix(addr, "[") {
-		if idx := strings.LastIndex(addr, "]:"); idx != -1 {
-			return addr[1:idx] // Extract IP without brackets and port
+// TrackView buffers a view and counts it if appropriate.
+// Views by bots are recorded for analytics but never counted.
+// Nothing is written to the database until the next flush.
+func (vt *ViewTracker) TrackView(r *http.Request, snippetID, userID string) {
+	viewerIdentifier := vt.ViewerIdentifier(r, userID)
+	clientIP := vt.hasher.IPAddress(GetClientIP(r))
+	bot, reason := vt.bots.Classify(r)
+	key := viewKey{snippetID: snippetID, viewerIdentifier: viewerIdentifier, bot: bot}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	vt.mutex.Lock()
+	defer vt.mutex.Unlock()
+
+	// Always record the view attempt (for analytics)
+	view, ok := vt.pending[key]
+	if !ok {
+		view = &repository.BufferedView{
+			SnippetID:        snippetID,
+			ViewerIdentifier: viewerIdentifier,
+			Bot:              bot,
 		}
-		return addr // Return as-is if no port found
+		vt.pending[key] = view
 	}
+	view.IPAddress = clientIP
+	view.LastViewedAt = now
+	view.Hits++
 
-	// Handle IPv4 addresses like 192.168.1.1:8080
-	if idx := strings.LastIndex(addr, ":"); idx != -1 {
-		return addr[:idx] // Extract IP without port
+	if bot {
+		vt.logger.Debug("view not counted (bot)",
+			zap.String("snippet_id", snippetID),
+			zap.String("viewer_identifier", viewerIdentifier),
+			zap.String("reason", reason),
+		)
+		return
 	}
 
-	return addr // Return as-is if no port found
+	// Only increment the public view count if cooldown has passed
+	if vt.shouldCountView(key, now) {
+		vt.lastCounted[key] = now
+		vt.increments[snippetID]++
+		view.Counted++
+
+		vt.logger.Debug("view counted",
+			zap.String("snippet_id", snippetID),
+			zap.String("viewer_identifier", viewerIdentifier),
+			zap.String("user_id", userID),
+		)
+	} else {
+		vt.logger.Debug("view not counted (cooldown active)",
+			zap.String("snippet_id", snippetID),
+			zap.String("viewer_identifier", viewerIdentifier),
+			zap.String("user_id", userID),
+		)
+	}
 }
 
-// ShouldCountView determines if a view should be counted based on debouncing rules
-func (vt *ViewTracker) ShouldCountView(ctx context.Context, snippetID, viewerIdentifier string) (bool, error) {
-	vt.logger.Debug("checking if view should be counted",
-		zap.String("snippet_id", snippetID),
-		zap.String("viewer_identifier", viewerIdentifier),
-		zap.Int("cooldown_minutes", vt.ViewCooldownMinutes),
-	)
+// Flush writes all buffered views to the database in a single transaction
+// and broadcasts the new counters of every snippet whose view count changed.
+// Views the repository rejects are kept and retried with the next flush.
+func (vt *ViewTracker) Flush(ctx context.Context) error {
+	vt.mutex.Lock()
+	views := make([]repository.BufferedView, 0, len(vt.pending))
+	for _, view := range vt.pending {
+		views = append(views, *view)
+	}
+	increments := vt.increments
+	vt.pending = make(map[viewKey]*repository.BufferedView)
+	vt.increments = make(map[string]int)
+	vt.pruneLastCounted(time.Now().UTC())
+	vt.mutex.Unlock()
 
-	recentView, err := vt.viewRepo.CheckRecentView(ctx, snippetID, viewerIdentifier)
+	if len(views) == 0 && len(increments) == 0 {
+		return nil
+	}
+
+	rejected, err := vt.viewRepo.FlushViews(ctx, views, increments)
 	if err != nil {
-		if err == repository.ErrNotFound {
-			// No previous view found, should count
-			vt.logger.Debug("no previous view found, counting view",
-				zap.String("snippet_id", snippetID),
-				zap.String("viewer_identifier", viewerIdentifier),
-			)
-			return true, nil
-		}
-		vt.logger.Error("error checking recent view",
+		vt.logger.Error("failed to flush buffered views",
 			zap.Error(err),
-			zap.String("snippet_id", snippetID),
-			zap.String("viewer_identifier", viewerIdentifier),
+			zap.Int("views", len(views)),
+			zap.Int("snippets", len(increments)),
 		)
-		return false, err
+		// Keep the batch so it is retried on the next flush. A busy database fails
+		// every batch alike, so it does not count against the views' attempts.
+		vt.requeue(views, increments, !repository.IsUnavailable(err))
+		return err
 	}
 
-	// Check if enough time has passed since last view
-	cooldownSeconds := int64(vt.ViewCooldownMinutes * 60)
-	shouldCount := recentView.SecondsSinceLastView >= cooldownSeconds
+	// Views the repository could not write are retried on their own
+	failed := make(map[viewKey]bool, len(rejected))
+	if len(rejected) > 0 {
+		vt.logger.Warn("some buffered views could not be written",
+			zap.Int("views", len(rejected)),
+		)
+		rejectedIncrements := make(map[string]int)
+		for _, view := range rejected {
+			failed[keyOf(view)] = true
+			rejectedIncrements[view.SnippetID] += view.Counted
+		}
+		vt.requeue(rejected, rejectedIncrements, true)
+	}
 
-	vt.logger.Debug("recent view found, checking cooldown",
-		zap.String("snippet_id", snippetID),
-		zap.String("viewer_identifier", viewerIdentifier),
-		zap.Int64("seconds_since_last_view", recentView.SecondsSinceLastView),
-		zap.Int64("cooldown_seconds", cooldownSeconds),
-		zap.Bool("should_count", shouldCount),
-		zap.Time("last_viewed_at", recentView.LastViewedAt),
+	vt.mutex.Lock()
+	for _, view := range views {
+		if key := keyOf(view); !failed[key] {
+			delete(vt.failures, key)
+		}
+	}
+	vt.mutex.Unlock()
+
+	vt.logger.Debug("flushed buffered views",
+		zap.Int("views", len(views)),
+		zap.Int("snippets", len(increments)),
 	)
 
-	return shouldCount, nil
+	vt.broadcastStats(ctx, increments)
+	return nil
 }
 
-// TrackView records a view and increments the counter if appropriate
-func (vt *ViewTracker) TrackView(ctx context.Context, r *http.Request, snippetID, userID string) error {
-	viewerIdentifier := vt.ViewerIdentifier(r, userID)
-	clientIP := GetClientIP(r)
+// keyOf returns the buffer key of a view
+func keyOf(view repository.BufferedView) viewKey {
+	return viewKey{snippetID: view.SnippetID, viewerIdentifier: view.ViewerIdentifier, bot: view.Bot}
+}
 
-	vt.logger.Debug("tracking view",
-		zap.String("snippet_id", snippetID),
-		zap.String("viewer_identifier", viewerIdentifier),
-		zap.String("client_ip", clientIP),
-		zap.String("user_id", userID),
-	)
+// requeue merges views that failed to flush back into the buffers. With countFailure,
+// views that failed MaxFlushAttempts times are dropped, so a view that can never be
+// written does not keep being retried.
+func (vt *ViewTracker) requeue(views []repository.BufferedView, increments map[string]int, countFailure bool) {
+	vt.mutex.Lock()
+	defer vt.mutex.Unlock()
 
-	shouldCount, err := vt.ShouldCountView(ctx, snippetID, viewerIdentifier)
-	if err != nil {
-		vt.logger.Error("failed to check if view should be counted",
-			zap.Error(err),
-			zap.String("snippet_id", snippetID),
-			zap.String("viewer_identifier", viewerIdentifier),
-		)
-		return err
+	dropped := 0
+	for _, view := range views {
+		key := keyOf(view)
+		if countFailure {
+			vt.failures[key]++
+		}
+		if vt.failures[key] >= vt.MaxFlushAttempts {
+			delete(vt.failures, key)
+			increments[view.SnippetID] -= view.Counted
+			dropped++
+			continue
+		}
+		if newer, ok := vt.pending[key]; ok {
+			// Newer attempts keep their IP and timestamp
+			newer.Hits += view.Hits
+			newer.Counted += view.Counted
+			continue
+		}
+		requeued := view
+		vt.pending[key] = &requeued
 	}
 
-	// Always record the view attempt (for analytics)
-	if err := vt.viewRepo.RecordView(ctx, snippetID, viewerIdentifier, clientIP); err != nil {
-		vt.logger.Error("failed to record view",
-			zap.Error(err),
-			zap.String("snippet_id", snippetID),
-			zap.String("viewer_identifier", viewerIdentifier),
+	for snippetID, count := range increments {
+		if count > 0 {
+			vt.increments[snippetID] += count
+		}
+	}
+
+	if dropped > 0 {
+		vt.logger.Error("dropped buffered views after repeated flush failures",
+			zap.Int("views", dropped),
+			zap.Int("attempts", vt.MaxFlushAttempts),
 		)
-		return err
 	}
+}
 
-	// Only increment the public view count if cooldown has passed
-	if shouldCount {
-		if err := vt.viewRepo.IncrementViewCount(ctx, snippetID); err != nil {
-			vt.logger.Error("failed to increment view count",
+// pruneLastCounted forgets viewers whose cooldown has expired.
+// Must be called with the mutex held.
+func (vt *ViewTracker) pruneLastCounted(now time.Time) {
+	for key, lastCounted := range vt.lastCounted {
+		if now.Sub(lastCounted) >= vt.cooldown() {
+			delete(vt.lastCounted, key)
+		}
+	}
+}
+
+// broadcastStats sends one stats update per snippet with new views
+func (vt *ViewTracker) broadcastStats(ctx context.Context, increments map[string]int) {
+	if vt.broadcaster == nil {
+		return
+	}
+
+	for snippetID := range increments {
+		stats, err := vt.viewRepo.GetSnippetStats(ctx, snippetID)
+		if err != nil {
+			vt.logger.Warn("failed to get snippet stats for broadcast",
 				zap.Error(err),
 				zap.String("snippet_id", snippetID),
 			)
-			return err
+			continue
 		}
+		vt.broadcaster.BroadcastSnippetStatsUpdate(snippetID, &stats.Views, &stats.Likes)
+	}
+}
 
-		vt.logger.Debug("view counted",
-			zap.String("snippet_id", snippetID),
-			zap.String("viewer_identifier", viewerIdentifier),
-		)
-	} else {
-		vt.logger.Debug("view not counted (cooldown active)",
-			zap.String("snippet_id", snippetID),
-			zap.String("viewer_identifier", viewerIdentifier),
-		)
+// Start begins flushing buffered views in the background every FlushInterval
+func (vt *ViewTracker) Start() {
+	vt.done = make(chan struct{})
+
+	go func() {
+		defer close(vt.done)
+
+		ticker := time.NewTicker(vt.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				vt.Flush(ctx) // Failures are logged and retried on the next tick
+				cancel()
+			case <-vt.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background flusher and writes all remaining buffered views
+func (vt *ViewTracker) Stop(ctx context.Context) error {
+	vt.stopOnce.Do(func() { close(vt.stop) })
+
+	if vt.done != nil {
+		select {
+		case <-vt.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
-	return nil
+	return vt.Flush(ctx)
 }
 
 // CleanupOldViews removes old view tracking records (should be called periodically)
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// fakeViewRepository records flushed batches in memory
+type fakeViewRepository struct {
+	repository.ViewRepository
+
+	mutex      sync.Mutex
+	views      map[string]int // viewer identifier -> hits
+	botViews   map[string]int // viewer identifier -> bot hits
+	increments map[string]int
+	flushes    int
+	failNext   bool
+	failAlways bool
+	busy       int             // Flushes failing because the database is locked
+	reject     map[string]bool // Viewer identifiers whose views cannot be written
+}
+
+func newFakeViewRepository() *fakeViewRepository {
+	return &fakeViewRepository{
+		views:      make(map[string]int),
+		botViews:   make(map[string]int),
+		increments: make(map[string]int),
+	}
+}
+
+func (r *fakeViewRepository) FlushViews(ctx context.Context, views []repository.BufferedView, increments map[string]int) ([]repository.BufferedView, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.failAlways {
+		return nil, errors.New("constraint failed")
+	}
+	if r.failNext {
+		r.failNext = false
+		return nil, errors.New("disk I/O error")
+	}
+	if r.busy > 0 {
+		r.busy--
+		return nil, fmt.Errorf("%w: database is locked", repository.ErrUnavailable)
+	}
+
+	r.flushes++
+	increments = maps.Clone(increments)
+	var rejected []repository.BufferedView
+	for _, view := range views {
+		if r.reject[view.ViewerIdentifier] {
+			increments[view.SnippetID] -= view.Counted
+			rejected = append(rejected, view)
+			continue
+		}
+		if view.Bot {
+			r.botViews[view.ViewerIdentifier] += view.Hits
+			continue
+		}
+		r.views[view.ViewerIdentifier] += view.Hits
+	}
+	for snippetID, count := range increments {
+		r.increments[snippetID] += count
+	}
+	return rejected, nil
+}
+
+func (r *fakeViewRepository) GetSnippetStats(ctx context.Context, snippetID string) (*repository.SnippetStats, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return &repository.SnippetStats{Views: r.increments[snippetID]}, nil
+}
+
+// fakeBroadcaster records stats broadcasts
+type fakeBroadcaster struct {
+	mutex sync.Mutex
+	calls map[string][]int // snippetID -> broadcast view counts
+}
+
+func (b *fakeBroadcaster) BroadcastSnippetStatsUpdate(snippetID string, viewCount, likeCount *int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.calls == nil {
+		b.calls = make(map[string][]int)
+	}
+	b.calls[snippetID] = append(b.calls[snippetID], *viewCount)
+}
+
+func setupTestLogger(t *testing.T) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+}
+
+// newBrowserRequest creates a request with the headers a browser would send
+func newBrowserRequest(method, target string) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
+	r.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	return r
+}
+
+func setupViewTracker(t *testing.T) (*ViewTracker, *fakeViewRepository, *fakeBroadcaster) {
+	setupTestLogger(t)
+
+	repo := newFakeViewRepository()
+	broadcaster := &fakeBroadcaster{}
+	privacy := ViewPrivacyConfig{HashSecret: "test-secret", StoreIP: true, IPv4PrefixBits: 24, IPv6PrefixBits: 48}
+	return NewViewTracker(repo, broadcaster, privacy, NewBotClassifier(nil)), repo, broadcaster
+}
+
+func TestViewTracker_TrackViewDeduplicates(t *testing.T) {
+	vt, repo, broadcaster := setupViewTracker(t)
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	user1 := vt.ViewerIdentifier(r, "user-1")
+	user2 := vt.ViewerIdentifier(r, "user-2")
+	for range 3 {
+		vt.TrackView(r, "snippet-1", "user-1")
+	}
+	vt.TrackView(r, "snippet-1", "user-2")
+	vt.TrackView(r, "snippet-2", "user-1")
+
+	// Nothing is written before the flush
+	assert.Equal(t, 0, repo.flushes)
+
+	err := vt.Flush(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, repo.flushes)
+	assert.Equal(t, 2, repo.increments["snippet-1"])
+	assert.Equal(t, 1, repo.increments["snippet-2"])
+	assert.Equal(t, 4, repo.views[user1])
+	assert.Equal(t, 1, repo.views[user2])
+
+	// One coalesced broadcast per snippet
+	assert.Equal(t, []int{2}, broadcaster.calls["snippet-1"])
+	assert.Equal(t, []int{1}, broadcaster.calls["snippet-2"])
+
+	t.Run("cooldown survives flush", func(t *testing.T) {
+		vt.TrackView(r, "snippet-1", "user-1")
+		err := vt.Flush(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, repo.increments["snippet-1"])
+		assert.Equal(t, 5, repo.views[user1])
+	})
+
+	t.Run("empty flush is a no-op", func(t *testing.T) {
+		flushes := repo.flushes
+		err := vt.Flush(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, flushes, repo.flushes)
+	})
+}
+
+func TestViewTracker_FlushRetriesFailedBatch(t *testing.T) {
+	vt, repo, broadcaster := setupViewTracker(t)
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	user1 := vt.ViewerIdentifier(r, "user-1")
+	user2 := vt.ViewerIdentifier(r, "user-2")
+	vt.TrackView(r, "snippet-1", "user-1")
+
+	repo.failNext = true
+	err := vt.Flush(context.Background())
+	assert.Error(t, err)
+	assert.Empty(t, broadcaster.calls)
+
+	vt.TrackView(r, "snippet-1", "user-2")
+	err = vt.Flush(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, repo.increments["snippet-1"])
+	assert.Equal(t, 1, repo.views[user1])
+	assert.Equal(t, 1, repo.views[user2])
+}
+
+func TestViewTracker_FlushDropsBatchThatKeepsFailing(t *testing.T) {
+	vt, repo, _ := setupViewTracker(t)
+	vt.MaxFlushAttempts = 3
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	user2 := vt.ViewerIdentifier(r, "user-2")
+	vt.TrackView(r, "snippet-1", "user-1")
+
+	repo.failAlways = true
+	for range vt.MaxFlushAttempts {
+		assert.Error(t, vt.Flush(context.Background()))
+	}
+
+	vt.mutex.Lock()
+	assert.Empty(t, vt.pending)
+	assert.Empty(t, vt.increments)
+	assert.Empty(t, vt.failures)
+	vt.mutex.Unlock()
+
+	// Later views are persisted once the repository recovers
+	repo.failAlways = false
+	vt.TrackView(r, "snippet-1", "user-2")
+	assert.NoError(t, vt.Flush(context.Background()))
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+	assert.Equal(t, 1, repo.views[user2])
+}
+
+func TestViewTracker_FlushKeepsRetryingBusyDatabase(t *testing.T) {
+	vt, repo, _ := setupViewTracker(t)
+	vt.MaxFlushAttempts = 3
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	user1 := vt.ViewerIdentifier(r, "user-1")
+	vt.TrackView(r, "snippet-1", "user-1")
+
+	repo.busy = vt.MaxFlushAttempts * 2
+	for range repo.busy {
+		assert.ErrorIs(t, vt.Flush(context.Background()), repository.ErrUnavailable)
+	}
+
+	assert.NoError(t, vt.Flush(context.Background()))
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+	assert.Equal(t, 1, repo.views[user1])
+}
+
+func TestViewTracker_FlushRetriesRejectedViews(t *testing.T) {
+	vt, repo, _ := setupViewTracker(t)
+	vt.MaxFlushAttempts = 3
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	user1 := vt.ViewerIdentifier(r, "user-1")
+	user2 := vt.ViewerIdentifier(r, "user-2")
+	repo.reject = map[string]bool{user1: true}
+
+	// Healthy views are written right away, the rejected one is retried on its own
+	vt.TrackView(r, "snippet-1", "user-1")
+	vt.TrackView(r, "snippet-1", "user-2")
+	assert.NoError(t, vt.Flush(context.Background()))
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+	assert.Equal(t, 1, repo.views[user2])
+
+	vt.mutex.Lock()
+	assert.Len(t, vt.pending, 1)
+	assert.Equal(t, 1, vt.increments["snippet-1"])
+	vt.mutex.Unlock()
+
+	for range vt.MaxFlushAttempts - 1 {
+		assert.NoError(t, vt.Flush(context.Background()))
+	}
+
+	vt.mutex.Lock()
+	assert.Empty(t, vt.pending)
+	assert.Empty(t, vt.increments)
+	assert.Empty(t, vt.failures)
+	vt.mutex.Unlock()
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+	assert.Zero(t, repo.views[user1])
+}
+
+func TestViewTracker_StopFlushes(t *testing.T) {
+	vt, repo, _ := setupViewTracker(t)
+	vt.Start()
+
+	r := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	vt.TrackView(r, "snippet-1", "")
+
+	err := vt.Stop(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+}
+
+func TestViewTracker_TrackViewIgnoresBots(t *testing.T) {
+	vt, repo, broadcaster := setupViewTracker(t)
+
+	bot := newBrowserRequest("GET", "/api/snippets/snippet-1")
+	bot.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	head := newBrowserRequest("HEAD", "/api/snippets/snippet-1")
+	human := newBrowserRequest("GET", "/api/snippets/snippet-1")
+
+	botIdentifier := vt.ViewerIdentifier(bot, "")
+	vt.TrackView(bot, "snippet-1", "")
+	vt.TrackView(bot, "snippet-1", "")
+	vt.TrackView(head, "snippet-1", "")
+	vt.TrackView(human, "snippet-1", "user-1")
+
+	err := vt.Flush(context.Background())
+	assert.NoError(t, err)
+
+	// Bot views are recorded but never counted
+	assert.Equal(t, 3, repo.botViews[botIdentifier])
+	assert.Equal(t, 1, repo.views[vt.ViewerIdentifier(human, "user-1")])
+	assert.Equal(t, 1, repo.increments["snippet-1"])
+	assert.Equal(t, []int{1}, broadcaster.calls["snippet-1"])
+}
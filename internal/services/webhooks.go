@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-CodeShare-Event"
+	WebhookDeliveryHeader  = "X-CodeShare-Delivery"
+	WebhookTimestampHeader = "X-CodeShare-Timestamp"
+	WebhookSignatureHeader = "X-CodeShare-Signature"
+)
+
+// ErrInvalidWebhookURL is returned for webhook URLs that are not absolute http(s) URLs
+var ErrInvalidWebhookURL = errors.New("webhook URL must be an absolute http or https URL")
+
+var (
+	errWebhookBlockedAddress = errors.New("webhook target address is not public")
+	errWebhookStatus         = errors.New("unexpected webhook response status")
+)
+
+// Error classes stored on failed deliveries, the underlying error is only logged
+// so that delivery logs don't reveal anything about the network the server runs in.
+const (
+	webhookErrorBlocked    = "target address is not allowed"
+	webhookErrorTimeout    = "request timed out"
+	webhookErrorStatus     = "unexpected response status"
+	webhookErrorConnection = "connection failed"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WebhookPayload is the JSON body of a webhook delivery
+type WebhookPayload struct {
+	ID        string              `json:"id"` // Delivery ID, stable across retries
+	Event     domain.WebhookEvent `json:"event"`
+	Timestamp time.Time           `json:"timestamp"`
+	Data      any                 `json:"data"`
+}
+
+// WebhookDispatcher queues webhook deliveries in the database and sends them in the background
+type WebhookDispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	logger *zap.Logger
+
+	// Configuration
+	PollInterval   time.Duration // Time between checks for due deliveries
+	BatchSize      int           // Deliveries sent per check
+	MaxAttempts    int           // Attempts before a delivery is marked as failed
+	RetryBaseDelay time.Duration // Delay after the first failed attempt, doubled for every further attempt
+	RetryMaxDelay  time.Duration
+	// AllowPrivateNetworks permits deliveries to loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher with default settings
+func NewWebhookDispatcher(repo repository.WebhookRepository) *WebhookDispatcher {
+	wd := &WebhookDispatcher{
+		repo:           repo,
+		logger:         logger.Log,
+		PollInterval:   5 * time.Second,
+		BatchSize:      20,
+		MaxAttempts:    10,
+		RetryBaseDelay: 30 * time.Second,
+		RetryMaxDelay:  6 * time.Hour,
+		wake:           make(chan struct{}, 1),
+		stop:           make(chan struct{}),
+	}
+	wd.client = wd.newClient()
+	return wd
+}
+
+// newClient creates the HTTP client for deliveries. Target addresses are checked after
+// DNS resolution so that hostnames pointing at internal services are rejected as well,
+// and redirects are never followed.
+func (wd *WebhookDispatcher) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if wd.AllowPrivateNetworks {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddress(addrPort.Addr()) {
+				return errWebhookBlockedAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicAddress reports whether an address is publicly routable
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// ValidateWebhookURL checks that a webhook target is an absolute http(s) URL
+func ValidateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// GenerateWebhookSecret creates a random signing key for a new webhook
+func GenerateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhookPayload returns the signature header value of a delivery body.
+// The HMAC-SHA256 covers the timestamp header and the body separated by a dot.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish queues an event of a snippet owned by ownerID for all subscribed webhooks.
+// Webhooks are best effort, failures are logged and never fail the triggering action.
+func (wd *WebhookDispatcher) Publish(ctx context.Context, event domain.WebhookEvent, ownerID string, data any) {
+	log := wd.logger.With(zap.String("event", string(event)), zap.String("owner_id", ownerID))
+
+	webhooks, err := wd.repo.GetSubscribed(ctx, ownerID, event)
+	if err != nil {
+		log.Error("failed to get subscribed webhooks", zap.Error(err))
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]*domain.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		delivery, err := newWebhookDelivery(webhook.ID, event, data, now)
+		if err != nil {
+			log.Error("failed to encode webhook payload", zap.Error(err))
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := wd.repo.Enqueue(ctx, deliveries); err != nil {
+		log.Error("failed to enqueue webhook deliveries", zap.Error(err))
+		return
+	}
+
+	log.Debug("queued webhook deliveries", zap.Int("count", len(deliveries)))
+
+	// Send right away instead of waiting for the next poll
+	select {
+	case wd.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SendTest sends a ping event to a webhook and returns the delivery after the first attempt.
+// Failed test deliveries are retried like any other delivery.
+func (wd *WebhookDispatcher) SendTest(ctx context.Context, webhook *domain.Webhook) (*domain.WebhookDelivery, error) {
+	now := time.Now()
+	delivery, err := newWebhookDelivery(webhook.ID, domain.WebhookEventPing, map[string]string{
+		"webhookId": webhook.ID,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the background worker from picking the delivery up while it is sent here
+	delivery.NextAttemptAt = now.Add(wd.RetryBaseDelay)
+	if err := wd.repo.Enqueue(ctx, []*domain.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	if err := wd.attempt(ctx, &repository.DueWebhookDelivery{
+		Delivery: delivery,
+		URL:      webhook.URL,
+		Secret:   webhook.Secret,
+	}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ProcessDue sends all deliveries that are due, a batch at a time
+func (wd *WebhookDispatcher) ProcessDue(ctx context.Context) error {
+	for {
+		due, err := wd.repo.GetDueDeliveries(ctx, time.Now(), wd.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range due {
+			if err := wd.attempt(ctx, delivery); err != nil {
+				return err
+			}
+		}
+
+		if len(due) < wd.BatchSize {
+			return nil
+		}
+	}
+}
+
+// Start starts the background delivery worker
+func (wd *WebhookDispatcher) Start() {
+	wd.done = make(chan struct{})
+
+	go func() {
+		defer close(wd.done)
+
+		ticker := time.NewTicker(wd.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-wd.wake:
+			case <-wd.stop:
+				return
+			}
+
+			// Failures are logged and retried on the next tick
+			if err := wd.ProcessDue(context.Background()); err != nil {
+				wd.logger.Error("failed to process webhook deliveries", zap.Error(err))
+			}
+		}
+	}()
+}
+
+// Stop stops the background delivery worker, queued deliveries are sent after the next start
+func (wd *WebhookDispatcher) Stop(ctx context.Context) error {
+	wd.stopOnce.Do(func() { close(wd.stop) })
+
+	if wd.done != nil {
+		select {
+		case <-wd.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// CleanupOldDeliveries removes old delivery logs (should be called periodically)
+func (wd *WebhookDispatcher) CleanupOldDeliveries(ctx context.Context) error {
+	return wd.repo.DeleteOldDeliveries(ctx)
+}
+
+// attempt sends a delivery once and stores the outcome
+func (wd *WebhookDispatcher) attempt(ctx context.Context, due *repository.DueWebhookDelivery) error {
+	delivery := due.Delivery
+	delivery.Attempts++
+
+	statusCode, sendErr := wd.send(ctx, due)
+	now := time.Now()
+
+	delivery.ResponseStatus = nil
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.LastError = nil
+		delivery.CompletedAt = &now
+	case delivery.Attempts >= wd.MaxAttempts:
+		message := webhookErrorClass(sendErr)
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = &message
+		delivery.CompletedAt = &now
+	default:
+		message := webhookErrorClass(sendErr)
+		delivery.LastError = &message
+		delivery.NextAttemptAt = now.Add(wd.retryDelay(delivery.Attempts))
+	}
+
+	wd.logger.Debug("attempted webhook delivery",
+		zap.String("delivery_id", delivery.ID),
+		zap.String("webhook_id", delivery.WebhookID),
+		zap.String("status", string(delivery.Status)),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Error(sendErr),
+	)
+
+	return wd.repo.UpdateDelivery(ctx, delivery)
+}
+
+// send posts the signed payload and returns the response status code, if any
+func (wd *WebhookDispatcher) send(ctx context.Context, due *repository.DueWebhookDelivery) (int, error) {
+	body := []byte(due.Delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CodeShare-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(due.Delivery.Event))
+	req.Header.Set(WebhookDeliveryHeader, due.Delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(due.Secret, timestamp, body))
+
+	resp, err := wd.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Allow connection reuse
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w %d", errWebhookStatus, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookErrorClass maps a delivery error to the generic message stored on the delivery
+func webhookErrorClass(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errWebhookBlockedAddress):
+		return webhookErrorBlocked
+	case errors.Is(err, errWebhookStatus):
+		return webhookErrorStatus
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return webhookErrorTimeout
+	default:
+		return webhookErrorConnection
+	}
+}
+
+// retryDelay returns the exponential backoff after the given number of failed attempts
+func (wd *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := wd.RetryBaseDelay
+	for i := 1; i < attempts && delay < wd.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, wd.RetryMaxDelay)
+}
+
+func newWebhookDelivery(webhookID string, event domain.WebhookEvent, data any, now time.Time) (*domain.WebhookDelivery, error) {
+	id := uuid.New().String()
+	payload, err := json.Marshal(WebhookPayload{
+		ID:        id,
+		Event:     event,
+		Timestamp: now.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookDelivery{
+		ID:            id,
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       string(payload),
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
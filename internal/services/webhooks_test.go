@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+// fakeWebhookRepository keeps webhooks and deliveries in memory
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+	mutex      sync.Mutex
+	webhooks   []*domain.Webhook
+	deliveries []*domain.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) GetSubscribed(ctx context.Context, ownerID string, event domain.WebhookEvent) ([]*domain.Webhook, error) {
+	var result []*domain.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.Global || webhook.OwnerID == ownerID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeWebhookRepository) Enqueue(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deliveries = append(r.deliveries, deliveries...)
+	return nil
+}
+
+func (r *fakeWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*repository.DueWebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var result []*repository.DueWebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		for _, webhook := range r.webhooks {
+			if webhook.ID == delivery.WebhookID {
+				result = append(result, &repository.DueWebhookDelivery{Delivery: delivery, URL: webhook.URL, Secret: webhook.Secret})
+			}
+		}
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil // Deliveries are updated in place
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("secret", 1700000000, []byte(`{"event":"ping"}`))
+	assert.Equal(t, signature, SignWebhookPayload("secret", 1700000000, []byte(`{"event":"ping"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("other", 1700000000, []byte(`{"event":"ping"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("secret", 1700000001, []byte(`{"event":"ping"}`)))
+	assert.Len(t, signature, len("sha256=")+64)
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, ValidateWebhookURL("https://example.com/hooks"))
+	assert.NoError(t, ValidateWebhookURL("http://indexer.internal:8080/events"))
+	assert.ErrorIs(t, ValidateWebhookURL("ftp://example.com"), ErrInvalidWebhookURL)
+	assert.ErrorIs(t, ValidateWebhookURL("/relative"), ErrInvalidWebhookURL)
+	assert.ErrorIs(t, ValidateWebhookURL(""), ErrInvalidWebhookURL)
+}
+
+func TestWebhookDispatcher_Deliver(t *testing.T) {
+	setupTestLogger(t)
+
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepository{webhooks: []*domain.Webhook{
+		{ID: "own", OwnerID: "author", URL: server.URL, Secret: "own-secret"},
+		{ID: "other", OwnerID: "someone", URL: server.URL, Secret: "other-secret"},
+	}}
+	dispatcher := NewWebhookDispatcher(repo)
+	dispatcher.AllowPrivateNetworks = true // httptest servers listen on loopback
+
+	dispatcher.Publish(context.Background(), domain.WebhookEventSnippetLiked, "author", map[string]string{"snippet": "snippet-1"})
+	assert.Len(t, repo.deliveries, 1)
+
+	err := dispatcher.ProcessDue(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, received, 1)
+
+	r := received[0]
+	assert.Equal(t, "snippet.liked", r.Header.Get(WebhookEventHeader))
+	assert.Equal(t, repo.deliveries[0].ID, r.Header.Get(WebhookDeliveryHeader))
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, SignWebhookPayload("own-secret", timestamp, bodies[0]), r.Header.Get(WebhookSignatureHeader))
+
+	var payload WebhookPayload
+	assert.NoError(t, json.Unmarshal(bodies[0], &payload))
+	assert.Equal(t, domain.WebhookEventSnippetLiked, payload.Event)
+	assert.Equal(t, repo.deliveries[0].ID, payload.ID)
+
+	assert.Equal(t, domain.WebhookDeliverySucceeded, repo.deliveries[0].Status)
+	assert.Equal(t, 1, repo.deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, *repo.deliveries[0].ResponseStatus)
+}
+
+func TestWebhookDispatcher_Retry(t *testing.T) {
+	setupTestLogger(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepository{webhooks: []*domain.Webhook{
+		{ID: "hook", OwnerID: "author", URL: server.URL, Secret: "secret"},
+	}}
+	dispatcher := NewWebhookDispatcher(repo)
+	dispatcher.AllowPrivateNetworks = true // httptest servers listen on loopback
+	dispatcher.MaxAttempts = 3
+
+	dispatcher.Publish(context.Background(), domain.WebhookEventSnippetCreated, "author", nil)
+	delivery := repo.deliveries[0]
+
+	t.Run("failed attempt is rescheduled", func(t *testing.T) {
+		before := time.Now()
+		err := dispatcher.ProcessDue(context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, domain.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, *delivery.ResponseStatus)
+		assert.NotNil(t, delivery.LastError)
+		assert.True(t, delivery.NextAttemptAt.After(before.Add(dispatcher.RetryBaseDelay-time.Second)))
+
+		// Not due yet
+		err = dispatcher.ProcessDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivery.Attempts)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		for range 2 {
+			delivery.NextAttemptAt = time.Now().Add(-time.Second)
+			err := dispatcher.ProcessDue(context.Background())
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, domain.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.NotNil(t, delivery.CompletedAt)
+	})
+}
+
+func TestWebhookDispatcher_RetryDelay(t *testing.T) {
+	dispatcher := NewWebhookDispatcher(nil)
+	dispatcher.RetryBaseDelay = time.Minute
+	dispatcher.RetryMaxDelay = 10 * time.Minute
+
+	assert.Equal(t, time.Minute, dispatcher.retryDelay(1))
+	assert.Equal(t, 2*time.Minute, dispatcher.retryDelay(2))
+	assert.Equal(t, 8*time.Minute, dispatcher.retryDelay(4))
+	assert.Equal(t, 10*time.Minute, dispatcher.retryDelay(5))
+	assert.Equal(t, 10*time.Minute, dispatcher.retryDelay(50))
+}
+
+func TestWebhookDispatcher_SendTest(t *testing.T) {
+	setupTestLogger(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepository{}
+	dispatcher := NewWebhookDispatcher(repo)
+	dispatcher.AllowPrivateNetworks = true // httptest servers listen on loopback
+
+	delivery, err := dispatcher.SendTest(context.Background(), &domain.Webhook{ID: "hook", URL: server.URL, Secret: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WebhookEventPing, delivery.Event)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, delivery.Status)
+	assert.Len(t, repo.deliveries, 1)
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, isPublicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestWebhookDispatcher_BlocksPrivateTargets(t *testing.T) {
+	setupTestLogger(t)
+
+	var hits int
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer internal.Close()
+
+	t.Run("loopback target", func(t *testing.T) {
+		repo := &fakeWebhookRepository{}
+		dispatcher := NewWebhookDispatcher(repo)
+
+		delivery, err := dispatcher.SendTest(context.Background(), &domain.Webhook{ID: "hook", URL: internal.URL, Secret: "secret"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WebhookDeliveryPending, delivery.Status)
+		assert.Nil(t, delivery.ResponseStatus)
+		assert.Equal(t, webhookErrorBlocked, *delivery.LastError)
+		assert.Zero(t, hits)
+	})
+
+	t.Run("redirect to private target", func(t *testing.T) {
+		redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+		}))
+		defer redirect.Close()
+
+		repo := &fakeWebhookRepository{}
+		dispatcher := NewWebhookDispatcher(repo)
+		dispatcher.AllowPrivateNetworks = true // Only to reach the redirecting test server
+
+		delivery, err := dispatcher.SendTest(context.Background(), &domain.Webhook{ID: "hook", URL: redirect.URL, Secret: "secret"})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, http.StatusTemporaryRedirect, *delivery.ResponseStatus)
+		assert.Equal(t, webhookErrorStatus, *delivery.LastError)
+		assert.Zero(t, hits)
+	})
+}
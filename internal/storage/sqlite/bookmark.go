@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
 
 	db "mitsimi.dev/codeShare/internal/db/sqlc"
 	"mitsimi.dev/codeShare/internal/domain"
@@ -43,7 +46,7 @@ func (r *BookmarkRepository) ToggleSave(ctx context.Context, userID, snippetID s
 			return err
 		}
 	} else {
-		err := r.q.DeleteSavedSnippet(ctx, db.DeleteSavedSnippetParams{
+		_, err := r.q.DeleteSavedSnippet(ctx, db.DeleteSavedSnippetParams{
 			SnippetID: snippetID,
 			UserID:    userID,
 		})
@@ -55,41 +58,256 @@ func (r *BookmarkRepository) ToggleSave(ctx context.Context, userID, snippetID s
 	return nil
 }
 
-func (r *BookmarkRepository) GetSavedSnippets(ctx context.Context, userID string) ([]*domain.Snippet, error) {
-	snippets, err := r.q.GetSavedSnippets(ctx, userID)
+func (r *BookmarkRepository) GetSavedSnippets(ctx context.Context, userID string, filter repository.BookmarkFilter) ([]*domain.Bookmark, error) {
+	params := db.GetSavedSnippetsParams{
+		UserID: userID,
+		Search: escapeLike(filter.Query),
+	}
+	if filter.FolderID != nil {
+		params.FilterFolder = true
+		params.FolderID = sql.NullString{String: *filter.FolderID, Valid: *filter.FolderID != ""}
+	}
+
+	snippets, err := r.q.GetSavedSnippets(ctx, params)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, repository.ErrNotFound
 		}
-		return nil, repository.WrapError(err, "failed to get liked snippets")
+		return nil, repository.WrapError(err, "failed to get saved snippets")
 	}
 
-	result := make([]*domain.Snippet, len(snippets))
+	result := make([]*domain.Bookmark, len(snippets))
 	for i, snippet := range snippets {
 		var avatar *string
 		if snippet.AuthorAvatar.Valid {
 			avatar = &snippet.AuthorAvatar.String
 		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
+		var folderID *string
+		if snippet.FolderID.Valid {
+			folderID = &snippet.FolderID.String
+		}
 
-		result[i] = &domain.Snippet{
-			ID:       snippet.ID,
-			Title:    snippet.Title,
-			Content:  snippet.Content,
-			Language: snippet.Language,
-			Author: &domain.User{
-				ID:       snippet.AuthorID.String,
-				Username: snippet.AuthorUsername.String,
-				Email:    snippet.AuthorEmail.String,
-				Avatar:   avatar,
+		result[i] = &domain.Bookmark{
+			Snippet: &domain.Snippet{
+				ID:       snippet.ID,
+				Title:    snippet.Title,
+				Content:  snippet.Content,
+				Language: snippet.Language,
+				Author: &domain.User{
+					ID:       snippet.AuthorID.String,
+					Username: snippet.AuthorUsername.String,
+					Email:    snippet.AuthorEmail.String,
+					Avatar:   avatar,
+				},
+				OrganizationID: organizationID,
+				Visibility:     domain.SnippetVisibility(snippet.Visibility),
+				ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+				BurnAfterRead:  snippet.BurnAfterRead,
+				PasswordHash:   snippet.PasswordHash.String,
+				CreatedAt:      snippet.CreatedAt,
+				UpdatedAt:      snippet.UpdatedAt,
+				Views:          int(snippet.Views),
+				Likes:          int(snippet.Likes),
+				IsLiked:        snippet.IsLiked == 1,
+				IsSaved:        snippet.IsSaved == 1,
 			},
-			CreatedAt: snippet.CreatedAt,
-			UpdatedAt: snippet.UpdatedAt,
-			Views:     int(snippet.Views),
-			Likes:     int(snippet.Likes),
-			IsLiked:   snippet.IsLiked == 1,
-			IsSaved:   snippet.IsSaved == 1,
+			FolderID: folderID,
+			Note:     snippet.Note.String,
+			SavedAt:  snippet.SavedAt.Time,
 		}
 	}
 
 	return result, nil
 }
+
+func (r *BookmarkRepository) SetNote(ctx context.Context, userID, snippetID, note string) error {
+	affected, err := r.q.UpdateSavedSnippetNote(ctx, db.UpdateSavedSnippetNoteParams{
+		Note:      note,
+		SnippetID: snippetID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to update bookmark note")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *BookmarkRepository) Move(ctx context.Context, userID string, folderID *string, snippetIDs []string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	var folder sql.NullString
+	if folderID != nil {
+		if _, err := qtx.GetBookmarkFolder(ctx, db.GetBookmarkFolderParams{ID: *folderID, UserID: userID}); err != nil {
+			if err == sql.ErrNoRows {
+				return 0, repository.ErrNotFound
+			}
+			return 0, repository.WrapError(err, "failed to get bookmark folder")
+		}
+		folder = sql.NullString{String: *folderID, Valid: true}
+	}
+
+	moved := 0
+	for _, snippetID := range snippetIDs {
+		affected, err := qtx.MoveSavedSnippet(ctx, db.MoveSavedSnippetParams{
+			FolderID:  folder,
+			SnippetID: snippetID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return 0, repository.WrapError(err, "failed to move bookmark")
+		}
+		moved += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, repository.WrapError(err, "failed to commit bookmark move")
+	}
+	return moved, nil
+}
+
+func (r *BookmarkRepository) Unsave(ctx context.Context, userID string, snippetIDs []string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	removed := 0
+	for _, snippetID := range snippetIDs {
+		affected, err := qtx.DeleteSavedSnippet(ctx, db.DeleteSavedSnippetParams{
+			SnippetID: snippetID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return 0, repository.WrapError(err, "failed to remove bookmark")
+		}
+		removed += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, repository.WrapError(err, "failed to commit bookmark removal")
+	}
+	return removed, nil
+}
+
+func (r *BookmarkRepository) GetFolders(ctx context.Context, userID string) ([]*domain.BookmarkFolder, error) {
+	rows, err := r.q.GetBookmarkFolders(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get bookmark folders")
+	}
+
+	result := make([]*domain.BookmarkFolder, len(rows))
+	for i, row := range rows {
+		result[i] = toDomainBookmarkFolder(db.GetBookmarkFolderRow(row))
+	}
+	return result, nil
+}
+
+func (r *BookmarkRepository) GetFolder(ctx context.Context, userID, folderID string) (*domain.BookmarkFolder, error) {
+	row, err := r.q.GetBookmarkFolder(ctx, db.GetBookmarkFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get bookmark folder")
+	}
+	return toDomainBookmarkFolder(row), nil
+}
+
+func (r *BookmarkRepository) CreateFolder(ctx context.Context, folder *domain.BookmarkFolder) (*domain.BookmarkFolder, error) {
+	if err := r.q.CreateBookmarkFolder(ctx, db.CreateBookmarkFolderParams{
+		ID:     folder.ID,
+		UserID: folder.UserID,
+		Name:   folder.Name,
+	}); err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, repository.WrapError(err, "failed to create bookmark folder")
+	}
+	return r.GetFolder(ctx, folder.UserID, folder.ID)
+}
+
+func (r *BookmarkRepository) RenameFolder(ctx context.Context, userID, folderID, name string) (*domain.BookmarkFolder, error) {
+	affected, err := r.q.RenameBookmarkFolder(ctx, db.RenameBookmarkFolderParams{
+		Name:   name,
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, repository.WrapError(err, "failed to rename bookmark folder")
+	}
+	if affected == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return r.GetFolder(ctx, userID, folderID)
+}
+
+func (r *BookmarkRepository) DeleteFolder(ctx context.Context, userID, folderID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	affected, err := qtx.DeleteBookmarkFolder(ctx, db.DeleteBookmarkFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to delete bookmark folder")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+
+	if err := qtx.UnfileSavedSnippets(ctx, sql.NullString{String: folderID, Valid: true}); err != nil {
+		return repository.WrapError(err, "failed to unfile bookmarks")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit bookmark folder deletion")
+	}
+	return nil
+}
+
+func toDomainBookmarkFolder(row db.GetBookmarkFolderRow) *domain.BookmarkFolder {
+	return &domain.BookmarkFolder{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Name:      row.Name,
+		ItemCount: int(row.ItemCount),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+// escapeLike escapes the LIKE wildcards of a search text, queries use a backslash as escape character
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func isUniqueViolation(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	return ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
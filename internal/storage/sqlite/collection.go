@@ -0,0 +1,381 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.CollectionRepository = (*CollectionRepository)(nil)
+
+type CollectionRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewCollectionRepository(dbConn *sql.DB) *CollectionRepository {
+	return &CollectionRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *CollectionRepository) Create(ctx context.Context, collection *domain.Collection) (*domain.Collection, error) {
+	if err := r.q.CreateCollection(ctx, db.CreateCollectionParams{
+		ID:          collection.ID,
+		OwnerID:     collection.Owner.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Visibility:  string(collection.Visibility),
+	}); err != nil {
+		return nil, repository.WrapError(err, "failed to create collection")
+	}
+	return r.GetByID(ctx, collection.ID, collection.Owner.ID)
+}
+
+func (r *CollectionRepository) GetByID(ctx context.Context, collectionID, userID string) (*domain.Collection, error) {
+	row, err := r.q.GetCollection(ctx, db.GetCollectionParams{
+		UserID: userID,
+		ID:     collectionID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get collection")
+	}
+
+	collaborators, err := r.q.GetCollectionCollaborators(ctx, collectionID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get collection collaborators")
+	}
+
+	collection := toDomainCollection(db.GetUserCollectionsRow(row))
+	collection.Collaborators = make([]*domain.User, len(collaborators))
+	for i, collaborator := range collaborators {
+		collection.Collaborators[i] = domain.ToDomainUser(collaborator)
+	}
+	return collection, nil
+}
+
+func (r *CollectionRepository) GetByUser(ctx context.Context, userID string) ([]*domain.Collection, error) {
+	rows, err := r.q.GetUserCollections(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get collections")
+	}
+
+	result := make([]*domain.Collection, len(rows))
+	for i, row := range rows {
+		result[i] = toDomainCollection(row)
+	}
+	return result, nil
+}
+
+func (r *CollectionRepository) Update(ctx context.Context, collection *domain.Collection) (*domain.Collection, error) {
+	affected, err := r.q.UpdateCollection(ctx, db.UpdateCollectionParams{
+		Name:        collection.Name,
+		Description: collection.Description,
+		Visibility:  string(collection.Visibility),
+		ID:          collection.ID,
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to update collection")
+	}
+	if affected == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return r.GetByID(ctx, collection.ID, collection.Owner.ID)
+}
+
+func (r *CollectionRepository) Delete(ctx context.Context, collectionID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	if err := qtx.DeleteCollectionItems(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to delete collection items")
+	}
+	if err := qtx.DeleteCollectionCollaborators(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to delete collection collaborators")
+	}
+	if err := qtx.DeleteCollectionFollows(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to delete collection follows")
+	}
+	if err := qtx.DeleteCollectionNotifications(ctx, sql.NullString{String: collectionID, Valid: true}); err != nil {
+		return repository.WrapError(err, "failed to delete collection notifications")
+	}
+
+	affected, err := qtx.DeleteCollection(ctx, collectionID)
+	if err != nil {
+		return repository.WrapError(err, "failed to delete collection")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit collection deletion")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) GetItems(ctx context.Context, collectionID, userID string) ([]*domain.CollectionItem, error) {
+	rows, err := r.q.GetCollectionItems(ctx, db.GetCollectionItemsParams{
+		UserID:       userID,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get collection items")
+	}
+
+	result := make([]*domain.CollectionItem, len(rows))
+	for i, row := range rows {
+		var avatar *string
+		if row.AuthorAvatar.Valid {
+			avatar = &row.AuthorAvatar.String
+		}
+		var organizationID *string
+		if row.OrganizationID.Valid {
+			organizationID = &row.OrganizationID.String
+		}
+
+		result[i] = &domain.CollectionItem{
+			Snippet: &domain.Snippet{
+				ID:       row.ID,
+				Title:    row.Title,
+				Content:  row.Content,
+				Language: row.Language,
+				Author: &domain.User{
+					ID:       row.AuthorID.String,
+					Username: row.AuthorUsername.String,
+					Email:    row.AuthorEmail.String,
+					Avatar:   avatar,
+				},
+				OrganizationID: organizationID,
+				Visibility:     domain.SnippetVisibility(row.Visibility),
+				ExpiresAt:      fromUnixTime(row.ExpiresAt),
+				BurnAfterRead:  row.BurnAfterRead,
+				PasswordHash:   row.PasswordHash.String,
+				CreatedAt:      row.CreatedAt,
+				UpdatedAt:      row.UpdatedAt,
+				Views:          int(row.Views),
+				Likes:          int(row.Likes),
+				IsLiked:        row.IsLiked == 1,
+				IsSaved:        row.IsSaved == 1,
+			},
+			Position: int(row.Position),
+			AddedBy:  row.AddedBy,
+			AddedAt:  row.AddedAt,
+		}
+	}
+	return result, nil
+}
+
+func (r *CollectionRepository) AddItem(ctx context.Context, collectionID, snippetID, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	row, err := qtx.GetSnippet(ctx, db.GetSnippetParams{SnippetID: snippetID, UserID: userID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return repository.WrapError(err, "failed to get snippet")
+	}
+
+	// Snippets the user cannot read are reported as missing
+	snippet := snippetFromRow(row)
+	if !snippet.CanView(userID) || snippet.IsLocked(userID) || snippet.BurnsOnViewBy(userID) || snippet.IsExpired(time.Now()) {
+		return repository.ErrNotFound
+	}
+
+	position, err := qtx.GetNextCollectionItemPosition(ctx, collectionID)
+	if err != nil {
+		return repository.WrapError(err, "failed to get next item position")
+	}
+
+	added, err := qtx.AddCollectionItem(ctx, db.AddCollectionItemParams{
+		CollectionID: collectionID,
+		SnippetID:    snippetID,
+		Position:     position,
+		AddedBy:      userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to add collection item")
+	}
+	if added == 0 {
+		return repository.ErrAlreadyExists
+	}
+
+	if err := qtx.TouchCollection(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to update collection")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit collection item")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) RemoveItem(ctx context.Context, collectionID, snippetID string) error {
+	removed, err := r.q.RemoveCollectionItem(ctx, db.RemoveCollectionItemParams{
+		CollectionID: collectionID,
+		SnippetID:    snippetID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to remove collection item")
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+
+	if err := r.q.TouchCollection(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to update collection")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) ReorderItems(ctx context.Context, collectionID string, snippetIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	current, err := qtx.GetCollectionItemIDs(ctx, collectionID)
+	if err != nil {
+		return repository.WrapError(err, "failed to get collection items")
+	}
+
+	// The new order must be a permutation of the current items, so concurrent
+	// additions or removals are not silently undone
+	remaining := make(map[string]bool, len(current))
+	for _, snippetID := range current {
+		remaining[snippetID] = true
+	}
+	if len(snippetIDs) != len(current) {
+		return repository.WrapError(repository.ErrInvalidInput, "order must list every item exactly once")
+	}
+	for _, snippetID := range snippetIDs {
+		if !remaining[snippetID] {
+			return repository.WrapError(repository.ErrInvalidInput, "order must list every item exactly once")
+		}
+		delete(remaining, snippetID)
+	}
+
+	for i, snippetID := range snippetIDs {
+		if err := qtx.SetCollectionItemPosition(ctx, db.SetCollectionItemPositionParams{
+			Position:     int64(i),
+			CollectionID: collectionID,
+			SnippetID:    snippetID,
+		}); err != nil {
+			return repository.WrapError(err, "failed to set item position")
+		}
+	}
+
+	if err := qtx.TouchCollection(ctx, collectionID); err != nil {
+		return repository.WrapError(err, "failed to update collection")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit item order")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) AddCollaborator(ctx context.Context, collectionID, userID string) error {
+	_, err := r.q.GetUser(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return repository.WrapError(err, "failed to get user")
+	}
+
+	if err := r.q.AddCollectionCollaborator(ctx, db.AddCollectionCollaboratorParams{
+		CollectionID: collectionID,
+		UserID:       userID,
+	}); err != nil {
+		return repository.WrapError(err, "failed to add collaborator")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) RemoveCollaborator(ctx context.Context, collectionID, userID string) error {
+	removed, err := r.q.RemoveCollectionCollaborator(ctx, db.RemoveCollectionCollaboratorParams{
+		CollectionID: collectionID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to remove collaborator")
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CollectionRepository) Follow(ctx context.Context, collectionID, userID string) error {
+	if err := r.q.FollowCollection(ctx, db.FollowCollectionParams{
+		CollectionID: collectionID,
+		UserID:       userID,
+	}); err != nil {
+		return repository.WrapError(err, "failed to follow collection")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) Unfollow(ctx context.Context, collectionID, userID string) error {
+	if err := r.q.UnfollowCollection(ctx, db.UnfollowCollectionParams{
+		CollectionID: collectionID,
+		UserID:       userID,
+	}); err != nil {
+		return repository.WrapError(err, "failed to unfollow collection")
+	}
+	return nil
+}
+
+func (r *CollectionRepository) GetFollowerIDs(ctx context.Context, collectionID string) ([]string, error) {
+	ids, err := r.q.GetCollectionFollowerIDs(ctx, collectionID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get collection follower IDs")
+	}
+	return ids, nil
+}
+
+func toDomainCollection(row db.GetUserCollectionsRow) *domain.Collection {
+	var avatar *string
+	if row.OwnerAvatar.Valid {
+		avatar = &row.OwnerAvatar.String
+	}
+
+	return &domain.Collection{
+		ID: row.ID,
+		Owner: &domain.User{
+			ID:       row.OwnerID,
+			Username: row.OwnerUsername,
+			Email:    row.OwnerEmail,
+			Avatar:   avatar,
+		},
+		Name:        row.Name,
+		Description: row.Description,
+		Visibility:  domain.CollectionVisibility(row.Visibility),
+		ItemCount:   int(row.ItemCount),
+		Followers:   int(row.FollowerCount),
+		IsFollowing: row.IsFollowing == 1,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
@@ -0,0 +1,274 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func setupCollectionTestDB(t *testing.T) (*sql.DB, *CollectionRepository, *SnippetRepository, *UserRepository) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	collectionRepo := NewCollectionRepository(storage.DB())
+	snippetRepo := NewSnippetRepository(storage.DB())
+	userRepo := NewUserRepository(storage.DB())
+	return storage.DB(), collectionRepo, snippetRepo, userRepo
+}
+
+func TestCollectionRepository_CRUD(t *testing.T) {
+	db, collectionRepo, _, userRepo := setupCollectionTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	created, err := collectionRepo.Create(context.Background(), &domain.Collection{
+		ID: "collection-1", Owner: alice, Name: "Go tricks", Description: "Handy snippets", Visibility: domain.CollectionPublic,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", created.Owner.Username)
+	assert.Equal(t, "Go tricks", created.Name)
+	assert.Empty(t, created.Collaborators)
+	assert.False(t, created.IsCollaborator(bob.ID))
+
+	t.Run("update", func(t *testing.T) {
+		created.Name = "Go idioms"
+		created.Visibility = domain.CollectionPrivate
+		updated, err := collectionRepo.Update(context.Background(), created)
+		assert.NoError(t, err)
+		assert.Equal(t, "Go idioms", updated.Name)
+		assert.Equal(t, domain.CollectionPrivate, updated.Visibility)
+		assert.False(t, updated.CanView(bob.ID))
+	})
+
+	t.Run("collaborators", func(t *testing.T) {
+		err := collectionRepo.AddCollaborator(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+
+		// Adding twice is a no-op
+		err = collectionRepo.AddCollaborator(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+
+		collection, err := collectionRepo.GetByID(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+		assert.Len(t, collection.Collaborators, 1)
+		assert.True(t, collection.IsCollaborator(bob.ID))
+		assert.True(t, collection.CanView(bob.ID))
+
+		// Collections of collaborators are listed with their own
+		collections, err := collectionRepo.GetByUser(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Len(t, collections, 1)
+
+		err = collectionRepo.AddCollaborator(context.Background(), created.ID, "missing")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		err = collectionRepo.RemoveCollaborator(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+		err = collectionRepo.RemoveCollaborator(context.Background(), created.ID, bob.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		collections, err = collectionRepo.GetByUser(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, collections)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := collectionRepo.Delete(context.Background(), created.ID)
+		assert.NoError(t, err)
+
+		_, err = collectionRepo.GetByID(context.Background(), created.ID, alice.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		err = collectionRepo.Delete(context.Background(), created.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestCollectionRepository_Items(t *testing.T) {
+	db, collectionRepo, snippetRepo, userRepo := setupCollectionTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	// Snippets of any author can be collected
+	var snippetIDs []string
+	for i, author := range []*domain.User{alice, bob, bob} {
+		snippet := &domain.Snippet{ID: fmt.Sprintf("snippet-%d", i+1), Title: "Snippet", Content: "Content", Language: "go", Author: author}
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+		snippetIDs = append(snippetIDs, snippet.ID)
+	}
+
+	collection, err := collectionRepo.Create(context.Background(), &domain.Collection{
+		ID: "collection-1", Owner: alice, Name: "Favorites", Visibility: domain.CollectionPublic,
+	})
+	require.NoError(t, err)
+
+	itemIDs := func() []string {
+		items, err := collectionRepo.GetItems(context.Background(), collection.ID, alice.ID)
+		require.NoError(t, err)
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.Snippet.ID
+		}
+		return ids
+	}
+
+	t.Run("add appends", func(t *testing.T) {
+		for _, snippetID := range snippetIDs {
+			err := collectionRepo.AddItem(context.Background(), collection.ID, snippetID, alice.ID)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, snippetIDs, itemIDs())
+
+		err := collectionRepo.AddItem(context.Background(), collection.ID, snippetIDs[0], alice.ID)
+		assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+		err = collectionRepo.AddItem(context.Background(), collection.ID, "missing", alice.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// Snippets the user cannot read are reported as missing
+		hidden := &domain.Snippet{ID: "snippet-hidden", Title: "Hidden", Content: "Content", Language: "go", Author: bob, Visibility: domain.SnippetPrivate}
+		require.NoError(t, snippetRepo.Create(context.Background(), hidden))
+		err = collectionRepo.AddItem(context.Background(), collection.ID, hidden.ID, alice.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		updated, err := collectionRepo.GetByID(context.Background(), collection.ID, alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, updated.ItemCount)
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		order := []string{snippetIDs[2], snippetIDs[0], snippetIDs[1]}
+		err := collectionRepo.ReorderItems(context.Background(), collection.ID, order)
+		assert.NoError(t, err)
+		assert.Equal(t, order, itemIDs())
+
+		// Orders missing or repeating items are rejected
+		err = collectionRepo.ReorderItems(context.Background(), collection.ID, order[:2])
+		assert.ErrorIs(t, err, repository.ErrInvalidInput)
+		err = collectionRepo.ReorderItems(context.Background(), collection.ID, []string{order[0], order[0], order[1]})
+		assert.ErrorIs(t, err, repository.ErrInvalidInput)
+		assert.Equal(t, order, itemIDs())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := collectionRepo.RemoveItem(context.Background(), collection.ID, snippetIDs[0])
+		assert.NoError(t, err)
+		assert.Equal(t, []string{snippetIDs[2], snippetIDs[1]}, itemIDs())
+
+		err = collectionRepo.RemoveItem(context.Background(), collection.ID, snippetIDs[0])
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		// Removed items are added at the end again
+		err = collectionRepo.AddItem(context.Background(), collection.ID, snippetIDs[0], bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{snippetIDs[2], snippetIDs[1], snippetIDs[0]}, itemIDs())
+	})
+
+	t.Run("deleted snippets are hidden", func(t *testing.T) {
+		err := snippetRepo.Delete(context.Background(), snippetIDs[1])
+		assert.NoError(t, err)
+		assert.Equal(t, []string{snippetIDs[2], snippetIDs[0]}, itemIDs())
+	})
+}
+
+func TestCollectionRepository_Follow(t *testing.T) {
+	db, collectionRepo, snippetRepo, userRepo := setupCollectionTestDB(t)
+	defer db.Close()
+	notificationRepo := NewNotificationRepository(db)
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content", Language: "go", Author: alice}
+	require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+
+	collection, err := collectionRepo.Create(context.Background(), &domain.Collection{
+		ID: "collection-1", Owner: alice, Name: "Favorites", Visibility: domain.CollectionPublic,
+	})
+	require.NoError(t, err)
+
+	t.Run("follow", func(t *testing.T) {
+		err := collectionRepo.Follow(context.Background(), collection.ID, bob.ID)
+		assert.NoError(t, err)
+
+		// Following twice is a no-op
+		err = collectionRepo.Follow(context.Background(), collection.ID, bob.ID)
+		assert.NoError(t, err)
+
+		followed, err := collectionRepo.GetByID(context.Background(), collection.ID, bob.ID)
+		assert.NoError(t, err)
+		assert.True(t, followed.IsFollowing)
+		assert.Equal(t, 1, followed.Followers)
+
+		followerIDs, err := collectionRepo.GetFollowerIDs(context.Background(), collection.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{bob.ID}, followerIDs)
+	})
+
+	t.Run("item notifications name the collection", func(t *testing.T) {
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-1", UserID: bob.ID, ActorID: alice.ID, Type: domain.NotificationCollectionItem,
+			SnippetID: &snippet.ID, CollectionID: &collection.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, collection.ID, *notification.CollectionID)
+		assert.Equal(t, "Favorites", *notification.CollectionName)
+		assert.Equal(t, "Snippet 1", *notification.SnippetTitle)
+	})
+
+	t.Run("item notifications hide titles of unreadable snippets", func(t *testing.T) {
+		hidden := &domain.Snippet{ID: "snippet-2", Title: "Secret plans", Content: "Content", Language: "go", Author: alice, Visibility: domain.SnippetPrivate}
+		require.NoError(t, snippetRepo.Create(context.Background(), hidden))
+
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-2", UserID: bob.ID, ActorID: alice.ID, Type: domain.NotificationCollectionItem,
+			SnippetID: &hidden.ID, CollectionID: &collection.ID,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, notification.SnippetTitle)
+	})
+
+	t.Run("unfollow", func(t *testing.T) {
+		err := collectionRepo.Unfollow(context.Background(), collection.ID, bob.ID)
+		assert.NoError(t, err)
+
+		followerIDs, err := collectionRepo.GetFollowerIDs(context.Background(), collection.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, followerIDs)
+	})
+
+	t.Run("delete removes notifications", func(t *testing.T) {
+		err := collectionRepo.Delete(context.Background(), collection.ID)
+		assert.NoError(t, err)
+
+		notifications, err := notificationRepo.List(context.Background(), bob.ID, false, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+}
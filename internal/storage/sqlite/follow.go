@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.FollowRepository = (*FollowRepository)(nil)
+
+type FollowRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewFollowRepository(dbConn *sql.DB) *FollowRepository {
+	return &FollowRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID string) error {
+	if followerID == followeeID {
+		return repository.WrapError(repository.ErrInvalidInput, "users cannot follow themselves")
+	}
+
+	_, err := r.q.GetUser(ctx, followeeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return repository.WrapError(err, "failed to get user")
+	}
+
+	if err := r.q.FollowUser(ctx, db.FollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}); err != nil {
+		return repository.WrapError(err, "failed to follow user")
+	}
+	return nil
+}
+
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	if err := r.q.UnfollowUser(ctx, db.UnfollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}); err != nil {
+		return repository.WrapError(err, "failed to unfollow user")
+	}
+	return nil
+}
+
+func (r *FollowRepository) GetCounts(ctx context.Context, userID string) (*domain.FollowCounts, error) {
+	counts, err := r.q.GetFollowCounts(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get follow counts")
+	}
+	return &domain.FollowCounts{
+		Followers: int(counts.Followers),
+		Following: int(counts.Following),
+	}, nil
+}
+
+func (r *FollowRepository) GetFollowerIDs(ctx context.Context, userID string) ([]string, error) {
+	ids, err := r.q.GetFollowerIDs(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get follower IDs")
+	}
+	return ids, nil
+}
+
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID string) ([]*domain.User, error) {
+	users, err := r.q.GetFollowers(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get followers")
+	}
+
+	result := make([]*domain.User, len(users))
+	for i, user := range users {
+		result[i] = domain.ToDomainUser(user)
+	}
+	return result, nil
+}
+
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID string) ([]*domain.User, error) {
+	users, err := r.q.GetFollowing(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get followed users")
+	}
+
+	result := make([]*domain.User, len(users))
+	for i, user := range users {
+		result[i] = domain.ToDomainUser(user)
+	}
+	return result, nil
+}
+
+func (r *FollowRepository) GetFeed(ctx context.Context, userID string, limit int) ([]*domain.Snippet, error) {
+	snippets, err := r.q.GetFeedSnippets(ctx, db.GetFeedSnippetsParams{
+		UserID: userID,
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get feed")
+	}
+
+	result := make([]*domain.Snippet, len(snippets))
+	for i, snippet := range snippets {
+		var avatar *string
+		if snippet.AuthorAvatar.Valid {
+			avatar = &snippet.AuthorAvatar.String
+		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
+
+		result[i] = &domain.Snippet{
+			ID:       snippet.ID,
+			Title:    snippet.Title,
+			Content:  snippet.Content,
+			Language: snippet.Language,
+			Author: &domain.User{
+				ID:       snippet.AuthorID.String,
+				Username: snippet.AuthorUsername.String,
+				Email:    snippet.AuthorEmail.String,
+				Avatar:   avatar,
+			},
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
+		}
+	}
+
+	return result, nil
+}
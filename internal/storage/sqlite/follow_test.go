@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func setupFollowTestDB(t *testing.T) (*sql.DB, *FollowRepository, *SnippetRepository, *UserRepository) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	followRepo := NewFollowRepository(storage.DB())
+	snippetRepo := NewSnippetRepository(storage.DB())
+	userRepo := NewUserRepository(storage.DB())
+	return storage.DB(), followRepo, snippetRepo, userRepo
+}
+
+func TestFollowRepository_Follow(t *testing.T) {
+	db, followRepo, _, userRepo := setupFollowTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		err := followRepo.Follow(context.Background(), alice.ID, bob.ID)
+		assert.NoError(t, err)
+
+		// Following twice is a no-op
+		err = followRepo.Follow(context.Background(), alice.ID, bob.ID)
+		assert.NoError(t, err)
+
+		counts, err := followRepo.GetCounts(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.FollowCounts{Followers: 1, Following: 0}, counts)
+
+		counts, err = followRepo.GetCounts(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.FollowCounts{Followers: 0, Following: 1}, counts)
+
+		followerIDs, err := followRepo.GetFollowerIDs(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{alice.ID}, followerIDs)
+
+		followers, err := followRepo.GetFollowers(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Len(t, followers, 1)
+		assert.Equal(t, "alice", followers[0].Username)
+
+		following, err := followRepo.GetFollowing(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, following, 1)
+		assert.Equal(t, "bob", following[0].Username)
+	})
+
+	t.Run("unfollow", func(t *testing.T) {
+		err := followRepo.Unfollow(context.Background(), alice.ID, bob.ID)
+		assert.NoError(t, err)
+
+		counts, err := followRepo.GetCounts(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, counts.Followers)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		err := followRepo.Follow(context.Background(), alice.ID, alice.ID)
+		assert.ErrorIs(t, err, repository.ErrInvalidInput)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		err := followRepo.Follow(context.Background(), alice.ID, "missing")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestFollowRepository_GetFeed(t *testing.T) {
+	db, followRepo, snippetRepo, userRepo := setupFollowTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+	carol, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-3", Username: "carol", Email: "carol@example.com"})
+	assert.NoError(t, err)
+
+	for _, snippet := range []*domain.Snippet{
+		{ID: "snippet-1", Title: "Old", Content: "Content 1", Language: "go", Author: bob},
+		{ID: "snippet-2", Title: "New", Content: "Content 2", Language: "go", Author: bob},
+		{ID: "snippet-3", Title: "Not followed", Content: "Content 3", Language: "go", Author: carol},
+	} {
+		err := snippetRepo.Create(context.Background(), snippet)
+		assert.NoError(t, err)
+	}
+	now := time.Now().UTC()
+	_, err = db.Exec("UPDATE snippets SET created_at = ?, updated_at = ? WHERE id = ?", now.Add(-2*time.Hour), now.Add(-time.Hour), "snippet-1")
+	assert.NoError(t, err)
+	_, err = db.Exec("UPDATE snippets SET created_at = ?, updated_at = ? WHERE id = ?", now.Add(-90*time.Minute), now.Add(-90*time.Minute), "snippet-2")
+	assert.NoError(t, err)
+
+	err = followRepo.Follow(context.Background(), alice.ID, bob.ID)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		snippets, err := followRepo.GetFeed(context.Background(), alice.ID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+
+		// Most recently created or updated first
+		assert.Equal(t, "snippet-1", snippets[0].ID)
+		assert.True(t, snippets[0].UpdatedAt.After(snippets[0].CreatedAt))
+		assert.Equal(t, "snippet-2", snippets[1].ID)
+		assert.Equal(t, "bob", snippets[1].Author.Username)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		snippets, err := followRepo.GetFeed(context.Background(), alice.ID, 1)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 1)
+	})
+
+	t.Run("no follows", func(t *testing.T) {
+		snippets, err := followRepo.GetFeed(context.Background(), carol.ID, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, snippets)
+	})
+}
@@ -89,6 +89,10 @@ func (r *LikeRepository) GetLikedSnippets(ctx context.Context, userID string) ([
 		if snippet.AuthorAvatar.Valid {
 			avatar = &snippet.AuthorAvatar.String
 		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
 
 		result[i] = &domain.Snippet{
 			ID:       snippet.ID,
@@ -101,12 +105,17 @@ func (r *LikeRepository) GetLikedSnippets(ctx context.Context, userID string) ([
 				Email:    snippet.AuthorEmail.String,
 				Avatar:   avatar,
 			},
-			CreatedAt: snippet.CreatedAt,
-			UpdatedAt: snippet.UpdatedAt,
-			Views:     int(snippet.Views),
-			Likes:     int(snippet.Likes),
-			IsLiked:   snippet.IsLiked == 1,
-			IsSaved:   snippet.IsSaved == 1,
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
 		}
 	}
 
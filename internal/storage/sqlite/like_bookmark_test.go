@@ -3,11 +3,14 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
 )
 
 func setupLikeBookmarkTestDB(t *testing.T) (*sql.DB, *LikeRepository, *BookmarkRepository, *SnippetRepository, *UserRepository) {
@@ -102,3 +105,112 @@ func TestBookmarkRepository_ToggleSave(t *testing.T) {
 		assert.Equal(t, 0, count)
 	})
 }
+
+func TestBookmarkRepository_Organize(t *testing.T) {
+	db, _, bookmarkRepo, snippetRepo, userRepo := setupLikeBookmarkTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	// Saved one day apart, snippet-3 last
+	titles := []string{"Binary search", "HTTP client", "100% coverage"}
+	var snippetIDs []string
+	for i, title := range titles {
+		snippet := &domain.Snippet{ID: fmt.Sprintf("snippet-%d", i+1), Title: title, Content: "Content", Language: "go", Author: alice}
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+		require.NoError(t, bookmarkRepo.ToggleSave(context.Background(), alice.ID, snippet.ID, true))
+		_, err := db.Exec("UPDATE user_saves SET created_at = ? WHERE snippet_id = ?", fmt.Sprintf("2025-01-0%d 12:00:00", i+1), snippet.ID)
+		require.NoError(t, err)
+		snippetIDs = append(snippetIDs, snippet.ID)
+	}
+
+	savedIDs := func(filter repository.BookmarkFilter) []string {
+		bookmarks, err := bookmarkRepo.GetSavedSnippets(context.Background(), alice.ID, filter)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, bookmark := range bookmarks {
+			ids = append(ids, bookmark.Snippet.ID)
+		}
+		return ids
+	}
+
+	folder, err := bookmarkRepo.CreateFolder(context.Background(), &domain.BookmarkFolder{ID: "folder-1", UserID: alice.ID, Name: "Algorithms"})
+	require.NoError(t, err)
+
+	t.Run("recently saved first", func(t *testing.T) {
+		assert.Equal(t, []string{snippetIDs[2], snippetIDs[1], snippetIDs[0]}, savedIDs(repository.BookmarkFilter{}))
+	})
+
+	t.Run("folders", func(t *testing.T) {
+		_, err := bookmarkRepo.CreateFolder(context.Background(), &domain.BookmarkFolder{ID: "folder-2", UserID: alice.ID, Name: "Algorithms"})
+		assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+		// Names are only unique per user
+		_, err = bookmarkRepo.CreateFolder(context.Background(), &domain.BookmarkFolder{ID: "folder-3", UserID: bob.ID, Name: "Algorithms"})
+		assert.NoError(t, err)
+
+		renamed, err := bookmarkRepo.RenameFolder(context.Background(), alice.ID, folder.ID, "Algos")
+		assert.NoError(t, err)
+		assert.Equal(t, "Algos", renamed.Name)
+
+		_, err = bookmarkRepo.RenameFolder(context.Background(), bob.ID, folder.ID, "Mine")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		moved, err := bookmarkRepo.Move(context.Background(), alice.ID, &folder.ID, []string{snippetIDs[0], snippetIDs[1], "missing"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, moved)
+
+		// Folders of other users cannot be used
+		_, err = bookmarkRepo.Move(context.Background(), bob.ID, &folder.ID, []string{snippetIDs[0]})
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		unfiled := ""
+		assert.Equal(t, []string{snippetIDs[1], snippetIDs[0]}, savedIDs(repository.BookmarkFilter{FolderID: &folder.ID}))
+		assert.Equal(t, []string{snippetIDs[2]}, savedIDs(repository.BookmarkFilter{FolderID: &unfiled}))
+
+		folders, err := bookmarkRepo.GetFolders(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, folders, 1)
+		assert.Equal(t, 2, folders[0].ItemCount)
+	})
+
+	t.Run("notes and search", func(t *testing.T) {
+		err := bookmarkRepo.SetNote(context.Background(), alice.ID, snippetIDs[1], "Use for the retry logic")
+		assert.NoError(t, err)
+		err = bookmarkRepo.SetNote(context.Background(), bob.ID, snippetIDs[1], "Not saved")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		bookmarks, err := bookmarkRepo.GetSavedSnippets(context.Background(), alice.ID, repository.BookmarkFilter{Query: "RETRY"})
+		assert.NoError(t, err)
+		assert.Len(t, bookmarks, 1)
+		assert.Equal(t, "Use for the retry logic", bookmarks[0].Note)
+		assert.Equal(t, folder.ID, *bookmarks[0].FolderID)
+
+		// Wildcards are matched literally
+		assert.Equal(t, []string{snippetIDs[2]}, savedIDs(repository.BookmarkFilter{Query: "100%"}))
+		assert.Empty(t, savedIDs(repository.BookmarkFilter{Query: "_"}))
+		assert.Equal(t, []string{snippetIDs[0]}, savedIDs(repository.BookmarkFilter{FolderID: &folder.ID, Query: "search"}))
+	})
+
+	t.Run("delete folder keeps bookmarks", func(t *testing.T) {
+		err := bookmarkRepo.DeleteFolder(context.Background(), alice.ID, folder.ID)
+		assert.NoError(t, err)
+		err = bookmarkRepo.DeleteFolder(context.Background(), alice.ID, folder.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		unfiled := ""
+		assert.Len(t, savedIDs(repository.BookmarkFilter{FolderID: &unfiled}), 3)
+	})
+
+	t.Run("unsave", func(t *testing.T) {
+		removed, err := bookmarkRepo.Unsave(context.Background(), alice.ID, []string{snippetIDs[0], snippetIDs[2], "missing"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, removed)
+		assert.Equal(t, []string{snippetIDs[1]}, savedIDs(repository.BookmarkFilter{}))
+	})
+}
@@ -0,0 +1,282 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// migration is a one-time schema or data change applied on top of the base schema
+type migration struct {
+	version     int
+	description string
+	apply       func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations lists all migrations in the order they are applied.
+// Never change or reorder released migrations, only append new ones.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "pseudonymize viewer identifiers and drop raw IP addresses",
+		apply:       pseudonymizeViewers,
+	},
+	{
+		version:     2,
+		description: "track bot views separately",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			if err := addColumnIfMissing(ctx, tx, "snippet_view_days", "bot_hits", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+			return addColumnIfMissing(ctx, tx, "snippet_daily_stats", "bot_views", "INTEGER NOT NULL DEFAULT 0")
+		},
+	},
+	{
+		version:     3,
+		description: "reference collections from notifications",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			return addColumnIfMissing(ctx, tx, "notifications", "collection_id", "TEXT REFERENCES collections(id) ON DELETE CASCADE")
+		},
+	},
+	{
+		version:     4,
+		description: "organize saved snippets in folders with notes",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			if err := addColumnIfMissing(ctx, tx, "user_saves", "folder_id", "TEXT REFERENCES bookmark_folders(id) ON DELETE SET NULL"); err != nil {
+				return err
+			}
+			return addColumnIfMissing(ctx, tx, "user_saves", "note", "TEXT NOT NULL DEFAULT ''")
+		},
+	},
+	{
+		version:     5,
+		description: "let organizations own snippets",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			if err := addColumnIfMissing(ctx, tx, "snippets", "organization_id", "TEXT REFERENCES organizations(id)"); err != nil {
+				return err
+			}
+			if err := addColumnIfMissing(ctx, tx, "snippets", "visibility", "TEXT NOT NULL DEFAULT 'public'"); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_snippets_organization_id ON snippets(organization_id)")
+			return err
+		},
+	},
+	{
+		version:     6,
+		description: "record who last changed a snippet",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			return addColumnIfMissing(ctx, tx, "snippets", "updated_by", "TEXT REFERENCES users(id)")
+		},
+	},
+	{
+		version:     7,
+		description: "let snippets expire or burn after reading",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			if err := addColumnIfMissing(ctx, tx, "snippets", "expires_at", "INTEGER"); err != nil {
+				return err
+			}
+			if err := addColumnIfMissing(ctx, tx, "snippets", "burn_after_read", "BOOLEAN NOT NULL DEFAULT FALSE"); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_snippets_expires_at ON snippets(expires_at)")
+			return err
+		},
+	},
+	{
+		version:     8,
+		description: "protect snippets with a password",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			return addColumnIfMissing(ctx, tx, "snippets", "password_hash", "TEXT")
+		},
+	},
+	{
+		version:     9,
+		description: "move deleted snippets to the trash",
+		apply: func(ctx context.Context, tx *sql.Tx) error {
+			if err := addColumnIfMissing(ctx, tx, "snippets", "deleted_at", "INTEGER"); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_snippets_deleted_at ON snippets(deleted_at)")
+			return err
+		},
+	},
+	{
+		version:     10,
+		description: "remove rows left behind while foreign keys were not enforced",
+		apply:       removeDanglingReferences,
+	},
+}
+
+// runMigrations applies all migrations that have not been applied yet, each in its own transaction
+func runMigrations(ctx context.Context, dbConn *sql.DB) error {
+	for _, m := range migrations {
+		var applied int
+		err := dbConn.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.version).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("failed to check migration %d: %w", m.version, err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		if err := applyMigration(ctx, dbConn, m); err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.description, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, dbConn *sql.DB, m migration) error {
+	tx, err := dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.apply(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, description) VALUES (?, ?)", m.version, m.description); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addColumnIfMissing adds a column to a table created before the column was
+// part of the base schema. Fresh databases already have it.
+func addColumnIfMissing(ctx context.Context, tx *sql.Tx, table, column, definition string) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE "+table+" ADD COLUMN "+column+" "+definition)
+	return err
+}
+
+// pseudonymizeViewers replaces raw user IDs, session tokens and IPs in view
+// records by keyed hashes. The key is random and discarded afterwards, so
+// the legacy records can no longer be linked to anyone.
+func pseudonymizeViewers(ctx context.Context, tx *sql.Tx) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	pseudonymize := func(identifier string) string {
+		kind, value, found := strings.Cut(identifier, ":")
+		if !found {
+			kind, value = "legacy", identifier
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(kind))
+		mac.Write([]byte{0})
+		mac.Write([]byte(value))
+		return kind + ":" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for _, table := range []string{"snippet_views", "snippet_view_days"} {
+		rows, err := tx.QueryContext(ctx, "SELECT DISTINCT viewer_identifier FROM "+table)
+		if err != nil {
+			return err
+		}
+		var identifiers []string
+		for rows.Next() {
+			var identifier string
+			if err := rows.Scan(&identifier); err != nil {
+				rows.Close()
+				return err
+			}
+			identifiers = append(identifiers, identifier)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, identifier := range identifiers {
+			if _, err := tx.ExecContext(ctx, "UPDATE "+table+" SET viewer_identifier = ? WHERE viewer_identifier = ?", pseudonymize(identifier), identifier); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE snippet_views SET ip_address = NULL")
+	return err
+}
+
+// foreignKeyViolation is a row whose foreign key references a missing parent
+type foreignKeyViolation struct {
+	table string
+	rowID int64
+	fkID  int
+}
+
+// removeDanglingReferences cleans up after the deletions made while foreign keys were off
+// by applying what the foreign key would have done: rows of ON DELETE CASCADE keys are
+// deleted, other references are cleared, and rows that require them are deleted.
+func removeDanglingReferences(ctx context.Context, tx *sql.Tx) error {
+	// Deleting a row can leave its own dependents behind, so repeat until none are left
+	for {
+		violations, err := foreignKeyViolations(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+
+		for _, v := range violations {
+			var column, onDelete string
+			err := tx.QueryRowContext(ctx, `SELECT "from", on_delete FROM pragma_foreign_key_list(?) WHERE id = ?`, v.table, v.fkID).Scan(&column, &onDelete)
+			if err != nil {
+				return fmt.Errorf("failed to look up foreign key %d of %s: %w", v.fkID, v.table, err)
+			}
+			var notNull bool
+			err = tx.QueryRowContext(ctx, `SELECT "notnull" FROM pragma_table_info(?) WHERE name = ?`, v.table, column).Scan(&notNull)
+			if err != nil {
+				return fmt.Errorf("failed to look up column %s of %s: %w", column, v.table, err)
+			}
+
+			query := fmt.Sprintf(`DELETE FROM "%s" WHERE rowid = ?`, v.table)
+			if onDelete != "CASCADE" && !notNull {
+				query = fmt.Sprintf(`UPDATE "%s" SET "%s" = NULL WHERE rowid = ?`, v.table, column)
+			}
+			if _, err := tx.ExecContext(ctx, query, v.rowID); err != nil {
+				return fmt.Errorf("failed to remove dangling reference from %s: %w", v.table, err)
+			}
+		}
+	}
+}
+
+// foreignKeyViolations lists the rows referencing missing parents
+func foreignKeyViolations(ctx context.Context, tx *sql.Tx) ([]foreignKeyViolation, error) {
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var violations []foreignKeyViolation
+	for rows.Next() {
+		var v foreignKeyViolation
+		var parent string
+		if err := rows.Scan(&v.table, &v.rowID, &parent, &v.fkID); err != nil {
+			return nil, err
+		}
+		violations = append(violations, v)
+	}
+	return violations, rows.Err()
+}
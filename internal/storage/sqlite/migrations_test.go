@@ -0,0 +1,295 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+func TestRunMigrations_PseudonymizeViewers(t *testing.T) {
+	db, _, snippetRepo, userRepo := setupViewTestDB(t)
+	defer db.Close()
+
+	// Create a user and a snippet
+	user := &domain.UserCreation{ID: "user-1", Username: "user1", Email: "user1@example.com"}
+	createdUser, err := userRepo.Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: createdUser}
+	err = snippetRepo.Create(context.Background(), snippet)
+	assert.NoError(t, err)
+
+	// Legacy rows written before identifiers were hashed
+	_, err = db.Exec("INSERT INTO snippet_views (snippet_id, viewer_identifier, ip_address) VALUES (?, ?, ?), (?, ?, ?)",
+		snippet.ID, "session:raw-token", "127.0.0.1",
+		snippet.ID, "ip:127.0.0.2", "127.0.0.2")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO snippet_view_days (snippet_id, viewer_identifier, day, hits, counted_views) VALUES (?, ?, ?, 1, 1)",
+		snippet.ID, "session:raw-token", "2025-03-03")
+	assert.NoError(t, err)
+
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 1")
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		err := runMigrations(context.Background(), db)
+		assert.NoError(t, err)
+
+		rows, err := db.Query("SELECT viewer_identifier, ip_address FROM snippet_views")
+		assert.NoError(t, err)
+		defer rows.Close()
+
+		var sessionIdentifier string
+		for rows.Next() {
+			var identifier string
+			var ipAddress sql.NullString
+			assert.NoError(t, rows.Scan(&identifier, &ipAddress))
+			assert.NotContains(t, identifier, "raw-token")
+			assert.NotContains(t, identifier, "127.0.0")
+			assert.False(t, ipAddress.Valid)
+			if strings.HasPrefix(identifier, "session:") {
+				sessionIdentifier = identifier
+			}
+		}
+		assert.NotEmpty(t, sessionIdentifier)
+
+		// Both tables use the same pseudonym for the same viewer
+		var dayIdentifier string
+		err = db.QueryRow("SELECT viewer_identifier FROM snippet_view_days").Scan(&dayIdentifier)
+		assert.NoError(t, err)
+		assert.Equal(t, sessionIdentifier, dayIdentifier)
+	})
+
+	t.Run("applied once", func(t *testing.T) {
+		var before string
+		err := db.QueryRow("SELECT viewer_identifier FROM snippet_view_days").Scan(&before)
+		assert.NoError(t, err)
+
+		err = runMigrations(context.Background(), db)
+		assert.NoError(t, err)
+
+		var after string
+		err = db.QueryRow("SELECT viewer_identifier FROM snippet_view_days").Scan(&after)
+		assert.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestRunMigrations_BotViewColumns(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	t.Run("adds missing columns", func(t *testing.T) {
+		// Tables created before bot views were tracked
+		_, err := db.Exec("ALTER TABLE snippet_view_days DROP COLUMN bot_hits")
+		assert.NoError(t, err)
+		_, err = db.Exec("ALTER TABLE snippet_daily_stats DROP COLUMN bot_views")
+		assert.NoError(t, err)
+		_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 2")
+		assert.NoError(t, err)
+
+		err = runMigrations(context.Background(), db)
+		assert.NoError(t, err)
+
+		_, err = db.Exec("SELECT bot_hits FROM snippet_view_days")
+		assert.NoError(t, err)
+		_, err = db.Exec("SELECT bot_views FROM snippet_daily_stats")
+		assert.NoError(t, err)
+	})
+
+	t.Run("existing columns", func(t *testing.T) {
+		_, err := db.Exec("DELETE FROM schema_migrations WHERE version = 2")
+		assert.NoError(t, err)
+
+		err = runMigrations(context.Background(), db)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunMigrations_NotificationCollectionColumn(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Notifications created before collections existed
+	_, err := db.Exec("ALTER TABLE notifications DROP COLUMN collection_id")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 3")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT collection_id FROM notifications")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_BookmarkColumns(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Saves created before bookmark folders existed
+	_, err := db.Exec("ALTER TABLE user_saves DROP COLUMN folder_id")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE user_saves DROP COLUMN note")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 4")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT folder_id, note FROM user_saves")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_OrganizationColumns(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Snippets created before organizations existed
+	_, err := db.Exec("DROP INDEX idx_snippets_organization_id")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE snippets DROP COLUMN organization_id")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE snippets DROP COLUMN visibility")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 5")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT organization_id, visibility FROM snippets")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_SnippetEditorColumn(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Snippets created before changes were attributed
+	_, err := db.Exec("ALTER TABLE snippets DROP COLUMN updated_by")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 6")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT updated_by FROM snippets")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_SnippetExpiryColumns(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Snippets created before they could expire
+	_, err := db.Exec("DROP INDEX idx_snippets_expires_at")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE snippets DROP COLUMN expires_at")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE snippets DROP COLUMN burn_after_read")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 7")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT expires_at, burn_after_read FROM snippets")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_SnippetPasswordColumn(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Snippets created before they could be protected
+	_, err := db.Exec("ALTER TABLE snippets DROP COLUMN password_hash")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 8")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT password_hash FROM snippets")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_SnippetTrashColumn(t *testing.T) {
+	db, _, _, _ := setupViewTestDB(t)
+	defer db.Close()
+
+	// Snippets created before deleted snippets were kept in the trash
+	_, err := db.Exec("DROP INDEX idx_snippets_deleted_at")
+	assert.NoError(t, err)
+	_, err = db.Exec("ALTER TABLE snippets DROP COLUMN deleted_at")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 9")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("SELECT deleted_at FROM snippets")
+	assert.NoError(t, err)
+}
+
+func TestRunMigrations_DanglingReferences(t *testing.T) {
+	db, _, snippetRepo, userRepo := setupViewTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: alice}
+	assert.NoError(t, snippetRepo.Create(context.Background(), snippet))
+
+	// Rows left behind by deletions made before foreign keys were enforced
+	_, err = db.Exec("PRAGMA foreign_keys = OFF")
+	assert.NoError(t, err)
+	for _, query := range []string{
+		"INSERT INTO snippets (id, title, language, content, author, organization_id) VALUES ('snippet-2', 'Snippet 2', 'go', 'Content 2', 'user-1', 'deleted-org')",
+		"INSERT INTO user_likes (snippet_id, user_id) VALUES ('snippet-1', 'user-1'), ('deleted', 'user-1')",
+		"INSERT INTO user_saves (snippet_id, user_id, folder_id) VALUES ('snippet-1', 'user-1', 'deleted-folder')",
+		"INSERT INTO snippet_views (snippet_id, viewer_identifier) VALUES ('deleted', 'user:hash')",
+		"INSERT INTO collections (id, owner_id, name) VALUES ('collection-1', 'deleted-user', 'Favorites')",
+		"INSERT INTO collection_items (collection_id, snippet_id, position, added_by) VALUES ('collection-1', 'snippet-1', 1, 'user-1')",
+	} {
+		_, err := db.Exec(query)
+		assert.NoError(t, err, query)
+	}
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	assert.NoError(t, err)
+	_, err = db.Exec("DELETE FROM schema_migrations WHERE version = 10")
+	assert.NoError(t, err)
+
+	err = runMigrations(context.Background(), db)
+	assert.NoError(t, err)
+
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	assert.NoError(t, err)
+	assert.False(t, rows.Next(), "no rows reference missing parents")
+	rows.Close()
+
+	// Cascading references are deleted, including the dependents of deleted rows
+	var likes, views, items int
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM user_likes").Scan(&likes))
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM snippet_views").Scan(&views))
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM collection_items").Scan(&items))
+	assert.Equal(t, 1, likes)
+	assert.Zero(t, views)
+	assert.Zero(t, items)
+
+	// Other references are cleared
+	var folderID, organizationID sql.NullString
+	assert.NoError(t, db.QueryRow("SELECT folder_id FROM user_saves").Scan(&folderID))
+	assert.NoError(t, db.QueryRow("SELECT organization_id FROM snippets WHERE id = 'snippet-2'").Scan(&organizationID))
+	assert.False(t, folderID.Valid)
+	assert.False(t, organizationID.Valid)
+}
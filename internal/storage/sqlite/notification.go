@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.NotificationRepository = (*NotificationRepository)(nil)
+
+type NotificationRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewNotificationRepository(dbConn *sql.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *repository.NewNotification) (*domain.Notification, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	enabled, err := qtx.GetNotificationPreference(ctx, db.GetNotificationPreferenceParams{
+		UserID: notification.UserID,
+		Type:   string(notification.Type),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, repository.WrapError(err, "failed to get notification preference")
+	}
+	if err == nil && !enabled {
+		return nil, nil
+	}
+
+	snippetID := sql.NullString{}
+	if notification.SnippetID != nil {
+		snippetID = sql.NullString{String: *notification.SnippetID, Valid: true}
+	}
+	collectionID := sql.NullString{}
+	if notification.CollectionID != nil {
+		collectionID = sql.NullString{String: *notification.CollectionID, Valid: true}
+	}
+
+	// Repeated like/unlike toggles should not flood the inbox
+	duplicates, err := qtx.CountUnreadDuplicateNotifications(ctx, db.CountUnreadDuplicateNotificationsParams{
+		UserID:       notification.UserID,
+		ActorID:      notification.ActorID,
+		Type:         string(notification.Type),
+		SnippetID:    snippetID,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to check duplicate notifications")
+	}
+	if duplicates > 0 {
+		return nil, nil
+	}
+
+	if err := qtx.InsertNotification(ctx, db.InsertNotificationParams{
+		ID:           notification.ID,
+		UserID:       notification.UserID,
+		ActorID:      notification.ActorID,
+		Type:         string(notification.Type),
+		SnippetID:    snippetID,
+		CollectionID: collectionID,
+	}); err != nil {
+		return nil, repository.WrapError(err, "failed to create notification")
+	}
+
+	row, err := qtx.GetNotification(ctx, notification.ID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get notification")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.WrapError(err, "failed to commit notification")
+	}
+	return toDomainNotification(db.GetNotificationsRow(row)), nil
+}
+
+func (r *NotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]*domain.Notification, error) {
+	rows, err := r.q.GetNotifications(ctx, db.GetNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		Limit:      int64(limit),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get notifications")
+	}
+
+	result := make([]*domain.Notification, len(rows))
+	for i, row := range rows {
+		result[i] = toDomainNotification(row)
+	}
+	return result, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	count, err := r.q.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return 0, repository.WrapError(err, "failed to count unread notifications")
+	}
+	return int(count), nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, notificationID string) error {
+	affected, err := r.q.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to mark notification as read")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) error {
+	if err := r.q.MarkAllNotificationsRead(ctx, userID); err != nil {
+		return repository.WrapError(err, "failed to mark notifications as read")
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	rows, err := r.q.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get notification preferences")
+	}
+
+	stored := make(map[domain.NotificationType]bool, len(rows))
+	for _, row := range rows {
+		stored[domain.NotificationType(row.Type)] = row.Enabled
+	}
+
+	result := make([]*domain.NotificationPreference, len(domain.NotificationTypes))
+	for i, notificationType := range domain.NotificationTypes {
+		enabled, ok := stored[notificationType]
+		result[i] = &domain.NotificationPreference{
+			Type:    notificationType,
+			Enabled: !ok || enabled,
+		}
+	}
+	return result, nil
+}
+
+func (r *NotificationRepository) SetPreference(ctx context.Context, userID string, notificationType domain.NotificationType, enabled bool) error {
+	if !notificationType.IsValid() {
+		return repository.WrapError(repository.ErrInvalidInput, "unknown notification type")
+	}
+	if err := r.q.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+		UserID:  userID,
+		Type:    string(notificationType),
+		Enabled: enabled,
+	}); err != nil {
+		return repository.WrapError(err, "failed to set notification preference")
+	}
+	return nil
+}
+
+func (r *NotificationRepository) DeleteOld(ctx context.Context) error {
+	if err := r.q.DeleteOldNotifications(ctx); err != nil {
+		return repository.WrapError(err, "failed to delete old notifications")
+	}
+	return nil
+}
+
+func toDomainNotification(row db.GetNotificationsRow) *domain.Notification {
+	var avatar *string
+	if row.ActorAvatar.Valid {
+		avatar = &row.ActorAvatar.String
+	}
+
+	notification := &domain.Notification{
+		ID:     row.ID,
+		UserID: row.UserID,
+		Type:   domain.NotificationType(row.Type),
+		Actor: &domain.User{
+			ID:       row.ActorID,
+			Username: row.ActorUsername,
+			Avatar:   avatar,
+		},
+		CreatedAt: row.CreatedAt,
+	}
+	if row.SnippetID.Valid {
+		notification.SnippetID = &row.SnippetID.String
+	}
+	if row.SnippetTitle.Valid {
+		notification.SnippetTitle = &row.SnippetTitle.String
+	}
+	if row.CollectionID.Valid {
+		notification.CollectionID = &row.CollectionID.String
+	}
+	if row.CollectionName.Valid {
+		notification.CollectionName = &row.CollectionName.String
+	}
+	if row.ReadAt.Valid {
+		notification.ReadAt = &row.ReadAt.Time
+	}
+	return notification
+}
@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func setupNotificationTestDB(t *testing.T) (*sql.DB, *NotificationRepository, *SnippetRepository, *UserRepository) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	notificationRepo := NewNotificationRepository(storage.DB())
+	snippetRepo := NewSnippetRepository(storage.DB())
+	userRepo := NewUserRepository(storage.DB())
+	return storage.DB(), notificationRepo, snippetRepo, userRepo
+}
+
+func TestNotificationRepository_Create(t *testing.T) {
+	db, notificationRepo, snippetRepo, userRepo := setupNotificationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: alice}
+	err = snippetRepo.Create(context.Background(), snippet)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-1", UserID: alice.ID, ActorID: bob.ID, Type: domain.NotificationLike, SnippetID: &snippet.ID,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, notification)
+		assert.Equal(t, "bob", notification.Actor.Username)
+		assert.Equal(t, "Snippet 1", *notification.SnippetTitle)
+		assert.Nil(t, notification.ReadAt)
+	})
+
+	t.Run("unread duplicate is skipped", func(t *testing.T) {
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-2", UserID: alice.ID, ActorID: bob.ID, Type: domain.NotificationLike, SnippetID: &snippet.ID,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, notification)
+	})
+
+	t.Run("muted type is skipped", func(t *testing.T) {
+		err := notificationRepo.SetPreference(context.Background(), alice.ID, domain.NotificationFollow, false)
+		assert.NoError(t, err)
+
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-3", UserID: alice.ID, ActorID: bob.ID, Type: domain.NotificationFollow,
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, notification)
+
+		count, err := notificationRepo.CountUnread(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestNotificationRepository_MarkRead(t *testing.T) {
+	db, notificationRepo, _, userRepo := setupNotificationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+
+	_, err = notificationRepo.Create(context.Background(), &repository.NewNotification{
+		ID: "notification-1", UserID: alice.ID, ActorID: bob.ID, Type: domain.NotificationFollow,
+	})
+	assert.NoError(t, err)
+
+	t.Run("other user cannot mark", func(t *testing.T) {
+		err := notificationRepo.MarkRead(context.Background(), bob.ID, "notification-1")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		err := notificationRepo.MarkRead(context.Background(), alice.ID, "notification-1")
+		assert.NoError(t, err)
+
+		unread, err := notificationRepo.List(context.Background(), alice.ID, true, 50)
+		assert.NoError(t, err)
+		assert.Len(t, unread, 0)
+
+		all, err := notificationRepo.List(context.Background(), alice.ID, false, 50)
+		assert.NoError(t, err)
+		assert.Len(t, all, 1)
+		assert.NotNil(t, all[0].ReadAt)
+	})
+
+	t.Run("read notification does not block a new one", func(t *testing.T) {
+		notification, err := notificationRepo.Create(context.Background(), &repository.NewNotification{
+			ID: "notification-2", UserID: alice.ID, ActorID: bob.ID, Type: domain.NotificationFollow,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, notification)
+
+		err = notificationRepo.MarkAllRead(context.Background(), alice.ID)
+		assert.NoError(t, err)
+
+		count, err := notificationRepo.CountUnread(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestNotificationRepository_Preferences(t *testing.T) {
+	db, notificationRepo, _, userRepo := setupNotificationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+
+	t.Run("enabled by default", func(t *testing.T) {
+		preferences, err := notificationRepo.GetPreferences(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, preferences, len(domain.NotificationTypes))
+		for _, preference := range preferences {
+			assert.True(t, preference.Enabled)
+		}
+	})
+
+	t.Run("mute", func(t *testing.T) {
+		err := notificationRepo.SetPreference(context.Background(), alice.ID, domain.NotificationLike, false)
+		assert.NoError(t, err)
+
+		preferences, err := notificationRepo.GetPreferences(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		for _, preference := range preferences {
+			assert.Equal(t, preference.Type != domain.NotificationLike, preference.Enabled)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		err := notificationRepo.SetPreference(context.Background(), alice.ID, "comment", false)
+		assert.ErrorIs(t, err, repository.ErrInvalidInput)
+	})
+}
+
+func TestNotificationRepository_DeleteOld(t *testing.T) {
+	db, notificationRepo, _, userRepo := setupNotificationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+
+	for _, id := range []string{"notification-1", "notification-2"} {
+		_, err = db.Exec("INSERT INTO notifications (id, user_id, actor_id, type, read_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+			id, alice.ID, bob.ID, domain.NotificationFollow, time.Now(), time.Now().Add(-100*24*time.Hour))
+		assert.NoError(t, err)
+	}
+	_, err = db.Exec("UPDATE notifications SET read_at = NULL WHERE id = ?", "notification-2")
+	assert.NoError(t, err)
+
+	err = notificationRepo.DeleteOld(context.Background())
+	assert.NoError(t, err)
+
+	// Unread notifications are kept regardless of their age
+	all, err := notificationRepo.List(context.Background(), alice.ID, false, 50)
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Equal(t, "notification-2", all[0].ID)
+}
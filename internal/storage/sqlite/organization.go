@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.OrganizationRepository = (*OrganizationRepository)(nil)
+
+type OrganizationRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewOrganizationRepository(dbConn *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization *domain.Organization, ownerID string) (*domain.Organization, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	if err := qtx.CreateOrganization(ctx, db.CreateOrganizationParams{
+		ID:          organization.ID,
+		Slug:        organization.Slug,
+		Name:        organization.Name,
+		Description: organization.Description,
+	}); err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, repository.WrapError(err, "failed to create organization")
+	}
+
+	if err := qtx.SetOrganizationMember(ctx, db.SetOrganizationMemberParams{
+		OrganizationID: organization.ID,
+		UserID:         ownerID,
+		Role:           string(domain.RoleOwner),
+	}); err != nil {
+		return nil, repository.WrapError(err, "failed to add organization owner")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.WrapError(err, "failed to commit organization creation")
+	}
+	return r.GetByID(ctx, organization.ID, ownerID)
+}
+
+func (r *OrganizationRepository) GetByID(ctx context.Context, idOrSlug, userID string) (*domain.Organization, error) {
+	row, err := r.q.GetOrganization(ctx, db.GetOrganizationParams{
+		UserID: userID,
+		ID:     idOrSlug,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get organization")
+	}
+	return toDomainOrganization(db.GetUserOrganizationsRow(row)), nil
+}
+
+func (r *OrganizationRepository) GetByUser(ctx context.Context, userID string) ([]*domain.Organization, error) {
+	rows, err := r.q.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get organizations")
+	}
+
+	result := make([]*domain.Organization, len(rows))
+	for i, row := range rows {
+		result[i] = toDomainOrganization(row)
+	}
+	return result, nil
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organization *domain.Organization) error {
+	affected, err := r.q.UpdateOrganization(ctx, db.UpdateOrganizationParams{
+		Name:        organization.Name,
+		Description: organization.Description,
+		ID:          organization.ID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to update organization")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) Delete(ctx context.Context, organizationID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	snippets, err := qtx.CountOrganizationSnippets(ctx, sql.NullString{String: organizationID, Valid: true})
+	if err != nil {
+		return repository.WrapError(err, "failed to count organization snippets")
+	}
+	if snippets > 0 {
+		return repository.ErrOrganizationHasSnippets
+	}
+
+	if err := qtx.DeleteOrganizationMembers(ctx, organizationID); err != nil {
+		return repository.WrapError(err, "failed to delete organization members")
+	}
+
+	affected, err := qtx.DeleteOrganization(ctx, organizationID)
+	if err != nil {
+		return repository.WrapError(err, "failed to delete organization")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit organization deletion")
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) GetMembers(ctx context.Context, organizationID string) ([]*domain.OrganizationMember, error) {
+	rows, err := r.q.GetOrganizationMembers(ctx, organizationID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get organization members")
+	}
+
+	result := make([]*domain.OrganizationMember, len(rows))
+	for i, row := range rows {
+		var avatar *string
+		if row.Avatar.Valid {
+			avatar = &row.Avatar.String
+		}
+
+		result[i] = &domain.OrganizationMember{
+			User: &domain.User{
+				ID:       row.ID,
+				Username: row.Username,
+				Email:    row.Email,
+				Avatar:   avatar,
+			},
+			Role:     domain.OrganizationRole(row.Role),
+			JoinedAt: row.JoinedAt,
+		}
+	}
+	return result, nil
+}
+
+func (r *OrganizationRepository) GetRole(ctx context.Context, organizationID, userID string) (domain.OrganizationRole, error) {
+	role, err := r.q.GetOrganizationMemberRole(ctx, db.GetOrganizationMemberRoleParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", repository.ErrNotFound
+		}
+		return "", repository.WrapError(err, "failed to get organization role")
+	}
+	return domain.OrganizationRole(role), nil
+}
+
+func (r *OrganizationRepository) SetMember(ctx context.Context, organizationID, userID string, role domain.OrganizationRole) error {
+	_, err := r.q.GetUser(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return repository.WrapError(err, "failed to get user")
+	}
+
+	if err := r.q.SetOrganizationMember(ctx, db.SetOrganizationMemberParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           string(role),
+	}); err != nil {
+		return repository.WrapError(err, "failed to set organization member")
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID string) error {
+	removed, err := r.q.RemoveOrganizationMember(ctx, db.RemoveOrganizationMemberParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to remove organization member")
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func toDomainOrganization(row db.GetUserOrganizationsRow) *domain.Organization {
+	return &domain.Organization{
+		ID:           row.ID,
+		Slug:         row.Slug,
+		Name:         row.Name,
+		Description:  row.Description,
+		MemberCount:  int(row.MemberCount),
+		SnippetCount: int(row.SnippetCount),
+		Role:         domain.OrganizationRole(row.Role),
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func setupOrganizationTestDB(t *testing.T) (*sql.DB, *OrganizationRepository, *SnippetRepository, *UserRepository) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	organizationRepo := NewOrganizationRepository(storage.DB())
+	snippetRepo := NewSnippetRepository(storage.DB())
+	userRepo := NewUserRepository(storage.DB())
+	return storage.DB(), organizationRepo, snippetRepo, userRepo
+}
+
+func TestOrganizationRepository_CRUD(t *testing.T) {
+	db, organizationRepo, _, userRepo := setupOrganizationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	created, err := organizationRepo.Create(context.Background(), &domain.Organization{
+		ID: "org-1", Slug: "gophers", Name: "Gophers", Description: "Go snippets",
+	}, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleOwner, created.Role)
+	assert.Equal(t, 1, created.MemberCount)
+
+	t.Run("duplicate slug", func(t *testing.T) {
+		_, err := organizationRepo.Create(context.Background(), &domain.Organization{
+			ID: "org-2", Slug: "gophers", Name: "Other gophers",
+		}, bob.ID)
+		assert.True(t, repository.IsAlreadyExists(err))
+	})
+
+	t.Run("get by slug", func(t *testing.T) {
+		organization, err := organizationRepo.GetByID(context.Background(), "gophers", bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "org-1", organization.ID)
+		assert.Empty(t, organization.Role)
+
+		_, err = organizationRepo.GetByID(context.Background(), "missing", bob.ID)
+		assert.True(t, repository.IsNotFound(err))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		created.Name = "Gopher guild"
+		err := organizationRepo.Update(context.Background(), created)
+		assert.NoError(t, err)
+
+		organization, err := organizationRepo.GetByID(context.Background(), created.ID, alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Gopher guild", organization.Name)
+	})
+
+	t.Run("members", func(t *testing.T) {
+		err := organizationRepo.SetMember(context.Background(), created.ID, bob.ID, domain.RoleMember)
+		assert.NoError(t, err)
+
+		// Setting again changes the role
+		err = organizationRepo.SetMember(context.Background(), created.ID, bob.ID, domain.RoleMaintainer)
+		assert.NoError(t, err)
+
+		role, err := organizationRepo.GetRole(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleMaintainer, role)
+
+		members, err := organizationRepo.GetMembers(context.Background(), created.ID)
+		assert.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "alice", members[0].User.Username) // Owners first
+		assert.Equal(t, domain.RoleMaintainer, members[1].Role)
+
+		organizations, err := organizationRepo.GetByUser(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		require.Len(t, organizations, 1)
+		assert.Equal(t, domain.RoleMaintainer, organizations[0].Role)
+
+		err = organizationRepo.SetMember(context.Background(), created.ID, "missing", domain.RoleMember)
+		assert.True(t, repository.IsNotFound(err))
+
+		err = organizationRepo.RemoveMember(context.Background(), created.ID, bob.ID)
+		assert.NoError(t, err)
+		_, err = organizationRepo.GetRole(context.Background(), created.ID, bob.ID)
+		assert.True(t, repository.IsNotFound(err))
+
+		err = organizationRepo.RemoveMember(context.Background(), created.ID, bob.ID)
+		assert.True(t, repository.IsNotFound(err))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := organizationRepo.Delete(context.Background(), created.ID)
+		assert.NoError(t, err)
+
+		_, err = organizationRepo.GetByID(context.Background(), created.ID, alice.ID)
+		assert.True(t, repository.IsNotFound(err))
+
+		organizations, err := organizationRepo.GetByUser(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, organizations)
+	})
+}
+
+func TestSnippetRepository_OrganizationVisibility(t *testing.T) {
+	db, organizationRepo, snippetRepo, userRepo := setupOrganizationTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	organization, err := organizationRepo.Create(context.Background(), &domain.Organization{ID: "org-1", Slug: "gophers", Name: "Gophers"}, alice.ID)
+	require.NoError(t, err)
+
+	orgID := organization.ID
+	require.NoError(t, snippetRepo.Create(context.Background(), &domain.Snippet{
+		ID: "internal", Title: "Internal", Content: "secret", Language: "go", Author: alice,
+		OrganizationID: &orgID, Visibility: domain.SnippetOrganization,
+	}))
+	require.NoError(t, snippetRepo.Create(context.Background(), &domain.Snippet{
+		ID: "shared", Title: "Shared", Content: "hello", Language: "go", Author: alice,
+		OrganizationID: &orgID, Visibility: domain.SnippetPublic,
+	}))
+	require.NoError(t, snippetRepo.Create(context.Background(), &domain.Snippet{
+		ID: "personal", Title: "Personal", Content: "mine", Language: "go", Author: alice,
+	}))
+
+	t.Run("members see organization-only snippets", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAll(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 3)
+
+		snippets, err = snippetRepo.GetAllByOrganization(context.Background(), orgID, alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "internal", alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleOwner, snippet.ViewerRole)
+		assert.True(t, snippet.CanView(alice.ID))
+		assert.True(t, snippet.CanEdit(alice.ID))
+	})
+
+	t.Run("others only see public snippets", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAll(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+
+		snippets, err = snippetRepo.GetAllByAuthor(context.Background(), alice.ID, "")
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+
+		snippets, err = snippetRepo.GetAllByOrganization(context.Background(), orgID, bob.ID)
+		assert.NoError(t, err)
+		require.Len(t, snippets, 1)
+		assert.Equal(t, "shared", snippets[0].ID)
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "internal", bob.ID)
+		assert.NoError(t, err)
+		assert.False(t, snippet.CanView(bob.ID))
+		assert.False(t, snippet.CanEdit(bob.ID))
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		err := snippetRepo.TransferToOrganization(context.Background(), "personal", orgID, domain.SnippetOrganization)
+		assert.NoError(t, err)
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "personal", bob.ID)
+		assert.NoError(t, err)
+		require.NotNil(t, snippet.OrganizationID)
+		assert.Equal(t, orgID, *snippet.OrganizationID)
+		assert.False(t, snippet.CanView(bob.ID))
+
+		// Snippets of an organization cannot be transferred again
+		err = snippetRepo.TransferToOrganization(context.Background(), "personal", orgID, domain.SnippetPublic)
+		assert.True(t, repository.IsNotFound(err))
+	})
+
+	t.Run("maintainers edit snippets of other members", func(t *testing.T) {
+		require.NoError(t, organizationRepo.SetMember(context.Background(), orgID, bob.ID, domain.RoleMaintainer))
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "internal", bob.ID)
+		assert.NoError(t, err)
+		assert.True(t, snippet.CanView(bob.ID))
+		assert.True(t, snippet.CanEdit(bob.ID))
+
+		// Plain members only edit their own
+		require.NoError(t, organizationRepo.SetMember(context.Background(), orgID, bob.ID, domain.RoleMember))
+		snippet, err = snippetRepo.GetByID(context.Background(), "internal", bob.ID)
+		assert.NoError(t, err)
+		assert.True(t, snippet.CanView(bob.ID))
+		assert.False(t, snippet.CanEdit(bob.ID))
+	})
+
+	t.Run("delete keeps organizations with trashed snippets", func(t *testing.T) {
+		for _, id := range []string{"internal", "shared", "personal"} {
+			require.NoError(t, snippetRepo.Trash(context.Background(), id))
+		}
+
+		err := organizationRepo.Delete(context.Background(), orgID)
+		assert.ErrorIs(t, err, repository.ErrOrganizationHasSnippets)
+
+		_, err = organizationRepo.GetByID(context.Background(), orgID, alice.ID)
+		assert.NoError(t, err)
+		role, err := organizationRepo.GetRole(context.Background(), orgID, alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleOwner, role)
+
+		for _, id := range []string{"internal", "shared", "personal"} {
+			require.NoError(t, snippetRepo.Delete(context.Background(), id))
+		}
+		assert.NoError(t, organizationRepo.Delete(context.Background(), orgID))
+	})
+}
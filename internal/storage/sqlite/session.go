@@ -56,6 +56,25 @@ func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*doma
 	}, nil
 }
 
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	session, err := r.q.GetSessionByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get session")
+	}
+
+	return &domain.Session{
+		ID:           session.ID,
+		UserID:       session.UserID,
+		Token:        session.Token,
+		RefreshToken: session.RefreshToken,
+		ExpiresAt:    session.ExpiresAt,
+		CreatedAt:    session.CreatedAt,
+	}, nil
+}
+
 func (r *SessionRepository) Delete(ctx context.Context, token string) error {
 	err := r.q.DeleteSession(ctx, token)
 	if err != nil {
@@ -72,6 +91,9 @@ func (r *SessionRepository) DeleteExpired(ctx context.Context) error {
 	if err != nil {
 		return repository.WrapError(err, "failed to delete expired sessions")
 	}
+	if err := r.q.DeleteExpiredTickets(ctx); err != nil {
+		return repository.WrapError(err, "failed to delete expired tickets")
+	}
 	return nil
 }
 
@@ -92,3 +114,17 @@ func (r *SessionRepository) UpdateExpiry(ctx context.Context, token string, expi
 	}
 	return nil
 }
+
+func (r *SessionRepository) RedeemTicket(ctx context.Context, ticketID string, expiresAt domain.UnixTime) error {
+	redeemed, err := r.q.RedeemTicket(ctx, db.RedeemTicketParams{
+		ID:        ticketID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to redeem ticket")
+	}
+	if redeemed == 0 {
+		return repository.ErrAlreadyExists
+	}
+	return nil
+}
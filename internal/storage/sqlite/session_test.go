@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func TestSessionRepository_RedeemTicket(t *testing.T) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer storage.Close()
+	sessionRepo := NewSessionRepository(storage.DB())
+	ctx := context.Background()
+
+	err = sessionRepo.RedeemTicket(ctx, "ticket-1", time.Now().Add(time.Minute).Unix())
+	assert.NoError(t, err)
+	err = sessionRepo.RedeemTicket(ctx, "ticket-1", time.Now().Add(time.Minute).Unix())
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+	// Expired tickets are forgotten, they are rejected by their signature anyway
+	err = sessionRepo.RedeemTicket(ctx, "ticket-2", time.Now().Add(-time.Minute).Unix())
+	assert.NoError(t, err)
+	assert.NoError(t, sessionRepo.DeleteExpired(ctx))
+
+	var count int
+	err = storage.DB().QueryRow("SELECT COUNT(*) FROM redeemed_tickets").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
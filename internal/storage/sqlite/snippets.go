@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	db "mitsimi.dev/codeShare/internal/db/sqlc"
 	"mitsimi.dev/codeShare/internal/domain"
@@ -24,12 +25,30 @@ func NewSnippetRepository(dbConn *sql.DB) *SnippetRepository {
 }
 
 func (r *SnippetRepository) Create(ctx context.Context, snippet *domain.Snippet) error {
+	if snippet.Visibility == "" {
+		snippet.Visibility = domain.SnippetPublic
+	}
+
+	var organizationID sql.NullString
+	if snippet.OrganizationID != nil {
+		organizationID = sql.NullString{String: *snippet.OrganizationID, Valid: true}
+	}
+	var expiresAt sql.NullInt64
+	if snippet.ExpiresAt != nil {
+		expiresAt = sql.NullInt64{Int64: snippet.ExpiresAt.Unix(), Valid: true}
+	}
+
 	_, err := r.q.CreateSnippet(ctx, db.CreateSnippetParams{
-		ID:       snippet.ID,
-		Title:    snippet.Title,
-		Content:  snippet.Content,
-		Language: snippet.Language,
-		Author:   snippet.Author.ID,
+		ID:             snippet.ID,
+		Title:          snippet.Title,
+		Content:        snippet.Content,
+		Language:       snippet.Language,
+		Author:         snippet.Author.ID,
+		OrganizationID: organizationID,
+		Visibility:     string(snippet.Visibility),
+		ExpiresAt:      expiresAt,
+		BurnAfterRead:  snippet.BurnAfterRead,
+		PasswordHash:   toPasswordHash(snippet.PasswordHash),
 	})
 	return err
 }
@@ -45,11 +64,26 @@ func (r *SnippetRepository) GetByID(ctx context.Context, snippetID, userID strin
 		}
 		return nil, repository.WrapError(err, "failed to get snippet")
 	}
+	return snippetFromRow(snippet), nil
+}
 
+// snippetFromRow converts a snippet loaded with the viewer's role and permission
+func snippetFromRow(snippet db.GetSnippetRow) *domain.Snippet {
 	var avatar *string
 	if snippet.AuthorAvatar.Valid {
 		avatar = &snippet.AuthorAvatar.String
 	}
+	var organizationID *string
+	if snippet.OrganizationID.Valid {
+		organizationID = &snippet.OrganizationID.String
+	}
+	var lastEditor *domain.User
+	if snippet.UpdatedBy.Valid {
+		lastEditor = &domain.User{
+			ID:       snippet.UpdatedBy.String,
+			Username: snippet.UpdatedByUsername.String,
+		}
+	}
 
 	return &domain.Snippet{
 		ID:       snippet.ID,
@@ -62,13 +96,21 @@ func (r *SnippetRepository) GetByID(ctx context.Context, snippetID, userID strin
 			Email:    snippet.AuthorEmail.String,
 			Avatar:   avatar,
 		},
-		CreatedAt: snippet.CreatedAt,
-		UpdatedAt: snippet.UpdatedAt,
-		Views:     int(snippet.Views),
-		Likes:     int(snippet.Likes),
-		IsLiked:   snippet.IsLiked == 1,
-		IsSaved:   snippet.IsSaved == 1,
-	}, nil
+		OrganizationID: organizationID,
+		Visibility:     domain.SnippetVisibility(snippet.Visibility),
+		ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+		BurnAfterRead:  snippet.BurnAfterRead,
+		PasswordHash:   snippet.PasswordHash.String,
+		ViewerRole:     domain.OrganizationRole(snippet.ViewerRole),
+		Permission:     domain.SnippetPermission(snippet.ViewerPermission),
+		LastEditor:     lastEditor,
+		CreatedAt:      snippet.CreatedAt,
+		UpdatedAt:      snippet.UpdatedAt,
+		Views:          int(snippet.Views),
+		Likes:          int(snippet.Likes),
+		IsLiked:        snippet.IsLiked == 1,
+		IsSaved:        snippet.IsSaved == 1,
+	}
 }
 
 func (r *SnippetRepository) GetAllByAuthor(ctx context.Context, authorID, userID string) ([]*domain.Snippet, error) {
@@ -89,6 +131,61 @@ func (r *SnippetRepository) GetAllByAuthor(ctx context.Context, authorID, userID
 		if snippet.AuthorAvatar.Valid {
 			avatar = &snippet.AuthorAvatar.String
 		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
+
+		result[i] = &domain.Snippet{
+			ID:       snippet.ID,
+			Title:    snippet.Title,
+			Content:  snippet.Content,
+			Language: snippet.Language,
+			Author: &domain.User{
+				ID:       snippet.AuthorID.String,
+				Username: snippet.AuthorUsername.String,
+				Email:    snippet.AuthorEmail.String,
+				Avatar:   avatar,
+			},
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *SnippetRepository) GetAllByOrganization(ctx context.Context, organizationID, userID string) ([]*domain.Snippet, error) {
+	snippets, err := r.q.GetSnippetsByOrganization(ctx, db.GetSnippetsByOrganizationParams{
+		UserID:         userID,
+		OrganizationID: sql.NullString{String: organizationID, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get snippets by organization")
+	}
+
+	result := make([]*domain.Snippet, len(snippets))
+	for i, snippet := range snippets {
+		var avatar *string
+		if snippet.AuthorAvatar.Valid {
+			avatar = &snippet.AuthorAvatar.String
+		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
 
 		result[i] = &domain.Snippet{
 			ID:       snippet.ID,
@@ -101,12 +198,17 @@ func (r *SnippetRepository) GetAllByAuthor(ctx context.Context, authorID, userID
 				Email:    snippet.AuthorEmail.String,
 				Avatar:   avatar,
 			},
-			CreatedAt: snippet.CreatedAt,
-			UpdatedAt: snippet.UpdatedAt,
-			Views:     int(snippet.Views),
-			Likes:     int(snippet.Likes),
-			IsLiked:   snippet.IsLiked == 1,
-			IsSaved:   snippet.IsSaved == 1,
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
 		}
 	}
 
@@ -128,6 +230,10 @@ func (r *SnippetRepository) GetAll(ctx context.Context, userID string) ([]*domai
 		if snippet.AuthorAvatar.Valid {
 			avatar = &snippet.AuthorAvatar.String
 		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
 
 		result[i] = &domain.Snippet{
 			ID:       snippet.ID,
@@ -140,12 +246,17 @@ func (r *SnippetRepository) GetAll(ctx context.Context, userID string) ([]*domai
 				Email:    snippet.AuthorEmail.String,
 				Avatar:   avatar,
 			},
-			CreatedAt: snippet.CreatedAt,
-			UpdatedAt: snippet.UpdatedAt,
-			Views:     int(snippet.Views),
-			Likes:     int(snippet.Likes),
-			IsLiked:   snippet.IsLiked == 1,
-			IsSaved:   snippet.IsSaved == 1,
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
 		}
 	}
 
@@ -153,11 +264,23 @@ func (r *SnippetRepository) GetAll(ctx context.Context, userID string) ([]*domai
 }
 
 func (r *SnippetRepository) Update(ctx context.Context, snippet *domain.Snippet) error {
+	if snippet.Visibility == "" {
+		snippet.Visibility = domain.SnippetPublic
+	}
+
+	var updatedBy sql.NullString
+	if snippet.LastEditor != nil {
+		updatedBy = sql.NullString{String: snippet.LastEditor.ID, Valid: true}
+	}
+
 	_, err := r.q.UpdateSnippet(ctx, db.UpdateSnippetParams{
-		SnippetID: snippet.ID, // The ID of the snippet to update
-		Title:     snippet.Title,
-		Content:   snippet.Content,
-		Language:  snippet.Language,
+		SnippetID:    snippet.ID, // The ID of the snippet to update
+		Title:        snippet.Title,
+		Content:      snippet.Content,
+		Language:     snippet.Language,
+		Visibility:   string(snippet.Visibility),
+		UpdatedBy:    updatedBy,
+		PasswordHash: toPasswordHash(snippet.PasswordHash),
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -169,12 +292,321 @@ func (r *SnippetRepository) Update(ctx context.Context, snippet *domain.Snippet)
 }
 
 func (r *SnippetRepository) Delete(ctx context.Context, id string) error {
-	err := r.q.DeleteSnippet(ctx, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	if err := qtx.DeleteSnippetCollaborators(ctx, id); err != nil {
+		return repository.WrapError(err, "failed to delete snippet collaborators")
+	}
+
+	err = qtx.DeleteSnippet(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return repository.ErrNotFound
 		}
 		return repository.WrapError(err, "failed to delete snippet")
 	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit snippet deletion")
+	}
+	return nil
+}
+
+func (r *SnippetRepository) Trash(ctx context.Context, id string) error {
+	trashed, err := r.q.TrashSnippet(ctx, id)
+	if err != nil {
+		return repository.WrapError(err, "failed to move snippet to trash")
+	}
+	if trashed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SnippetRepository) Restore(ctx context.Context, id string) error {
+	restored, err := r.q.RestoreSnippet(ctx, id)
+	if err != nil {
+		return repository.WrapError(err, "failed to restore snippet")
+	}
+	if restored == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SnippetRepository) GetTrashedByID(ctx context.Context, id, userID string) (*domain.Snippet, error) {
+	snippet, err := r.q.GetTrashedSnippet(ctx, db.GetTrashedSnippetParams{
+		UserID:    userID,
+		SnippetID: id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get trashed snippet")
+	}
+
+	var avatar *string
+	if snippet.AuthorAvatar.Valid {
+		avatar = &snippet.AuthorAvatar.String
+	}
+	var organizationID *string
+	if snippet.OrganizationID.Valid {
+		organizationID = &snippet.OrganizationID.String
+	}
+
+	return &domain.Snippet{
+		ID:       snippet.ID,
+		Title:    snippet.Title,
+		Content:  snippet.Content,
+		Language: snippet.Language,
+		Author: &domain.User{
+			ID:       snippet.AuthorID.String,
+			Username: snippet.AuthorUsername.String,
+			Email:    snippet.AuthorEmail.String,
+			Avatar:   avatar,
+		},
+		OrganizationID: organizationID,
+		Visibility:     domain.SnippetVisibility(snippet.Visibility),
+		ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+		BurnAfterRead:  snippet.BurnAfterRead,
+		PasswordHash:   snippet.PasswordHash.String,
+		DeletedAt:      fromUnixTime(snippet.DeletedAt),
+		ViewerRole:     domain.OrganizationRole(snippet.ViewerRole),
+		CreatedAt:      snippet.CreatedAt,
+		UpdatedAt:      snippet.UpdatedAt,
+		Views:          int(snippet.Views),
+		Likes:          int(snippet.Likes),
+	}, nil
+}
+
+func (r *SnippetRepository) GetTrash(ctx context.Context, userID string) ([]*domain.Snippet, error) {
+	snippets, err := r.q.GetTrashedSnippets(ctx, userID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get trashed snippets")
+	}
+
+	result := make([]*domain.Snippet, len(snippets))
+	for i, snippet := range snippets {
+		var avatar *string
+		if snippet.AuthorAvatar.Valid {
+			avatar = &snippet.AuthorAvatar.String
+		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
+
+		result[i] = &domain.Snippet{
+			ID:       snippet.ID,
+			Title:    snippet.Title,
+			Content:  snippet.Content,
+			Language: snippet.Language,
+			Author: &domain.User{
+				ID:       snippet.AuthorID.String,
+				Username: snippet.AuthorUsername.String,
+				Email:    snippet.AuthorEmail.String,
+				Avatar:   avatar,
+			},
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			DeletedAt:      fromUnixTime(snippet.DeletedAt),
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+		}
+	}
+
+	return result, nil
+}
+
+func (r *SnippetRepository) PurgeTrash(ctx context.Context, cutoff time.Time) ([]*domain.Snippet, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	rows, err := qtx.GetPurgeableSnippets(ctx, sql.NullInt64{Int64: cutoff.Unix(), Valid: true})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get purgeable snippets")
+	}
+
+	result := make([]*domain.Snippet, len(rows))
+	for i, row := range rows {
+		if err := qtx.DeleteSnippetCollaborators(ctx, row.ID); err != nil {
+			return nil, repository.WrapError(err, "failed to delete snippet collaborators")
+		}
+		if err := qtx.DeleteSnippet(ctx, row.ID); err != nil {
+			return nil, repository.WrapError(err, "failed to purge snippet")
+		}
+		result[i] = &domain.Snippet{
+			ID:       row.ID,
+			Language: row.Language,
+			Author:   &domain.User{ID: row.Author},
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.WrapError(err, "failed to commit snippet purge")
+	}
+	return result, nil
+}
+
+func (r *SnippetRepository) Burn(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	burned, err := qtx.BurnSnippet(ctx, id)
+	if err != nil {
+		return repository.WrapError(err, "failed to burn snippet")
+	}
+	if burned == 0 {
+		return repository.ErrNotFound
+	}
+	if err := qtx.DeleteSnippetCollaborators(ctx, id); err != nil {
+		return repository.WrapError(err, "failed to delete snippet collaborators")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit snippet burn")
+	}
+	return nil
+}
+
+func (r *SnippetRepository) DeleteExpired(ctx context.Context) ([]*domain.Snippet, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	rows, err := qtx.GetExpiredSnippets(ctx)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get expired snippets")
+	}
+
+	result := make([]*domain.Snippet, len(rows))
+	for i, row := range rows {
+		if err := qtx.DeleteSnippetCollaborators(ctx, row.ID); err != nil {
+			return nil, repository.WrapError(err, "failed to delete snippet collaborators")
+		}
+		if err := qtx.DeleteSnippet(ctx, row.ID); err != nil {
+			return nil, repository.WrapError(err, "failed to delete expired snippet")
+		}
+		result[i] = &domain.Snippet{
+			ID:       row.ID,
+			Language: row.Language,
+			Author:   &domain.User{ID: row.Author},
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, repository.WrapError(err, "failed to commit expired snippet deletion")
+	}
+	return result, nil
+}
+
+func (r *SnippetRepository) TransferToOrganization(ctx context.Context, snippetID, organizationID string, visibility domain.SnippetVisibility) error {
+	affected, err := r.q.TransferSnippet(ctx, db.TransferSnippetParams{
+		OrganizationID: sql.NullString{String: organizationID, Valid: true},
+		Visibility:     string(visibility),
+		SnippetID:      snippetID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to transfer snippet")
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SnippetRepository) GetCollaborators(ctx context.Context, snippetID string) ([]*domain.SnippetCollaborator, error) {
+	rows, err := r.q.GetSnippetCollaborators(ctx, snippetID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get snippet collaborators")
+	}
+
+	result := make([]*domain.SnippetCollaborator, len(rows))
+	for i, row := range rows {
+		var avatar *string
+		if row.Avatar.Valid {
+			avatar = &row.Avatar.String
+		}
+
+		result[i] = &domain.SnippetCollaborator{
+			User: &domain.User{
+				ID:       row.ID,
+				Username: row.Username,
+				Email:    row.Email,
+				Avatar:   avatar,
+			},
+			Permission: domain.SnippetPermission(row.Permission),
+			AddedAt:    row.AddedAt,
+		}
+	}
+	return result, nil
+}
+
+func (r *SnippetRepository) SetCollaborator(ctx context.Context, snippetID, userID string, permission domain.SnippetPermission) error {
+	_, err := r.q.GetUser(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrNotFound
+		}
+		return repository.WrapError(err, "failed to get user")
+	}
+
+	if err := r.q.SetSnippetCollaborator(ctx, db.SetSnippetCollaboratorParams{
+		SnippetID:  snippetID,
+		UserID:     userID,
+		Permission: string(permission),
+	}); err != nil {
+		return repository.WrapError(err, "failed to set snippet collaborator")
+	}
 	return nil
 }
+
+func (r *SnippetRepository) RemoveCollaborator(ctx context.Context, snippetID, userID string) error {
+	removed, err := r.q.RemoveSnippetCollaborator(ctx, db.RemoveSnippetCollaboratorParams{
+		SnippetID: snippetID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return repository.WrapError(err, "failed to remove snippet collaborator")
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// fromUnixTime converts a stored time in Unix seconds, such as the expiry time
+func fromUnixTime(unix sql.NullInt64) *time.Time {
+	if !unix.Valid {
+		return nil
+	}
+	t := time.Unix(unix.Int64, 0)
+	return &t
+}
+
+// toPasswordHash stores unprotected snippets with a NULL password hash
+func toPasswordHash(hash string) sql.NullString {
+	return sql.NullString{String: hash, Valid: hash != ""}
+}
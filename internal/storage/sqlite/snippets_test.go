@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
 )
 
 func setupSnippetTestDB(t *testing.T) (*sql.DB, *SnippetRepository, *UserRepository) {
@@ -230,3 +233,348 @@ func TestSnippetRepository_Delete(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestSnippetRepository_Collaborators(t *testing.T) {
+	db, snippetRepo, userRepo := setupSnippetTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+	carol, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-3", Username: "carol", Email: "carol@example.com"})
+	require.NoError(t, err)
+
+	require.NoError(t, snippetRepo.Create(context.Background(), &domain.Snippet{
+		ID: "private", Title: "Private", Content: "draft", Language: "go", Author: alice,
+		Visibility: domain.SnippetPrivate,
+	}))
+
+	t.Run("private snippets are hidden from others", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAll(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, snippets)
+
+		snippets, err = snippetRepo.GetAllByAuthor(context.Background(), alice.ID, alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 1)
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "private", bob.ID)
+		assert.NoError(t, err)
+		assert.False(t, snippet.CanView(bob.ID))
+		assert.True(t, snippet.CanView(alice.ID))
+	})
+
+	t.Run("collaborators", func(t *testing.T) {
+		require.NoError(t, snippetRepo.SetCollaborator(context.Background(), "private", bob.ID, domain.PermissionView))
+		require.NoError(t, snippetRepo.SetCollaborator(context.Background(), "private", carol.ID, domain.PermissionEdit))
+
+		err := snippetRepo.SetCollaborator(context.Background(), "private", "missing", domain.PermissionView)
+		assert.True(t, repository.IsNotFound(err))
+
+		collaborators, err := snippetRepo.GetCollaborators(context.Background(), "private")
+		assert.NoError(t, err)
+		require.Len(t, collaborators, 2)
+		assert.Equal(t, "carol", collaborators[0].User.Username)
+		assert.Equal(t, domain.PermissionEdit, collaborators[0].Permission)
+
+		snippets, err := snippetRepo.GetAll(context.Background(), bob.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 1)
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "private", bob.ID)
+		assert.NoError(t, err)
+		assert.True(t, snippet.CanView(bob.ID))
+		assert.False(t, snippet.CanEdit(bob.ID))
+
+		snippet, err = snippetRepo.GetByID(context.Background(), "private", carol.ID)
+		assert.NoError(t, err)
+		assert.True(t, snippet.CanEdit(carol.ID))
+		assert.False(t, snippet.IsOwner(carol.ID))
+	})
+
+	t.Run("update records the editor", func(t *testing.T) {
+		snippet, err := snippetRepo.GetByID(context.Background(), "private", carol.ID)
+		require.NoError(t, err)
+		snippet.Content = "final"
+		snippet.LastEditor = &domain.User{ID: carol.ID}
+		require.NoError(t, snippetRepo.Update(context.Background(), snippet))
+
+		snippet, err = snippetRepo.GetByID(context.Background(), "private", alice.ID)
+		assert.NoError(t, err)
+		require.NotNil(t, snippet.LastEditor)
+		assert.Equal(t, carol.ID, snippet.LastEditor.ID)
+		assert.Equal(t, "carol", snippet.LastEditor.Username)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, snippetRepo.RemoveCollaborator(context.Background(), "private", bob.ID))
+		err := snippetRepo.RemoveCollaborator(context.Background(), "private", bob.ID)
+		assert.True(t, repository.IsNotFound(err))
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "private", bob.ID)
+		assert.NoError(t, err)
+		assert.False(t, snippet.CanView(bob.ID))
+	})
+
+	t.Run("deleting the snippet removes its collaborators", func(t *testing.T) {
+		require.NoError(t, snippetRepo.Delete(context.Background(), "private"))
+
+		collaborators, err := snippetRepo.GetCollaborators(context.Background(), "private")
+		assert.NoError(t, err)
+		assert.Empty(t, collaborators)
+	})
+}
+
+func TestSnippetRepository_Expiry(t *testing.T) {
+	db, snippetRepo, userRepo := setupSnippetTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	for _, snippet := range []*domain.Snippet{
+		{ID: "expired", ExpiresAt: &past},
+		{ID: "expiring", ExpiresAt: &future},
+		{ID: "burning", BurnAfterRead: true},
+		{ID: "trashed", ExpiresAt: &past},
+	} {
+		snippet.Title, snippet.Content, snippet.Language, snippet.Author = "Title", "content", "go", alice
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+	}
+	require.NoError(t, snippetRepo.Trash(context.Background(), "trashed"))
+
+	t.Run("lists skip expired and burn-after-read snippets", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAll(context.Background(), "")
+		assert.NoError(t, err)
+		require.Len(t, snippets, 1)
+		assert.Equal(t, "expiring", snippets[0].ID)
+		require.NotNil(t, snippets[0].ExpiresAt)
+		assert.Equal(t, future.Unix(), snippets[0].ExpiresAt.Unix())
+
+		// Authors still see their burn-after-read snippets
+		snippets, err = snippetRepo.GetAllByAuthor(context.Background(), alice.ID, alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		snippet, err := snippetRepo.GetByID(context.Background(), "expired", "")
+		assert.NoError(t, err)
+		assert.True(t, snippet.IsExpired(time.Now()))
+
+		snippet, err = snippetRepo.GetByID(context.Background(), "burning", "")
+		assert.NoError(t, err)
+		assert.True(t, snippet.BurnsOnViewBy(""))
+		assert.False(t, snippet.BurnsOnViewBy(alice.ID))
+	})
+
+	t.Run("burn", func(t *testing.T) {
+		assert.NoError(t, snippetRepo.Burn(context.Background(), "burning"))
+		assert.True(t, repository.IsNotFound(snippetRepo.Burn(context.Background(), "burning")))
+
+		// Only burn-after-read snippets can be burned
+		assert.True(t, repository.IsNotFound(snippetRepo.Burn(context.Background(), "expiring")))
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		deleted, err := snippetRepo.DeleteExpired(context.Background())
+		assert.NoError(t, err)
+		require.Len(t, deleted, 1)
+		assert.Equal(t, "expired", deleted[0].ID)
+		assert.Equal(t, alice.ID, deleted[0].Author.ID)
+		assert.Equal(t, "go", deleted[0].Language)
+
+		_, err = snippetRepo.GetByID(context.Background(), "expired", "")
+		assert.True(t, repository.IsNotFound(err))
+		_, err = snippetRepo.GetByID(context.Background(), "expiring", "")
+		assert.NoError(t, err)
+
+		// Trashed snippets are left to the trash purge
+		_, err = snippetRepo.GetTrashedByID(context.Background(), "trashed", alice.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSnippetRepository_PasswordProtection(t *testing.T) {
+	db, snippetRepo, userRepo := setupSnippetTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+
+	for _, snippet := range []*domain.Snippet{
+		{ID: "open"},
+		{ID: "protected", PasswordHash: "$2a$14$hash"},
+	} {
+		snippet.Title, snippet.Content, snippet.Language, snippet.Author = "Title", "content", "go", alice
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+	}
+
+	t.Run("lists skip protected snippets of other authors", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAll(context.Background(), "")
+		assert.NoError(t, err)
+		require.Len(t, snippets, 1)
+		assert.Equal(t, "open", snippets[0].ID)
+
+		snippets, err = snippetRepo.GetAllByAuthor(context.Background(), alice.ID, alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+	})
+
+	t.Run("get by id", func(t *testing.T) {
+		snippet, err := snippetRepo.GetByID(context.Background(), "protected", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "$2a$14$hash", snippet.PasswordHash)
+		assert.True(t, snippet.IsLocked("user-2"))
+		assert.False(t, snippet.IsLocked(alice.ID))
+
+		snippet, err = snippetRepo.GetByID(context.Background(), "open", "")
+		assert.NoError(t, err)
+		assert.False(t, snippet.IsPasswordProtected())
+	})
+
+	t.Run("update removes the password", func(t *testing.T) {
+		snippet, err := snippetRepo.GetByID(context.Background(), "protected", alice.ID)
+		require.NoError(t, err)
+		snippet.PasswordHash = ""
+		require.NoError(t, snippetRepo.Update(context.Background(), snippet))
+
+		snippets, err := snippetRepo.GetAll(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+	})
+}
+
+func TestSnippetRepository_Trash(t *testing.T) {
+	db, snippetRepo, userRepo := setupSnippetTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+
+	for _, id := range []string{"kept", "trashed", "old"} {
+		snippet := &domain.Snippet{ID: id, Title: "Title", Content: "content", Language: "go", Author: alice}
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+	}
+	require.NoError(t, snippetRepo.Trash(context.Background(), "trashed"))
+	require.NoError(t, snippetRepo.Trash(context.Background(), "old"))
+	_, err = db.Exec("UPDATE snippets SET deleted_at = unixepoch() - 86400 * 40 WHERE id = 'old'")
+	require.NoError(t, err)
+
+	t.Run("trashed snippets are hidden", func(t *testing.T) {
+		snippets, err := snippetRepo.GetAllByAuthor(context.Background(), alice.ID, alice.ID)
+		assert.NoError(t, err)
+		require.Len(t, snippets, 1)
+		assert.Equal(t, "kept", snippets[0].ID)
+
+		_, err = snippetRepo.GetByID(context.Background(), "trashed", alice.ID)
+		assert.True(t, repository.IsNotFound(err))
+		assert.True(t, repository.IsNotFound(snippetRepo.Trash(context.Background(), "trashed")))
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		snippets, err := snippetRepo.GetTrash(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		require.Len(t, snippets, 2)
+		assert.Equal(t, "trashed", snippets[0].ID)
+		require.NotNil(t, snippets[0].DeletedAt)
+
+		snippets, err = snippetRepo.GetTrash(context.Background(), "user-2")
+		assert.NoError(t, err)
+		assert.Empty(t, snippets)
+
+		snippet, err := snippetRepo.GetTrashedByID(context.Background(), "trashed", alice.ID)
+		assert.NoError(t, err)
+		assert.True(t, snippet.IsOwner(alice.ID))
+		assert.False(t, snippet.IsOwner("user-2"))
+
+		_, err = snippetRepo.GetTrashedByID(context.Background(), "kept", alice.ID)
+		assert.True(t, repository.IsNotFound(err))
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		purged, err := snippetRepo.PurgeTrash(context.Background(), time.Now().Add(-30*24*time.Hour))
+		assert.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, "old", purged[0].ID)
+		assert.Equal(t, alice.ID, purged[0].Author.ID)
+
+		_, err = snippetRepo.GetTrashedByID(context.Background(), "old", alice.ID)
+		assert.True(t, repository.IsNotFound(err))
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		assert.NoError(t, snippetRepo.Restore(context.Background(), "trashed"))
+		assert.True(t, repository.IsNotFound(snippetRepo.Restore(context.Background(), "trashed")))
+
+		snippet, err := snippetRepo.GetByID(context.Background(), "trashed", alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Title", snippet.Title)
+	})
+}
+
+func TestSnippetRepository_DeletionRemovesDependents(t *testing.T) {
+	db, snippetRepo, userRepo := setupSnippetTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	_, err = userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO collections (id, owner_id, name) VALUES ('collection-1', 'user-2', 'Favorites')")
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	for _, snippet := range []*domain.Snippet{
+		{ID: "burning", BurnAfterRead: true},
+		{ID: "expired", ExpiresAt: &past},
+		{ID: "old"},
+	} {
+		snippet.Title, snippet.Content, snippet.Language, snippet.Author = "Title", "content", "go", alice
+		require.NoError(t, snippetRepo.Create(context.Background(), snippet))
+
+		for _, query := range []string{
+			"INSERT INTO user_likes (snippet_id, user_id) VALUES (?, 'user-2')",
+			"INSERT INTO user_saves (snippet_id, user_id) VALUES (?, 'user-2')",
+			"INSERT INTO snippet_collaborators (snippet_id, user_id) VALUES (?, 'user-2')",
+			"INSERT INTO notifications (id, user_id, actor_id, type, snippet_id) VALUES (?1 || '-like', 'user-1', 'user-2', 'like', ?1)",
+			"INSERT INTO collection_items (collection_id, snippet_id, position, added_by) VALUES ('collection-1', ?, 1, 'user-2')",
+			"INSERT INTO snippet_views (snippet_id, viewer_identifier) VALUES (?, 'user:hash')",
+			"INSERT INTO snippet_view_days (snippet_id, viewer_identifier, day) VALUES (?, 'user:hash', '2025-03-03')",
+			"INSERT INTO snippet_daily_stats (snippet_id, day) VALUES (?, '2025-03-03')",
+			"INSERT INTO snippet_trending_scores (period, snippet_id, score) VALUES ('day', ?, 1)",
+		} {
+			_, err := db.Exec(query, snippet.ID)
+			require.NoError(t, err, query)
+		}
+	}
+	require.NoError(t, snippetRepo.Trash(context.Background(), "old"))
+	_, err = db.Exec("UPDATE snippets SET deleted_at = unixepoch() - 86400 * 40 WHERE id = 'old'")
+	require.NoError(t, err)
+
+	require.NoError(t, snippetRepo.Burn(context.Background(), "burning"))
+	deleted, err := snippetRepo.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	purged, err := snippetRepo.PurgeTrash(context.Background(), time.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+
+	for _, table := range []string{
+		"user_likes", "user_saves", "snippet_collaborators", "notifications", "collection_items",
+		"snippet_views", "snippet_view_days", "snippet_daily_stats", "snippet_trending_scores",
+	} {
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+		assert.NoError(t, err)
+		assert.Zero(t, count, table)
+	}
+
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	require.NoError(t, err)
+	defer rows.Close()
+	assert.False(t, rows.Next(), "no rows reference missing parents")
+}
@@ -17,8 +17,9 @@ type Storage struct {
 
 // New creates a new SQLite storage instance
 func New(dbPath string) (*Storage, error) {
-	// Add SQLite configuration options
-	dbConn, err := sql.Open("sqlite3", dbPath+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=temp_store(MEMORY)&_pragma=mmap_size(30000000000)&_pragma=page_size(4096)&_pragma=busy_timeout(5000)")
+	// Add SQLite configuration options, foreign keys are enforced so deleting a snippet
+	// or user cascades to the rows referencing it
+	dbConn, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_synchronous=NORMAL&_foreign_keys=1&_busy_timeout=5000")
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +34,11 @@ func New(dbPath string) (*Storage, error) {
 		return nil, err
 	}
 
+	// Apply data and schema changes to existing databases
+	if err := runMigrations(context.Background(), dbConn); err != nil {
+		return nil, err
+	}
+
 	return &Storage{
 		db: dbConn,
 		q:  db.New(dbConn),
@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.TrendingRepository = (*TrendingRepository)(nil)
+
+// activityHourLayout is the format of the hourly buckets returned by the activity queries
+const activityHourLayout = time.DateTime
+
+type TrendingRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewTrendingRepository(dbConn *sql.DB) *TrendingRepository {
+	return &TrendingRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *TrendingRepository) GetRecentActivity(ctx context.Context) ([]repository.SnippetActivity, error) {
+	likes, err := r.q.GetRecentLikeActivity(ctx)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get like activity")
+	}
+	saves, err := r.q.GetRecentSaveActivity(ctx)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get save activity")
+	}
+	views, err := r.q.GetRecentViewActivity(ctx)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get view activity")
+	}
+
+	result := make([]repository.SnippetActivity, 0, len(likes)+len(saves)+len(views))
+	for _, like := range likes {
+		at, err := time.Parse(activityHourLayout, like.Hour)
+		if err != nil {
+			return nil, repository.WrapError(err, "failed to parse like activity hour")
+		}
+		result = append(result, repository.SnippetActivity{
+			SnippetID: like.SnippetID,
+			Kind:      repository.ActivityLike,
+			At:        at,
+			Count:     int(like.Count),
+		})
+	}
+	for _, save := range saves {
+		at, err := time.Parse(activityHourLayout, save.Hour)
+		if err != nil {
+			return nil, repository.WrapError(err, "failed to parse save activity hour")
+		}
+		result = append(result, repository.SnippetActivity{
+			SnippetID: save.SnippetID,
+			Kind:      repository.ActivitySave,
+			At:        at,
+			Count:     int(save.Count),
+		})
+	}
+	for _, view := range views {
+		at, err := time.Parse(time.DateOnly, view.Day)
+		if err != nil {
+			return nil, repository.WrapError(err, "failed to parse view activity day")
+		}
+		result = append(result, repository.SnippetActivity{
+			SnippetID: view.SnippetID,
+			Kind:      repository.ActivityView,
+			At:        at,
+			Count:     int(view.Views),
+		})
+	}
+
+	return result, nil
+}
+
+func (r *TrendingRepository) ReplaceScores(ctx context.Context, window string, scores map[string]float64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	if err := qtx.DeleteTrendingScores(ctx, window); err != nil {
+		return repository.WrapError(err, "failed to delete trending scores")
+	}
+
+	for snippetID, score := range scores {
+		if err := qtx.InsertTrendingScore(ctx, db.InsertTrendingScoreParams{
+			Period:    window,
+			SnippetID: snippetID,
+			Score:     score,
+		}); err != nil {
+			return repository.WrapError(err, "failed to insert trending score")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit trending scores")
+	}
+	return nil
+}
+
+func (r *TrendingRepository) GetTrending(ctx context.Context, window, language, userID string, limit int) ([]*domain.Snippet, error) {
+	snippets, err := r.q.GetTrendingSnippets(ctx, db.GetTrendingSnippetsParams{
+		UserID:   userID,
+		Period:   window,
+		Language: language,
+		Limit:    int64(limit),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get trending snippets")
+	}
+
+	result := make([]*domain.Snippet, len(snippets))
+	for i, snippet := range snippets {
+		var avatar *string
+		if snippet.AuthorAvatar.Valid {
+			avatar = &snippet.AuthorAvatar.String
+		}
+		var organizationID *string
+		if snippet.OrganizationID.Valid {
+			organizationID = &snippet.OrganizationID.String
+		}
+
+		result[i] = &domain.Snippet{
+			ID:       snippet.ID,
+			Title:    snippet.Title,
+			Content:  snippet.Content,
+			Language: snippet.Language,
+			Author: &domain.User{
+				ID:       snippet.AuthorID.String,
+				Username: snippet.AuthorUsername.String,
+				Email:    snippet.AuthorEmail.String,
+				Avatar:   avatar,
+			},
+			OrganizationID: organizationID,
+			Visibility:     domain.SnippetVisibility(snippet.Visibility),
+			ExpiresAt:      fromUnixTime(snippet.ExpiresAt),
+			BurnAfterRead:  snippet.BurnAfterRead,
+			PasswordHash:   snippet.PasswordHash.String,
+			CreatedAt:      snippet.CreatedAt,
+			UpdatedAt:      snippet.UpdatedAt,
+			Views:          int(snippet.Views),
+			Likes:          int(snippet.Likes),
+			IsLiked:        snippet.IsLiked == 1,
+			IsSaved:        snippet.IsSaved == 1,
+		}
+	}
+
+	return result, nil
+}
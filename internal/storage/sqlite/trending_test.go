@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func TestTrendingRepository(t *testing.T) {
+	db, viewRepo, snippetRepo, userRepo := setupViewTestDB(t)
+	defer db.Close()
+	trendingRepo := NewTrendingRepository(db)
+
+	// Create a user and two snippets
+	user := &domain.UserCreation{ID: "user-1", Username: "user1", Email: "user1@example.com"}
+	createdUser, err := userRepo.Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	goSnippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: createdUser}
+	err = snippetRepo.Create(context.Background(), goSnippet)
+	assert.NoError(t, err)
+
+	pySnippet := &domain.Snippet{ID: "snippet-2", Title: "Snippet 2", Content: "Content 2", Language: "python", Author: createdUser}
+	err = snippetRepo.Create(context.Background(), pySnippet)
+	assert.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO user_likes (snippet_id, user_id) VALUES (?, ?)", goSnippet.ID, createdUser.ID)
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO user_saves (snippet_id, user_id) VALUES (?, ?)", pySnippet.ID, createdUser.ID)
+	assert.NoError(t, err)
+	_, err = viewRepo.FlushViews(context.Background(), []repository.BufferedView{
+		{SnippetID: goSnippet.ID, ViewerIdentifier: "viewer-1", LastViewedAt: time.Now(), Hits: 2, Counted: 1},
+		{SnippetID: pySnippet.ID, ViewerIdentifier: "viewer-2", LastViewedAt: time.Now(), Hits: 3, Bot: true},
+	}, map[string]int{goSnippet.ID: 1})
+	assert.NoError(t, err)
+
+	t.Run("recent activity", func(t *testing.T) {
+		activity, err := trendingRepo.GetRecentActivity(context.Background())
+		assert.NoError(t, err)
+
+		counts := make(map[string]int)
+		for _, a := range activity {
+			counts[a.SnippetID+"/"+string(a.Kind)] += a.Count
+			assert.WithinDuration(t, time.Now(), a.At, 24*time.Hour)
+		}
+		assert.Equal(t, map[string]int{
+			"snippet-1/like": 1,
+			"snippet-1/view": 1,
+			"snippet-2/save": 1,
+		}, counts)
+	})
+
+	t.Run("replace and get", func(t *testing.T) {
+		err := trendingRepo.ReplaceScores(context.Background(), "day", map[string]float64{goSnippet.ID: 1, pySnippet.ID: 2})
+		assert.NoError(t, err)
+		err = trendingRepo.ReplaceScores(context.Background(), "week", map[string]float64{goSnippet.ID: 5})
+		assert.NoError(t, err)
+
+		snippets, err := trendingRepo.GetTrending(context.Background(), "day", "", createdUser.ID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 2)
+		assert.Equal(t, pySnippet.ID, snippets[0].ID)
+		assert.True(t, snippets[0].IsSaved)
+		assert.Equal(t, goSnippet.ID, snippets[1].ID)
+		assert.True(t, snippets[1].IsLiked)
+
+		snippets, err = trendingRepo.GetTrending(context.Background(), "day", "go", createdUser.ID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 1)
+		assert.Equal(t, goSnippet.ID, snippets[0].ID)
+
+		// Replacing drops snippets that no longer trend
+		err = trendingRepo.ReplaceScores(context.Background(), "day", map[string]float64{goSnippet.ID: 3})
+		assert.NoError(t, err)
+		snippets, err = trendingRepo.GetTrending(context.Background(), "day", "", "", 10)
+		assert.NoError(t, err)
+		assert.Len(t, snippets, 1)
+	})
+}
@@ -3,9 +3,15 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
 	"strconv"
+	"time"
 
+	"github.com/mattn/go-sqlite3"
 	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/repository"
 )
 
@@ -89,10 +95,180 @@ func (r *ViewRepository) IncrementViewCount(ctx context.Context, snippetID strin
 	return nil
 }
 
+func (r *ViewRepository) FlushViews(ctx context.Context, views []repository.BufferedView, increments map[string]int) ([]repository.BufferedView, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, flushError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	// Each view is written under its own savepoint, so one bad row does not hold back the batch
+	increments = maps.Clone(increments)
+	var rejected []repository.BufferedView
+	for _, view := range views {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT flush_view"); err != nil {
+			return nil, flushError(err, "failed to create savepoint")
+		}
+
+		if err := upsertBufferedView(ctx, qtx, view); err != nil {
+			if isBusy(err) {
+				return nil, flushError(err, "failed to record view")
+			}
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO flush_view"); err != nil {
+				return nil, flushError(err, "failed to roll back view")
+			}
+			increments[view.SnippetID] -= view.Counted
+			if !isForeignKeyViolation(err) {
+				rejected = append(rejected, view)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "RELEASE flush_view"); err != nil {
+			return nil, flushError(err, "failed to release savepoint")
+		}
+	}
+
+	for snippetID, count := range increments {
+		if count <= 0 {
+			continue
+		}
+		if err := qtx.AddViews(ctx, db.AddViewsParams{
+			Count:     int64(count),
+			SnippetID: snippetID,
+		}); err != nil {
+			return nil, flushError(err, "failed to increment view count")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, flushError(err, "failed to commit views")
+	}
+	return rejected, nil
+}
+
+// upsertBufferedView adds a buffered view to the viewer's record and daily record
+func upsertBufferedView(ctx context.Context, qtx *db.Queries, view repository.BufferedView) error {
+	if err := qtx.UpsertView(ctx, db.UpsertViewParams{
+		SnippetID:        view.SnippetID,
+		ViewerIdentifier: view.ViewerIdentifier,
+		IpAddress:        sql.NullString{String: view.IPAddress, Valid: view.IPAddress != ""},
+		LastViewedAt:     sql.NullTime{Time: view.LastViewedAt, Valid: !view.LastViewedAt.IsZero()},
+		Hits:             sql.NullInt64{Int64: int64(view.Hits), Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	day := view.LastViewedAt
+	if day.IsZero() {
+		day = time.Now()
+	}
+	params := db.UpsertViewDayParams{
+		SnippetID:        view.SnippetID,
+		ViewerIdentifier: view.ViewerIdentifier,
+		Day:              day.UTC().Format(time.DateOnly),
+	}
+	if view.Bot {
+		params.BotHits = int64(view.Hits)
+	} else {
+		params.Hits = int64(view.Hits)
+		params.CountedViews = int64(view.Counted)
+	}
+	return qtx.UpsertViewDay(ctx, params)
+}
+
+// flushError wraps an error of a view flush, marking a busy or locked database as
+// repository.ErrUnavailable so the batch is retried as a whole
+func flushError(err error, msg string) error {
+	if isBusy(err) {
+		err = fmt.Errorf("%w: %w", repository.ErrUnavailable, err)
+	}
+	return repository.WrapError(err, msg)
+}
+
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked)
+}
+
+func isForeignKeyViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+}
+
+func (r *ViewRepository) GetSnippetStats(ctx context.Context, snippetID string) (*repository.SnippetStats, error) {
+	stats, err := r.q.GetSnippetStats(ctx, snippetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get snippet stats")
+	}
+	return &repository.SnippetStats{
+		Views: int(stats.Views),
+		Likes: int(stats.Likes),
+	}, nil
+}
+
+func (r *ViewRepository) AggregateDailyStats(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	if err := qtx.AggregateDailyViews(ctx); err != nil {
+		return repository.WrapError(err, "failed to aggregate daily views")
+	}
+	if err := qtx.ResetDailyLikes(ctx); err != nil {
+		return repository.WrapError(err, "failed to reset daily likes")
+	}
+	if err := qtx.AggregateDailyLikes(ctx); err != nil {
+		return repository.WrapError(err, "failed to aggregate daily likes")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit daily stats")
+	}
+	return nil
+}
+
+func (r *ViewRepository) GetDailyStats(ctx context.Context, snippetID string, from, to time.Time) ([]*domain.SnippetStatsPoint, error) {
+	rows, err := r.q.GetDailyStats(ctx, db.GetDailyStatsParams{
+		SnippetID: snippetID,
+		FromDay:   from.UTC().Format(time.DateOnly),
+		ToDay:     to.UTC().Format(time.DateOnly),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get daily stats")
+	}
+
+	result := make([]*domain.SnippetStatsPoint, 0, len(rows))
+	for _, row := range rows {
+		day, err := time.Parse(time.DateOnly, row.Day)
+		if err != nil {
+			return nil, repository.WrapError(err, "failed to parse stats day")
+		}
+		result = append(result, &domain.SnippetStatsPoint{
+			Start:         day,
+			Views:         int(row.Views),
+			UniqueViewers: int(row.UniqueViewers),
+			Likes:         int(row.Likes),
+			BotViews:      int(row.BotViews),
+		})
+	}
+
+	return result, nil
+}
+
 func (r *ViewRepository) CleanupOldViews(ctx context.Context) error {
 	err := r.q.CleanupOldViews(ctx)
 	if err != nil {
 		return repository.WrapError(err, "failed to cleanup old views")
 	}
+	if err := r.q.CleanupOldViewDays(ctx); err != nil {
+		return repository.WrapError(err, "failed to cleanup old daily views")
+	}
 	return nil
 }
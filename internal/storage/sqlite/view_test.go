@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
 )
 
 func setupViewTestDB(t *testing.T) (*sql.DB, *ViewRepository, *SnippetRepository, *UserRepository) {
@@ -149,3 +150,165 @@ func TestViewRepository_CleanupOldViews(t *testing.T) {
 		assert.Equal(t, 1, count)
 	})
 }
+
+func TestViewRepository_FlushViews(t *testing.T) {
+	db, viewRepo, snippetRepo, userRepo := setupViewTestDB(t)
+	defer db.Close()
+
+	// Create a user and a snippet
+	user := &domain.UserCreation{ID: "user-1", Username: "user1", Email: "user1@example.com"}
+	createdUser, err := userRepo.Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: createdUser}
+	err = snippetRepo.Create(context.Background(), snippet)
+	assert.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("inserts and aggregates", func(t *testing.T) {
+		views := []repository.BufferedView{
+			{SnippetID: snippet.ID, ViewerIdentifier: "viewer-1", IPAddress: "127.0.0.1", LastViewedAt: now, Hits: 3},
+			{SnippetID: snippet.ID, ViewerIdentifier: "viewer-2", IPAddress: "127.0.0.2", LastViewedAt: now, Hits: 1},
+		}
+		_, err := viewRepo.FlushViews(context.Background(), views, map[string]int{snippet.ID: 2})
+		assert.NoError(t, err)
+
+		_, err = viewRepo.FlushViews(context.Background(), views[:1], map[string]int{snippet.ID: 1})
+		assert.NoError(t, err)
+
+		var viewCount int
+		err = db.QueryRow("SELECT view_count FROM snippet_views WHERE snippet_id = ? AND viewer_identifier = ?", snippet.ID, "viewer-1").Scan(&viewCount)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, viewCount)
+
+		stats, err := viewRepo.GetSnippetStats(context.Background(), snippet.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.Views)
+		assert.Equal(t, 0, stats.Likes)
+	})
+
+	t.Run("rejected views do not hold back the batch", func(t *testing.T) {
+		_, err := db.Exec(`CREATE TRIGGER reject_view BEFORE INSERT ON snippet_views
+			WHEN NEW.viewer_identifier = 'broken'
+			BEGIN SELECT RAISE(ABORT, 'broken view'); END`)
+		assert.NoError(t, err)
+		defer db.Exec("DROP TRIGGER reject_view")
+
+		views := []repository.BufferedView{
+			{SnippetID: snippet.ID, ViewerIdentifier: "broken", LastViewedAt: now, Hits: 1, Counted: 1},
+			{SnippetID: snippet.ID, ViewerIdentifier: "viewer-3", LastViewedAt: now, Hits: 1, Counted: 1},
+		}
+		rejected, err := viewRepo.FlushViews(context.Background(), views, map[string]int{snippet.ID: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, views[:1], rejected)
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM snippet_view_days WHERE viewer_identifier = 'broken'").Scan(&count)
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+		err = db.QueryRow("SELECT COUNT(*) FROM snippet_views WHERE viewer_identifier = 'viewer-3'").Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// Only the written view is counted
+		stats, err := viewRepo.GetSnippetStats(context.Background(), snippet.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, stats.Views)
+	})
+
+	t.Run("recent view is visible", func(t *testing.T) {
+		viewRecord, err := viewRepo.CheckRecentView(context.Background(), snippet.ID, "viewer-2")
+		assert.NoError(t, err)
+		assert.Equal(t, now, viewRecord.LastViewedAt.UTC())
+	})
+
+	t.Run("stats not found", func(t *testing.T) {
+		_, err := viewRepo.GetSnippetStats(context.Background(), "non-existent-snippet")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestViewRepository_AggregateDailyStats(t *testing.T) {
+	db, viewRepo, snippetRepo, userRepo := setupViewTestDB(t)
+	defer db.Close()
+
+	// Create a user and a snippet
+	user := &domain.UserCreation{ID: "user-1", Username: "user1", Email: "user1@example.com"}
+	createdUser, err := userRepo.Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	snippet := &domain.Snippet{ID: "snippet-1", Title: "Snippet 1", Content: "Content 1", Language: "go", Author: createdUser}
+	err = snippetRepo.Create(context.Background(), snippet)
+	assert.NoError(t, err)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+
+	views := []repository.BufferedView{
+		{SnippetID: snippet.ID, ViewerIdentifier: "viewer-1", LastViewedAt: yesterday, Hits: 4, Counted: 2},
+		{SnippetID: snippet.ID, ViewerIdentifier: "viewer-2", LastViewedAt: yesterday, Hits: 1, Counted: 1},
+		{SnippetID: snippet.ID, ViewerIdentifier: "viewer-3", LastViewedAt: yesterday, Hits: 5, Bot: true},
+		{SnippetID: snippet.ID, ViewerIdentifier: "viewer-1", LastViewedAt: today, Hits: 1, Counted: 1},
+	}
+	_, err = viewRepo.FlushViews(context.Background(), views, map[string]int{snippet.ID: 4})
+	assert.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO user_likes (snippet_id, user_id) VALUES (?, ?)", snippet.ID, createdUser.ID)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		err := viewRepo.AggregateDailyStats(context.Background())
+		assert.NoError(t, err)
+
+		// Aggregation is idempotent
+		err = viewRepo.AggregateDailyStats(context.Background())
+		assert.NoError(t, err)
+
+		stats, err := viewRepo.GetDailyStats(context.Background(), snippet.ID, yesterday, today)
+		assert.NoError(t, err)
+		assert.Len(t, stats, 2)
+
+		assert.Equal(t, yesterday, stats[0].Start)
+		assert.Equal(t, 3, stats[0].Views)
+		assert.Equal(t, 2, stats[0].UniqueViewers)
+		assert.Equal(t, 5, stats[0].BotViews)
+
+		assert.Equal(t, today, stats[1].Start)
+		assert.Equal(t, 1, stats[1].Views)
+		assert.Equal(t, 1, stats[1].UniqueViewers)
+		assert.Equal(t, 1, stats[1].Likes)
+	})
+
+	t.Run("likes taken back", func(t *testing.T) {
+		_, err := db.Exec("DELETE FROM user_likes WHERE snippet_id = ?", snippet.ID)
+		assert.NoError(t, err)
+
+		err = viewRepo.AggregateDailyStats(context.Background())
+		assert.NoError(t, err)
+
+		stats, err := viewRepo.GetDailyStats(context.Background(), snippet.ID, today, today)
+		assert.NoError(t, err)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, 0, stats[0].Likes)
+		assert.Equal(t, 1, stats[0].Views)
+	})
+
+	t.Run("stats survive cleanup", func(t *testing.T) {
+		_, err := db.Exec("UPDATE snippet_view_days SET day = ? WHERE day = ?", today.AddDate(0, 0, -40).Format(time.DateOnly), yesterday.Format(time.DateOnly))
+		assert.NoError(t, err)
+
+		err = viewRepo.CleanupOldViews(context.Background())
+		assert.NoError(t, err)
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM snippet_view_days").Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		stats, err := viewRepo.GetDailyStats(context.Background(), snippet.ID, yesterday, yesterday)
+		assert.NoError(t, err)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, 3, stats[0].Views)
+	})
+}
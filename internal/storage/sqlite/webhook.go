@@ -0,0 +1,272 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	db "mitsimi.dev/codeShare/internal/db/sqlc"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
+
+type WebhookRepository struct {
+	db *sql.DB
+	q  *db.Queries
+}
+
+func NewWebhookRepository(dbConn *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: dbConn,
+		q:  db.New(dbConn),
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	created, err := r.q.CreateWebhook(ctx, db.CreateWebhookParams{
+		ID:      webhook.ID,
+		OwnerID: webhook.OwnerID,
+		Url:     webhook.URL,
+		Secret:  webhook.Secret,
+		Events:  joinWebhookEvents(webhook.Events),
+		Global:  webhook.Global,
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to create webhook")
+	}
+	return toDomainWebhook(created), nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+	webhook, err := r.q.GetWebhook(ctx, webhookID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get webhook")
+	}
+	return toDomainWebhook(webhook), nil
+}
+
+func (r *WebhookRepository) GetByOwner(ctx context.Context, ownerID string) ([]*domain.Webhook, error) {
+	webhooks, err := r.q.GetWebhooksByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get webhooks")
+	}
+
+	result := make([]*domain.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		result[i] = toDomainWebhook(webhook)
+	}
+	return result, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	updated, err := r.q.UpdateWebhook(ctx, db.UpdateWebhookParams{
+		Url:    webhook.URL,
+		Events: joinWebhookEvents(webhook.Events),
+		Active: webhook.Active,
+		ID:     webhook.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to update webhook")
+	}
+	return toDomainWebhook(updated), nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, webhookID string) error {
+	if err := r.q.DeleteWebhook(ctx, webhookID); err != nil {
+		return repository.WrapError(err, "failed to delete webhook")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetSubscribed(ctx context.Context, ownerID string, event domain.WebhookEvent) ([]*domain.Webhook, error) {
+	webhooks, err := r.q.GetSubscribedWebhooks(ctx, db.GetSubscribedWebhooksParams{
+		OwnerID: ownerID,
+		Event:   string(event),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get subscribed webhooks")
+	}
+
+	result := make([]*domain.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		result[i] = toDomainWebhook(webhook)
+	}
+	return result, nil
+}
+
+func (r *WebhookRepository) Enqueue(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.WrapError(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+	qtx := r.q.WithTx(tx)
+
+	for _, delivery := range deliveries {
+		if err := qtx.InsertWebhookDelivery(ctx, db.InsertWebhookDeliveryParams{
+			ID:            delivery.ID,
+			WebhookID:     delivery.WebhookID,
+			Event:         string(delivery.Event),
+			Payload:       delivery.Payload,
+			NextAttemptAt: delivery.NextAttemptAt.Unix(),
+		}); err != nil {
+			return repository.WrapError(err, "failed to enqueue webhook delivery")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return repository.WrapError(err, "failed to commit webhook deliveries")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*repository.DueWebhookDelivery, error) {
+	rows, err := r.q.GetDueWebhookDeliveries(ctx, db.GetDueWebhookDeliveriesParams{
+		Now:   now.Unix(),
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get due webhook deliveries")
+	}
+
+	result := make([]*repository.DueWebhookDelivery, len(rows))
+	for i, row := range rows {
+		result[i] = &repository.DueWebhookDelivery{
+			Delivery: toDomainWebhookDelivery(db.WebhookDelivery{
+				ID:             row.ID,
+				WebhookID:      row.WebhookID,
+				Event:          row.Event,
+				Payload:        row.Payload,
+				Status:         row.Status,
+				Attempts:       row.Attempts,
+				NextAttemptAt:  row.NextAttemptAt,
+				ResponseStatus: row.ResponseStatus,
+				LastError:      row.LastError,
+				CreatedAt:      row.CreatedAt,
+				CompletedAt:    row.CompletedAt,
+			}),
+			URL:    row.Url,
+			Secret: row.Secret,
+		}
+	}
+	return result, nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	delivery, err := r.q.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrNotFound
+		}
+		return nil, repository.WrapError(err, "failed to get webhook delivery")
+	}
+	return toDomainWebhookDelivery(delivery), nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID string, limit int) ([]*domain.WebhookDelivery, error) {
+	deliveries, err := r.q.GetWebhookDeliveries(ctx, db.GetWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		return nil, repository.WrapError(err, "failed to get webhook deliveries")
+	}
+
+	result := make([]*domain.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = toDomainWebhookDelivery(delivery)
+	}
+	return result, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	params := db.UpdateWebhookDeliveryParams{
+		Status:        string(delivery.Status),
+		Attempts:      int64(delivery.Attempts),
+		NextAttemptAt: delivery.NextAttemptAt.Unix(),
+		ID:            delivery.ID,
+	}
+	if delivery.ResponseStatus != nil {
+		params.ResponseStatus = sql.NullInt64{Int64: int64(*delivery.ResponseStatus), Valid: true}
+	}
+	if delivery.LastError != nil {
+		params.LastError = sql.NullString{String: *delivery.LastError, Valid: true}
+	}
+	if delivery.CompletedAt != nil {
+		params.CompletedAt = sql.NullTime{Time: *delivery.CompletedAt, Valid: true}
+	}
+
+	if err := r.q.UpdateWebhookDelivery(ctx, params); err != nil {
+		return repository.WrapError(err, "failed to update webhook delivery")
+	}
+	return nil
+}
+
+func (r *WebhookRepository) DeleteOldDeliveries(ctx context.Context) error {
+	if err := r.q.DeleteOldWebhookDeliveries(ctx); err != nil {
+		return repository.WrapError(err, "failed to delete old webhook deliveries")
+	}
+	return nil
+}
+
+func joinWebhookEvents(events []domain.WebhookEvent) string {
+	values := make([]string, len(events))
+	for i, event := range events {
+		values[i] = string(event)
+	}
+	return strings.Join(values, ",")
+}
+
+func toDomainWebhook(webhook db.Webhook) *domain.Webhook {
+	var events []domain.WebhookEvent
+	if webhook.Events != "" {
+		for _, event := range strings.Split(webhook.Events, ",") {
+			events = append(events, domain.WebhookEvent(event))
+		}
+	}
+
+	return &domain.Webhook{
+		ID:        webhook.ID,
+		OwnerID:   webhook.OwnerID,
+		URL:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    events,
+		Global:    webhook.Global,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func toDomainWebhookDelivery(delivery db.WebhookDelivery) *domain.WebhookDelivery {
+	result := &domain.WebhookDelivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		Event:         domain.WebhookEvent(delivery.Event),
+		Payload:       delivery.Payload,
+		Status:        domain.WebhookDeliveryStatus(delivery.Status),
+		Attempts:      int(delivery.Attempts),
+		NextAttemptAt: time.Unix(delivery.NextAttemptAt, 0),
+		CreatedAt:     delivery.CreatedAt,
+	}
+	if delivery.ResponseStatus.Valid {
+		status := int(delivery.ResponseStatus.Int64)
+		result.ResponseStatus = &status
+	}
+	if delivery.LastError.Valid {
+		result.LastError = &delivery.LastError.String
+	}
+	if delivery.CompletedAt.Valid {
+		result.CompletedAt = &delivery.CompletedAt.Time
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+func setupWebhookTestDB(t *testing.T) (*sql.DB, *WebhookRepository, *UserRepository) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	storage, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	webhookRepo := NewWebhookRepository(storage.DB())
+	userRepo := NewUserRepository(storage.DB())
+	return storage.DB(), webhookRepo, userRepo
+}
+
+func TestWebhookRepository_CRUD(t *testing.T) {
+	db, webhookRepo, userRepo := setupWebhookTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+
+	created, err := webhookRepo.Create(context.Background(), &domain.Webhook{
+		ID: "webhook-1", OwnerID: alice.ID, URL: "https://example.com/hook", Secret: "secret",
+		Events: []domain.WebhookEvent{domain.WebhookEventSnippetCreated, domain.WebhookEventSnippetLiked},
+	})
+	assert.NoError(t, err)
+	assert.True(t, created.Active)
+	assert.False(t, created.Global)
+	assert.Equal(t, []domain.WebhookEvent{domain.WebhookEventSnippetCreated, domain.WebhookEventSnippetLiked}, created.Events)
+
+	t.Run("update", func(t *testing.T) {
+		created.Active = false
+		created.Events = nil
+		updated, err := webhookRepo.Update(context.Background(), created)
+		assert.NoError(t, err)
+		assert.False(t, updated.Active)
+		assert.Empty(t, updated.Events)
+	})
+
+	t.Run("list by owner", func(t *testing.T) {
+		webhooks, err := webhookRepo.GetByOwner(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Len(t, webhooks, 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := webhookRepo.Delete(context.Background(), created.ID)
+		assert.NoError(t, err)
+
+		_, err = webhookRepo.GetByID(context.Background(), created.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestWebhookRepository_GetSubscribed(t *testing.T) {
+	db, webhookRepo, userRepo := setupWebhookTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	bob, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-2", Username: "bob", Email: "bob@example.com"})
+	assert.NoError(t, err)
+
+	webhooks := []*domain.Webhook{
+		{ID: "all-events", OwnerID: alice.ID, URL: "https://example.com/1", Secret: "s"},
+		{ID: "likes-only", OwnerID: alice.ID, URL: "https://example.com/2", Secret: "s", Events: []domain.WebhookEvent{domain.WebhookEventSnippetLiked}},
+		{ID: "bob", OwnerID: bob.ID, URL: "https://example.com/3", Secret: "s"},
+		{ID: "global", OwnerID: bob.ID, URL: "https://example.com/4", Secret: "s", Global: true, Events: []domain.WebhookEvent{domain.WebhookEventSnippetCreated}},
+	}
+	for _, webhook := range webhooks {
+		_, err := webhookRepo.Create(context.Background(), webhook)
+		assert.NoError(t, err)
+	}
+
+	ids := func(webhooks []*domain.Webhook) []string {
+		result := make([]string, len(webhooks))
+		for i, webhook := range webhooks {
+			result[i] = webhook.ID
+		}
+		return result
+	}
+
+	subscribed, err := webhookRepo.GetSubscribed(context.Background(), alice.ID, domain.WebhookEventSnippetCreated)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"all-events", "global"}, ids(subscribed))
+
+	subscribed, err = webhookRepo.GetSubscribed(context.Background(), alice.ID, domain.WebhookEventSnippetLiked)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"all-events", "likes-only"}, ids(subscribed))
+
+	// Inactive webhooks receive nothing
+	webhooks[0].Active = false
+	_, err = webhookRepo.Update(context.Background(), webhooks[0])
+	assert.NoError(t, err)
+
+	subscribed, err = webhookRepo.GetSubscribed(context.Background(), alice.ID, domain.WebhookEventSnippetDeleted)
+	assert.NoError(t, err)
+	assert.Empty(t, subscribed)
+}
+
+func TestWebhookRepository_Deliveries(t *testing.T) {
+	db, webhookRepo, userRepo := setupWebhookTestDB(t)
+	defer db.Close()
+
+	alice, err := userRepo.Create(context.Background(), &domain.UserCreation{ID: "user-1", Username: "alice", Email: "alice@example.com"})
+	assert.NoError(t, err)
+	_, err = webhookRepo.Create(context.Background(), &domain.Webhook{ID: "webhook-1", OwnerID: alice.ID, URL: "https://example.com/hook", Secret: "secret"})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	err = webhookRepo.Enqueue(context.Background(), []*domain.WebhookDelivery{
+		{ID: "due", WebhookID: "webhook-1", Event: domain.WebhookEventSnippetCreated, Payload: `{}`, NextAttemptAt: now.Add(-time.Minute)},
+		{ID: "later", WebhookID: "webhook-1", Event: domain.WebhookEventSnippetUpdated, Payload: `{}`, NextAttemptAt: now.Add(time.Hour)},
+	})
+	assert.NoError(t, err)
+
+	t.Run("only due deliveries", func(t *testing.T) {
+		due, err := webhookRepo.GetDueDeliveries(context.Background(), now, 10)
+		assert.NoError(t, err)
+		assert.Len(t, due, 1)
+		assert.Equal(t, "due", due[0].Delivery.ID)
+		assert.Equal(t, domain.WebhookDeliveryPending, due[0].Delivery.Status)
+		assert.Equal(t, "https://example.com/hook", due[0].URL)
+		assert.Equal(t, "secret", due[0].Secret)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		delivery, err := webhookRepo.GetDelivery(context.Background(), "due")
+		assert.NoError(t, err)
+
+		status := 200
+		completed := time.Now()
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.Attempts = 1
+		delivery.ResponseStatus = &status
+		delivery.CompletedAt = &completed
+		err = webhookRepo.UpdateDelivery(context.Background(), delivery)
+		assert.NoError(t, err)
+
+		due, err := webhookRepo.GetDueDeliveries(context.Background(), now, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, due)
+
+		delivery, err = webhookRepo.GetDelivery(context.Background(), "due")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 200, *delivery.ResponseStatus)
+		assert.NotNil(t, delivery.CompletedAt)
+	})
+
+	t.Run("log", func(t *testing.T) {
+		deliveries, err := webhookRepo.GetDeliveries(context.Background(), "webhook-1", 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 2)
+	})
+
+	t.Run("cleanup keeps pending deliveries", func(t *testing.T) {
+		_, err := db.Exec("UPDATE webhook_deliveries SET created_at = datetime('now', '-40 days')")
+		assert.NoError(t, err)
+
+		err = webhookRepo.DeleteOldDeliveries(context.Background())
+		assert.NoError(t, err)
+
+		deliveries, err := webhookRepo.GetDeliveries(context.Background(), "webhook-1", 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, "later", deliveries[0].ID)
+	})
+}
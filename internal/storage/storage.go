@@ -71,7 +71,16 @@ func (s *Storage) ToggleLikeSnippet(ctx context.Context, userID, snippetID strin
 
 // Bookmark operations
 func (s *Storage) GetSavedSnippets(ctx context.Context, userID string) ([]*domain.Snippet, error) {
-	return s.bookmarks.GetSavedSnippets(ctx, userID)
+	bookmarks, err := s.bookmarks.GetSavedSnippets(ctx, userID, repository.BookmarkFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	snippets := make([]*domain.Snippet, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		snippets[i] = bookmark.Snippet
+	}
+	return snippets, nil
 }
 
 func (s *Storage) ToggleSaveSnippet(ctx context.Context, userID, snippetID string, isSave bool) error {
@@ -0,0 +1,129 @@
+package ws
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// accessCheckTimeout bounds looking up whether a client may follow a snippet
+const accessCheckTimeout = 5 * time.Second
+
+// SnippetAccess decides who may follow the updates of a snippet
+type SnippetAccess interface {
+	// CanView returns an error if the user, empty for anonymous clients, may not see the snippet
+	CanView(ctx context.Context, snippetID, userID string) error
+}
+
+// authorizeSubscriptions drops the snippet subscriptions the client may not follow and
+// tells the client about them. It queries the snippet access and must be called from
+// the client's reader goroutine, never from the hub goroutine.
+func (h *Hub) authorizeSubscriptions(client *Client, subscriptions []SubscriptionRequest) []SubscriptionRequest {
+	if h.access == nil {
+		return subscriptions
+	}
+
+	userID := client.userID
+	if userID == "anonymous" {
+		userID = ""
+	}
+
+	allowed := subscriptions[:0:0]
+	for _, subscription := range subscriptions {
+		if subscription.Type == SubTypeSnippetUpdates && subscription.SnippetID != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), accessCheckTimeout)
+			err := h.access.CanView(ctx, *subscription.SnippetID, userID)
+			cancel()
+			if err != nil {
+				client.logger.Warn("Snippet subscription rejected", zap.String("snippet_id", *subscription.SnippetID), zap.Error(err))
+				client.sendError("Snippet not found", subscription.SnippetID)
+				continue
+			}
+		}
+		allowed = append(allowed, subscription)
+	}
+	return allowed
+}
+
+// RevalidateSnippet checks the subscribers and editors of a snippet again after who may
+// see or edit it changed, e.g. its visibility, password or collaborators. Clients that
+// lost access are unsubscribed and removed from the edit session. The checks query the
+// snippet access and the edit store, so they run in the background.
+func (h *Hub) RevalidateSnippet(snippetID string) {
+	go h.revalidateSnippet(snippetID)
+}
+
+func (h *Hub) revalidateSnippet(snippetID string) {
+	if h.access != nil {
+		var subscribers []*Client
+		h.query(func() {
+			for client := range h.snippetUpdateClients[snippetID] {
+				subscribers = append(subscribers, client)
+			}
+		})
+
+		denied := deniedClients(subscribers, func(ctx context.Context, userID string) error {
+			return h.access.CanView(ctx, snippetID, userID)
+		})
+		if len(denied) > 0 {
+			h.query(func() {
+				for _, client := range denied {
+					h.evictSubscriber(client, snippetID)
+				}
+			})
+		}
+	}
+
+	if h.editStore != nil {
+		denied := deniedClients(h.editSessionEditors(snippetID), func(ctx context.Context, userID string) error {
+			_, err := h.editStore.Load(ctx, snippetID, userID)
+			return err
+		})
+		for _, client := range denied {
+			h.leaveEditSession(client, snippetID)
+			client.sendError("Snippet cannot be edited", &snippetID)
+			client.logger.Info("Removed from edit session after losing access", zap.String("snippet_id", snippetID))
+		}
+	}
+}
+
+// deniedClients returns the clients whose user fails the check, checking each user once.
+// Clients are denied when the check cannot be completed.
+func deniedClients(clients []*Client, check func(ctx context.Context, userID string) error) []*Client {
+	results := make(map[string]error)
+	var denied []*Client
+	for _, client := range clients {
+		userID := client.userID
+		if userID == "anonymous" {
+			userID = ""
+		}
+
+		err, checked := results[userID]
+		if !checked {
+			ctx, cancel := context.WithTimeout(context.Background(), accessCheckTimeout)
+			err = check(ctx, userID)
+			cancel()
+			results[userID] = err
+		}
+		if err != nil {
+			denied = append(denied, client)
+		}
+	}
+	return denied
+}
+
+// evictSubscriber ends the snippet subscription of a client that may no longer see the
+// snippet. It must be called from the hub goroutine.
+func (h *Hub) evictSubscriber(client *Client, snippetID string) {
+	if _, registered := h.clients[client]; !registered || !client.snippetUpdatesSubscribed[snippetID] {
+		return
+	}
+	delete(client.snippetUpdatesSubscribed, snippetID)
+	removeClient(h.snippetUpdateClients, snippetID, client)
+	h.releaseTopic(topicKey(SubTypeSnippetUpdates, snippetID))
+	h.markPresenceChanged(snippetID, nil)
+
+	client.sendError("Snippet not found", &snippetID)
+	client.logger.Info("Snippet subscription revoked", zap.String("snippet_id", snippetID))
+}
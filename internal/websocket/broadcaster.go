@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/domain"
 )
 
 // BroadcastUserAction syncs like/save status across user's devices
@@ -32,6 +34,10 @@ func (h *Hub) BroadcastUserAction(userID string, data UserActionData) {
 
 // BroadcastSnippetUpdate sends content + stats updates to specific snippet subscribers
 func (h *Hub) BroadcastSnippetUpdate(snippetID string, data SnippetUpdateData) {
+	h.broadcastSnippetUpdate(snippetID, data, false)
+}
+
+func (h *Hub) broadcastSnippetUpdate(snippetID string, data SnippetUpdateData, stats bool) {
 	message := WebSocketMessage{
 		Type:      MessageTypeSnippetUpdates,
 		Data:      data,
@@ -45,6 +51,7 @@ func (h *Hub) BroadcastSnippetUpdate(snippetID string, data SnippetUpdateData) {
 			Type:      BroadcastTargetTypeSnippetUpdates,
 			SnippetID: &snippetID,
 		},
+		Stats: stats,
 	}
 
 	h.logger.Debug("Broadcasting snippet update",
@@ -52,8 +59,17 @@ func (h *Hub) BroadcastSnippetUpdate(snippetID string, data SnippetUpdateData) {
 		zap.String("snippet_id", snippetID))
 }
 
-// BroadcastListUpdate sends content changes to all list view subscribers
+// BroadcastListUpdate sends changes to all list view subscribers, and to the
+// subscribers filtering by the author or language of data.Snippet
 func (h *Hub) BroadcastListUpdate(data ListUpdateData) {
+	if data.Snippet != nil {
+		h.broadcastListUpdate(data, &data.Snippet.Author.ID, &data.Snippet.Language)
+	} else {
+		h.broadcastListUpdate(data, nil, nil)
+	}
+}
+
+func (h *Hub) broadcastListUpdate(data ListUpdateData, authorID, language *string) {
 	message := WebSocketMessage{
 		Type:      MessageTypeListUpdates,
 		Data:      data,
@@ -64,40 +80,164 @@ func (h *Hub) BroadcastListUpdate(data ListUpdateData) {
 	h.broadcast <- BroadcastMessage{
 		Message: message,
 		Target: BroadcastTarget{
-			Type: BroadcastTargetTypeListUpdates,
+			Type:     BroadcastTargetTypeListUpdates,
+			AuthorID: authorID,
+			Language: language,
 		},
 	}
 
-	h.logger.Debug("Broadcasting list update", zap.String("snippet_id", data.SnippetID))
+	h.logger.Debug("Broadcasting list update",
+		zap.String("event", string(data.Event)),
+		zap.String("snippet_id", data.SnippetID))
 }
 
-// BroadcastSnippetContentUpdate is a convenience method that broadcasts both
-// snippet_updates and list_updates for content changes
-func (h *Hub) BroadcastSnippetContentUpdate(snippetID string, title, content, language *string) {
-	// Broadcast to specific snippet subscribers (detail view)
-	h.BroadcastSnippetUpdate(snippetID, SnippetUpdateData{
-		SnippetID:  snippetID,
+// BroadcastSnippetCreated adds a new public snippet to the list views
+func (h *Hub) BroadcastSnippetCreated(snippet dto.SnippetResponse) {
+	if !isListed(snippet) {
+		return
+	}
+	h.BroadcastListUpdate(ListUpdateData{
+		Event:     ListEventCreated,
+		SnippetID: snippet.ID,
+		Snippet:   listSnippet(snippet),
+	})
+}
+
+// BroadcastSnippetUpdated broadcasts an edited snippet to its viewers, and to the list
+// views if it is public
+func (h *Hub) BroadcastSnippetUpdated(snippet dto.SnippetResponse) {
+	h.BroadcastSnippetUpdate(snippet.ID, SnippetUpdateData{
+		SnippetID:  snippet.ID,
 		UpdateType: "content",
-		Title:      title,
-		Content:    content,
-		Language:   language,
+		Title:      &snippet.Title,
+		Content:    &snippet.Content,
+		Language:   &snippet.Language,
+		UpdatedBy:  snippet.LastEditor,
 	})
 
-	// Broadcast to list view subscribers
+	if !isListed(snippet) {
+		return
+	}
 	h.BroadcastListUpdate(ListUpdateData{
-		SnippetID: snippetID,
-		Title:     title,
-		Content:   content,
-		Language:  language,
+		Event:     ListEventUpdated,
+		SnippetID: snippet.ID,
+		Snippet:   listSnippet(snippet),
+		Title:     &snippet.Title,
+		Content:   &snippet.Content,
+		Language:  &snippet.Language,
+	})
+}
+
+// BroadcastSnippetVisibilityChanged tells the list views to add or remove a snippet
+// that became visible or hidden to them
+func (h *Hub) BroadcastSnippetVisibilityChanged(snippet dto.SnippetResponse) {
+	data := ListUpdateData{
+		Event:     ListEventVisibilityChanged,
+		SnippetID: snippet.ID,
+	}
+	// Hidden snippets are not sent, list subscribers only need to remove them
+	if isListed(snippet) {
+		data.Snippet = listSnippet(snippet)
+	}
+	h.broadcastListUpdate(data, &snippet.Author.ID, &snippet.Language)
+}
+
+// BroadcastSnippetDeleted removes a snippet from the list views and tells its viewers
+func (h *Hub) BroadcastSnippetDeleted(snippet dto.SnippetResponse) {
+	h.BroadcastSnippetUpdate(snippet.ID, SnippetUpdateData{
+		SnippetID:  snippet.ID,
+		UpdateType: "deleted",
 	})
+
+	// The snippet is not sent, list subscribers only need to remove it
+	h.broadcastListUpdate(ListUpdateData{
+		Event:     ListEventDeleted,
+		SnippetID: snippet.ID,
+	}, &snippet.Author.ID, &snippet.Language)
+}
+
+// isListed reports whether a snippet may be broadcast to all list subscribers.
+// Burn-after-read and password-protected snippets are only handed out by their link.
+func isListed(snippet dto.SnippetResponse) bool {
+	return (snippet.Visibility == "" || snippet.Visibility == string(domain.SnippetPublic)) &&
+		!snippet.BurnAfterRead && !snippet.PasswordProtected
 }
 
-// BroadcastSnippetStatsUpdate is a convenience method for stats-only updates
+// listSnippet strips the flags of the requesting user from a snippet broadcast to everyone
+func listSnippet(snippet dto.SnippetResponse) *dto.SnippetResponse {
+	snippet.IsLiked = false
+	snippet.IsSaved = false
+	return &snippet
+}
+
+// BroadcastSnippetStatsUpdate is a convenience method for stats-only updates.
+// Clients that fall behind only receive the latest stats.
 func (h *Hub) BroadcastSnippetStatsUpdate(snippetID string, viewCount, likeCount *int) {
-	h.BroadcastSnippetUpdate(snippetID, SnippetUpdateData{
+	h.broadcastSnippetUpdate(snippetID, SnippetUpdateData{
 		SnippetID:  snippetID,
 		UpdateType: "stats",
 		ViewCount:  viewCount,
 		LikeCount:  likeCount,
-	})
+	}, true)
+}
+
+// BroadcastFeedItem sends a new or updated snippet to the feed subscribers among the author's followers
+func (h *Hub) BroadcastFeedItem(followerIDs []string, data FeedItemData) {
+	if len(followerIDs) == 0 {
+		return
+	}
+
+	message := WebSocketMessage{
+		Type:      MessageTypeFeed,
+		Data:      data,
+		SnippetID: &data.SnippetID,
+		Timestamp: time.Now().Unix(),
+	}
+
+	h.broadcast <- BroadcastMessage{
+		Message: message,
+		Target: BroadcastTarget{
+			Type:    BroadcastTargetTypeFeed,
+			UserIDs: followerIDs,
+		},
+	}
+
+	h.logger.Debug("Broadcasting feed item",
+		zap.String("type", data.Type),
+		zap.String("snippet_id", data.SnippetID),
+		zap.Int("followers", len(followerIDs)))
+}
+
+// BroadcastNotification delivers a new notification to all of the recipient's devices
+func (h *Hub) BroadcastNotification(userID string, notification *domain.Notification, unreadCount int) {
+	message := WebSocketMessage{
+		Type: MessageTypeNotification,
+		Data: NotificationData{
+			ID:            notification.ID,
+			Type:          string(notification.Type),
+			ActorID:       notification.Actor.ID,
+			ActorUsername: notification.Actor.Username,
+			ActorAvatar:   notification.Actor.Avatar,
+			SnippetID:     notification.SnippetID,
+			SnippetTitle:  notification.SnippetTitle,
+			CreatedAt:     notification.CreatedAt.Unix(),
+			UnreadCount:   unreadCount,
+		},
+		UserID:    &userID,
+		SnippetID: notification.SnippetID,
+		Timestamp: time.Now().Unix(),
+	}
+
+	h.broadcast <- BroadcastMessage{
+		Message: message,
+		Target: BroadcastTarget{
+			Type:   BroadcastTargetTypeUser,
+			UserID: &userID,
+		},
+	}
+
+	h.logger.Debug("Broadcasting notification",
+		zap.String("type", string(notification.Type)),
+		zap.String("notification_id", notification.ID),
+		zap.String("user_id", userID))
 }
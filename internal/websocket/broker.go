@@ -0,0 +1,74 @@
+package ws
+
+import (
+	"errors"
+	"sync"
+)
+
+var _ Broker = (*MemoryBroker)(nil)
+
+// ErrBrokerClosed is returned when publishing to or subscribing on a closed broker
+var ErrBrokerClosed = errors.New("broker closed")
+
+// Broker fans broadcasts out between hubs running in different instances.
+// Every published message is delivered to every subscriber, including the
+// hub that published it; hubs drop their own messages by origin.
+type Broker interface {
+	// Publish sends an encoded broadcast to all subscribed hubs
+	Publish(data []byte) error
+	// Subscribe registers a handler for broadcasts published by any hub
+	Subscribe(handler func(data []byte)) error
+	// Close stops delivery and releases the connection to the backend
+	Close() error
+}
+
+// MemoryBroker connects hubs within a single process, for tests and single-instance setups
+type MemoryBroker struct {
+	handlers []func(data []byte)
+	closed   bool
+	mutex    sync.RWMutex
+}
+
+// NewMemoryBroker creates a new in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish delivers the message to all subscribers before returning
+func (b *MemoryBroker) Publish(data []byte) error {
+	b.mutex.RLock()
+	if b.closed {
+		b.mutex.RUnlock()
+		return ErrBrokerClosed
+	}
+	handlers := make([]func(data []byte), len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(data)
+	}
+	return nil
+}
+
+// Subscribe registers a handler for all published messages
+func (b *MemoryBroker) Subscribe(handler func(data []byte)) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return ErrBrokerClosed
+	}
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+// Close drops all subscribers
+func (b *MemoryBroker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.handlers = nil
+	return nil
+}
@@ -0,0 +1,188 @@
+package ws
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+// duplicatingBroker delivers every message twice, like a backend with at-least-once delivery
+type duplicatingBroker struct {
+	*MemoryBroker
+}
+
+func (b duplicatingBroker) Publish(data []byte) error {
+	if err := b.MemoryBroker.Publish(data); err != nil {
+		return err
+	}
+	return b.MemoryBroker.Publish(data)
+}
+
+// openListUpdates subscribes to list_updates on a hub's SSE endpoint and waits until the subscription is active
+func openListUpdates(t *testing.T, url string) func() sseEvent {
+	_, next := openSSE(t, url+"?subscribe=list_updates", "")
+	nextOfType(t, next, MessageTypeSuccess) // Connected
+	nextOfType(t, next, MessageTypeSuccess) // Subscribed
+	return next
+}
+
+// nextListUpdate returns the snippet ID of the next list update
+func nextListUpdate(t *testing.T, next func() sseEvent) string {
+	event := nextOfType(t, next, MessageTypeListUpdates)
+	require.NotNil(t, event.message.SnippetID)
+	return *event.message.SnippetID
+}
+
+// assertFanOut checks that broadcasts on either hub reach the clients of both, exactly once
+func assertFanOut(t *testing.T, hubA, hubB *Hub, nextA, nextB func() sseEvent) {
+	hubA.BroadcastListUpdate(ListUpdateData{SnippetID: "from-a"})
+	assert.Equal(t, "from-a", nextListUpdate(t, nextA))
+	assert.Equal(t, "from-a", nextListUpdate(t, nextB))
+
+	// A duplicate of the first broadcast would arrive before the second one
+	hubB.BroadcastListUpdate(ListUpdateData{SnippetID: "from-b"})
+	assert.Equal(t, "from-b", nextListUpdate(t, nextA))
+	assert.Equal(t, "from-b", nextListUpdate(t, nextB))
+}
+
+func TestHub_MemoryBroker(t *testing.T) {
+	broker := NewMemoryBroker()
+	hubA, serverA := setupSSETestWithBroker(t, broker)
+	hubB, serverB := setupSSETestWithBroker(t, broker)
+
+	nextA := openListUpdates(t, serverA.URL)
+	nextB := openListUpdates(t, serverB.URL)
+
+	assertFanOut(t, hubA, hubB, nextA, nextB)
+
+	t.Run("targets are kept across instances", func(t *testing.T) {
+		_, next := openSSE(t, serverB.URL+"?subscribe=snippet_updates&snippet_id=snippet-1", "")
+		nextOfType(t, next, MessageTypeSuccess)
+		nextOfType(t, next, MessageTypeSuccess)
+
+		hubA.BroadcastSnippetStatsUpdate("snippet-2", nil, nil)
+		hubA.BroadcastSnippetStatsUpdate("snippet-1", nil, nil)
+		event := nextOfType(t, next, MessageTypeSnippetUpdates)
+		assert.Equal(t, "snippet-1", *event.message.SnippetID)
+	})
+}
+
+func TestHub_BrokerDeduplication(t *testing.T) {
+	broker := duplicatingBroker{NewMemoryBroker()}
+	hubA, serverA := setupSSETestWithBroker(t, broker)
+	hubB, serverB := setupSSETestWithBroker(t, broker)
+
+	nextA := openListUpdates(t, serverA.URL)
+	nextB := openListUpdates(t, serverB.URL)
+
+	assertFanOut(t, hubA, hubB, nextA, nextB)
+}
+
+func TestHub_NATSBroker(t *testing.T) {
+	natsServer := runNATSServer(t, &natsserver.Options{})
+
+	brokerA, err := NewNATSBroker(natsURL(natsServer), "codeshare.test")
+	require.NoError(t, err)
+	brokerB, err := NewNATSBroker(natsURL(natsServer), "codeshare.test")
+	require.NoError(t, err)
+
+	hubA, serverA := setupSSETestWithBroker(t, brokerA)
+	hubB, serverB := setupSSETestWithBroker(t, brokerB)
+
+	require.Eventually(t, func() bool { return natsServer.GlobalAccount().Interest("codeshare.test") == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	nextA := openListUpdates(t, serverA.URL)
+	nextB := openListUpdates(t, serverB.URL)
+
+	assertFanOut(t, hubA, hubB, nextA, nextB)
+
+	t.Run("resubscribes after reconnect", func(t *testing.T) {
+		port := natsServer.Addr().(*net.TCPAddr).Port
+		natsServer.Shutdown()
+		restarted := runNATSServer(t, &natsserver.Options{Port: port})
+		require.Eventually(t, func() bool { return restarted.GlobalAccount().Interest("codeshare.test") == 2 }, 10*time.Second, 10*time.Millisecond)
+
+		assertFanOut(t, hubA, hubB, nextA, nextB)
+	})
+}
+
+func TestNewNATSBroker_TLSRequired(t *testing.T) {
+	natsServer := runNATSServer(t, &natsserver.Options{TLSConfig: selfSignedTLSConfig(t), TLSTimeout: 2})
+
+	// The server asks for TLS and the certificate is not trusted
+	start := time.Now()
+	_, err := NewNATSBroker(natsURL(natsServer), "codeshare.test")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), natsDialTimeout)
+}
+
+func TestNewNATSBroker_InvalidConfig(t *testing.T) {
+	for _, tc := range []struct{ url, subject string }{
+		{"http://localhost:4222", "codeshare"},
+		{"nats://", "codeshare"},
+		{"nats://localhost:4222", ""},
+		{"nats://localhost:4222", "code share"},
+		{"nats://localhost:4222", "codeshare.*"},
+	} {
+		_, err := NewNATSBroker(tc.url, tc.subject)
+		assert.Error(t, err, tc.url+" "+tc.subject)
+	}
+}
+
+// runNATSServer starts an embedded NATS server on a free port, or on the port of opts
+func runNATSServer(t *testing.T, opts *natsserver.Options) *natsserver.Server {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	opts.Host = "127.0.0.1"
+	if opts.Port == 0 {
+		opts.Port = -1
+	}
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	server, err := natsserver.NewServer(opts)
+	require.NoError(t, err)
+	go server.Start()
+	require.True(t, server.ReadyForConnections(5*time.Second), "NATS server not ready")
+	t.Cleanup(server.Shutdown)
+	return server
+}
+
+// natsURL returns the client URL of an embedded NATS server
+func natsURL(server *natsserver.Server) string {
+	return "nats://" + server.Addr().String()
+}
+
+// selfSignedTLSConfig returns a server TLS config with a certificate no client trusts
+func selfSignedTLSConfig(t *testing.T) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
@@ -2,25 +2,51 @@ package ws
 
 import (
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
 	"mitsimi.dev/codeShare/internal/logger"
 )
 
-// Client represents a WebSocket client
+// maxMessageSize is the largest message accepted from a client, enough for pasted text in edit operations
+const maxMessageSize = 64 * 1024
+
+// topicKey names the topic of a subscription, e.g. "list_updates" or "snippet_updates:<id>".
+// Each topic has its own sequence numbers.
+func topicKey(subType SubscriptionType, id string) string {
+	if id == "" {
+		return string(subType)
+	}
+	return string(subType) + ":" + id
+}
+
+// Client represents a WebSocket or Server-Sent Events client
 type Client struct {
+	id     string // Connection ID, tells apart the tabs of a user in edit sessions
 	hub    *Hub
-	conn   *websocket.Conn
-	send   chan []byte
+	conn   *websocket.Conn // nil for Server-Sent Events clients
+	queue  *sendQueue
 	userID string
 
-	// Subscriptions
+	// Credential the connection was opened with, checked again while it is open
+	credential api.Credential
+
+	// Profile shown to other viewers of a snippet, empty for anonymous clients
+	username string
+	avatar   *string
+
+	// Subscriptions, only accessed by the hub's Run goroutine
 	userActionsSubscribed    bool
 	snippetUpdatesSubscribed map[string]bool // snippetID -> subscribed
 	listUpdatesSubscribed    bool            // Global list updates subscription
+	listFilters              map[string]bool // List filter -> subscribed, e.g. "author:<id>"
+	feedSubscribed           bool            // Items of followed authors
+	editing                  map[string]bool // snippetID -> joined edit session, guarded by mutex
 
 	mutex  sync.RWMutex
 	logger *zap.Logger
@@ -29,19 +55,24 @@ type Client struct {
 // NewClient creates a new client
 func NewClient(hub *Hub, conn *websocket.Conn, userID string) *Client {
 	return &Client{
+		id:                       uuid.New().String(),
 		hub:                      hub,
 		conn:                     conn,
-		send:                     make(chan []byte, 256),
+		queue:                    newSendQueue(sendQueueSize),
 		userID:                   userID,
 		userActionsSubscribed:    false,
 		snippetUpdatesSubscribed: make(map[string]bool),
 		listUpdatesSubscribed:    false,
+		listFilters:              make(map[string]bool),
+		feedSubscribed:           false,
+		editing:                  make(map[string]bool),
 		mutex:                    sync.RWMutex{},
 		logger:                   logger.With(zap.String("websocket", "client"), zap.String("user_id", userID)),
 	}
 }
 
-// SendMessage sends a message to the client
+// SendMessage queues a message for the client without waiting. Unlike broadcasts,
+// these messages are never dropped, a client that cannot take them is disconnected.
 func (c *Client) SendMessage(message WebSocketMessage) {
 	messageBytes, err := json.Marshal(message)
 	if err != nil {
@@ -49,159 +80,130 @@ func (c *Client) SendMessage(message WebSocketMessage) {
 		return
 	}
 
-	select {
-	case c.send <- messageBytes:
-	default:
-		c.hub.unregister <- c
+	if !c.queue.push(outboundMessage{topic: message.Topic, seq: message.Seq, data: messageBytes}) {
+		c.logger.Debug("Client queue closed, message not sent", zap.String("type", string(message.Type)))
 	}
 }
 
-// HandleSubscription handles subscription requests
-func (c *Client) HandleSubscription(subReq SubscriptionRequest) {
+// disconnect closes the connection with a close code telling the client why.
+// Server-Sent Events have no close codes, their stream ends after an error event.
+func (c *Client) disconnect(code int, reason string) {
+	c.queue.close(code, reason)
+}
+
+// sendError queues an error message
+func (c *Client) sendError(text string, snippetID *string) {
+	c.SendMessage(WebSocketMessage{
+		Type:      MessageTypeError,
+		Data:      text,
+		SnippetID: snippetID,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// setEditing records whether the client is an editor of a snippet's edit session
+func (c *Client) setEditing(snippetID string, editing bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	switch subReq.Type {
-	case SubTypeUserActions:
-		if c.userID == "anonymous" {
-			c.SendMessage(WebSocketMessage{
-				Type:      MessageTypeError,
-				Data:      "Anonymous users cannot subscribe to user_actions",
-				Timestamp: time.Now().Unix(),
-			})
-			return
-		}
+	if editing {
+		c.editing[snippetID] = true
+	} else {
+		delete(c.editing, snippetID)
+	}
+}
 
-		if !c.userActionsSubscribed {
-			c.userActionsSubscribed = true
-			c.hub.mutex.Lock()
-			c.hub.userClients[c.userID] = append(c.hub.userClients[c.userID], c)
-			c.hub.mutex.Unlock()
+// editingSnippets returns the snippets whose edit sessions the client joined
+func (c *Client) editingSnippets() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
-			c.SendMessage(WebSocketMessage{
-				Type:      MessageTypeSuccess,
-				Data:      "Subscribed to user_actions",
-				Timestamp: time.Now().Unix(),
-			})
-			c.logger.Info("User subscribed to user_actions")
-		}
+	snippetIDs := make([]string, 0, len(c.editing))
+	for snippetID := range c.editing {
+		snippetIDs = append(snippetIDs, snippetID)
+	}
+	return snippetIDs
+}
 
+// topic returns the topic a subscription of this client receives
+func (c *Client) topic(subReq SubscriptionRequest) string {
+	switch subReq.Type {
+	case SubTypeUserActions, SubTypeFeed:
+		return topicKey(subReq.Type, c.userID)
 	case SubTypeSnippetUpdates:
 		if subReq.SnippetID != nil {
-			snippetID := *subReq.SnippetID
-			if !c.snippetUpdatesSubscribed[snippetID] {
-				c.snippetUpdatesSubscribed[snippetID] = true
-				c.hub.mutex.Lock()
-				c.hub.snippetUpdateClients[snippetID] = append(
-					c.hub.snippetUpdateClients[snippetID], c)
-				c.hub.mutex.Unlock()
-
-				c.SendMessage(WebSocketMessage{
-					Type:      MessageTypeSuccess,
-					Data:      "Subscribed to snippet_updates for " + snippetID,
-					SnippetID: &snippetID,
-					Timestamp: time.Now().Unix(),
-				})
-				c.logger.Info("User subscribed to snippet_updates", zap.String("snippet_id", snippetID))
-			}
+			return topicKey(subReq.Type, *subReq.SnippetID)
 		}
-
 	case SubTypeListUpdates:
-		if !c.listUpdatesSubscribed {
-			c.listUpdatesSubscribed = true
-			c.hub.mutex.Lock()
-			c.hub.listUpdateClients = append(c.hub.listUpdateClients, c)
-			c.hub.mutex.Unlock()
+		return topicKey(subReq.Type, subReq.listFilter())
+	}
+	return topicKey(subReq.Type, "")
+}
 
-			c.SendMessage(WebSocketMessage{
-				Type:      MessageTypeSuccess,
-				Data:      "Subscribed to list_updates",
-				Timestamp: time.Now().Unix(),
-			})
-			c.logger.Info("User subscribed to list_updates")
-		}
+// listFilter returns the filter of a list_updates subscription, empty for all list updates
+func (r SubscriptionRequest) listFilter() string {
+	switch {
+	case r.AuthorID != nil:
+		return authorFilter(*r.AuthorID)
+	case r.Language != nil:
+		return languageFilter(*r.Language)
 	}
+	return ""
 }
 
-// HandleUnsubscription handles unsubscription requests
-func (c *Client) HandleUnsubscription(subReq SubscriptionRequest) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+func authorFilter(authorID string) string {
+	return "author:" + authorID
+}
 
-	switch subReq.Type {
-	case SubTypeUserActions:
-		if c.userActionsSubscribed {
-			c.userActionsSubscribed = false
-			c.hub.removeFromUserClients(c)
+func languageFilter(language string) string {
+	return "language:" + strings.ToLower(language)
+}
 
-			c.SendMessage(WebSocketMessage{
-				Type:      MessageTypeSuccess,
-				Data:      "Unsubscribed from user_actions",
-				Timestamp: time.Now().Unix(),
-			})
-			c.logger.Info("User unsubscribed from user_actions")
+// subscriptionCount returns the number of subscriptions of the client
+func (c *Client) subscriptionCount() int {
+	count := len(c.snippetUpdatesSubscribed) + len(c.listFilters)
+	for _, subscribed := range []bool{c.userActionsSubscribed, c.listUpdatesSubscribed, c.feedSubscribed} {
+		if subscribed {
+			count++
 		}
+	}
+	return count
+}
 
+// isSubscribed reports whether the subscription is active. Subscriptions are only
+// accessed by the hub's Run goroutine.
+func (c *Client) isSubscribed(subReq SubscriptionRequest) bool {
+	switch subReq.Type {
+	case SubTypeUserActions:
+		return c.userActionsSubscribed
 	case SubTypeSnippetUpdates:
-		if subReq.SnippetID != nil {
-			snippetID := *subReq.SnippetID
-			if c.snippetUpdatesSubscribed[snippetID] {
-				delete(c.snippetUpdatesSubscribed, snippetID)
-				// Remove from hub's snippet update clients
-				c.hub.mutex.Lock()
-				if clients, exists := c.hub.snippetUpdateClients[snippetID]; exists {
-					for i, client := range clients {
-						if client == c {
-							c.hub.snippetUpdateClients[snippetID] = append(
-								clients[:i], clients[i+1:]...)
-							if len(c.hub.snippetUpdateClients[snippetID]) == 0 {
-								delete(c.hub.snippetUpdateClients, snippetID)
-							}
-							break
-						}
-					}
-				}
-				c.hub.mutex.Unlock()
-
-				c.SendMessage(WebSocketMessage{
-					Type:      MessageTypeSuccess,
-					Data:      "Unsubscribed from snippet_updates for " + snippetID,
-					SnippetID: &snippetID,
-					Timestamp: time.Now().Unix(),
-				})
-				c.logger.Info("User unsubscribed from snippet_updates", zap.String("snippet_id", snippetID))
-			}
-		}
-
+		return subReq.SnippetID != nil && c.snippetUpdatesSubscribed[*subReq.SnippetID]
 	case SubTypeListUpdates:
-		if c.listUpdatesSubscribed {
-			c.listUpdatesSubscribed = false
-			c.hub.removeFromListClients(c)
-
-			c.SendMessage(WebSocketMessage{
-				Type:      MessageTypeSuccess,
-				Data:      "Unsubscribed from list_updates",
-				Timestamp: time.Now().Unix(),
-			})
-			c.logger.Info("User unsubscribed from list_updates")
+		if filter := subReq.listFilter(); filter != "" {
+			return c.listFilters[filter]
 		}
+		return c.listUpdatesSubscribed
+	case SubTypeFeed:
+		return c.feedSubscribed
 	}
+	return false
 }
 
 // ReadPump handles reading messages from the WebSocket connection
 func (c *Client) ReadPump() {
+	// Unregistering closes the send queue, the write pump then closes the connection
 	defer func() {
 		c.hub.unregister <- c
-		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(512)
+	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 		return nil
 	})
 
+	limiter := newMessageLimiter(messageRate, messageBurst)
 	for {
 		_, messageBytes, err := c.conn.ReadMessage()
 		if err != nil {
@@ -211,6 +213,13 @@ func (c *Client) ReadPump() {
 			break
 		}
 
+		// Dropping messages would desync edit sessions, so flooding clients are disconnected
+		if !limiter.allow(time.Now()) {
+			c.logger.Warn("Client exceeded the message rate limit")
+			c.disconnect(websocket.ClosePolicyViolation, "Message rate limit exceeded")
+			break
+		}
+
 		var message WebSocketMessage
 		if err := json.Unmarshal(messageBytes, &message); err != nil {
 			c.SendMessage(WebSocketMessage{
@@ -231,8 +240,58 @@ func (c *Client) ReadPump() {
 				if snippetID, exists := subData["snippet_id"].(string); exists {
 					subReq.SnippetID = &snippetID
 				}
-				c.HandleSubscription(subReq)
+				if authorID, exists := subData["author_id"].(string); exists {
+					subReq.AuthorID = &authorID
+				}
+				if language, exists := subData["language"].(string); exists {
+					subReq.Language = &language
+				}
+				if subscriptions := c.hub.authorizeSubscriptions(c, []SubscriptionRequest{subReq}); len(subscriptions) > 0 {
+					c.hub.resume <- resumeRequest{client: c, subscriptions: subscriptions}
+				}
+			}
+
+		case MessageTypeResume:
+			var resume ResumeData
+			if err := decodeMessageData(message.Data, &resume); err != nil || len(resume.Subscriptions) == 0 {
+				c.SendMessage(WebSocketMessage{
+					Type:      MessageTypeError,
+					Data:      "Invalid resume request",
+					Timestamp: time.Now().Unix(),
+				})
+				continue
+			}
+			if subscriptions := c.hub.authorizeSubscriptions(c, resume.Subscriptions); len(subscriptions) > 0 {
+				c.hub.resume <- resumeRequest{client: c, subscriptions: subscriptions}
+			}
+
+		case MessageTypeEditJoin, MessageTypeEditLeave:
+			var request EditSessionRequest
+			if err := decodeMessageData(message.Data, &request); err != nil || request.SnippetID == "" {
+				c.sendError("Invalid edit session request", nil)
+				continue
+			}
+			if message.Type == MessageTypeEditJoin {
+				c.hub.joinEditSession(c, request.SnippetID)
+			} else {
+				c.hub.leaveEditSession(c, request.SnippetID)
+			}
+
+		case MessageTypeEditOperation:
+			var operation EditOperationData
+			if err := decodeMessageData(message.Data, &operation); err != nil {
+				c.sendError("Invalid edit operation", nil)
+				continue
 			}
+			c.hub.applyEditOperation(c, operation)
+
+		case MessageTypeEditCursor:
+			var cursor EditCursorData
+			if err := decodeMessageData(message.Data, &cursor); err != nil {
+				c.sendError("Invalid edit cursor", nil)
+				continue
+			}
+			c.hub.updateEditCursor(c, cursor)
 
 		case MessageTypeUnsubscribe:
 			if subData, ok := message.Data.(map[string]any); ok {
@@ -243,13 +302,29 @@ func (c *Client) ReadPump() {
 				if snippetID, exists := subData["snippet_id"].(string); exists {
 					subReq.SnippetID = &snippetID
 				}
-				c.HandleUnsubscription(subReq)
+				if authorID, exists := subData["author_id"].(string); exists {
+					subReq.AuthorID = &authorID
+				}
+				if language, exists := subData["language"].(string); exists {
+					subReq.Language = &language
+				}
+				c.hub.unsubscribe <- unsubscribeRequest{client: c, subscription: subReq}
 			}
 		}
 	}
 }
 
-// WritePump handles writing messages to the WebSocket connection
+// decodeMessageData converts the generically decoded data of a client message into a typed struct
+func decodeMessageData(data any, target any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, target)
+}
+
+// WritePump handles writing messages to the WebSocket connection. It owns the
+// connection's writes and closes it once the send queue is closed.
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer func() {
@@ -259,18 +334,23 @@ func (c *Client) WritePump() {
 
 	for {
 		select {
-		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+		case <-c.queue.ready:
+			if closed, code, reason := c.queue.closeStatus(); closed {
+				c.conn.WriteControl(websocket.CloseMessage, closeMessage(code, reason), time.Now().Add(10*time.Second))
 				return
 			}
 
-			c.logger.Debug("Sending message", zap.String("message", string(message)))
-			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
-				return
+			for {
+				message, ok := c.queue.pop()
+				if !ok {
+					break
+				}
+				c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+				if err := c.conn.WriteMessage(websocket.TextMessage, message.data); err != nil {
+					return
+				}
+				c.logger.Debug("Message sent", zap.String("message", string(message.data)))
 			}
-			c.logger.Debug("Message sent", zap.String("message", string(message)))
 
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
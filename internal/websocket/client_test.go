@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dialWebSocket(t *testing.T, hub *Hub) *websocket.Conn {
+	server := httptest.NewServer(HandleWebSocket(hub, NewGuard(hub, GuardConfig{})))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, conn).Type) // Connected
+	return conn
+}
+
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) WebSocketMessage {
+	var message WebSocketMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestClient_Resume(t *testing.T) {
+	hub, _ := setupSSETest(t)
+
+	// Only topics with subscribers are buffered
+	live := dialWebSocket(t, hub)
+	require.NoError(t, live.WriteJSON(WebSocketMessage{
+		Type: MessageTypeSubscribe,
+		Data: SubscriptionRequest{Type: SubTypeListUpdates},
+	}))
+	assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, live).Type)
+
+	for _, snippetID := range []string{"snippet-1", "snippet-2", "snippet-3"} {
+		hub.BroadcastListUpdate(ListUpdateData{SnippetID: snippetID})
+	}
+
+	t.Run("subscribe reports the current position", func(t *testing.T) {
+		conn := dialWebSocket(t, hub)
+		require.NoError(t, conn.WriteJSON(WebSocketMessage{
+			Type: MessageTypeSubscribe,
+			Data: SubscriptionRequest{Type: SubTypeListUpdates},
+		}))
+
+		message := readWebSocketMessage(t, conn)
+		assert.Equal(t, MessageTypeSuccess, message.Type)
+		assert.Equal(t, "list_updates", message.Topic)
+		assert.Equal(t, uint64(3), message.Seq)
+		assert.Equal(t, hub.instanceID, message.Epoch)
+	})
+
+	t.Run("replays and resyncs per subscription", func(t *testing.T) {
+		listSeq, snippetSeq := uint64(1), uint64(5)
+		snippetID := "snippet-1"
+
+		conn := dialWebSocket(t, hub)
+		require.NoError(t, conn.WriteJSON(WebSocketMessage{
+			Type: MessageTypeResume,
+			Data: ResumeData{Subscriptions: []SubscriptionRequest{
+				{Type: SubTypeListUpdates, LastSeq: &listSeq, Epoch: hub.instanceID},
+				{Type: SubTypeSnippetUpdates, SnippetID: &snippetID, LastSeq: &snippetSeq, Epoch: hub.instanceID},
+			}},
+		}))
+
+		assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, conn).Type)
+		for _, seq := range []uint64{2, 3} {
+			message := readWebSocketMessage(t, conn)
+			assert.Equal(t, MessageTypeListUpdates, message.Type)
+			assert.Equal(t, seq, message.Seq)
+		}
+
+		assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, conn).Type)
+		message := readWebSocketMessage(t, conn)
+		assert.Equal(t, MessageTypeResync, message.Type)
+		assert.Equal(t, "snippet_updates:snippet-1", message.Topic)
+		assert.Equal(t, uint64(0), message.Seq)
+	})
+
+	t.Run("resyncs positions of another epoch", func(t *testing.T) {
+		lastSeq := uint64(1)
+
+		conn := dialWebSocket(t, hub)
+		require.NoError(t, conn.WriteJSON(WebSocketMessage{
+			Type: MessageTypeResume,
+			Data: ResumeData{Subscriptions: []SubscriptionRequest{
+				{Type: SubTypeListUpdates, LastSeq: &lastSeq, Epoch: "other-epoch"},
+			}},
+		}))
+
+		assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, conn).Type)
+		message := readWebSocketMessage(t, conn)
+		assert.Equal(t, MessageTypeResync, message.Type)
+		assert.Equal(t, uint64(3), message.Seq)
+		assert.Equal(t, hub.instanceID, message.Epoch)
+	})
+
+	t.Run("invalid resume request", func(t *testing.T) {
+		conn := dialWebSocket(t, hub)
+		require.NoError(t, conn.WriteJSON(WebSocketMessage{Type: MessageTypeResume, Data: "list_updates"}))
+
+		assert.Equal(t, MessageTypeError, readWebSocketMessage(t, conn).Type)
+	})
+}
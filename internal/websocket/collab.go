@@ -0,0 +1,488 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/domain"
+)
+
+const (
+	// editSaveInterval is how often changed documents are written back to the snippet
+	editSaveInterval = 5 * time.Second
+	// editSaveTimeout bounds loading and saving a document
+	editSaveTimeout = 10 * time.Second
+	// editHistorySize is the number of operations kept to transform late operations and cursors
+	editHistorySize = 200
+	// editMaxContentLength is the largest document, in code points, an editing session accepts
+	editMaxContentLength = 1 << 20
+	// editMaxSaveAttempts is the number of failed saves after which a session without
+	// editors is closed and its unsaved changes are dropped
+	editMaxSaveAttempts = 3
+)
+
+// EditStore loads and persists the documents of collaborative editing sessions
+type EditStore interface {
+	// Load returns the content of a snippet, or an error if the user may not edit it
+	Load(ctx context.Context, snippetID, userID string) (string, error)
+	// Save writes the content of a snippet on behalf of the editor who changed it last
+	// and returns the saved snippet, or an error if that editor may no longer edit it
+	Save(ctx context.Context, snippetID, content, editorID string) (*domain.Snippet, error)
+}
+
+// editSession is the authoritative document of a snippet being edited. Operations
+// are transformed against the history, applied and relayed while holding the mutex,
+// so every editor sees them in the same order. Sessions are not shared through the
+// broker, all editors of a snippet have to be connected to the same instance.
+type editSession struct {
+	snippetID     string
+	content       []rune
+	revision      uint64
+	history       []TextOperation // Operations that created the last len(history) revisions
+	savedRevision uint64
+	lastEditorID  string // User who applied the latest operation
+	saveFailures  int    // Failed saves since the last successful one
+	editors       map[*Client]*editorCursor
+	mutex         sync.Mutex
+}
+
+// editorCursor is the last reported selection of an editor, in the current revision
+type editorCursor struct {
+	anchor int
+	head   int
+	set    bool
+}
+
+// joinEditSession authorizes the client, opens the snippet's session if needed and
+// sends the current document
+func (h *Hub) joinEditSession(client *Client, snippetID string) {
+	if h.editStore == nil {
+		client.sendError("Collaborative editing is not available", &snippetID)
+		return
+	}
+	if client.userID == "anonymous" {
+		client.sendError("Anonymous users cannot edit snippets", &snippetID)
+		return
+	}
+
+	// Authorize every join, the session may have been opened by another editor
+	ctx, cancel := context.WithTimeout(context.Background(), editSaveTimeout)
+	defer cancel()
+	content, err := h.editStore.Load(ctx, snippetID, client.userID)
+	if err != nil {
+		client.logger.Warn("Edit session join rejected", zap.String("snippet_id", snippetID), zap.Error(err))
+		client.sendError("Snippet cannot be edited", &snippetID)
+		return
+	}
+
+	h.editMutex.Lock()
+	defer h.editMutex.Unlock()
+
+	session, exists := h.editSessions[snippetID]
+	if !exists {
+		session = &editSession{
+			snippetID: snippetID,
+			content:   []rune(content),
+			editors:   make(map[*Client]*editorCursor),
+		}
+		h.editSessions[snippetID] = session
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if _, joined := session.editors[client]; joined {
+		session.sendState(client)
+		return
+	}
+	session.editors[client] = &editorCursor{}
+	client.setEditing(snippetID, true)
+
+	session.sendState(client)
+	session.relay(client, MessageTypeEditJoin, EditorChangeData{SnippetID: snippetID, Editor: session.editorData(client)})
+
+	client.logger.Info("Joined edit session", zap.String("snippet_id", snippetID), zap.Int("editors", len(session.editors)))
+}
+
+// leaveEditSession removes the client from a session, closing and saving the session
+// when the last editor leaves
+func (h *Hub) leaveEditSession(client *Client, snippetID string) {
+	h.editMutex.Lock()
+	defer h.editMutex.Unlock()
+
+	session, exists := h.editSessions[snippetID]
+	if !exists {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if _, joined := session.editors[client]; !joined {
+		return
+	}
+	delete(session.editors, client)
+	client.setEditing(snippetID, false)
+	session.relay(client, MessageTypeEditLeave, EditorChangeData{SnippetID: snippetID, Editor: session.editorData(client)})
+
+	if len(session.editors) > 0 {
+		return
+	}
+	if session.revision == session.savedRevision {
+		delete(h.editSessions, snippetID)
+		return
+	}
+
+	// The session stays open until its document is saved, so editors joining in the
+	// meantime continue from it instead of loading the outdated snippet
+	go func() {
+		h.saveEditSession(context.Background(), session)
+		h.closeIdleEditSession(session)
+	}()
+}
+
+// closeIdleEditSession removes a session without editors once its document is saved.
+// Sessions whose save failed stay open and are saved again by the persist loop, until
+// the save has failed too often.
+func (h *Hub) closeIdleEditSession(session *editSession) {
+	h.editMutex.Lock()
+	defer h.editMutex.Unlock()
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if h.editSessions[session.snippetID] != session || len(session.editors) > 0 {
+		return
+	}
+	if session.revision != session.savedRevision && session.saveFailures < editMaxSaveAttempts {
+		return
+	}
+	delete(h.editSessions, session.snippetID)
+
+	if session.revision != session.savedRevision {
+		h.logger.Error("Dropped unsaved edit session",
+			zap.String("snippet_id", session.snippetID),
+			zap.Uint64("revision", session.revision),
+			zap.Int("attempts", session.saveFailures))
+	}
+}
+
+// applyEditOperation transforms an operation against the ones the client had not seen,
+// applies it and relays it to the other editors
+func (h *Hub) applyEditOperation(client *Client, data EditOperationData) {
+	session := h.lockEditSession(client, data.SnippetID)
+	if session == nil {
+		return
+	}
+	defer session.mutex.Unlock()
+
+	if err := data.Operation.Validate(); err != nil {
+		client.sendError("Invalid edit operation", &data.SnippetID)
+		return
+	}
+
+	// Operations based on revisions no longer in the history cannot be transformed,
+	// the client has to start over from the current document
+	operation, ok := session.transformToCurrent(data.Revision, data.Operation)
+	if !ok {
+		session.sendState(client)
+		return
+	}
+
+	content, err := operation.Apply(session.content)
+	if err != nil {
+		client.sendError("Edit operation does not match the document", &data.SnippetID)
+		session.sendState(client)
+		return
+	}
+	if len(content) > editMaxContentLength {
+		client.sendError("Snippet content is too long", &data.SnippetID)
+		session.sendState(client)
+		return
+	}
+
+	session.content = content
+	session.revision++
+	session.lastEditorID = client.userID
+	session.history = append(session.history, operation)
+	if len(session.history) > editHistorySize {
+		session.history = session.history[len(session.history)-editHistorySize:]
+	}
+	for _, cursor := range session.editors {
+		if cursor.set {
+			cursor.anchor = operation.TransformIndex(cursor.anchor)
+			cursor.head = operation.TransformIndex(cursor.head)
+		}
+	}
+
+	client.SendMessage(WebSocketMessage{
+		Type:      MessageTypeEditAck,
+		Data:      EditAckData{SnippetID: data.SnippetID, Revision: session.revision},
+		SnippetID: &data.SnippetID,
+		Timestamp: time.Now().Unix(),
+	})
+	session.relay(client, MessageTypeEditOperation, EditOperationData{
+		SnippetID:    data.SnippetID,
+		Revision:     session.revision,
+		Operation:    operation,
+		ConnectionID: client.id,
+		UserID:       client.userID,
+	})
+}
+
+// updateEditCursor stores an editor's selection and shares it with the other editors
+func (h *Hub) updateEditCursor(client *Client, data EditCursorData) {
+	session := h.lockEditSession(client, data.SnippetID)
+	if session == nil {
+		return
+	}
+	defer session.mutex.Unlock()
+
+	// Selections from revisions no longer in the history are outdated, the next one follows
+	if data.Revision > session.revision || session.revision-data.Revision > uint64(len(session.history)) {
+		return
+	}
+	anchor, head := data.Anchor, data.Head
+	for _, operation := range session.history[uint64(len(session.history))-(session.revision-data.Revision):] {
+		anchor = operation.TransformIndex(anchor)
+		head = operation.TransformIndex(head)
+	}
+
+	cursor := session.editors[client]
+	cursor.anchor = max(0, min(anchor, len(session.content)))
+	cursor.head = max(0, min(head, len(session.content)))
+	cursor.set = true
+
+	editor := session.editorData(client)
+	session.relay(client, MessageTypeEditCursor, EditCursorData{
+		SnippetID:    data.SnippetID,
+		Revision:     session.revision,
+		Anchor:       cursor.anchor,
+		Head:         cursor.head,
+		ConnectionID: editor.ConnectionID,
+		UserID:       editor.UserID,
+		Username:     editor.Username,
+	})
+}
+
+// editSessionEditors returns the editors of a snippet's session
+func (h *Hub) editSessionEditors(snippetID string) []*Client {
+	h.editMutex.Lock()
+	session := h.editSessions[snippetID]
+	h.editMutex.Unlock()
+	if session == nil {
+		return nil
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	editors := make([]*Client, 0, len(session.editors))
+	for client := range session.editors {
+		editors = append(editors, client)
+	}
+	return editors
+}
+
+// lockEditSession returns the locked session of a snippet if the client is one of its editors
+func (h *Hub) lockEditSession(client *Client, snippetID string) *editSession {
+	h.editMutex.Lock()
+	session := h.editSessions[snippetID]
+	h.editMutex.Unlock()
+
+	if session != nil {
+		session.mutex.Lock()
+		if _, joined := session.editors[client]; joined {
+			return session
+		}
+		session.mutex.Unlock()
+	}
+
+	client.sendError("Join the edit session first", &snippetID)
+	return nil
+}
+
+// ReplaceEditContent resets the session of a snippet after its content was changed
+// outside of the session, e.g. by a regular update. Editors receive the new document.
+func (h *Hub) ReplaceEditContent(snippetID, content string) {
+	h.editMutex.Lock()
+	session := h.editSessions[snippetID]
+	h.editMutex.Unlock()
+	if session == nil {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	session.content = []rune(content)
+	session.revision++
+	session.savedRevision = session.revision
+	session.history = nil // Pending operations no longer apply to the document
+	for _, cursor := range session.editors {
+		cursor.anchor = min(cursor.anchor, len(session.content))
+		cursor.head = min(cursor.head, len(session.content))
+	}
+	for client := range session.editors {
+		session.sendState(client)
+	}
+}
+
+// CloseEditSession ends the session of a deleted snippet without saving it
+func (h *Hub) CloseEditSession(snippetID string) {
+	h.editMutex.Lock()
+	session := h.editSessions[snippetID]
+	delete(h.editSessions, snippetID)
+	h.editMutex.Unlock()
+	if session == nil {
+		return
+	}
+
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	for client := range session.editors {
+		client.setEditing(snippetID, false)
+		client.sendError("The snippet was deleted", &snippetID)
+	}
+	session.editors = make(map[*Client]*editorCursor)
+	session.savedRevision = session.revision
+}
+
+// editPersistLoop periodically saves changed documents until the hub is closed
+func (h *Hub) editPersistLoop() {
+	ticker := time.NewTicker(editSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+			h.saveEditSessions(context.Background())
+		}
+	}
+}
+
+// saveEditSessions saves the documents of all sessions with unsaved changes
+func (h *Hub) saveEditSessions(ctx context.Context) {
+	h.editMutex.Lock()
+	sessions := make([]*editSession, 0, len(h.editSessions))
+	for _, session := range h.editSessions {
+		sessions = append(sessions, session)
+	}
+	h.editMutex.Unlock()
+
+	for _, session := range sessions {
+		h.saveEditSession(ctx, session)
+		h.closeIdleEditSession(session)
+	}
+}
+
+// saveEditSession writes the current document back to the snippet and shares the
+// new content with snippet and list subscribers
+func (h *Hub) saveEditSession(ctx context.Context, session *editSession) {
+	session.mutex.Lock()
+	if session.revision == session.savedRevision {
+		session.mutex.Unlock()
+		return
+	}
+	content := string(session.content)
+	revision := session.revision
+	editorID := session.lastEditorID
+	session.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, editSaveTimeout)
+	defer cancel()
+	snippet, err := h.editStore.Save(ctx, session.snippetID, content, editorID)
+	if err != nil {
+		session.mutex.Lock()
+		session.saveFailures++
+		session.mutex.Unlock()
+
+		h.logger.Error("Failed to save edit session",
+			zap.String("snippet_id", session.snippetID),
+			zap.Uint64("revision", revision),
+			zap.Error(err))
+		return
+	}
+
+	session.mutex.Lock()
+	session.savedRevision = max(session.savedRevision, revision)
+	session.saveFailures = 0
+	session.mutex.Unlock()
+
+	h.BroadcastSnippetUpdated(dto.ToSnippetResponse(snippet))
+	h.logger.Debug("Saved edit session", zap.String("snippet_id", session.snippetID), zap.Uint64("revision", revision))
+}
+
+// transformToCurrent transforms an operation based on an older revision over the
+// operations applied since. Must be called with the mutex held.
+func (s *editSession) transformToCurrent(revision uint64, operation TextOperation) (TextOperation, bool) {
+	if revision > s.revision || s.revision-revision > uint64(len(s.history)) {
+		return nil, false
+	}
+
+	var err error
+	for _, concurrent := range s.history[uint64(len(s.history))-(s.revision-revision):] {
+		operation, _, err = TransformOperations(operation, concurrent)
+		if err != nil {
+			return nil, false
+		}
+	}
+	return operation, true
+}
+
+// sendState sends the current document and editors. Must be called with the mutex held.
+func (s *editSession) sendState(client *Client) {
+	editors := make([]EditorData, 0, len(s.editors))
+	for editor := range s.editors {
+		editors = append(editors, s.editorData(editor))
+	}
+
+	client.SendMessage(WebSocketMessage{
+		Type: MessageTypeEditState,
+		Data: EditStateData{
+			SnippetID: s.snippetID,
+			Revision:  s.revision,
+			Content:   string(s.content),
+			Editors:   editors,
+		},
+		SnippetID: &s.snippetID,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// relay sends a message to every editor except the sender. Must be called with the mutex held.
+func (s *editSession) relay(sender *Client, messageType MessageType, data any) {
+	message := WebSocketMessage{
+		Type:      messageType,
+		Data:      data,
+		SnippetID: &s.snippetID,
+		Timestamp: time.Now().Unix(),
+	}
+	for client := range s.editors {
+		if client != sender {
+			client.SendMessage(message)
+		}
+	}
+}
+
+// editorData describes an editor and its selection. Must be called with the mutex held.
+func (s *editSession) editorData(client *Client) EditorData {
+	editor := EditorData{
+		ConnectionID: client.id,
+		UserID:       client.userID,
+		Username:     client.username,
+		Avatar:       client.avatar,
+	}
+	if cursor, ok := s.editors[client]; ok && cursor.set {
+		anchor, head := cursor.anchor, cursor.head
+		editor.Anchor = &anchor
+		editor.Head = &head
+	}
+	return editor
+}
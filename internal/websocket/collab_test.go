@@ -0,0 +1,316 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+// fakeEditStore keeps snippet contents in memory and lets only the author edit
+type fakeEditStore struct {
+	authors  map[string]string // snippet ID -> author ID
+	contents map[string]string
+	saves    chan string
+	hold     chan struct{} // Blocks saves until closed, when set
+	mutex    sync.Mutex
+}
+
+func newFakeEditStore() *fakeEditStore {
+	return &fakeEditStore{
+		authors:  map[string]string{"snippet-1": "user-alice"},
+		contents: map[string]string{"snippet-1": "hello"},
+		saves:    make(chan string, 16),
+	}
+}
+
+func (s *fakeEditStore) Load(_ context.Context, snippetID, userID string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.authors[snippetID] != userID {
+		return "", errors.New("forbidden")
+	}
+	return s.contents[snippetID], nil
+}
+
+func (s *fakeEditStore) Save(_ context.Context, snippetID, content, editorID string) (*domain.Snippet, error) {
+	if s.hold != nil {
+		<-s.hold
+	}
+
+	s.mutex.Lock()
+	if s.authors[snippetID] != editorID {
+		s.mutex.Unlock()
+		return nil, errors.New("forbidden")
+	}
+	s.contents[snippetID] = content
+	snippet := &domain.Snippet{ID: snippetID, Content: content, Author: &domain.User{ID: s.authors[snippetID]}}
+	s.mutex.Unlock()
+
+	s.saves <- content
+	return snippet, nil
+}
+
+func setupCollabTest(t *testing.T) (*Hub, *fakeEditStore) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	store := newFakeEditStore()
+	hub := NewHub(nil, store, nil)
+	go hub.Run()
+	t.Cleanup(func() { hub.Close() })
+	return hub, store
+}
+
+// newEditor creates a registered client of the user, without a connection
+func newEditor(hub *Hub, userID string) *Client {
+	client := NewClient(hub, nil, userID)
+	hub.register <- client
+	return client
+}
+
+// nextEditMessage waits for the next message of the given type and decodes its data
+func nextEditMessage(t *testing.T, client *Client, messageType MessageType, data any) {
+	t.Helper()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		message, ok := nextMessage(client, deadline)
+		if !ok {
+			t.Fatalf("No %s message received", messageType)
+		}
+
+		var decoded struct {
+			Type MessageType     `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(message.data, &decoded))
+		if decoded.Type != messageType {
+			continue
+		}
+		if data != nil {
+			require.NoError(t, json.Unmarshal(decoded.Data, data))
+		}
+		return
+	}
+}
+
+// joinEditor joins the client to snippet-1 and returns the document it received
+func joinEditor(t *testing.T, hub *Hub, client *Client) EditStateData {
+	hub.joinEditSession(client, "snippet-1")
+	var state EditStateData
+	nextEditMessage(t, client, MessageTypeEditState, &state)
+	return state
+}
+
+func TestHub_EditSession(t *testing.T) {
+	hub, store := setupCollabTest(t)
+
+	alice := newEditor(hub, "user-alice")
+	aliceState := joinEditor(t, hub, alice)
+	assert.Equal(t, "hello", aliceState.Content)
+	assert.Equal(t, uint64(0), aliceState.Revision)
+
+	// A second tab of the same author joins the session
+	tab := newEditor(hub, "user-alice")
+	tabState := joinEditor(t, hub, tab)
+	assert.Len(t, tabState.Editors, 2)
+
+	var joined EditorChangeData
+	nextEditMessage(t, alice, MessageTypeEditJoin, &joined)
+	assert.Equal(t, tab.id, joined.Editor.ConnectionID)
+
+	t.Run("concurrent operations converge", func(t *testing.T) {
+		// Both editors change revision 0 before seeing the other's change
+		aliceOp := TextOperation{{Insert: "Oh, "}, {Retain: 5}}
+		tabOp := TextOperation{{Retain: 5}, {Insert: " world"}}
+		hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 0, Operation: aliceOp})
+		hub.applyEditOperation(tab, EditOperationData{SnippetID: "snippet-1", Revision: 0, Operation: tabOp})
+
+		// Alice's operation was applied first, the tab receives it before its own ack
+		var ack EditAckData
+		nextEditMessage(t, alice, MessageTypeEditAck, &ack)
+		assert.Equal(t, uint64(1), ack.Revision)
+
+		var relayed EditOperationData
+		nextEditMessage(t, tab, MessageTypeEditOperation, &relayed)
+		assert.Equal(t, alice.id, relayed.ConnectionID)
+		nextEditMessage(t, tab, MessageTypeEditAck, &ack)
+		assert.Equal(t, uint64(2), ack.Revision)
+
+		// The tab's operation was still pending when alice's arrived, so it transforms it
+		tabDoc, err := tabOp.Apply([]rune("hello"))
+		require.NoError(t, err)
+		incoming, _, err := TransformOperations(relayed.Operation, tabOp)
+		require.NoError(t, err)
+		tabDoc, err = incoming.Apply(tabDoc)
+		require.NoError(t, err)
+
+		// Alice's operation was acknowledged, so the tab's arrives already transformed
+		aliceDoc, err := aliceOp.Apply([]rune("hello"))
+		require.NoError(t, err)
+		var transformed EditOperationData
+		nextEditMessage(t, alice, MessageTypeEditOperation, &transformed)
+		assert.Equal(t, tab.id, transformed.ConnectionID)
+		aliceDoc, err = transformed.Operation.Apply(aliceDoc)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Oh, hello world", string(aliceDoc))
+		assert.Equal(t, string(aliceDoc), string(tabDoc))
+	})
+
+	t.Run("cursors are transformed to the current revision", func(t *testing.T) {
+		// Selects "hello" in revision 1, before " world" was added at its end
+		hub.updateEditCursor(alice, EditCursorData{SnippetID: "snippet-1", Revision: 1, Anchor: 4, Head: 9})
+
+		var cursor EditCursorData
+		nextEditMessage(t, tab, MessageTypeEditCursor, &cursor)
+		assert.Equal(t, uint64(2), cursor.Revision)
+		assert.Equal(t, 4, cursor.Anchor)
+		assert.Equal(t, 15, cursor.Head)
+		assert.Equal(t, alice.id, cursor.ConnectionID)
+	})
+
+	t.Run("invalid operations are rejected", func(t *testing.T) {
+		hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 2, Operation: TextOperation{{Retain: 1}}})
+		nextEditMessage(t, alice, MessageTypeError, nil)
+
+		var state EditStateData
+		nextEditMessage(t, alice, MessageTypeEditState, &state)
+		assert.Equal(t, "Oh, hello world", state.Content)
+
+		hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 5, Operation: TextOperation{{Retain: 15}}})
+		nextEditMessage(t, alice, MessageTypeEditState, nil)
+	})
+
+	t.Run("content replaced by an update", func(t *testing.T) {
+		hub.ReplaceEditContent("snippet-1", "replaced")
+
+		for _, client := range []*Client{alice, tab} {
+			var state EditStateData
+			nextEditMessage(t, client, MessageTypeEditState, &state)
+			assert.Equal(t, "replaced", state.Content)
+			assert.Equal(t, uint64(3), state.Revision)
+		}
+	})
+
+	t.Run("last editor leaving saves the document", func(t *testing.T) {
+		hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 3, Operation: TextOperation{{Retain: 8}, {Insert: "!"}}})
+		nextEditMessage(t, alice, MessageTypeEditAck, nil)
+
+		hub.leaveEditSession(tab, "snippet-1")
+		var left EditorChangeData
+		nextEditMessage(t, alice, MessageTypeEditLeave, &left)
+		assert.Equal(t, tab.id, left.Editor.ConnectionID)
+
+		hub.unregister <- alice
+		select {
+		case content := <-store.saves:
+			assert.Equal(t, "replaced!", content)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Document was not saved")
+		}
+	})
+}
+
+func TestHub_EditSessionRejoin(t *testing.T) {
+	hub, store := setupCollabTest(t)
+	store.hold = make(chan struct{})
+
+	alice := newEditor(hub, "user-alice")
+	joinEditor(t, hub, alice)
+	hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 0, Operation: TextOperation{{Retain: 5}, {Insert: "!"}}})
+	nextEditMessage(t, alice, MessageTypeEditAck, nil)
+
+	// Rejoining while the document is still being saved continues from the session
+	hub.leaveEditSession(alice, "snippet-1")
+	state := joinEditor(t, hub, alice)
+	assert.Equal(t, "hello!", state.Content)
+	assert.Equal(t, uint64(1), state.Revision)
+
+	close(store.hold)
+	select {
+	case content := <-store.saves:
+		assert.Equal(t, "hello!", content)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Document was not saved")
+	}
+
+	// The session of the rejoined editor stays open after the save
+	hub.editMutex.Lock()
+	assert.Contains(t, hub.editSessions, "snippet-1")
+	hub.editMutex.Unlock()
+
+	hub.leaveEditSession(alice, "snippet-1")
+	require.Eventually(t, func() bool {
+		hub.editMutex.Lock()
+		defer hub.editMutex.Unlock()
+		return len(hub.editSessions) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHub_EditSessionRevalidate(t *testing.T) {
+	hub, store := setupCollabTest(t)
+
+	alice := newEditor(hub, "user-alice")
+	joinEditor(t, hub, alice)
+	hub.applyEditOperation(alice, EditOperationData{SnippetID: "snippet-1", Revision: 0, Operation: TextOperation{{Retain: 5}, {Insert: "!"}}})
+	nextEditMessage(t, alice, MessageTypeEditAck, nil)
+
+	// Alice loses access, e.g. the snippet was transferred
+	store.mutex.Lock()
+	store.authors["snippet-1"] = "user-bob"
+	store.mutex.Unlock()
+
+	hub.revalidateSnippet("snippet-1")
+	nextEditMessage(t, alice, MessageTypeError, nil)
+	assert.Empty(t, alice.editingSnippets())
+
+	// Her unsaved changes cannot be saved anymore and are dropped eventually
+	for range editMaxSaveAttempts {
+		hub.saveEditSessions(context.Background())
+	}
+	hub.editMutex.Lock()
+	assert.Empty(t, hub.editSessions)
+	hub.editMutex.Unlock()
+	assert.Equal(t, "hello", store.contents["snippet-1"])
+}
+
+func TestHub_EditSessionRejected(t *testing.T) {
+	hub, _ := setupCollabTest(t)
+
+	for _, userID := range []string{"anonymous", "user-bob"} {
+		client := newEditor(hub, userID)
+		hub.joinEditSession(client, "snippet-1")
+		nextEditMessage(t, client, MessageTypeError, nil)
+
+		hub.applyEditOperation(client, EditOperationData{SnippetID: "snippet-1", Operation: TextOperation{{Retain: 5}}})
+		nextEditMessage(t, client, MessageTypeError, nil)
+	}
+
+	t.Run("deleted snippet closes the session", func(t *testing.T) {
+		alice := newEditor(hub, "user-alice")
+		joinEditor(t, hub, alice)
+
+		hub.CloseEditSession("snippet-1")
+		nextEditMessage(t, alice, MessageTypeError, nil)
+		assert.Empty(t, alice.editingSnippets())
+
+		hub.editMutex.Lock()
+		defer hub.editMutex.Unlock()
+		assert.Empty(t, hub.editSessions)
+	})
+}
@@ -0,0 +1,302 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/auth"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/logger"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const (
+	// sessionCheckInterval is how often the credentials of open connections are checked again
+	sessionCheckInterval = time.Minute
+
+	// messageRate and messageBurst limit the messages a connection may send per second,
+	// generous enough for typing in an edit session
+	messageRate  = 20
+	messageBurst = 60
+
+	// CloseSessionRevoked is the close code of connections whose session expired or was
+	// revoked. Clients reconnect with fresh credentials and resume their subscriptions.
+	CloseSessionRevoked = 4001
+)
+
+var errInvalidTicket = errors.New("invalid or already used ticket")
+
+// SessionLookup checks that the sessions of open connections still exist
+type SessionLookup interface {
+	GetByID(ctx context.Context, id string) (*domain.Session, error)
+}
+
+// TicketStore records redeemed tickets in state shared by every instance, so a ticket
+// can only be used once however many replicas serve live updates
+type TicketStore interface {
+	// RedeemTicket returns repository.ErrAlreadyExists if the ticket was used before
+	RedeemTicket(ctx context.Context, ticketID string, expiresAt domain.UnixTime) error
+}
+
+// GuardConfig configures who may open live update connections
+type GuardConfig struct {
+	AllowedOrigins      []string // Origins besides the server's own allowed to open WebSockets, "*" wildcards allowed
+	MaxConnectionsPerIP int      // Open WebSocket and SSE connections per client IP, 0 for no limit
+	SecretKey           string   // Verifies tickets
+	Users               UserLookup
+	Sessions            SessionLookup
+	Tickets             TicketStore // Without it tickets are only single use within this instance
+}
+
+// Guard authenticates live update connections, enforces the origin and connection
+// limits and disconnects clients whose credentials expire or are revoked
+type Guard struct {
+	hub      *Hub
+	config   GuardConfig
+	upgrader websocket.Upgrader
+
+	connections map[string]int   // Client IP -> open connections
+	redeemed    map[string]int64 // Ticket ID -> expiry, used without a ticket store
+	mutex       sync.Mutex
+
+	checkInterval time.Duration
+	logger        *zap.Logger
+}
+
+// NewGuard creates a guard for the connections of a hub
+func NewGuard(hub *Hub, config GuardConfig) *Guard {
+	g := &Guard{
+		hub:           hub,
+		config:        config,
+		connections:   make(map[string]int),
+		redeemed:      make(map[string]int64),
+		checkInterval: sessionCheckInterval,
+		logger:        logger.With(zap.String("websocket", "guard")),
+	}
+	g.upgrader = websocket.Upgrader{CheckOrigin: g.checkOrigin}
+	return g
+}
+
+// Start checks the credentials of open connections periodically until the hub is closed
+func (g *Guard) Start() {
+	go func() {
+		ticker := time.NewTicker(g.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-g.hub.done:
+				return
+			case <-ticker.C:
+				g.revalidate(context.Background())
+			}
+		}
+	}()
+}
+
+// checkOrigin allows requests without Origin header, as sent by non-browser clients,
+// from the server's own host and from the allowed origins
+func (g *Guard) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originURL.Host, r.Host) {
+		return true
+	}
+
+	for _, allowed := range g.config.AllowedOrigins {
+		if matchOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin compares an origin with an allowed origin, which may contain one "*"
+// wildcard like "https://*.example.com"
+func matchOrigin(allowed, origin string) bool {
+	allowed, origin = strings.ToLower(allowed), strings.ToLower(origin)
+	prefix, suffix, wildcard := strings.Cut(allowed, "*")
+	if !wildcard {
+		return allowed == origin
+	}
+	return len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
+
+// authenticate returns the credential of a connection request. Tickets in the query
+// take precedence over the session cookie and bearer token checked by the auth middleware.
+// Requests without credentials connect anonymously.
+func (g *Guard) authenticate(r *http.Request) (api.Credential, error) {
+	ticket := r.URL.Query().Get("ticket")
+	if ticket == "" {
+		return api.GetCredential(r), nil
+	}
+
+	claims, err := auth.ValidateTicket(ticket, g.config.SecretKey)
+	if err != nil {
+		return api.Credential{}, err
+	}
+	if err := g.redeem(r.Context(), claims.ID, claims.RegisteredClaims.ExpiresAt.Unix()); err != nil {
+		return api.Credential{}, err
+	}
+
+	return api.Credential{UserID: claims.UserID, SessionID: claims.SessionID, ExpiresAt: claims.ExpiresAt}, nil
+}
+
+// redeem marks a ticket as used, errInvalidTicket if it was used before
+func (g *Guard) redeem(ctx context.Context, ticketID string, expiresAt int64) error {
+	if g.config.Tickets != nil {
+		err := g.config.Tickets.RedeemTicket(ctx, ticketID, expiresAt)
+		if repository.IsAlreadyExists(err) {
+			return errInvalidTicket
+		}
+		return err
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, used := g.redeemed[ticketID]; used {
+		return errInvalidTicket
+	}
+	g.redeemed[ticketID] = expiresAt
+	return nil
+}
+
+// acquire reserves a connection for a client IP, false if the IP has too many open
+func (g *Guard) acquire(ip string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.config.MaxConnectionsPerIP > 0 && g.connections[ip] >= g.config.MaxConnectionsPerIP {
+		return false
+	}
+	g.connections[ip]++
+	return true
+}
+
+// release frees a connection reserved by acquire
+func (g *Guard) release(ip string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.connections[ip]--; g.connections[ip] <= 0 {
+		delete(g.connections, ip)
+	}
+}
+
+// revalidate disconnects clients whose credentials expired or were revoked, and
+// forgets redeemed tickets that expired
+func (g *Guard) revalidate(ctx context.Context) {
+	now := time.Now().Unix()
+
+	g.mutex.Lock()
+	for ticketID, expiresAt := range g.redeemed {
+		if expiresAt < now {
+			delete(g.redeemed, ticketID)
+		}
+	}
+	g.mutex.Unlock()
+
+	var clients []*Client
+	g.hub.query(func() {
+		for client := range g.hub.clients {
+			if client.credential.UserID != "" {
+				clients = append(clients, client)
+			}
+		}
+	})
+
+	// Tabs of the same user usually share a credential, check each once
+	valid := make(map[api.Credential]bool)
+	for _, client := range clients {
+		ok, checked := valid[client.credential]
+		if !checked {
+			ok = g.validCredential(ctx, client.credential, now)
+			valid[client.credential] = ok
+		}
+		if !ok {
+			client.logger.Info("Disconnecting client with expired or revoked session")
+			client.disconnect(CloseSessionRevoked, "Session expired or revoked")
+		}
+	}
+}
+
+// validCredential reports whether a credential is still valid. Lookup errors other
+// than missing records keep connections open, a database hiccup should not drop everyone.
+func (g *Guard) validCredential(ctx context.Context, credential api.Credential, now int64) bool {
+	if credential.ExpiresAt != 0 && credential.ExpiresAt <= now {
+		return false
+	}
+
+	if credential.SessionID != "" && g.config.Sessions != nil {
+		session, err := g.config.Sessions.GetByID(ctx, credential.SessionID)
+		switch {
+		case repository.IsNotFound(err):
+			return false
+		case err != nil:
+			g.logger.Warn("Failed to check session", zap.Error(err))
+		case session.UserID != credential.UserID || session.ExpiresAt <= now:
+			return false
+		}
+	}
+
+	if g.config.Users != nil {
+		if _, err := g.config.Users.GetByID(ctx, credential.UserID); repository.IsNotFound(err) {
+			return false
+		} else if err != nil {
+			g.logger.Warn("Failed to check user", zap.Error(err))
+		}
+	}
+	return true
+}
+
+// clientIP returns the IP of a request. The ClientIP middleware already resolved
+// RemoteAddr, without it the port is stripped here.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// messageLimiter is a token bucket limiting the messages a connection may send
+type messageLimiter struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  float64
+}
+
+func newMessageLimiter(rate, burst int) *messageLimiter {
+	return &messageLimiter{tokens: float64(burst), rate: float64(rate), burst: float64(burst)}
+}
+
+// allow takes a token for a message, false if the connection sends too fast
+func (l *messageLimiter) allow(now time.Time) bool {
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
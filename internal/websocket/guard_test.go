@@ -0,0 +1,245 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/auth"
+	"mitsimi.dev/codeShare/internal/domain"
+	"mitsimi.dev/codeShare/internal/repository"
+)
+
+const testSecretKey = "test-secret-key"
+
+// fakeSessionLookup keeps sessions in memory
+type fakeSessionLookup struct {
+	sessions map[string]*domain.Session
+	mutex    sync.Mutex
+}
+
+func (s *fakeSessionLookup) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return session, nil
+}
+
+// fakeTicketStore records redeemed tickets in memory, shared by several guards
+type fakeTicketStore struct {
+	redeemed map[string]bool
+	mutex    sync.Mutex
+}
+
+func (s *fakeTicketStore) RedeemTicket(ctx context.Context, ticketID string, expiresAt domain.UnixTime) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.redeemed[ticketID] {
+		return repository.ErrAlreadyExists
+	}
+	s.redeemed[ticketID] = true
+	return nil
+}
+
+func (s *fakeSessionLookup) delete(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sessions, id)
+}
+
+// setupGuardTest serves a hub's WebSocket endpoint behind a guard with the given config
+func setupGuardTest(t *testing.T, config GuardConfig) (*Hub, *Guard, string) {
+	hub, _ := setupSSETest(t)
+	config.SecretKey = testSecretKey
+	guard := NewGuard(hub, config)
+
+	server := httptest.NewServer(HandleWebSocket(hub, guard))
+	t.Cleanup(server.Close)
+	return hub, guard, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// dial opens a WebSocket connection, returning the response when the upgrade is rejected
+func dial(t *testing.T, url string, header http.Header) (*websocket.Conn, *http.Response) {
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		return nil, resp
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, conn).Type) // Connected
+	return conn, resp
+}
+
+// ticket issues a ticket for a user
+func ticket(t *testing.T, userID, sessionID string) string {
+	response, err := auth.GenerateTicket(userID, sessionID, 0, testSecretKey)
+	require.NoError(t, err)
+	return response.Token
+}
+
+// readCloseCode reads until the server closes the connection and returns the close code
+func readCloseCode(t *testing.T, conn *websocket.Conn) int {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			var closeErr *websocket.CloseError
+			require.True(t, errors.As(err, &closeErr), "unexpected error %v", err)
+			return closeErr.Code
+		}
+	}
+}
+
+func TestGuard_CheckOrigin(t *testing.T) {
+	guard := NewGuard(NewHub(nil, nil, nil), GuardConfig{
+		AllowedOrigins: []string{"http://localhost:3000", "https://*.example.com"},
+	})
+
+	for origin, allowed := range map[string]bool{
+		"":                          true, // Non-browser clients
+		"https://codeshare.test":    true, // Same host
+		"http://localhost:3000":     true,
+		"HTTP://LOCALHOST:3000":     true,
+		"https://app.example.com":   true,
+		"https://example.com":       false,
+		"https://evil.com":          false,
+		"http://localhost:3001":     false,
+		"https://app.example.com.x": false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "https://codeshare.test/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		assert.Equal(t, allowed, guard.checkOrigin(r), origin)
+	}
+}
+
+func TestMessageLimiter(t *testing.T) {
+	limiter := newMessageLimiter(10, 3)
+	now := time.Now()
+
+	for range 3 {
+		assert.True(t, limiter.allow(now))
+	}
+	assert.False(t, limiter.allow(now))
+
+	// One token is refilled every 100ms
+	assert.True(t, limiter.allow(now.Add(100*time.Millisecond)))
+	assert.False(t, limiter.allow(now.Add(100*time.Millisecond)))
+	assert.True(t, limiter.allow(now.Add(time.Hour)))
+}
+
+func TestHandleWebSocket_Guard(t *testing.T) {
+	t.Run("rejects foreign origins", func(t *testing.T) {
+		_, _, url := setupGuardTest(t, GuardConfig{AllowedOrigins: []string{"http://localhost:3000"}})
+
+		conn, resp := dial(t, url, http.Header{"Origin": {"https://evil.com"}})
+		assert.Nil(t, conn)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		conn, _ = dial(t, url, http.Header{"Origin": {"http://localhost:3000"}})
+		assert.NotNil(t, conn)
+	})
+
+	t.Run("authenticates with single use tickets", func(t *testing.T) {
+		hub, _, url := setupGuardTest(t, GuardConfig{})
+		userTicket := ticket(t, "user-1", "")
+
+		conn, _ := dial(t, url+"?ticket="+userTicket, nil)
+		require.NotNil(t, conn)
+		hub.query(func() {
+			for client := range hub.clients {
+				assert.Equal(t, "user-1", client.userID)
+			}
+		})
+
+		conn, resp := dial(t, url+"?ticket="+userTicket, nil)
+		assert.Nil(t, conn)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		conn, resp = dial(t, url+"?ticket=invalid", nil)
+		assert.Nil(t, conn)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("tickets are single use across instances", func(t *testing.T) {
+		tickets := &fakeTicketStore{redeemed: make(map[string]bool)}
+		_, _, urlA := setupGuardTest(t, GuardConfig{Tickets: tickets})
+		_, _, urlB := setupGuardTest(t, GuardConfig{Tickets: tickets})
+		userTicket := ticket(t, "user-1", "")
+
+		conn, _ := dial(t, urlA+"?ticket="+userTicket, nil)
+		require.NotNil(t, conn)
+
+		conn, resp := dial(t, urlB+"?ticket="+userTicket, nil)
+		assert.Nil(t, conn)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("limits connections per IP", func(t *testing.T) {
+		_, guard, url := setupGuardTest(t, GuardConfig{MaxConnectionsPerIP: 2})
+
+		first, _ := dial(t, url, nil)
+		dial(t, url, nil)
+		conn, resp := dial(t, url, nil)
+		assert.Nil(t, conn)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+		// Closed connections free their slot
+		first.Close()
+		require.Eventually(t, func() bool {
+			guard.mutex.Lock()
+			defer guard.mutex.Unlock()
+			return guard.connections["127.0.0.1"] == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		conn, _ = dial(t, url, nil)
+		assert.NotNil(t, conn)
+	})
+
+	t.Run("disconnects revoked sessions", func(t *testing.T) {
+		sessions := &fakeSessionLookup{sessions: map[string]*domain.Session{
+			"session-1": {ID: "session-1", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+			"session-2": {ID: "session-2", UserID: "user-2", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		}}
+		_, guard, url := setupGuardTest(t, GuardConfig{Sessions: sessions})
+
+		revoked, _ := dial(t, url+"?ticket="+ticket(t, "user-1", "session-1"), nil)
+		active, _ := dial(t, url+"?ticket="+ticket(t, "user-2", "session-2"), nil)
+		anonymous, _ := dial(t, url, nil)
+
+		sessions.delete("session-1")
+		guard.revalidate(context.Background())
+		assert.Equal(t, CloseSessionRevoked, readCloseCode(t, revoked))
+
+		// The other connections stay open and keep receiving messages
+		require.NoError(t, active.WriteJSON(WebSocketMessage{Type: MessageTypeSubscribe, Data: SubscriptionRequest{Type: SubTypeListUpdates}}))
+		assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, active).Type)
+		require.NoError(t, anonymous.WriteJSON(WebSocketMessage{Type: MessageTypeSubscribe, Data: SubscriptionRequest{Type: SubTypeListUpdates}}))
+		assert.Equal(t, MessageTypeSuccess, readWebSocketMessage(t, anonymous).Type)
+	})
+
+	t.Run("disconnects clients exceeding the message rate", func(t *testing.T) {
+		_, _, url := setupGuardTest(t, GuardConfig{})
+		conn, _ := dial(t, url, nil)
+
+		for range messageBurst + 10 {
+			if err := conn.WriteJSON(WebSocketMessage{Type: MessageTypeUnsubscribe}); err != nil {
+				break
+			}
+		}
+		assert.Equal(t, websocket.ClosePolicyViolation, readCloseCode(t, conn))
+	})
+}
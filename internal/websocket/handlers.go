@@ -1,46 +1,90 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/domain"
 	"mitsimi.dev/codeShare/internal/logger"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// In production, implement proper origin checking
-		return true
-	},
+// UserLookup resolves the profiles shown to other viewers of a snippet
+type UserLookup interface {
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+}
+
+// loadProfile sets the username and avatar shown in presence lists.
+// Without a lookup, presence lists only carry user IDs.
+func (c *Client) loadProfile(ctx context.Context, users UserLookup) error {
+	if c.userID == "anonymous" || users == nil {
+		return nil
+	}
+
+	user, err := users.GetByID(ctx, c.userID)
+	if err != nil {
+		return err
+	}
+	c.username = user.Username
+	c.avatar = user.Avatar
+	return nil
 }
 
 // HandleWebSocket creates the HTTP handler for WebSocket connections
-func HandleWebSocket(hub *Hub) http.HandlerFunc {
+func HandleWebSocket(hub *Hub, guard *Guard) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetReqID(r.Context())
-		userID := api.GetUserID(r)
+		log := logger.With(zap.String("request_id", requestID))
+
+		if !guard.checkOrigin(r) {
+			log.Warn("WebSocket origin not allowed", zap.String("origin", r.Header.Get("Origin")))
+			api.WriteError(w, http.StatusForbidden, "Origin not allowed")
+			return
+		}
+
+		credential, err := guard.authenticate(r)
+		if err != nil {
+			log.Warn("WebSocket authentication failed", zap.Error(err))
+			api.WriteError(w, http.StatusUnauthorized, "Invalid ticket")
+			return
+		}
+		userID := credential.UserID
 		if userID == "" {
 			userID = "anonymous"
 		}
-		log := logger.With(zap.String("request_id", requestID), zap.String("user_id", userID))
+		log = log.With(zap.String("user_id", userID))
 
-		conn, err := upgrader.Upgrade(w, r, nil)
+		ip := clientIP(r)
+		if !guard.acquire(ip) {
+			log.Warn("Too many WebSocket connections", zap.String("ip", ip))
+			api.WriteError(w, http.StatusTooManyRequests, "Too many connections")
+			return
+		}
+
+		conn, err := guard.upgrader.Upgrade(w, r, nil)
 		if err != nil {
+			guard.release(ip)
 			log.Warn("WebSocket upgrade failed", zap.Error(err))
 			return
 		}
 
 		client := NewClient(hub, conn, userID)
+		client.credential = credential
+		if err := client.loadProfile(r.Context(), guard.config.Users); err != nil {
+			log.Warn("failed to load profile for presence", zap.Error(err))
+		}
 		client.hub.register <- client
 
 		log.Info("WebSocket connection established", zap.String("user_id", userID), zap.String("request_id", requestID))
-		// Start client pumps
+		// Start client pumps, the connection ends with the read pump
 		go client.WritePump()
-		go client.ReadPump()
+		go func() {
+			client.ReadPump()
+			guard.release(ip)
+		}()
 	}
 }
 
@@ -51,10 +95,13 @@ func HandleStats(hub *Hub) http.HandlerFunc {
 		stats := hub.GetStats()
 
 		type StatsResponse struct {
-			TotalClients         int `json:"total_clients"`
-			UserSubscriptions    int `json:"user_subscriptions"`
-			SnippetSubscriptions int `json:"snippet_subscriptions"`
-			ListSubscriptions    int `json:"list_subscriptions"`
+			TotalClients         int    `json:"total_clients"`
+			UserSubscriptions    int    `json:"user_subscriptions"`
+			SnippetSubscriptions int    `json:"snippet_subscriptions"`
+			ListSubscriptions    int    `json:"list_subscriptions"`
+			ListFilters          int    `json:"list_filters"`
+			FeedSubscriptions    int    `json:"feed_subscriptions"`
+			DroppedMessages      uint64 `json:"dropped_messages"`
 		}
 
 		response := StatsResponse{
@@ -62,6 +109,9 @@ func HandleStats(hub *Hub) http.HandlerFunc {
 			UserSubscriptions:    stats["user_subscriptions"].(int),
 			SnippetSubscriptions: stats["snippet_subscriptions"].(int),
 			ListSubscriptions:    stats["list_subscriptions"].(int),
+			ListFilters:          stats["list_filters"].(int),
+			FeedSubscriptions:    stats["feed_subscriptions"].(int),
+			DroppedMessages:      stats["dropped_messages"].(uint64),
 		}
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -1,49 +1,181 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 	"mitsimi.dev/codeShare/internal/logger"
 )
 
-// Hub maintains active clients and handles broadcasting
+const (
+	// replayBufferSize is the number of recent broadcasts, across all topics, kept for clients resuming a stream
+	replayBufferSize = 512
+	// topicReplayBufferSize is the number of recent broadcasts kept per topic, so one busy
+	// topic cannot push every other topic out of the replay buffer
+	topicReplayBufferSize = 64
+	// brokerBufferSize is the number of broadcasts queued for and from the broker
+	brokerBufferSize = 1024
+	// broadcastBufferSize is the number of local broadcasts queued for the Run goroutine,
+	// so request handlers are not held up by bursts
+	broadcastBufferSize = 1024
+	// maxClientSubscriptions limits the subscriptions of a client, each snippet and list filter counting as one
+	maxClientSubscriptions = 100
+)
+
+// brokerEnvelope is a broadcast shared with the other instances through the broker
+type brokerEnvelope struct {
+	Origin  string           `json:"origin"` // Instance ID of the publishing hub
+	Seq     uint64           `json:"seq"`    // Counts the broadcasts published by the origin
+	Target  BroadcastTarget  `json:"target"`
+	Message WebSocketMessage `json:"message"`
+	Stats   bool             `json:"stats,omitempty"`
+}
+
+// topicState tracks the sequence numbers of a topic. It is kept while the topic has
+// local subscribers or broadcasts in the replay buffer.
+type topicState struct {
+	seq         uint64 // Sequence number of the last broadcast
+	evicted     uint64 // Highest sequence number dropped from the replay buffer
+	buffered    int    // Broadcasts of the topic in the replay buffer
+	subscribers int    // Local subscriptions to the topic
+}
+
+// historyEntry is a broadcast kept for replay
+type historyEntry struct {
+	topic string
+	seq   uint64
+	data  []byte
+}
+
+// resumeRequest subscribes a client and replays the broadcasts it missed
+// for every subscription carrying a LastSeq
+type resumeRequest struct {
+	client        *Client
+	subscriptions []SubscriptionRequest
+}
+
+// unsubscribeRequest ends a subscription of a client
+type unsubscribeRequest struct {
+	client       *Client
+	subscription SubscriptionRequest
+}
+
+// clientSet is a set of subscribed clients
+type clientSet map[*Client]struct{}
+
+// Hub maintains active clients and handles broadcasting. The Run goroutine owns
+// the clients, their subscriptions and the sequencing state; other goroutines only
+// talk to it through channels. Messages are pushed to the clients' send queues
+// without blocking, so a slow client never holds up the hub.
 type Hub struct {
 	// Client management
-	clients    map[*Client]struct{}
+	clients    clientSet
 	register   chan *Client
 	unregister chan *Client
 
 	// Subscription maps
-	userClients          map[string][]*Client // userID -> clients for user_actions
-	snippetUpdateClients map[string][]*Client // snippetID -> clients for snippet_updates
-	listUpdateClients    []*Client            // clients subscribed to list_updates
+	userClients          map[string]clientSet // userID -> clients for user_actions
+	snippetUpdateClients map[string]clientSet // snippetID -> clients for snippet_updates
+	listUpdateClients    clientSet            // clients subscribed to list_updates
+	listFilterClients    map[string]clientSet // list filter -> clients for filtered list_updates
+	feedClients          map[string]clientSet // userID -> clients for feed
+
+	// Requests handled by the Run goroutine
+	broadcast   chan BroadcastMessage
+	resume      chan resumeRequest
+	unsubscribe chan unsubscribeRequest
+	queries     chan func() // Functions reading the hub's state
+
+	// Sequencing and replay
+	topics  map[string]*topicState
+	history []historyEntry // Most recent broadcasts, oldest first
+
+	// Cross-instance fan-out, disabled when broker is nil. The instance ID is also
+	// the epoch of the hub's sequence numbers.
+	instanceID string
+	broker     Broker
+	outbox     chan brokerEnvelope // Local broadcasts waiting to be published
+	remote     chan brokerEnvelope // Broadcasts received from other instances
+	published  uint64              // Broadcasts shared through the broker
+	remoteSeqs map[string]uint64   // Origin -> last delivered sequence
+	done       chan struct{}
+	closeOnce  sync.Once
+
+	dropped uint64 // Messages dropped for clients that have disconnected since
 
-	// Broadcasting
-	broadcast chan BroadcastMessage
+	// Presence
+	presenceChanges   map[string]*presenceChange // snippetID -> pending change
+	presenceSnapshots map[string]presenceSnapshot
+	presenceDebounce  time.Duration
+
+	// Collaborative editing, disabled when editStore is nil
+	editStore    EditStore
+	editSessions map[string]*editSession // snippetID -> session, guarded by editMutex
+	editMutex    sync.Mutex
+
+	// Authorizes snippet subscriptions, every snippet can be followed when nil
+	access SnippetAccess
 
-	mutex  sync.RWMutex
 	logger *zap.Logger
 }
 
-// NewHub creates a new Hub
-func NewHub() *Hub {
+// NewHub creates a new Hub. With a non-nil broker, broadcasts are shared with
+// every other hub subscribed to the same broker. With a non-nil edit store,
+// clients can edit snippets together. With a non-nil snippet access, clients can
+// only follow the snippets they may see.
+func NewHub(broker Broker, editStore EditStore, access SnippetAccess) *Hub {
 	return &Hub{
-		clients:              make(map[*Client]struct{}),
+		clients:              make(clientSet),
 		register:             make(chan *Client),
 		unregister:           make(chan *Client),
-		userClients:          make(map[string][]*Client),
-		snippetUpdateClients: make(map[string][]*Client),
-		listUpdateClients:    make([]*Client, 0),
-		broadcast:            make(chan BroadcastMessage),
+		userClients:          make(map[string]clientSet),
+		snippetUpdateClients: make(map[string]clientSet),
+		listUpdateClients:    make(clientSet),
+		listFilterClients:    make(map[string]clientSet),
+		feedClients:          make(map[string]clientSet),
+		broadcast:            make(chan BroadcastMessage, broadcastBufferSize),
+		resume:               make(chan resumeRequest),
+		unsubscribe:          make(chan unsubscribeRequest),
+		queries:              make(chan func()),
+		topics:               make(map[string]*topicState),
+		history:              make([]historyEntry, 0, replayBufferSize),
+		instanceID:           uuid.New().String(),
+		broker:               broker,
+		outbox:               make(chan brokerEnvelope, brokerBufferSize),
+		remote:               make(chan brokerEnvelope, brokerBufferSize),
+		remoteSeqs:           make(map[string]uint64),
+		done:                 make(chan struct{}),
+		presenceChanges:      make(map[string]*presenceChange),
+		presenceSnapshots:    make(map[string]presenceSnapshot),
+		presenceDebounce:     presenceDebounce,
+		editStore:            editStore,
+		editSessions:         make(map[string]*editSession),
+		access:               access,
 		logger:               logger.With(zap.String("websocket", "hub")),
 	}
 }
 
 // Run starts the hub
 func (h *Hub) Run() {
+	if h.broker != nil {
+		if err := h.broker.Subscribe(h.receive); err != nil {
+			h.logger.Error("Failed to subscribe to broker, broadcasts stay on this instance", zap.Error(err))
+		}
+		go h.publishLoop()
+	}
+
+	if h.editStore != nil {
+		go h.editPersistLoop()
+	}
+
+	presenceTicker := time.NewTicker(h.presenceDebounce / 4)
+	defer presenceTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -54,14 +186,37 @@ func (h *Hub) Run() {
 
 		case broadcastMsg := <-h.broadcast:
 			h.handleBroadcast(broadcastMsg)
+
+		case envelope := <-h.remote:
+			h.handleRemote(envelope)
+
+		case req := <-h.resume:
+			h.handleResume(req)
+
+		case req := <-h.unsubscribe:
+			h.unsubscribeClient(req.client, req.subscription)
+
+		case query := <-h.queries:
+			query()
+
+		case now := <-presenceTicker.C:
+			h.flushPresence(now)
 		}
 	}
 }
 
-func (h *Hub) registerClient(client *Client) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+// query runs a function on the Run goroutine and waits for it, so it can read the
+// hub's state. The hub must be running.
+func (h *Hub) query(fn func()) {
+	done := make(chan struct{})
+	h.queries <- func() {
+		fn()
+		close(done)
+	}
+	<-done
+}
 
+func (h *Hub) registerClient(client *Client) {
 	h.clients[client] = struct{}{}
 	h.logger.Info("Client registered",
 		zap.String("user_id", client.userID),
@@ -76,133 +231,578 @@ func (h *Hub) registerClient(client *Client) {
 }
 
 func (h *Hub) unregisterClient(client *Client) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+
+	for _, snippetID := range client.editingSnippets() {
+		h.leaveEditSession(client, snippetID)
+	}
+	for snippetID := range client.snippetUpdatesSubscribed {
+		h.markPresenceChanged(snippetID, nil)
+	}
+
+	// Remove from all subscription maps
+	if client.userActionsSubscribed {
+		removeClient(h.userClients, client.userID, client)
+		h.releaseTopic(topicKey(SubTypeUserActions, client.userID))
+	}
+	for snippetID := range client.snippetUpdatesSubscribed {
+		removeClient(h.snippetUpdateClients, snippetID, client)
+		h.releaseTopic(topicKey(SubTypeSnippetUpdates, snippetID))
+	}
+	if client.listUpdatesSubscribed {
+		delete(h.listUpdateClients, client)
+		h.releaseTopic(topicKey(SubTypeListUpdates, ""))
+	}
+	for filter := range client.listFilters {
+		removeClient(h.listFilterClients, filter, client)
+		h.releaseTopic(topicKey(SubTypeListUpdates, filter))
+	}
+	if client.feedSubscribed {
+		removeClient(h.feedClients, client.userID, client)
+		h.releaseTopic(topicKey(SubTypeFeed, client.userID))
+	}
 
-	if _, ok := h.clients[client]; ok {
-		// Remove from all subscription maps
-		h.removeFromUserClients(client)
-		h.removeFromSnippetClients(client)
-		h.removeFromListClients(client)
+	delete(h.clients, client)
+	client.queue.close(websocket.CloseNormalClosure, "")
+	h.dropped += client.queue.droppedCount()
 
-		delete(h.clients, client)
-		close(client.send)
+	h.logger.Info("Client unregistered",
+		zap.String("user_id", client.userID),
+		zap.Uint64("dropped_messages", client.queue.droppedCount()),
+		zap.Int("total_clients", len(h.clients)))
+}
 
-		h.logger.Info("Client unregistered",
-			zap.String("user_id", client.userID),
-			zap.Int("total_clients", len(h.clients)))
+// addClient adds a client to the subscribers of a key
+func addClient(sets map[string]clientSet, key string, client *Client) {
+	set, exists := sets[key]
+	if !exists {
+		set = make(clientSet)
+		sets[key] = set
 	}
+	set[client] = struct{}{}
 }
 
-func (h *Hub) removeFromUserClients(client *Client) {
-	if clients, exists := h.userClients[client.userID]; exists {
-		for i, c := range clients {
-			if c == client {
-				h.userClients[client.userID] = append(clients[:i], clients[i+1:]...)
-				if len(h.userClients[client.userID]) == 0 {
-					delete(h.userClients, client.userID)
-				}
-				break
-			}
+// removeClient removes a client from the subscribers of a key
+func removeClient(sets map[string]clientSet, key string, client *Client) {
+	if set, exists := sets[key]; exists {
+		delete(set, client)
+		if len(set) == 0 {
+			delete(sets, key)
 		}
 	}
 }
 
-func (h *Hub) removeFromSnippetClients(client *Client) {
-	// Remove from snippet updates
-	for snippetID := range client.snippetUpdatesSubscribed {
-		if clients, exists := h.snippetUpdateClients[snippetID]; exists {
-			for i, c := range clients {
-				if c == client {
-					h.snippetUpdateClients[snippetID] = append(clients[:i], clients[i+1:]...)
-					if len(h.snippetUpdateClients[snippetID]) == 0 {
-						delete(h.snippetUpdateClients, snippetID)
-					}
-					break
-				}
+// subscribeClient adds a subscription of a client and confirms it with the topic's
+// current sequence number
+func (h *Hub) subscribeClient(client *Client, subReq SubscriptionRequest) {
+	if !client.isSubscribed(subReq) && client.subscriptionCount() >= maxClientSubscriptions {
+		client.sendError("Too many subscriptions", subReq.SnippetID)
+		return
+	}
+
+	switch subReq.Type {
+	case SubTypeUserActions:
+		if client.userID == "anonymous" {
+			client.sendError("Anonymous users cannot subscribe to user_actions", nil)
+			return
+		}
+
+		if !client.userActionsSubscribed {
+			client.userActionsSubscribed = true
+			addClient(h.userClients, client.userID, client)
+			h.topicState(client.topic(subReq)).subscribers++
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Subscribed to user_actions",
+				Topic:     client.topic(subReq),
+				Seq:       h.topicSeq(client.topic(subReq)),
+				Epoch:     h.instanceID,
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User subscribed to user_actions")
+		}
+
+	case SubTypeSnippetUpdates:
+		if subReq.SnippetID != nil {
+			snippetID := *subReq.SnippetID
+			if !client.snippetUpdatesSubscribed[snippetID] {
+				client.snippetUpdatesSubscribed[snippetID] = true
+				addClient(h.snippetUpdateClients, snippetID, client)
+				h.topicState(client.topic(subReq)).subscribers++
+
+				client.SendMessage(WebSocketMessage{
+					Type:      MessageTypeSuccess,
+					Data:      "Subscribed to snippet_updates for " + snippetID,
+					SnippetID: &snippetID,
+					Topic:     client.topic(subReq),
+					Seq:       h.topicSeq(client.topic(subReq)),
+					Epoch:     h.instanceID,
+					Timestamp: time.Now().Unix(),
+				})
+				h.markPresenceChanged(snippetID, client)
+				client.logger.Info("User subscribed to snippet_updates", zap.String("snippet_id", snippetID))
 			}
 		}
+
+	case SubTypeListUpdates:
+		if subReq.AuthorID != nil || subReq.Language != nil {
+			h.subscribeListFilter(client, subReq)
+			return
+		}
+
+		if !client.listUpdatesSubscribed {
+			client.listUpdatesSubscribed = true
+			h.listUpdateClients[client] = struct{}{}
+			h.topicState(client.topic(subReq)).subscribers++
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Subscribed to list_updates",
+				Topic:     client.topic(subReq),
+				Seq:       h.topicSeq(client.topic(subReq)),
+				Epoch:     h.instanceID,
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User subscribed to list_updates")
+		}
+
+	case SubTypeFeed:
+		if client.userID == "anonymous" {
+			client.sendError("Anonymous users cannot subscribe to feed", nil)
+			return
+		}
+
+		if !client.feedSubscribed {
+			client.feedSubscribed = true
+			addClient(h.feedClients, client.userID, client)
+			h.topicState(client.topic(subReq)).subscribers++
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Subscribed to feed",
+				Topic:     client.topic(subReq),
+				Seq:       h.topicSeq(client.topic(subReq)),
+				Epoch:     h.instanceID,
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User subscribed to feed")
+		}
 	}
 }
 
-func (h *Hub) removeFromListClients(client *Client) {
-	for i, c := range h.listUpdateClients {
-		if c == client {
-			h.listUpdateClients = append(h.listUpdateClients[:i], h.listUpdateClients[i+1:]...)
-			break
+// subscribeListFilter subscribes a client to the list_updates of one author or language
+func (h *Hub) subscribeListFilter(client *Client, subReq SubscriptionRequest) {
+	if subReq.AuthorID != nil && subReq.Language != nil {
+		client.sendError("Filter list_updates by author_id or language, not both", nil)
+		return
+	}
+	if (subReq.AuthorID != nil && *subReq.AuthorID == "") || (subReq.Language != nil && *subReq.Language == "") {
+		client.sendError("List filters cannot be empty", nil)
+		return
+	}
+
+	filter := subReq.listFilter()
+	if client.listFilters[filter] {
+		return
+	}
+	client.listFilters[filter] = true
+	addClient(h.listFilterClients, filter, client)
+
+	topic := client.topic(subReq)
+	h.topicState(topic).subscribers++
+
+	client.SendMessage(WebSocketMessage{
+		Type:      MessageTypeSuccess,
+		Data:      "Subscribed to list_updates for " + filter,
+		Topic:     topic,
+		Seq:       h.topicSeq(topic),
+		Epoch:     h.instanceID,
+		Timestamp: time.Now().Unix(),
+	})
+	client.logger.Info("User subscribed to filtered list_updates", zap.String("filter", filter))
+}
+
+// unsubscribeClient ends a subscription of a client
+func (h *Hub) unsubscribeClient(client *Client, subReq SubscriptionRequest) {
+	if _, registered := h.clients[client]; !registered {
+		return
+	}
+
+	switch subReq.Type {
+	case SubTypeUserActions:
+		if client.userActionsSubscribed {
+			client.userActionsSubscribed = false
+			removeClient(h.userClients, client.userID, client)
+			h.releaseTopic(client.topic(subReq))
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Unsubscribed from user_actions",
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User unsubscribed from user_actions")
+		}
+
+	case SubTypeSnippetUpdates:
+		if subReq.SnippetID != nil {
+			snippetID := *subReq.SnippetID
+			if client.snippetUpdatesSubscribed[snippetID] {
+				delete(client.snippetUpdatesSubscribed, snippetID)
+				removeClient(h.snippetUpdateClients, snippetID, client)
+				h.releaseTopic(client.topic(subReq))
+				h.markPresenceChanged(snippetID, nil)
+
+				client.SendMessage(WebSocketMessage{
+					Type:      MessageTypeSuccess,
+					Data:      "Unsubscribed from snippet_updates for " + snippetID,
+					SnippetID: &snippetID,
+					Timestamp: time.Now().Unix(),
+				})
+				client.logger.Info("User unsubscribed from snippet_updates", zap.String("snippet_id", snippetID))
+			}
+		}
+
+	case SubTypeListUpdates:
+		if filter := subReq.listFilter(); filter != "" {
+			if client.listFilters[filter] {
+				delete(client.listFilters, filter)
+				removeClient(h.listFilterClients, filter, client)
+				h.releaseTopic(client.topic(subReq))
+
+				client.SendMessage(WebSocketMessage{
+					Type:      MessageTypeSuccess,
+					Data:      "Unsubscribed from list_updates for " + filter,
+					Timestamp: time.Now().Unix(),
+				})
+				client.logger.Info("User unsubscribed from filtered list_updates", zap.String("filter", filter))
+			}
+			return
+		}
+
+		if client.listUpdatesSubscribed {
+			client.listUpdatesSubscribed = false
+			delete(h.listUpdateClients, client)
+			h.releaseTopic(client.topic(subReq))
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Unsubscribed from list_updates",
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User unsubscribed from list_updates")
+		}
+
+	case SubTypeFeed:
+		if client.feedSubscribed {
+			client.feedSubscribed = false
+			removeClient(h.feedClients, client.userID, client)
+			h.releaseTopic(client.topic(subReq))
+
+			client.SendMessage(WebSocketMessage{
+				Type:      MessageTypeSuccess,
+				Data:      "Unsubscribed from feed",
+				Timestamp: time.Now().Unix(),
+			})
+			client.logger.Info("User unsubscribed from feed")
 		}
 	}
 }
 
 func (h *Hub) handleBroadcast(broadcastMsg BroadcastMessage) {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
+	h.deliver(broadcastMsg.Target, broadcastMsg.Message, broadcastMsg.Stats)
+
+	if h.broker == nil {
+		return
+	}
+	h.published++
+	select {
+	case h.outbox <- brokerEnvelope{Origin: h.instanceID, Seq: h.published, Target: broadcastMsg.Target, Message: broadcastMsg.Message, Stats: broadcastMsg.Stats}:
+	default:
+		h.logger.Warn("Broker queue full, broadcast not shared with other instances")
+	}
+}
 
-	switch broadcastMsg.Target.Type {
+// handleRemote delivers a broadcast from another instance, dropping redelivered ones
+func (h *Hub) handleRemote(envelope brokerEnvelope) {
+	if envelope.Seq <= h.remoteSeqs[envelope.Origin] {
+		return
+	}
+	h.remoteSeqs[envelope.Origin] = envelope.Seq
+
+	h.deliver(envelope.Target, envelope.Message, envelope.Stats)
+}
+
+// deliver sends a broadcast to the local subscribers of every topic it targets.
+// Feed items are sequenced per recipient, as each follower has their own feed topic.
+// Queued stats updates of a topic are replaced by newer ones.
+func (h *Hub) deliver(target BroadcastTarget, message WebSocketMessage, stats bool) {
+	switch target.Type {
 	case BroadcastTargetTypeUser:
-		if broadcastMsg.Target.UserID != nil {
-			h.broadcastToUser(*broadcastMsg.Target.UserID, broadcastMsg.Message)
+		if target.UserID != nil {
+			h.sendToTopic(topicKey(SubTypeUserActions, *target.UserID), message, stats, h.userClients[*target.UserID])
 		}
 	case BroadcastTargetTypeSnippetUpdates:
-		if broadcastMsg.Target.SnippetID != nil {
-			h.broadcastToSnippetUpdates(*broadcastMsg.Target.SnippetID, broadcastMsg.Message)
+		if target.SnippetID != nil {
+			h.sendToTopic(topicKey(SubTypeSnippetUpdates, *target.SnippetID), message, stats, h.snippetUpdateClients[*target.SnippetID])
 		}
 	case BroadcastTargetTypeListUpdates:
-		h.broadcastToListUpdates(broadcastMsg.Message)
+		h.sendToTopic(topicKey(SubTypeListUpdates, ""), message, stats, h.listUpdateClients)
+
+		var filters []string
+		if target.AuthorID != nil {
+			filters = append(filters, authorFilter(*target.AuthorID))
+		}
+		if target.Language != nil {
+			filters = append(filters, languageFilter(*target.Language))
+		}
+		for _, filter := range filters {
+			h.sendToTopic(topicKey(SubTypeListUpdates, filter), message, stats, h.listFilterClients[filter])
+		}
+	case BroadcastTargetTypeFeed:
+		for _, userID := range target.UserIDs {
+			h.sendToTopic(topicKey(SubTypeFeed, userID), message, stats, h.feedClients[userID])
+		}
 	}
 }
 
-func (h *Hub) broadcastToUser(userID string, message WebSocketMessage) {
-	clients := h.userClients[userID]
-	messageBytes, _ := json.Marshal(message)
+// sendToTopic stamps the message with the next sequence number of the topic,
+// records it for replay and queues it for the topic's subscribers. Broadcasts to
+// topics without local subscribers are only counted.
+func (h *Hub) sendToTopic(topic string, message WebSocketMessage, stats bool, clients clientSet) {
+	if len(clients) == 0 {
+		h.skipTopic(topic)
+		return
+	}
+	state := h.topicState(topic)
+	state.seq++
 
-	for _, client := range clients {
-		select {
-		case client.send <- messageBytes:
-		default:
-			// Client buffer full, remove client
-			close(client.send)
-			delete(h.clients, client)
+	message.Topic = topic
+	message.Seq = state.seq
+	message.Epoch = h.instanceID
+	data, err := json.Marshal(message)
+	if err != nil {
+		h.logger.Error("Failed to encode broadcast", zap.String("topic", topic), zap.Error(err))
+		return
+	}
+	h.record(historyEntry{topic: topic, seq: state.seq, data: data})
+
+	outbound := outboundMessage{topic: topic, seq: state.seq, data: data, droppable: true}
+	if stats {
+		outbound.coalesce = "stats:" + topic
+	}
+	for client := range clients {
+		if !client.queue.push(outbound) {
+			// The writer closes the connection, which unregisters the client
+			h.logger.Warn("Disconnecting slow client", zap.String("user_id", client.userID))
 		}
 	}
 }
 
-func (h *Hub) broadcastToSnippetUpdates(snippetID string, message WebSocketMessage) {
-	clients := h.snippetUpdateClients[snippetID]
-	messageBytes, _ := json.Marshal(message)
+// topicState returns the sequencing state of a topic, creating it if needed
+func (h *Hub) topicState(topic string) *topicState {
+	state, exists := h.topics[topic]
+	if !exists {
+		state = &topicState{}
+		h.topics[topic] = state
+	}
+	return state
+}
 
-	for _, client := range clients {
-		select {
-		case client.send <- messageBytes:
-		default:
-			close(client.send)
-			delete(h.clients, client)
+// skipTopic counts a broadcast to a topic without local subscribers. It is not
+// buffered, clients that subscribed before and resume it later have to resync.
+// Topics nobody subscribed to on this instance are not tracked at all.
+func (h *Hub) skipTopic(topic string) {
+	if state, exists := h.topics[topic]; exists {
+		state.seq++
+		state.evicted = state.seq
+	}
+}
+
+// releaseTopic ends a local subscription to a topic, dropping the topic's state
+// when it has no subscribers left and nothing in the replay buffer
+func (h *Hub) releaseTopic(topic string) {
+	state, exists := h.topics[topic]
+	if !exists {
+		return
+	}
+	state.subscribers--
+	h.evictTopic(topic, state)
+}
+
+// evictTopic drops the state of a topic that is no longer used. Clients resuming it
+// later get a resync, since their sequence number is ahead of the new state.
+func (h *Hub) evictTopic(topic string, state *topicState) {
+	if state.subscribers <= 0 && state.buffered == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// topicSeq returns the sequence number of the last broadcast of a topic
+func (h *Hub) topicSeq(topic string) uint64 {
+	if state, exists := h.topics[topic]; exists {
+		return state.seq
+	}
+	return 0
+}
+
+// record keeps a broadcast for replay, dropping the oldest one of its topic when the
+// topic's share is used up and the oldest one overall when the buffer is full
+func (h *Hub) record(entry historyEntry) {
+	if h.topics[entry.topic].buffered == topicReplayBufferSize {
+		for i, buffered := range h.history {
+			if buffered.topic == entry.topic {
+				h.dropHistory(i)
+				break
+			}
 		}
 	}
+	if len(h.history) == replayBufferSize {
+		h.dropHistory(0)
+	}
+	h.history = append(h.history, entry)
+	h.topics[entry.topic].buffered++
 }
 
-func (h *Hub) broadcastToListUpdates(message WebSocketMessage) {
-	messageBytes, _ := json.Marshal(message)
+// dropHistory removes a broadcast from the replay buffer
+func (h *Hub) dropHistory(i int) {
+	entry := h.history[i]
+	h.history = append(h.history[:i], h.history[i+1:]...)
+
+	state := h.topics[entry.topic]
+	state.evicted = max(state.evicted, entry.seq)
+	state.buffered--
+	h.evictTopic(entry.topic, state)
+}
+
+// handleResume subscribes a client and replays the buffered broadcasts it missed.
+// It runs on the Run goroutine, so no broadcast can interleave with the replay.
+func (h *Hub) handleResume(req resumeRequest) {
+	if _, registered := h.clients[req.client]; !registered {
+		return
+	}
+	for _, subscription := range req.subscriptions {
+		h.subscribeClient(req.client, subscription)
+
+		if subscription.LastSeq != nil && req.client.isSubscribed(subscription) {
+			h.replay(req.client, subscription, *subscription.LastSeq)
+		}
+	}
+}
+
+// replay sends the broadcasts of a subscription's topic after lastSeq, or a resync
+// message when some of them are no longer buffered
+func (h *Hub) replay(client *Client, subscription SubscriptionRequest, lastSeq uint64) {
+	topic := client.topic(subscription)
+	state, exists := h.topics[topic]
+	if !exists {
+		state = &topicState{}
+	}
+	sameEpoch := subscription.Epoch == h.instanceID
+	if sameEpoch && lastSeq == state.seq {
+		return
+	}
+
+	// The sequence was assigned by another instance or before a restart, so it says
+	// nothing about this hub's history, or missed broadcasts are no longer buffered
+	if !sameEpoch || lastSeq > state.seq || lastSeq < state.evicted {
+		client.SendMessage(WebSocketMessage{
+			Type:      MessageTypeResync,
+			Data:      ResyncData{Type: subscription.Type, SnippetID: subscription.SnippetID},
+			SnippetID: subscription.SnippetID,
+			Topic:     topic,
+			Seq:       state.seq,
+			Epoch:     h.instanceID,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	for _, entry := range h.history {
+		if entry.topic != topic || entry.seq <= lastSeq {
+			continue
+		}
+		if !client.queue.push(outboundMessage{topic: entry.topic, seq: entry.seq, data: entry.data, droppable: true}) {
+			h.logger.Warn("Client queue closed during replay", zap.String("user_id", client.userID))
+			return
+		}
+	}
+}
 
-	for _, client := range h.listUpdateClients {
+// publishLoop shares local broadcasts through the broker, off the Run goroutine
+// so a slow backend never delays local delivery
+func (h *Hub) publishLoop() {
+	for {
 		select {
-		case client.send <- messageBytes:
-		default:
-			close(client.send)
-			delete(h.clients, client)
+		case <-h.done:
+			return
+		case envelope := <-h.outbox:
+			data, err := json.Marshal(envelope)
+			if err != nil {
+				h.logger.Error("Failed to encode broadcast for broker", zap.Error(err))
+				continue
+			}
+			if err := h.broker.Publish(data); err != nil {
+				h.logger.Warn("Failed to publish broadcast to broker", zap.Error(err))
+			}
 		}
 	}
 }
 
+// receive is the broker handler; the hub's own broadcasts were already delivered locally
+func (h *Hub) receive(data []byte) {
+	// The payload stays encoded until it is stamped with this hub's sequence numbers
+	var payload json.RawMessage
+	envelope := brokerEnvelope{Message: WebSocketMessage{Data: &payload}}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		h.logger.Warn("Dropping malformed broker message", zap.Error(err))
+		return
+	}
+	if envelope.Origin == h.instanceID {
+		return
+	}
+
+	select {
+	case h.remote <- envelope:
+	case <-h.done:
+	}
+}
+
+// Close saves the documents of open edit sessions and stops sharing broadcasts with other instances
+func (h *Hub) Close() error {
+	var err error
+	h.closeOnce.Do(func() {
+		close(h.done)
+		if h.editStore != nil {
+			h.saveEditSessions(context.Background())
+		}
+		if h.broker != nil {
+			err = h.broker.Close()
+		}
+	})
+	return err
+}
+
 // GetStats returns hub statistics
 func (h *Hub) GetStats() map[string]any {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
+	var stats map[string]any
+	h.query(func() {
+		dropped := h.dropped
+		for client := range h.clients {
+			dropped += client.queue.droppedCount()
+		}
 
-	return map[string]any{
-		"total_clients":         len(h.clients),
-		"user_subscriptions":    len(h.userClients),
-		"snippet_subscriptions": len(h.snippetUpdateClients),
-		"list_subscriptions":    len(h.listUpdateClients),
-	}
+		stats = map[string]any{
+			"total_clients":         len(h.clients),
+			"user_subscriptions":    len(h.userClients),
+			"snippet_subscriptions": len(h.snippetUpdateClients),
+			"list_subscriptions":    len(h.listUpdateClients),
+			"list_filters":          len(h.listFilterClients),
+			"feed_subscriptions":    len(h.feedClients),
+			"dropped_messages":      dropped,
+		}
+	})
+	return stats
 }
@@ -0,0 +1,442 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/api/dto"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+func setupHubTest(tb testing.TB) *Hub {
+	if err := logger.Init(logger.Config{Environment: "production", Level: "error"}); err != nil {
+		tb.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	hub := NewHub(nil, nil, nil)
+	go hub.Run()
+	tb.Cleanup(func() { hub.Close() })
+	return hub
+}
+
+// waitForBroadcasts waits until the hub delivered the queued broadcasts
+func waitForBroadcasts(hub *Hub) {
+	for len(hub.broadcast) > 0 {
+		runtime.Gosched()
+	}
+	hub.query(func() {}) // The last broadcast taken may still be delivered
+}
+
+func TestHub_ConcurrentClients(t *testing.T) {
+	hub := setupHubTest(t)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			snippetID := "snippet-" + strconv.Itoa(i%5)
+			client := NewClient(hub, nil, "user-"+strconv.Itoa(i))
+			hub.register <- client
+			hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{
+				{Type: SubTypeListUpdates},
+				{Type: SubTypeSnippetUpdates, SnippetID: &snippetID},
+				{Type: SubTypeUserActions},
+			}}
+			hub.unsubscribe <- unsubscribeRequest{client: client, subscription: SubscriptionRequest{Type: SubTypeUserActions}}
+			drain(client.queue)
+			hub.unregister <- client
+		}()
+		go func() {
+			defer wg.Done()
+			hub.BroadcastListUpdate(ListUpdateData{SnippetID: "snippet-" + strconv.Itoa(i)})
+			hub.BroadcastSnippetStatsUpdate("snippet-"+strconv.Itoa(i%5), nil, nil)
+			hub.GetStats()
+		}()
+	}
+	wg.Wait()
+
+	hub.query(func() {
+		assert.Empty(t, hub.clients)
+		assert.Empty(t, hub.userClients)
+		assert.Empty(t, hub.snippetUpdateClients)
+		assert.Empty(t, hub.listUpdateClients)
+	})
+}
+
+func TestHub_SlowConsumer(t *testing.T) {
+	hub := setupHubTest(t)
+
+	slow := NewClient(hub, nil, "user-slow")
+	fast := NewClient(hub, nil, "user-fast")
+	for _, client := range []*Client{slow, fast} {
+		hub.register <- client
+		hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{{Type: SubTypeListUpdates}}}
+	}
+	hub.query(func() {})
+	drain(slow.queue)
+
+	// The slow client never reads, the hub keeps delivering to the others
+	for i := range 2 * sendQueueSize {
+		hub.BroadcastListUpdate(ListUpdateData{SnippetID: strconv.Itoa(i)})
+		if i%(sendQueueSize/2) == 0 {
+			waitForBroadcasts(hub)
+			drain(fast.queue)
+		}
+	}
+	waitForBroadcasts(hub)
+
+	var last outboundMessage
+	for message, ok := fast.queue.pop(); ok; message, ok = fast.queue.pop() {
+		last = message
+	}
+	assert.Equal(t, uint64(2*sendQueueSize), last.seq)
+	assert.Zero(t, fast.queue.droppedCount())
+
+	// The slow client keeps the newest broadcasts and notices the gap in sequence numbers
+	first, ok := slow.queue.pop()
+	require.True(t, ok)
+	assert.Equal(t, uint64(sendQueueSize+1), first.seq)
+	assert.Equal(t, uint64(sendQueueSize), hub.GetStats()["dropped_messages"])
+}
+
+func TestHub_TopicState(t *testing.T) {
+	hub := setupHubTest(t)
+	busy, quiet := "snippet-busy", "snippet-quiet"
+
+	client := NewClient(hub, nil, "user-1")
+	hub.register <- client
+	hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{
+		{Type: SubTypeSnippetUpdates, SnippetID: &busy},
+		{Type: SubTypeSnippetUpdates, SnippetID: &quiet},
+	}}
+
+	t.Run("topics without subscribers are not tracked", func(t *testing.T) {
+		for i := range 100 {
+			hub.BroadcastSnippetStatsUpdate("snippet-"+strconv.Itoa(i), nil, nil)
+		}
+		waitForBroadcasts(hub)
+
+		hub.query(func() {
+			assert.Len(t, hub.topics, 2)
+			assert.Empty(t, hub.history)
+		})
+	})
+
+	t.Run("busy topics only use their share of the buffer", func(t *testing.T) {
+		hub.BroadcastSnippetStatsUpdate(quiet, nil, nil)
+		for range replayBufferSize {
+			hub.BroadcastSnippetStatsUpdate(busy, nil, nil)
+		}
+		waitForBroadcasts(hub)
+
+		hub.query(func() {
+			assert.Len(t, hub.history, topicReplayBufferSize+1)
+			assert.Equal(t, 1, hub.topics[topicKey(SubTypeSnippetUpdates, quiet)].buffered)
+			assert.Equal(t, uint64(replayBufferSize-topicReplayBufferSize), hub.topics[topicKey(SubTypeSnippetUpdates, busy)].evicted)
+		})
+	})
+
+	t.Run("state is dropped once unused", func(t *testing.T) {
+		hub.unregister <- client
+
+		// The buffered broadcasts age out, then nothing is left of the topics
+		other := NewClient(hub, nil, "user-2")
+		hub.register <- other
+		var subscriptions []SubscriptionRequest
+		for i := range replayBufferSize / topicReplayBufferSize {
+			snippetID := "snippet-" + strconv.Itoa(i)
+			subscriptions = append(subscriptions, SubscriptionRequest{Type: SubTypeSnippetUpdates, SnippetID: &snippetID})
+		}
+		hub.resume <- resumeRequest{client: other, subscriptions: subscriptions}
+		for i := range replayBufferSize {
+			hub.BroadcastSnippetStatsUpdate("snippet-"+strconv.Itoa(i%len(subscriptions)), nil, nil)
+		}
+		waitForBroadcasts(hub)
+
+		hub.query(func() {
+			assert.Len(t, hub.topics, len(subscriptions))
+			assert.NotContains(t, hub.topics, topicKey(SubTypeSnippetUpdates, busy))
+			assert.NotContains(t, hub.topics, topicKey(SubTypeSnippetUpdates, quiet))
+		})
+	})
+}
+
+func TestHub_SnippetEvents(t *testing.T) {
+	hub := setupHubTest(t)
+	snippetID := "snippet-1"
+
+	client := NewClient(hub, nil, "user-1")
+	hub.register <- client
+	hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{
+		{Type: SubTypeListUpdates},
+		{Type: SubTypeSnippetUpdates, SnippetID: &snippetID},
+	}}
+
+	// next returns the data of the next broadcast of a topic
+	next := func(topic string, data any) {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for {
+			message, ok := nextMessage(client, deadline)
+			require.True(t, ok, "no broadcast for %s", topic)
+			var decoded struct {
+				Type MessageType     `json:"type"`
+				Data json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(message.data, &decoded))
+			if message.topic != topic || decoded.Type == MessageTypeSuccess {
+				continue
+			}
+			require.NoError(t, json.Unmarshal(decoded.Data, data))
+			return
+		}
+	}
+
+	hub.BroadcastSnippetCreated(dto.SnippetResponse{ID: snippetID, Title: "Hello", IsLiked: true})
+	var created ListUpdateData
+	next("list_updates", &created)
+	assert.Equal(t, ListEventCreated, created.Event)
+	require.NotNil(t, created.Snippet)
+	assert.Equal(t, "Hello", created.Snippet.Title)
+	assert.False(t, created.Snippet.IsLiked) // Flags of the author are not shared
+
+	hub.BroadcastSnippetDeleted(dto.SnippetResponse{ID: snippetID})
+	var deletedUpdate SnippetUpdateData
+	next("snippet_updates:"+snippetID, &deletedUpdate)
+	assert.Equal(t, "deleted", deletedUpdate.UpdateType)
+	var deleted ListUpdateData
+	next("list_updates", &deleted)
+	assert.Equal(t, ListEventDeleted, deleted.Event)
+	assert.Equal(t, snippetID, deleted.SnippetID)
+	assert.Nil(t, deleted.Snippet)
+}
+
+// nextOfTypeFrom returns the next queued message of a type sent to a client
+func nextOfTypeFrom(t *testing.T, client *Client, messageType MessageType) WebSocketMessage {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		message, ok := nextMessage(client, deadline)
+		require.True(t, ok, "no %s message", messageType)
+		var decoded WebSocketMessage
+		require.NoError(t, json.Unmarshal(message.data, &decoded))
+		if decoded.Type == messageType {
+			return decoded
+		}
+	}
+}
+
+func TestHub_ListFilters(t *testing.T) {
+	hub := setupHubTest(t)
+	alice, golang := "user-alice", "Go"
+
+	subscribe := func(client *Client, subscriptions ...SubscriptionRequest) {
+		hub.resume <- resumeRequest{client: client, subscriptions: subscriptions}
+	}
+	snippet := func(id, authorID, language string) dto.SnippetResponse {
+		return dto.SnippetResponse{ID: id, Author: dto.UserResponse{ID: authorID}, Language: language}
+	}
+
+	profile := NewClient(hub, nil, "anonymous")
+	hub.register <- profile
+	subscribe(profile, SubscriptionRequest{Type: SubTypeListUpdates, AuthorID: &alice})
+	confirmation := nextOfTypeFrom(t, profile, MessageTypeSuccess) // Connected
+	confirmation = nextOfTypeFrom(t, profile, MessageTypeSuccess)
+	assert.Equal(t, "list_updates:author:user-alice", confirmation.Topic)
+
+	filtered := NewClient(hub, nil, "anonymous")
+	hub.register <- filtered
+	subscribe(filtered, SubscriptionRequest{Type: SubTypeListUpdates, Language: &golang})
+
+	t.Run("only matching snippets are delivered", func(t *testing.T) {
+		hub.BroadcastSnippetCreated(snippet("snippet-1", "user-bob", "python"))
+		hub.BroadcastSnippetCreated(snippet("snippet-2", "user-bob", "go"))
+		hub.BroadcastSnippetCreated(snippet("snippet-3", alice, "python"))
+
+		message := nextOfTypeFrom(t, profile, MessageTypeListUpdates)
+		assert.Equal(t, "snippet-3", *message.SnippetID)
+		assert.Equal(t, uint64(1), message.Seq)
+
+		message = nextOfTypeFrom(t, filtered, MessageTypeListUpdates)
+		assert.Equal(t, "snippet-2", *message.SnippetID)
+		assert.Equal(t, "list_updates:language:go", message.Topic)
+	})
+
+	t.Run("unsubscribed filters resync after missed broadcasts", func(t *testing.T) {
+		hub.unsubscribe <- unsubscribeRequest{client: profile, subscription: SubscriptionRequest{Type: SubTypeListUpdates, AuthorID: &alice}}
+		hub.BroadcastSnippetDeleted(snippet("snippet-3", alice, "python"))
+		waitForBroadcasts(hub)
+
+		lastSeq := uint64(1)
+		subscribe(profile, SubscriptionRequest{Type: SubTypeListUpdates, AuthorID: &alice, LastSeq: &lastSeq, Epoch: hub.instanceID})
+		message := nextOfTypeFrom(t, profile, MessageTypeResync)
+		assert.Equal(t, uint64(2), message.Seq)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		empty := ""
+		subscribe(filtered, SubscriptionRequest{Type: SubTypeListUpdates, AuthorID: &alice, Language: &golang})
+		assert.Equal(t, "Filter list_updates by author_id or language, not both", nextOfTypeFrom(t, filtered, MessageTypeError).Data)
+		subscribe(filtered, SubscriptionRequest{Type: SubTypeListUpdates, Language: &empty})
+		assert.Equal(t, "List filters cannot be empty", nextOfTypeFrom(t, filtered, MessageTypeError).Data)
+	})
+
+	t.Run("organization-only snippets are not listed", func(t *testing.T) {
+		internal := snippet("snippet-4", "user-bob", "go")
+		internal.Visibility = "organization"
+		hub.BroadcastSnippetCreated(internal)
+		hub.BroadcastSnippetVisibilityChanged(internal)
+
+		// Only the visibility change is delivered, without the snippet
+		message := nextOfTypeFrom(t, filtered, MessageTypeListUpdates)
+		assert.Equal(t, "snippet-4", *message.SnippetID)
+		data := message.Data.(map[string]any)
+		assert.Equal(t, string(ListEventVisibilityChanged), data["event"])
+		assert.NotContains(t, data, "snippet")
+	})
+
+	t.Run("burn-after-read snippets are not listed", func(t *testing.T) {
+		burning := snippet("snippet-5", "user-bob", "go")
+		burning.BurnAfterRead = true
+		hub.BroadcastSnippetCreated(burning)
+		hub.BroadcastSnippetDeleted(burning)
+
+		// Only the deletion is delivered
+		message := nextOfTypeFrom(t, filtered, MessageTypeListUpdates)
+		assert.Equal(t, "snippet-5", *message.SnippetID)
+		assert.Equal(t, string(ListEventDeleted), message.Data.(map[string]any)["event"])
+	})
+
+	t.Run("password-protected snippets are not listed", func(t *testing.T) {
+		protected := snippet("snippet-6", "user-bob", "go")
+		protected.PasswordProtected = true
+		hub.BroadcastSnippetCreated(protected)
+		hub.BroadcastSnippetDeleted(protected)
+
+		// Only the deletion is delivered
+		message := nextOfTypeFrom(t, filtered, MessageTypeListUpdates)
+		assert.Equal(t, "snippet-6", *message.SnippetID)
+		assert.Equal(t, string(ListEventDeleted), message.Data.(map[string]any)["event"])
+	})
+
+	t.Run("subscriptions are limited per client", func(t *testing.T) {
+		client := NewClient(hub, nil, "anonymous")
+		hub.register <- client
+
+		subscriptions := make([]SubscriptionRequest, maxClientSubscriptions+1)
+		for i := range subscriptions {
+			authorID := "user-" + strconv.Itoa(i)
+			subscriptions[i] = SubscriptionRequest{Type: SubTypeListUpdates, AuthorID: &authorID}
+		}
+		subscribe(client, subscriptions...)
+		assert.Equal(t, "Too many subscriptions", nextOfTypeFrom(t, client, MessageTypeError).Data)
+
+		hub.query(func() {
+			assert.Equal(t, maxClientSubscriptions, client.subscriptionCount())
+		})
+	})
+}
+
+// BenchmarkHub_Broadcast fans list updates out to 10k connected clients
+func BenchmarkHub_Broadcast(b *testing.B) {
+	hub := setupHubTest(b)
+
+	const clients = 10000
+	done := make(chan struct{})
+	defer close(done)
+	for i := range clients {
+		client := NewClient(hub, nil, "user-"+strconv.Itoa(i))
+		hub.register <- client
+		hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{{Type: SubTypeListUpdates}}}
+
+		go func() {
+			for {
+				select {
+				case <-client.queue.ready:
+					drain(client.queue)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		hub.BroadcastListUpdate(ListUpdateData{SnippetID: strconv.Itoa(i)})
+	}
+	waitForBroadcasts(hub)
+}
+
+// fakeSnippetAccess only lets the author see their private snippet
+type fakeSnippetAccess struct{}
+
+func (fakeSnippetAccess) CanView(_ context.Context, snippetID, userID string) error {
+	if snippetID == "private" && userID != "user-1" {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func TestHub_SnippetAccess(t *testing.T) {
+	hub := setupHubTest(t)
+	hub.access = fakeSnippetAccess{}
+	private, public := "private", "public"
+	subscriptions := []SubscriptionRequest{
+		{Type: SubTypeSnippetUpdates, SnippetID: &private},
+		{Type: SubTypeSnippetUpdates, SnippetID: &public},
+		{Type: SubTypeListUpdates},
+	}
+
+	author := NewClient(hub, nil, "user-1")
+	assert.Len(t, hub.authorizeSubscriptions(author, subscriptions), 3)
+
+	for _, userID := range []string{"user-2", "anonymous"} {
+		client := NewClient(hub, nil, userID)
+		allowed := hub.authorizeSubscriptions(client, subscriptions)
+		require.Len(t, allowed, 2)
+		assert.Equal(t, &public, allowed[0].SnippetID)
+		assert.Equal(t, SubTypeListUpdates, allowed[1].Type)
+
+		rejected := nextOfTypeFrom(t, client, MessageTypeError)
+		assert.Equal(t, "Snippet not found", rejected.Data)
+		require.NotNil(t, rejected.SnippetID)
+		assert.Equal(t, private, *rejected.SnippetID)
+	}
+	assert.Len(t, subscriptions, 3) // The request is not modified
+}
+
+func TestHub_RevalidateSnippet(t *testing.T) {
+	hub := setupHubTest(t)
+	hub.access = fakeSnippetAccess{}
+	private := "private"
+
+	// Both subscribed while the snippet was still public
+	author := NewClient(hub, nil, "user-1")
+	other := NewClient(hub, nil, "user-2")
+	for _, client := range []*Client{author, other} {
+		hub.register <- client
+		hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{{Type: SubTypeSnippetUpdates, SnippetID: &private}}}
+	}
+
+	hub.revalidateSnippet(private)
+
+	revoked := nextOfTypeFrom(t, other, MessageTypeError)
+	assert.Equal(t, "Snippet not found", revoked.Data)
+	assert.Equal(t, private, *revoked.SnippetID)
+	hub.query(func() {
+		assert.Len(t, hub.snippetUpdateClients[private], 1)
+		assert.Contains(t, hub.snippetUpdateClients[private], author)
+		assert.Empty(t, other.snippetUpdatesSubscribed)
+	})
+}
@@ -0,0 +1,122 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+var _ Broker = (*NATSBroker)(nil)
+
+const (
+	natsDialTimeout   = 5 * time.Second
+	natsReconnectWait = time.Second
+)
+
+// NATSBroker shares broadcasts between instances over a NATS subject. The client
+// reconnects in the background and restores the subscription; messages published
+// while disconnected are dropped. Connections use TLS with tls:// URLs and whenever
+// the server requires it.
+type NATSBroker struct {
+	conn    *nats.Conn
+	subject string
+	logger  *zap.Logger
+
+	// Guarded by mutex
+	subscription *nats.Subscription
+	mutex        sync.Mutex
+}
+
+// NewNATSBroker connects to the NATS server at rawURL (nats:// or tls://, optionally
+// with user:pass@ or token@) and publishes on the given subject. It fails right away
+// when the server cannot be reached or the TLS handshake fails.
+func NewNATSBroker(rawURL, subject string) (*NATSBroker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS url: %w", err)
+	}
+	if (u.Scheme != "nats" && u.Scheme != "tls") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid NATS url %q: expected nats://host[:port] or tls://host[:port]", u.Redacted())
+	}
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n*>") {
+		return nil, fmt.Errorf("invalid NATS subject %q", subject)
+	}
+
+	b := &NATSBroker{
+		subject: subject,
+		logger:  logger.With(zap.String("websocket", "nats"), zap.String("subject", subject)),
+	}
+
+	conn, err := nats.Connect(rawURL,
+		nats.Name("codeshare"),
+		nats.Timeout(natsDialTimeout),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(natsReconnectWait),
+		nats.ReconnectBufSize(-1), // Publishing fails while disconnected instead of queueing
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				b.logger.Warn("NATS connection lost, reconnecting", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			b.logger.Info("Reconnected to NATS", zap.String("address", conn.ConnectedUrlRedacted()))
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			b.logger.Warn("NATS error", zap.Error(err))
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	b.conn = conn
+
+	b.logger.Info("Connected to NATS", zap.String("address", conn.ConnectedUrlRedacted()), zap.Bool("tls", conn.TLSRequired()))
+	return b, nil
+}
+
+// Publish sends the message to all subscribers of the subject
+func (b *NATSBroker) Publish(data []byte) error {
+	if err := b.conn.Publish(b.subject, data); err != nil {
+		if errors.Is(err, nats.ErrConnectionClosed) {
+			return ErrBrokerClosed
+		}
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	return nil
+}
+
+// Subscribe registers the handler for the subject, the client restores the
+// subscription after reconnects
+func (b *NATSBroker) Subscribe(handler func(data []byte)) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.conn.IsClosed() {
+		return ErrBrokerClosed
+	}
+	if b.subscription != nil {
+		return errors.New("NATS broker already has a subscriber")
+	}
+
+	subscription, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to NATS: %w", err)
+	}
+	b.subscription = subscription
+	return nil
+}
+
+// Close disconnects from the server and stops reconnecting
+func (b *NATSBroker) Close() error {
+	b.conn.Close()
+	return nil
+}
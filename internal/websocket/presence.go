@@ -0,0 +1,157 @@
+package ws
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// presenceDebounce is the quiet period before a viewer change is broadcast,
+	// so reconnects that leave and rejoin within it are not announced at all
+	presenceDebounce = time.Second
+	// presenceMaxDelay bounds the debounce while viewers keep changing
+	presenceMaxDelay = 5 * time.Second
+)
+
+// presenceChange collects the viewer changes of a snippet until they are broadcast
+type presenceChange struct {
+	first  time.Time
+	last   time.Time
+	joined []*Client // Clients that need the viewer list even if it did not change
+}
+
+// presenceSnapshot is the last viewer list broadcast for a snippet
+type presenceSnapshot struct {
+	viewers   map[string]PresenceViewer // userID -> viewer
+	anonymous int
+}
+
+// markPresenceChanged schedules a presence broadcast for a snippet. joined is the
+// client that subscribed, or nil when a client left.
+func (h *Hub) markPresenceChanged(snippetID string, joined *Client) {
+	now := time.Now()
+	change, exists := h.presenceChanges[snippetID]
+	if !exists {
+		change = &presenceChange{first: now}
+		h.presenceChanges[snippetID] = change
+	}
+	change.last = now
+	if joined != nil {
+		change.joined = append(change.joined, joined)
+	}
+}
+
+// flushPresence broadcasts the viewer lists of snippets whose changes have settled
+func (h *Hub) flushPresence(now time.Time) {
+	due := make(map[string]*presenceChange)
+	for snippetID, change := range h.presenceChanges {
+		if now.Sub(change.last) >= h.presenceDebounce || now.Sub(change.first) >= presenceMaxDelay {
+			due[snippetID] = change
+			delete(h.presenceChanges, snippetID)
+		}
+	}
+
+	for snippetID, change := range due {
+		h.sendPresence(snippetID, change.joined)
+	}
+}
+
+// sendPresence sends the current viewers of a snippet to its subscribers if they
+// changed since the last broadcast, otherwise only to clients that just joined
+func (h *Hub) sendPresence(snippetID string, joined []*Client) {
+	clients := h.snippetUpdateClients[snippetID]
+	if len(clients) == 0 {
+		delete(h.presenceSnapshots, snippetID)
+		return
+	}
+
+	current := presenceSnapshot{viewers: make(map[string]PresenceViewer)}
+	for client := range clients {
+		if client.userID == "anonymous" {
+			current.anonymous++
+			continue
+		}
+		current.viewers[client.userID] = PresenceViewer{
+			UserID:   client.userID,
+			Username: client.username,
+			Avatar:   client.avatar,
+		}
+	}
+
+	previous := h.presenceSnapshots[snippetID]
+	h.presenceSnapshots[snippetID] = current
+
+	data := PresenceData{
+		SnippetID:      snippetID,
+		Viewers:        sortedViewers(current.viewers),
+		AnonymousCount: current.anonymous,
+		ViewerCount:    len(current.viewers) + current.anonymous,
+	}
+	for userID, viewer := range current.viewers {
+		if _, ok := previous.viewers[userID]; !ok {
+			data.Joined = append(data.Joined, viewer)
+		}
+	}
+	for userID, viewer := range previous.viewers {
+		if _, ok := current.viewers[userID]; !ok {
+			data.Left = append(data.Left, viewer)
+		}
+	}
+	slices.SortFunc(data.Joined, compareViewers)
+	slices.SortFunc(data.Left, compareViewers)
+
+	recipients := clients
+	if len(data.Joined) == 0 && len(data.Left) == 0 && current.anonymous == previous.anonymous {
+		// Nothing changed for existing viewers, e.g. a reconnect within the debounce
+		recipients = make(clientSet)
+		for _, client := range joined {
+			if _, subscribed := clients[client]; subscribed {
+				recipients[client] = struct{}{}
+			}
+		}
+		if len(recipients) == 0 {
+			return
+		}
+	}
+
+	messageBytes, err := json.Marshal(WebSocketMessage{
+		Type:      MessageTypePresence,
+		Data:      data,
+		SnippetID: &snippetID,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		h.logger.Error("Failed to encode presence", zap.String("snippet_id", snippetID), zap.Error(err))
+		return
+	}
+
+	// Only the latest viewer list matters to clients that fall behind
+	outbound := outboundMessage{data: messageBytes, droppable: true, coalesce: "presence:" + snippetID}
+	for client := range recipients {
+		if !client.queue.push(outbound) {
+			h.logger.Warn("Disconnecting slow client", zap.String("user_id", client.userID))
+		}
+	}
+
+	h.logger.Debug("Broadcasting presence",
+		zap.String("snippet_id", snippetID),
+		zap.Int("viewers", data.ViewerCount),
+		zap.Int("recipients", len(recipients)))
+}
+
+func sortedViewers(viewers map[string]PresenceViewer) []PresenceViewer {
+	sorted := make([]PresenceViewer, 0, len(viewers))
+	for _, viewer := range viewers {
+		sorted = append(sorted, viewer)
+	}
+	slices.SortFunc(sorted, compareViewers)
+	return sorted
+}
+
+func compareViewers(a, b PresenceViewer) int {
+	return cmp.Or(cmp.Compare(a.Username, b.Username), cmp.Compare(a.UserID, b.UserID))
+}
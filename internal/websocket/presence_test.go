@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+const testPresenceDebounce = 50 * time.Millisecond
+
+func setupPresenceTest(t *testing.T) *Hub {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	hub := NewHub(nil, nil, nil)
+	hub.presenceDebounce = testPresenceDebounce
+	go hub.Run()
+	t.Cleanup(func() { hub.Close() })
+	return hub
+}
+
+// joinSnippet connects a viewer and subscribes it to the snippet's updates
+func joinSnippet(hub *Hub, userID, username, snippetID string) *Client {
+	client := NewClient(hub, nil, userID)
+	client.username = username
+	hub.register <- client
+	hub.resume <- resumeRequest{client: client, subscriptions: []SubscriptionRequest{
+		{Type: SubTypeSnippetUpdates, SnippetID: &snippetID},
+	}}
+	return client
+}
+
+// nextPresence waits for the next presence message of a client, nil if none arrives
+func nextPresence(t *testing.T, client *Client, timeout time.Duration) *PresenceData {
+	deadline := time.After(timeout)
+	for {
+		message, ok := nextMessage(client, deadline)
+		if !ok {
+			return nil
+		}
+
+		var decoded struct {
+			Type MessageType     `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(message.data, &decoded))
+		if decoded.Type != MessageTypePresence {
+			continue
+		}
+		var data PresenceData
+		require.NoError(t, json.Unmarshal(decoded.Data, &data))
+		return &data
+	}
+}
+
+func usernames(viewers []PresenceViewer) []string {
+	names := make([]string, len(viewers))
+	for i, viewer := range viewers {
+		names[i] = viewer.Username
+	}
+	return names
+}
+
+func TestHub_Presence(t *testing.T) {
+	hub := setupPresenceTest(t)
+	wait := 20 * testPresenceDebounce
+
+	alice := joinSnippet(hub, "user-alice", "alice", "snippet-1")
+	anonymous := joinSnippet(hub, "anonymous", "", "snippet-1")
+	bob := joinSnippet(hub, "user-bob", "bob", "snippet-1")
+	joinSnippet(hub, "user-carol", "carol", "snippet-2") // Other snippets are not listed
+
+	// Joins within the debounce are announced once
+	for _, client := range []*Client{alice, anonymous, bob} {
+		presence := nextPresence(t, client, wait)
+		require.NotNil(t, presence)
+		assert.Equal(t, "snippet-1", presence.SnippetID)
+		assert.Equal(t, []string{"alice", "bob"}, usernames(presence.Viewers))
+		assert.Equal(t, 1, presence.AnonymousCount)
+		assert.Equal(t, 3, presence.ViewerCount)
+		assert.Equal(t, []string{"alice", "bob"}, usernames(presence.Joined))
+	}
+	assert.Nil(t, nextPresence(t, alice, 4*testPresenceDebounce))
+
+	t.Run("reconnect within the debounce is not announced", func(t *testing.T) {
+		hub.unregister <- bob
+		bob = joinSnippet(hub, "user-bob", "bob", "snippet-1")
+
+		// Only the new connection receives the viewer list
+		presence := nextPresence(t, bob, wait)
+		require.NotNil(t, presence)
+		assert.Equal(t, 3, presence.ViewerCount)
+		assert.Empty(t, presence.Joined)
+		assert.Nil(t, nextPresence(t, alice, 4*testPresenceDebounce))
+	})
+
+	t.Run("leave", func(t *testing.T) {
+		hub.unregister <- bob
+
+		presence := nextPresence(t, alice, wait)
+		require.NotNil(t, presence)
+		assert.Equal(t, []string{"alice"}, usernames(presence.Viewers))
+		assert.Equal(t, []string{"bob"}, usernames(presence.Left))
+		assert.Equal(t, 2, presence.ViewerCount)
+
+		hub.unregister <- anonymous
+		presence = nextPresence(t, alice, wait)
+		require.NotNil(t, presence)
+		assert.Equal(t, 0, presence.AnonymousCount)
+		assert.Empty(t, presence.Left)
+	})
+
+	t.Run("same user in several tabs is listed once", func(t *testing.T) {
+		secondTab := joinSnippet(hub, "user-alice", "alice", "snippet-1")
+
+		presence := nextPresence(t, secondTab, wait)
+		require.NotNil(t, presence)
+		assert.Equal(t, []string{"alice"}, usernames(presence.Viewers))
+		assert.Equal(t, 1, presence.ViewerCount)
+		assert.Nil(t, nextPresence(t, alice, 4*testPresenceDebounce))
+	})
+}
@@ -0,0 +1,167 @@
+package ws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// sendQueueSize is the number of messages queued for a client before load shedding starts
+	sendQueueSize = 256
+	// slowConsumerTimeout is how long a client's queue may stay full before it is disconnected
+	slowConsumerTimeout = 10 * time.Second
+
+	// CloseSlowConsumer is the close code of clients that could not keep up with their
+	// messages. Clients reconnect and resume their subscriptions.
+	CloseSlowConsumer = 4002
+)
+
+// outboundMessage is an encoded message queued for a client
+type outboundMessage struct {
+	topic string // Topic of the sequence number, empty for messages without one
+	seq   uint64
+	data  []byte
+
+	// Load shedding policy. Droppable messages make room for newer ones when the
+	// queue is full, clients notice the gap in seq and can resume. A queued message
+	// with the same coalesce key is replaced, only the latest state matters.
+	droppable bool
+	coalesce  string
+}
+
+// sendQueue holds the messages of a client until its writer sends them. Pushing never
+// blocks, so a slow client cannot hold up the hub or other clients.
+type sendQueue struct {
+	messages []outboundMessage // Oldest first
+	capacity int
+	ready    chan struct{} // Signaled when messages were queued or the queue was closed
+
+	fullSince    time.Time // When pushes started finding the queue full, zero while it drains
+	stallTimeout time.Duration
+	dropped      uint64
+
+	closed      bool
+	closeCode   int
+	closeReason string
+
+	mutex sync.Mutex
+}
+
+func newSendQueue(capacity int) *sendQueue {
+	return &sendQueue{
+		messages:     make([]outboundMessage, 0, capacity),
+		capacity:     capacity,
+		ready:        make(chan struct{}, 1),
+		stallTimeout: slowConsumerTimeout,
+	}
+}
+
+// push queues a message, shedding load when the queue is full. It returns false when
+// the client was disconnected because it cannot keep up.
+func (q *sendQueue) push(message outboundMessage) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	if message.coalesce != "" {
+		for i, queued := range q.messages {
+			if queued.coalesce == message.coalesce {
+				q.messages = append(q.messages[:i], q.messages[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if len(q.messages) >= q.capacity {
+		now := time.Now()
+		if q.fullSince.IsZero() {
+			q.fullSince = now
+		}
+
+		oldest := -1
+		for i, queued := range q.messages {
+			if queued.droppable {
+				oldest = i
+				break
+			}
+		}
+		if oldest < 0 || now.Sub(q.fullSince) >= q.stallTimeout {
+			q.closeLocked(CloseSlowConsumer, "Client too slow")
+			return false
+		}
+		q.messages = append(q.messages[:oldest], q.messages[oldest+1:]...)
+		q.dropped++
+	}
+
+	q.messages = append(q.messages, message)
+	q.signal()
+	return true
+}
+
+// pop takes the oldest message, false when the queue is empty
+func (q *sendQueue) pop() (outboundMessage, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(q.messages) == 0 {
+		return outboundMessage{}, false
+	}
+	message := q.messages[0]
+	q.messages[0] = outboundMessage{}
+	q.messages = q.messages[1:]
+	q.fullSince = time.Time{}
+	return message, true
+}
+
+// close stops the queue, the writer ends the connection with the close code.
+// Queued messages are discarded. Only the first close counts.
+func (q *sendQueue) close(code int, reason string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.closeLocked(code, reason)
+}
+
+func (q *sendQueue) closeLocked(code int, reason string) {
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.closeCode = code
+	q.closeReason = reason
+	q.messages = nil
+	q.signal()
+}
+
+// closeStatus reports whether the queue was closed, and with which code and reason
+func (q *sendQueue) closeStatus() (bool, int, string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.closed, q.closeCode, q.closeReason
+}
+
+// droppedCount returns the number of messages dropped to make room for newer ones
+func (q *sendQueue) droppedCount() uint64 {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.dropped
+}
+
+func (q *sendQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// closeMessage encodes the close frame sent when the queue was closed
+func closeMessage(code int, reason string) []byte {
+	if code == 0 {
+		code = websocket.CloseNormalClosure
+	}
+	return websocket.FormatCloseMessage(code, reason)
+}
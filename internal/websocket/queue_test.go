@@ -0,0 +1,112 @@
+package ws
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextMessage waits for the next message queued for a client, false if the deadline
+// passes or the queue is closed first
+func nextMessage(client *Client, deadline <-chan time.Time) (outboundMessage, bool) {
+	for {
+		if message, ok := client.queue.pop(); ok {
+			return message, true
+		}
+		if closed, _, _ := client.queue.closeStatus(); closed {
+			return outboundMessage{}, false
+		}
+		select {
+		case <-client.queue.ready:
+		case <-deadline:
+			return outboundMessage{}, false
+		}
+	}
+}
+
+// drain pops all queued messages and returns their data
+func drain(queue *sendQueue) []string {
+	var data []string
+	for {
+		message, ok := queue.pop()
+		if !ok {
+			return data
+		}
+		data = append(data, string(message.data))
+	}
+}
+
+func broadcastMessage(n int) outboundMessage {
+	return outboundMessage{data: []byte(strconv.Itoa(n)), droppable: true}
+}
+
+func TestSendQueue(t *testing.T) {
+	t.Run("drops the oldest broadcasts when full", func(t *testing.T) {
+		queue := newSendQueue(3)
+		require.True(t, queue.push(outboundMessage{data: []byte("required")}))
+		for n := range 4 {
+			require.True(t, queue.push(broadcastMessage(n)))
+		}
+
+		assert.Equal(t, []string{"required", "2", "3"}, drain(queue))
+		assert.Equal(t, uint64(2), queue.droppedCount())
+	})
+
+	t.Run("coalesces stats updates", func(t *testing.T) {
+		queue := newSendQueue(3)
+		for n := range 5 {
+			message := broadcastMessage(n)
+			message.coalesce = "stats:snippet_updates:snippet-1"
+			require.True(t, queue.push(message))
+		}
+		require.True(t, queue.push(broadcastMessage(5)))
+
+		assert.Equal(t, []string{"4", "5"}, drain(queue))
+		assert.Equal(t, uint64(0), queue.droppedCount())
+	})
+
+	t.Run("disconnects when a required message does not fit", func(t *testing.T) {
+		queue := newSendQueue(2)
+		require.True(t, queue.push(outboundMessage{data: []byte("a")}))
+		require.True(t, queue.push(outboundMessage{data: []byte("b")}))
+		assert.False(t, queue.push(outboundMessage{data: []byte("c")}))
+
+		closed, code, _ := queue.closeStatus()
+		assert.True(t, closed)
+		assert.Equal(t, CloseSlowConsumer, code)
+		assert.Empty(t, drain(queue))
+		assert.False(t, queue.push(broadcastMessage(0)))
+	})
+
+	t.Run("disconnects when the queue stays full", func(t *testing.T) {
+		queue := newSendQueue(2)
+		queue.stallTimeout = 50 * time.Millisecond
+		require.True(t, queue.push(broadcastMessage(0)))
+		require.True(t, queue.push(broadcastMessage(1)))
+		require.True(t, queue.push(broadcastMessage(2)))
+
+		// Draining resets the stall
+		queue.pop()
+		require.True(t, queue.push(broadcastMessage(3)))
+		time.Sleep(2 * queue.stallTimeout)
+		require.True(t, queue.push(broadcastMessage(4)))
+
+		time.Sleep(2 * queue.stallTimeout)
+		assert.False(t, queue.push(broadcastMessage(5)))
+		_, code, _ := queue.closeStatus()
+		assert.Equal(t, CloseSlowConsumer, code)
+	})
+
+	t.Run("first close wins", func(t *testing.T) {
+		queue := newSendQueue(2)
+		queue.close(CloseSessionRevoked, "Session expired or revoked")
+		queue.close(CloseSlowConsumer, "Client too slow")
+
+		_, code, reason := queue.closeStatus()
+		assert.Equal(t, CloseSessionRevoked, code)
+		assert.Equal(t, "Session expired or revoked", reason)
+	})
+}
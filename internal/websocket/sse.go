@@ -0,0 +1,297 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	"mitsimi.dev/codeShare/internal/api"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+const (
+	// sseKeepAliveInterval is the time between comments that keep proxies from closing idle streams
+	sseKeepAliveInterval = 30 * time.Second
+
+	// sseRetryMillis is the reconnect delay suggested to EventSource clients
+	sseRetryMillis = 3000
+
+	// sseWriteTimeout bounds each write, so a stalled stream is dropped like a stalled WebSocket
+	sseWriteTimeout = 10 * time.Second
+)
+
+// parseSSESubscriptions reads subscriptions from the query, e.g.
+// ?subscribe=user_actions,list_updates&subscribe=snippet_updates&snippet_id=abc
+// or ?subscribe=list_updates&author_id=abc for the list updates of one author
+func parseSSESubscriptions(r *http.Request, userID string) ([]SubscriptionRequest, error) {
+	query := r.URL.Query()
+
+	var types []SubscriptionType
+	for _, value := range query["subscribe"] {
+		for _, subType := range strings.Split(value, ",") {
+			if subType = strings.TrimSpace(subType); subType != "" {
+				types = append(types, SubscriptionType(subType))
+			}
+		}
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("at least one subscription is required")
+	}
+
+	var subscriptions []SubscriptionRequest
+	for _, subType := range types {
+		switch subType {
+		case SubTypeUserActions, SubTypeFeed:
+			if userID == "anonymous" {
+				return nil, fmt.Errorf("anonymous users cannot subscribe to %s", subType)
+			}
+			subscriptions = append(subscriptions, SubscriptionRequest{Type: subType})
+		case SubTypeListUpdates:
+			// With author_id or language, only the matching snippets are streamed
+			authorIDs, languages := query["author_id"], query["language"]
+			if len(authorIDs) == 0 && len(languages) == 0 {
+				subscriptions = append(subscriptions, SubscriptionRequest{Type: subType})
+			}
+			for _, authorID := range authorIDs {
+				subscriptions = append(subscriptions, SubscriptionRequest{Type: subType, AuthorID: &authorID})
+			}
+			for _, language := range languages {
+				subscriptions = append(subscriptions, SubscriptionRequest{Type: subType, Language: &language})
+			}
+		case SubTypeSnippetUpdates:
+			snippetIDs := query["snippet_id"]
+			if len(snippetIDs) == 0 {
+				return nil, fmt.Errorf("snippet_updates requires a snippet_id")
+			}
+			for _, snippetID := range snippetIDs {
+				subscriptions = append(subscriptions, SubscriptionRequest{Type: subType, SnippetID: &snippetID})
+			}
+		default:
+			return nil, fmt.Errorf("unknown subscription type %q", subType)
+		}
+	}
+	if len(subscriptions) > maxClientSubscriptions {
+		return nil, fmt.Errorf("at most %d subscriptions are allowed", maxClientSubscriptions)
+	}
+	return subscriptions, nil
+}
+
+// parseLastEventID reads the cursor of a reconnecting client, the epoch of the stream
+// and the last sequence number seen per topic, e.g.
+// "<epoch>;list_updates=42,snippet_updates:abc=7". Browsers send the Last-Event-ID
+// header on automatic reconnects, the lastEventId query parameter allows resuming
+// from a new EventSource.
+func parseLastEventID(r *http.Request) (string, map[string]uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return "", nil, nil
+	}
+
+	// IDs without an epoch are resumed as from another instance
+	epoch, positions, found := strings.Cut(value, ";")
+	if !found {
+		epoch, positions = "", value
+	}
+
+	cursor := make(map[string]uint64)
+	for _, position := range strings.Split(positions, ",") {
+		topic, seqValue, found := strings.Cut(position, "=")
+		if !found || topic == "" {
+			return "", nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		seq, err := strconv.ParseUint(seqValue, 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		cursor[topic] = seq
+	}
+	return epoch, cursor, nil
+}
+
+// formatEventID encodes the cursor of a stream as event ID, topics in a stable order
+func formatEventID(epoch string, cursor map[string]uint64) string {
+	topics := make([]string, 0, len(cursor))
+	for topic := range cursor {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+
+	positions := make([]string, len(topics))
+	for i, topic := range topics {
+		positions[i] = topic + "=" + strconv.FormatUint(cursor[topic], 10)
+	}
+	return epoch + ";" + strings.Join(positions, ",")
+}
+
+// HandleSSE creates the HTTP handler for Server-Sent Events streams, an alternative
+// to the WebSocket for networks that block upgrades. Streams receive the same messages
+// as WebSocket clients. The event ID holds the hub's epoch and the last sequence number
+// of every subscribed topic, so a reconnect resumes all subscriptions at once.
+func HandleSSE(hub *Hub, guard *Guard) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+		log := logger.With(zap.String("request_id", requestID))
+
+		// EventSource cannot set headers, cross-origin clients without cookies use a ticket
+		credential, err := guard.authenticate(r)
+		if err != nil {
+			log.Warn("SSE authentication failed", zap.Error(err))
+			api.WriteError(w, http.StatusUnauthorized, "Invalid ticket")
+			return
+		}
+		userID := credential.UserID
+		if userID == "" {
+			userID = "anonymous"
+		}
+		log = log.With(zap.String("user_id", userID))
+
+		subscriptions, err := parseSSESubscriptions(r, userID)
+		if err != nil {
+			log.Warn("invalid SSE subscriptions", zap.Error(err))
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		lastEpoch, lastSeqs, err := parseLastEventID(r)
+		if err != nil {
+			log.Warn("invalid SSE Last-Event-ID", zap.Error(err))
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ip := clientIP(r)
+		if !guard.acquire(ip) {
+			log.Warn("Too many SSE connections", zap.String("ip", ip))
+			api.WriteError(w, http.StatusTooManyRequests, "Too many connections")
+			return
+		}
+		defer guard.release(ip)
+
+		// Streams outlive the server's write timeout
+		controller := http.NewResponseController(w)
+		if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+			log.Warn("failed to clear write deadline for SSE stream", zap.Error(err))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis); err != nil {
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			log.Warn("SSE streaming not supported", zap.Error(err))
+			return
+		}
+
+		client := NewClient(hub, nil, userID)
+		client.credential = credential
+		if err := client.loadProfile(r.Context(), guard.config.Users); err != nil {
+			log.Warn("failed to load profile for presence", zap.Error(err))
+		}
+
+		// Topics missing from the cursor are subscribed without replay. Positions of
+		// another epoch are resynced and don't carry over into the new cursor.
+		cursor := make(map[string]uint64)
+		for i, subscription := range subscriptions {
+			topic := client.topic(subscription)
+			if seq, ok := lastSeqs[topic]; ok {
+				if lastEpoch == hub.instanceID {
+					cursor[topic] = seq
+				}
+				subscriptions[i].LastSeq = &seq
+				subscriptions[i].Epoch = lastEpoch
+			}
+		}
+
+		hub.register <- client
+		hub.resume <- resumeRequest{client: client, subscriptions: hub.authorizeSubscriptions(client, subscriptions)}
+		defer func() {
+			hub.unregister <- client
+		}()
+
+		log.Info("SSE stream established", zap.Int("subscriptions", len(subscriptions)))
+
+		ticker := time.NewTicker(sseKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-client.queue.ready:
+				if closed, _, reason := client.queue.closeStatus(); closed {
+					// SSE has no close codes, the reason is sent as error event instead
+					if reason != "" {
+						writeSSEError(w, reason)
+						controller.Flush()
+					}
+					return
+				}
+
+				for {
+					message, ok := client.queue.pop()
+					if !ok {
+						break
+					}
+					controller.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+					if err := writeSSEEvent(w, message, hub.instanceID, cursor); err != nil {
+						return
+					}
+				}
+
+			case <-ticker.C:
+				controller.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+
+			case <-r.Context().Done():
+				log.Info("SSE stream closed")
+				return
+			}
+
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeSSEEvent writes a message as an SSE event. Messages with a sequence number
+// advance the stream's cursor and carry it as ID, other messages carry no ID, so
+// they don't move the client's Last-Event-ID.
+func writeSSEEvent(w http.ResponseWriter, message outboundMessage, epoch string, cursor map[string]uint64) error {
+	if message.topic != "" {
+		cursor[message.topic] = message.seq
+		if _, err := fmt.Fprintf(w, "id: %s\n", formatEventID(epoch, cursor)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "data: %s\n\n", message.data)
+	return err
+}
+
+// writeSSEError writes an error event telling the client why the stream ends
+func writeSSEError(w http.ResponseWriter, reason string) error {
+	data, err := json.Marshal(WebSocketMessage{
+		Type:      MessageTypeError,
+		Data:      reason,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,206 @@
+package ws
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mitsimi.dev/codeShare/internal/logger"
+)
+
+// sseEvent is a parsed Server-Sent Event
+type sseEvent struct {
+	id      string
+	message WebSocketMessage
+}
+
+func setupSSETest(t *testing.T) (*Hub, *httptest.Server) {
+	return setupSSETestWithBroker(t, nil)
+}
+
+func setupSSETestWithBroker(t *testing.T, broker Broker) (*Hub, *httptest.Server) {
+	err := logger.Init(logger.Config{
+		Environment: "development",
+		Level:       "debug",
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	hub := NewHub(broker, nil, nil)
+	go hub.Run()
+	t.Cleanup(func() { hub.Close() })
+
+	server := httptest.NewServer(HandleSSE(hub, NewGuard(hub, GuardConfig{})))
+	t.Cleanup(server.Close)
+	return hub, server
+}
+
+// openSSE connects to the stream and returns a function reading the next event
+func openSSE(t *testing.T, url, lastEventID string) (*http.Response, func() sseEvent) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	reader := bufio.NewReader(resp.Body)
+	next := func() sseEvent {
+		var event sseEvent
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.message))
+			case line == "" && event.message.Type != "":
+				return event
+			}
+		}
+	}
+	return resp, next
+}
+
+// nextOfType skips control messages until a message of the given type arrives
+func nextOfType(t *testing.T, next func() sseEvent, messageType MessageType) sseEvent {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if event := next(); event.message.Type == messageType {
+			return event
+		}
+	}
+	t.Fatalf("no %s message received", messageType)
+	return sseEvent{}
+}
+
+func TestHandleSSE_Subscriptions(t *testing.T) {
+	_, server := setupSSETest(t)
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, query := range []string{"", "?subscribe=unknown", "?subscribe=snippet_updates", "?subscribe=user_actions"} {
+			resp, err := http.Get(server.URL + query)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+
+	t.Run("invalid last event id", func(t *testing.T) {
+		for _, lastEventID := range []string{"abc", "list_updates=abc", "=1", "epoch;", "epoch;list_updates"} {
+			resp, err := http.Get(server.URL + "?subscribe=list_updates&lastEventId=" + url.QueryEscape(lastEventID))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, lastEventID)
+		}
+	})
+}
+
+func TestHandleSSE_Stream(t *testing.T) {
+	hub, server := setupSSETest(t)
+
+	resp, next := openSSE(t, server.URL+"?subscribe=list_updates,snippet_updates&snippet_id=snippet-1", "")
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	epoch := hub.instanceID + ";"
+
+	// Wait until both subscriptions are active, confirmations carry the starting position
+	nextOfType(t, next, MessageTypeSuccess)
+	event := nextOfType(t, next, MessageTypeSuccess)
+	assert.Equal(t, epoch+"list_updates=0", event.id)
+	event = nextOfType(t, next, MessageTypeSuccess)
+	assert.Equal(t, epoch+"list_updates=0,snippet_updates:snippet-1=0", event.id)
+
+	title := "Hello"
+	hub.BroadcastListUpdate(ListUpdateData{SnippetID: "snippet-2", Title: &title})
+	event = nextOfType(t, next, MessageTypeListUpdates)
+	assert.Equal(t, epoch+"list_updates=1,snippet_updates:snippet-1=0", event.id)
+	assert.Equal(t, "list_updates", event.message.Topic)
+	assert.Equal(t, uint64(1), event.message.Seq)
+
+	// Updates of other snippets are filtered, each snippet has its own sequence
+	hub.BroadcastSnippetStatsUpdate("snippet-3", nil, nil)
+	hub.BroadcastSnippetStatsUpdate("snippet-1", nil, nil)
+	event = nextOfType(t, next, MessageTypeSnippetUpdates)
+	assert.Equal(t, epoch+"list_updates=1,snippet_updates:snippet-1=1", event.id)
+	assert.Equal(t, "snippet-1", *event.message.SnippetID)
+	assert.Equal(t, uint64(1), event.message.Seq)
+}
+
+func TestHandleSSE_Resume(t *testing.T) {
+	hub, server := setupSSETest(t)
+
+	// Only topics with subscribers are buffered
+	_, live := openSSE(t, server.URL+"?subscribe=list_updates", "")
+	nextOfType(t, live, MessageTypeSuccess)
+	nextOfType(t, live, MessageTypeSuccess)
+
+	for _, snippetID := range []string{"snippet-1", "snippet-2", "snippet-3"} {
+		hub.BroadcastListUpdate(ListUpdateData{SnippetID: snippetID})
+	}
+	hub.BroadcastSnippetStatsUpdate("snippet-1", nil, nil) // Not subscribed, not even counted
+	epoch := hub.instanceID + ";"
+
+	t.Run("replays missed messages", func(t *testing.T) {
+		_, next := openSSE(t, server.URL+"?subscribe=list_updates", epoch+"list_updates=1")
+
+		event := nextOfType(t, next, MessageTypeListUpdates)
+		assert.Equal(t, epoch+"list_updates=2", event.id)
+		event = nextOfType(t, next, MessageTypeListUpdates)
+		assert.Equal(t, epoch+"list_updates=3", event.id)
+
+		// Live messages continue after the replay
+		hub.BroadcastListUpdate(ListUpdateData{SnippetID: "snippet-4"})
+		event = nextOfType(t, next, MessageTypeListUpdates)
+		assert.Equal(t, epoch+"list_updates=4", event.id)
+	})
+
+	t.Run("resumes each topic from its own position", func(t *testing.T) {
+		_, next := openSSE(t, server.URL+"?subscribe=list_updates,snippet_updates&snippet_id=snippet-1", epoch+"list_updates=4,snippet_updates:snippet-1=0")
+		nextOfType(t, next, MessageTypeSuccess)
+		nextOfType(t, next, MessageTypeSuccess)
+		nextOfType(t, next, MessageTypeSuccess)
+
+		hub.BroadcastSnippetStatsUpdate("snippet-1", nil, nil)
+		event := nextOfType(t, next, MessageTypeSnippetUpdates)
+		assert.Equal(t, epoch+"list_updates=4,snippet_updates:snippet-1=1", event.id)
+	})
+
+	t.Run("sequence from another epoch", func(t *testing.T) {
+		// Positions from before a restart or from another replica, even ones this hub
+		// could replay, say nothing about what the client missed
+		for _, lastEventID := range []string{"other-epoch;list_updates=1", "other-epoch;list_updates=1000", "list_updates=1"} {
+			_, next := openSSE(t, server.URL+"?subscribe=list_updates", lastEventID)
+			event := nextOfType(t, next, MessageTypeResync)
+			assert.Equal(t, epoch+"list_updates=4", event.id, lastEventID)
+		}
+	})
+
+	t.Run("sequence ahead of the topic", func(t *testing.T) {
+		_, next := openSSE(t, server.URL+"?subscribe=list_updates", epoch+"list_updates=1000")
+		event := nextOfType(t, next, MessageTypeResync)
+		assert.Equal(t, epoch+"list_updates=4", event.id)
+	})
+
+	t.Run("gap larger than the buffer", func(t *testing.T) {
+		for range replayBufferSize {
+			hub.BroadcastListUpdate(ListUpdateData{SnippetID: "snippet-5"})
+		}
+
+		_, next := openSSE(t, server.URL+"?subscribe=list_updates", epoch+"list_updates=1")
+		nextOfType(t, next, MessageTypeResync)
+	})
+}
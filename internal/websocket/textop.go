@@ -0,0 +1,206 @@
+package ws
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+var (
+	errInvalidOperation = errors.New("invalid text operation")
+	errLengthMismatch   = errors.New("text operation does not match the document length")
+)
+
+// TextOperationComponent is one step of a text operation. Exactly one field is set:
+// Retain skips characters, Insert adds text and Delete removes characters.
+// Lengths count Unicode code points, not bytes or UTF-16 units.
+type TextOperationComponent struct {
+	Retain int    `json:"retain,omitempty"`
+	Insert string `json:"insert,omitempty"`
+	Delete int    `json:"delete,omitempty"`
+}
+
+// TextOperation transforms a document into a new one. Its components walk the whole
+// document, so the retained and deleted lengths add up to the document length.
+type TextOperation []TextOperationComponent
+
+// Validate checks that every component sets exactly one field
+func (op TextOperation) Validate() error {
+	for _, c := range op {
+		set := 0
+		if c.Retain != 0 {
+			set++
+		}
+		if c.Insert != "" {
+			set++
+		}
+		if c.Delete != 0 {
+			set++
+		}
+		if set != 1 || c.Retain < 0 || c.Delete < 0 || !utf8.ValidString(c.Insert) {
+			return errInvalidOperation
+		}
+	}
+	return nil
+}
+
+// BaseLen returns the length of the document the operation applies to
+func (op TextOperation) BaseLen() int {
+	n := 0
+	for _, c := range op {
+		n += c.Retain + c.Delete
+	}
+	return n
+}
+
+// Apply runs the operation on a document
+func (op TextOperation) Apply(doc []rune) ([]rune, error) {
+	if op.BaseLen() != len(doc) {
+		return nil, errLengthMismatch
+	}
+
+	result := make([]rune, 0, len(doc))
+	pos := 0
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			result = append(result, doc[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case c.Insert != "":
+			result = append(result, []rune(c.Insert)...)
+		case c.Delete > 0:
+			pos += c.Delete
+		}
+	}
+	return result, nil
+}
+
+// TransformIndex moves a cursor position over the operation. Text inserted at the
+// cursor ends up before it, so other users' typing pushes the cursor forward.
+func (op TextOperation) TransformIndex(index int) int {
+	pos, result := 0, index
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			pos += c.Retain
+			if pos > index {
+				return result
+			}
+		case c.Insert != "":
+			result += utf8.RuneCountInString(c.Insert)
+		case c.Delete > 0:
+			result -= min(c.Delete, max(index-pos, 0))
+			pos += c.Delete
+		}
+	}
+	return result
+}
+
+// textOperationBuilder assembles an operation, merging adjacent components of the same kind
+type textOperationBuilder struct {
+	op TextOperation
+}
+
+func (b *textOperationBuilder) retain(n int) {
+	if n == 0 {
+		return
+	}
+	if last := len(b.op) - 1; last >= 0 && b.op[last].Retain > 0 {
+		b.op[last].Retain += n
+		return
+	}
+	b.op = append(b.op, TextOperationComponent{Retain: n})
+}
+
+func (b *textOperationBuilder) insert(text string) {
+	if text == "" {
+		return
+	}
+	if last := len(b.op) - 1; last >= 0 && b.op[last].Insert != "" {
+		b.op[last].Insert += text
+		return
+	}
+	b.op = append(b.op, TextOperationComponent{Insert: text})
+}
+
+func (b *textOperationBuilder) delete(n int) {
+	if n == 0 {
+		return
+	}
+	if last := len(b.op) - 1; last >= 0 && b.op[last].Delete > 0 {
+		b.op[last].Delete += n
+		return
+	}
+	b.op = append(b.op, TextOperationComponent{Delete: n})
+}
+
+// TransformOperations transforms two concurrent operations on the same document into
+// a' and b', so that applying a then b' gives the same document as b then a'.
+// When both insert at the same position, a's text goes first.
+func TransformOperations(a, b TextOperation) (TextOperation, TextOperation, error) {
+	if a.BaseLen() != b.BaseLen() {
+		return nil, nil, errLengthMismatch
+	}
+
+	var aPrime, bPrime textOperationBuilder
+	i, j := 0, 0
+	var ca, cb TextOperationComponent
+	hasA, hasB := false, false
+
+	for {
+		if !hasA && i < len(a) {
+			ca, hasA = a[i], true
+			i++
+		}
+		if !hasB && j < len(b) {
+			cb, hasB = b[j], true
+			j++
+		}
+		if !hasA && !hasB {
+			break
+		}
+
+		// Inserts don't consume the document, they are retained by the other side
+		if hasA && ca.Insert != "" {
+			aPrime.insert(ca.Insert)
+			bPrime.retain(utf8.RuneCountInString(ca.Insert))
+			hasA = false
+			continue
+		}
+		if hasB && cb.Insert != "" {
+			aPrime.retain(utf8.RuneCountInString(cb.Insert))
+			bPrime.insert(cb.Insert)
+			hasB = false
+			continue
+		}
+		if !hasA || !hasB {
+			return nil, nil, errLengthMismatch
+		}
+
+		n := min(ca.Retain+ca.Delete, cb.Retain+cb.Delete)
+		switch {
+		case ca.Retain > 0 && cb.Retain > 0:
+			aPrime.retain(n)
+			bPrime.retain(n)
+		case ca.Delete > 0 && cb.Retain > 0:
+			aPrime.delete(n)
+		case ca.Retain > 0 && cb.Delete > 0:
+			bPrime.delete(n)
+		}
+		// Both deleting the same text leaves nothing to do for either side
+
+		ca, hasA = consume(ca, n)
+		cb, hasB = consume(cb, n)
+	}
+
+	return aPrime.op, bPrime.op, nil
+}
+
+// consume shortens a retain or delete component by n, reporting whether anything is left
+func consume(c TextOperationComponent, n int) (TextOperationComponent, bool) {
+	if c.Retain > 0 {
+		c.Retain -= n
+		return c, c.Retain > 0
+	}
+	c.Delete -= n
+	return c, c.Delete > 0
+}
@@ -0,0 +1,157 @@
+package ws
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextOperation_Apply(t *testing.T) {
+	op := TextOperation{{Retain: 6}, {Delete: 5}, {Insert: "Wörld"}, {Retain: 1}}
+	result, err := op.Apply([]rune("Hello world!"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello Wörld!", string(result))
+
+	_, err = op.Apply([]rune("Hello"))
+	assert.ErrorIs(t, err, errLengthMismatch)
+}
+
+func TestTextOperation_Validate(t *testing.T) {
+	assert.NoError(t, TextOperation{{Retain: 1}, {Insert: "a"}, {Delete: 2}}.Validate())
+
+	for _, op := range []TextOperation{
+		{{}},
+		{{Retain: 1, Insert: "a"}},
+		{{Retain: -1}},
+		{{Delete: -1}},
+		{{Insert: "\xff"}},
+	} {
+		assert.ErrorIs(t, op.Validate(), errInvalidOperation)
+	}
+}
+
+func TestTextOperation_TransformIndex(t *testing.T) {
+	// "abcdef" -> "abXYef": deletes "cd" and inserts "XY" at 2
+	op := TextOperation{{Retain: 2}, {Delete: 2}, {Insert: "XY"}, {Retain: 2}}
+
+	assert.Equal(t, 1, op.TransformIndex(1)) // Before the change
+	assert.Equal(t, 4, op.TransformIndex(2)) // Insert at the cursor pushes it forward
+	assert.Equal(t, 4, op.TransformIndex(3)) // Inside the deleted text
+	assert.Equal(t, 4, op.TransformIndex(4))
+	assert.Equal(t, 6, op.TransformIndex(6)) // After the change
+}
+
+func TestTransformOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		a, b     TextOperation
+		expected string
+	}{
+		{
+			name:     "inserts at different positions",
+			doc:      "abc",
+			a:        TextOperation{{Insert: "X"}, {Retain: 3}},
+			b:        TextOperation{{Retain: 3}, {Insert: "Y"}},
+			expected: "XabcY",
+		},
+		{
+			name:     "inserts at the same position keep a first",
+			doc:      "abc",
+			a:        TextOperation{{Retain: 1}, {Insert: "X"}, {Retain: 2}},
+			b:        TextOperation{{Retain: 1}, {Insert: "Y"}, {Retain: 2}},
+			expected: "aXYbc",
+		},
+		{
+			name:     "overlapping deletes",
+			doc:      "abcdef",
+			a:        TextOperation{{Retain: 1}, {Delete: 3}, {Retain: 2}},
+			b:        TextOperation{{Retain: 2}, {Delete: 3}, {Retain: 1}},
+			expected: "af",
+		},
+		{
+			name:     "insert inside deleted text",
+			doc:      "abcdef",
+			a:        TextOperation{{Retain: 3}, {Insert: "X"}, {Retain: 3}},
+			b:        TextOperation{{Retain: 1}, {Delete: 4}, {Retain: 1}},
+			expected: "aXf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertConverges(t, []rune(tt.doc), tt.a, tt.b, tt.expected)
+		})
+	}
+
+	t.Run("length mismatch", func(t *testing.T) {
+		_, _, err := TransformOperations(TextOperation{{Retain: 1}}, TextOperation{{Retain: 2}})
+		assert.ErrorIs(t, err, errLengthMismatch)
+	})
+}
+
+func TestTransformOperations_Random(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for range 500 {
+		doc := []rune(randomText(random, random.Intn(20)))
+		a := randomOperation(random, len(doc))
+		b := randomOperation(random, len(doc))
+		assertConverges(t, doc, a, b, "")
+	}
+}
+
+// assertConverges checks that a then b' and b then a' produce the same document
+func assertConverges(t *testing.T, doc []rune, a, b TextOperation, expected string) {
+	t.Helper()
+
+	aPrime, bPrime, err := TransformOperations(a, b)
+	require.NoError(t, err)
+
+	afterA, err := a.Apply(doc)
+	require.NoError(t, err)
+	viaA, err := bPrime.Apply(afterA)
+	require.NoError(t, err)
+
+	afterB, err := b.Apply(doc)
+	require.NoError(t, err)
+	viaB, err := aPrime.Apply(afterB)
+	require.NoError(t, err)
+
+	require.Equal(t, string(viaA), string(viaB), "doc %q, a %v, b %v", string(doc), a, b)
+	if expected != "" {
+		assert.Equal(t, expected, string(viaA))
+	}
+}
+
+func randomText(random *rand.Rand, n int) string {
+	letters := []rune("abcxyzäö€")
+	text := make([]rune, n)
+	for i := range text {
+		text[i] = letters[random.Intn(len(letters))]
+	}
+	return string(text)
+}
+
+func randomOperation(random *rand.Rand, docLen int) TextOperation {
+	var b textOperationBuilder
+	for remaining := docLen; remaining > 0; {
+		n := 1 + random.Intn(remaining)
+		switch random.Intn(3) {
+		case 0:
+			b.retain(n)
+			remaining -= n
+		case 1:
+			b.delete(n)
+			remaining -= n
+		case 2:
+			b.insert(randomText(random, 1+random.Intn(3)))
+		}
+	}
+	if random.Intn(2) == 0 {
+		b.insert(randomText(random, 1+random.Intn(3)))
+	}
+	return b.op
+}
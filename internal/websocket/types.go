@@ -1,5 +1,7 @@
 package ws
 
+import "mitsimi.dev/codeShare/internal/api/dto"
+
 // Message types
 type MessageType string
 
@@ -11,6 +13,19 @@ const (
 	MessageTypeUserActions    MessageType = "user_actions"
 	MessageTypeSnippetUpdates MessageType = "snippet_updates"
 	MessageTypeListUpdates    MessageType = "list_updates"
+	MessageTypeFeed           MessageType = "feed"
+	MessageTypeNotification   MessageType = "notification"
+	MessageTypePresence       MessageType = "presence" // Viewers of a snippet, sent to snippet_updates subscribers
+	MessageTypeResume         MessageType = "resume"   // Client resubscribes after a reconnect
+	MessageTypeResync         MessageType = "resync"   // Missed updates cannot be replayed
+
+	// Collaborative editing
+	MessageTypeEditJoin      MessageType = "edit_join"      // Client joins a snippet's edit session, relayed to the other editors
+	MessageTypeEditLeave     MessageType = "edit_leave"     // Client leaves, relayed to the other editors
+	MessageTypeEditState     MessageType = "edit_state"     // Current document, sent on join and when the client has to start over
+	MessageTypeEditOperation MessageType = "edit_operation" // Text operation, sent by clients and relayed to the other editors
+	MessageTypeEditAck       MessageType = "edit_ack"       // Confirms the client's operation with its revision
+	MessageTypeEditCursor    MessageType = "edit_cursor"    // Cursor and selection, sent by clients and relayed to the other editors
 )
 
 // Subscription types
@@ -20,6 +35,7 @@ const (
 	SubTypeUserActions    SubscriptionType = "user_actions"
 	SubTypeSnippetUpdates SubscriptionType = "snippet_updates"
 	SubTypeListUpdates    SubscriptionType = "list_updates"
+	SubTypeFeed           SubscriptionType = "feed"
 )
 
 // WebSocket message structure
@@ -28,6 +44,9 @@ type WebSocketMessage struct {
 	Data      any         `json:"data"`
 	SnippetID *string     `json:"snippet_id,omitempty"`
 	UserID    *string     `json:"user_id,omitempty"`
+	Topic     string      `json:"topic,omitempty"` // Subscription topic the sequence number belongs to
+	Seq       uint64      `json:"seq,omitempty"`   // Position in the topic, increases by one per broadcast
+	Epoch     string      `json:"epoch,omitempty"` // Instance that assigned Seq, changes when the server restarts
 	Timestamp int64       `json:"timestamp"`
 }
 
@@ -35,6 +54,22 @@ type WebSocketMessage struct {
 type SubscriptionRequest struct {
 	Type      SubscriptionType `json:"type"`
 	SnippetID *string          `json:"snippet_id,omitempty"`
+	AuthorID  *string          `json:"author_id,omitempty"` // Only list_updates of the author's snippets
+	Language  *string          `json:"language,omitempty"`  // Only list_updates of snippets in the language
+	LastSeq   *uint64          `json:"last_seq,omitempty"`  // Last sequence number seen, only for resume
+	Epoch     string           `json:"epoch,omitempty"`     // Epoch of LastSeq, only for resume
+}
+
+// Resume request - resubscribes after a reconnect and replays missed broadcasts
+type ResumeData struct {
+	Subscriptions []SubscriptionRequest `json:"subscriptions"`
+}
+
+// Resync data - missed broadcasts of a subscription can no longer be replayed,
+// the client should refetch the current state
+type ResyncData struct {
+	Type      SubscriptionType `json:"type"`
+	SnippetID *string          `json:"snippet_id,omitempty"`
 }
 
 // User actions data - syncs liked/saved status across devices
@@ -48,36 +83,154 @@ type UserActionData struct {
 // Snippet updates data - for single snippet view (includes content + stats)
 type SnippetUpdateData struct {
 	SnippetID  string `json:"snippet_id"`
-	UpdateType string `json:"update_type"` // "content", "stats", "both"
+	UpdateType string `json:"update_type"` // "content", "stats", "both", "deleted"
 
 	// Content changes (optional)
-	Title    *string `json:"title,omitempty"`
-	Content  *string `json:"content,omitempty"`
-	Language *string `json:"language,omitempty"`
+	Title     *string           `json:"title,omitempty"`
+	Content   *string           `json:"content,omitempty"`
+	Language  *string           `json:"language,omitempty"`
+	UpdatedBy *dto.UserResponse `json:"updated_by,omitempty"` // User who made the content change, if known
 
 	// Stats changes (optional)
 	ViewCount *int `json:"view_count,omitempty"`
 	LikeCount *int `json:"like_count,omitempty"`
 }
 
-// List updates data - for list view (content changes only)
+// List event kinds
+type ListEvent string
+
+const (
+	ListEventCreated           ListEvent = "created"
+	ListEventUpdated           ListEvent = "updated"
+	ListEventDeleted           ListEvent = "deleted"
+	ListEventVisibilityChanged ListEvent = "visibility_changed" // Snippet appears in or disappears from public lists
+)
+
+// List updates data - for list view
 type ListUpdateData struct {
-	SnippetID string  `json:"snippet_id"`
-	Title     *string `json:"title,omitempty"`
-	Content   *string `json:"content,omitempty"`
-	Language  *string `json:"language,omitempty"`
+	Event     ListEvent `json:"event"`
+	SnippetID string    `json:"snippet_id"`
+
+	// The whole snippet for created, updated and visibility_changed events, missing for
+	// deletions, edit session saves and snippets that were limited to an organization
+	Snippet *dto.SnippetResponse `json:"snippet,omitempty"`
+
+	// Changed fields of updated events
+	Title    *string `json:"title,omitempty"`
+	Content  *string `json:"content,omitempty"`
+	Language *string `json:"language,omitempty"`
+}
+
+// Feed item data - new and updated snippets of followed authors
+type FeedItemData struct {
+	Type           string `json:"type"` // "created", "updated"
+	SnippetID      string `json:"snippet_id"`
+	AuthorID       string `json:"author_id"`
+	AuthorUsername string `json:"author_username"`
+	Title          string `json:"title"`
+	Language       string `json:"language"`
+}
+
+// Notification data - new inbox entries, delivered with the user_actions subscription
+type NotificationData struct {
+	ID            string  `json:"id"`
+	Type          string  `json:"type"` // "like", "save", "follow"
+	ActorID       string  `json:"actor_id"`
+	ActorUsername string  `json:"actor_username"`
+	ActorAvatar   *string `json:"actor_avatar,omitempty"`
+	SnippetID     *string `json:"snippet_id,omitempty"`
+	SnippetTitle  *string `json:"snippet_title,omitempty"`
+	CreatedAt     int64   `json:"created_at"`
+	UnreadCount   int     `json:"unread_count"`
+}
+
+// Presence data - who is viewing a snippet, sent when viewers join or leave
+type PresenceData struct {
+	SnippetID      string           `json:"snippet_id"`
+	Viewers        []PresenceViewer `json:"viewers"` // Authenticated viewers, each user listed once
+	AnonymousCount int              `json:"anonymous_count"`
+	ViewerCount    int              `json:"viewer_count"`
+	Joined         []PresenceViewer `json:"joined,omitempty"` // Since the previous presence message
+	Left           []PresenceViewer `json:"left,omitempty"`
+}
+
+type PresenceViewer struct {
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	Avatar   *string `json:"avatar,omitempty"`
+}
+
+// Edit session request - joins or leaves the edit session of a snippet
+type EditSessionRequest struct {
+	SnippetID string `json:"snippet_id"`
+}
+
+// Edit state data - the document of an edit session
+type EditStateData struct {
+	SnippetID string       `json:"snippet_id"`
+	Revision  uint64       `json:"revision"`
+	Content   string       `json:"content"`
+	Editors   []EditorData `json:"editors"`
+}
+
+// Edit operation data - clients send the revision the operation is based on,
+// relayed operations carry the revision they created
+type EditOperationData struct {
+	SnippetID    string        `json:"snippet_id"`
+	Revision     uint64        `json:"revision"`
+	Operation    TextOperation `json:"operation"`
+	ConnectionID string        `json:"connection_id,omitempty"` // Set by the server
+	UserID       string        `json:"user_id,omitempty"`       // Set by the server
+}
+
+// Edit ack data - the client's operation was applied as this revision
+type EditAckData struct {
+	SnippetID string `json:"snippet_id"`
+	Revision  uint64 `json:"revision"`
+}
+
+// Edit cursor data - positions count code points in the document of the revision
+type EditCursorData struct {
+	SnippetID    string `json:"snippet_id"`
+	Revision     uint64 `json:"revision"`
+	Anchor       int    `json:"anchor"`                  // Start of the selection, equal to Head without one
+	Head         int    `json:"head"`                    // Caret position
+	ConnectionID string `json:"connection_id,omitempty"` // Set by the server
+	UserID       string `json:"user_id,omitempty"`       // Set by the server
+	Username     string `json:"username,omitempty"`      // Set by the server
+}
+
+// Editor change data - an editor joined or left an edit session
+type EditorChangeData struct {
+	SnippetID string     `json:"snippet_id"`
+	Editor    EditorData `json:"editor"`
+}
+
+type EditorData struct {
+	ConnectionID string  `json:"connection_id"`
+	UserID       string  `json:"user_id"`
+	Username     string  `json:"username"`
+	Avatar       *string `json:"avatar,omitempty"`
+	Anchor       *int    `json:"anchor,omitempty"` // Selection, once the editor reported one
+	Head         *int    `json:"head,omitempty"`
 }
 
 // Broadcast message with targeting
 type BroadcastMessage struct {
 	Message WebSocketMessage
 	Target  BroadcastTarget
+	Stats   bool // Stats-only update, replaces a queued stats update of the same topic
 }
 
 type BroadcastTarget struct {
-	Type      BroadcastTargetType
-	UserID    *string
-	SnippetID *string
+	Type      BroadcastTargetType `json:"type"`
+	UserID    *string             `json:"user_id,omitempty"`
+	UserIDs   []string            `json:"user_ids,omitempty"` // Recipients of feed items
+	SnippetID *string             `json:"snippet_id,omitempty"`
+
+	// Attributes of the snippet filtered list_updates subscriptions match
+	AuthorID *string `json:"author_id,omitempty"`
+	Language *string `json:"language,omitempty"`
 }
 
 type BroadcastTargetType string
@@ -86,4 +239,5 @@ const (
 	BroadcastTargetTypeUser           BroadcastTargetType = "user"
 	BroadcastTargetTypeSnippetUpdates BroadcastTargetType = "snippet_updates"
 	BroadcastTargetTypeListUpdates    BroadcastTargetType = "list_updates"
+	BroadcastTargetTypeFeed           BroadcastTargetType = "feed"
 )
@@ -12,8 +12,10 @@ import (
 	"mitsimi.dev/codeShare/internal/logger"
 	"mitsimi.dev/codeShare/internal/repository"
 	"mitsimi.dev/codeShare/internal/server"
+	"mitsimi.dev/codeShare/internal/services"
 	"mitsimi.dev/codeShare/internal/storage"
 	sqlite "mitsimi.dev/codeShare/internal/storage/sqlite"
+	ws "mitsimi.dev/codeShare/internal/websocket"
 
 	_ "github.com/mattn/go-sqlite3"
 	"go.uber.org/zap"
@@ -48,9 +50,15 @@ func main() {
 	users := sqlite.NewUserRepository(sqliteStorage.DB())
 	sessions := sqlite.NewSessionRepository(sqliteStorage.DB())
 	views := sqlite.NewViewRepository(sqliteStorage.DB())
+	trending := sqlite.NewTrendingRepository(sqliteStorage.DB())
+	follows := sqlite.NewFollowRepository(sqliteStorage.DB())
+	notifications := sqlite.NewNotificationRepository(sqliteStorage.DB())
+	webhooks := sqlite.NewWebhookRepository(sqliteStorage.DB())
+	collections := sqlite.NewCollectionRepository(sqliteStorage.DB())
+	organizations := sqlite.NewOrganizationRepository(sqliteStorage.DB())
 
 	// Create repository container
-	repos := repository.NewContainer(snippets, likes, bookmarks, users, sessions, views)
+	repos := repository.NewContainer(snippets, likes, bookmarks, users, sessions, views, trending, follows, notifications, webhooks, collections, organizations)
 
 	// Create storage instance
 	storage := storage.NewStorage(snippets, likes, bookmarks, users, sessions)
@@ -64,13 +72,38 @@ func main() {
 		}
 	}
 
+	// Share live updates with the other instances when a broker is configured
+	var wsBroker ws.Broker
+	if cfg.WSBrokerURL != "" {
+		natsBroker, err := ws.NewNATSBroker(cfg.WSBrokerURL, cfg.WSBrokerSubject)
+		if err != nil {
+			logger.Fatal("Failed to connect to websocket broker", zap.Error(err))
+		}
+		wsBroker = natsBroker
+	}
+
 	// Create server with repository container
-	srv := server.New(
-		repos,
-		cfg.JWTSecret,
-		cfg.ServeStatic,
-		cfg.CORSAllowedOrigins,
-	)
+	srv := server.New(repos, server.Options{
+		SecretKey:          cfg.JWTSecret,
+		ServeStatic:        cfg.ServeStatic,
+		CORSAllowedOrigins: cfg.CORSAllowedOrigins,
+		ViewPrivacy: services.ViewPrivacyConfig{
+			HashSecret:     cfg.ViewHashSecret,
+			StoreIP:        cfg.ViewStoreIP,
+			IPv4PrefixBits: cfg.ViewIPv4PrefixBits,
+			IPv6PrefixBits: cfg.ViewIPv6PrefixBits,
+		},
+		TrustedProxies:        cfg.TrustedProxies,
+		TrustedProxyHeader:    cfg.TrustedProxyHeader,
+		BotUserAgents:         cfg.BotUserAgents,
+		WSBroker:              wsBroker,
+		WSMaxConnectionsPerIP: cfg.WSMaxConnectionsPerIP,
+		UnlockLimits: services.UnlockLimiterConfig{
+			MaxFailures: cfg.UnlockMaxFailures,
+			Window:      time.Duration(cfg.UnlockWindowMinutes) * time.Minute,
+		},
+		TrashRetention: time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour,
+	})
 
 	// Channel to listen for interrupt signals
 	sigChan := make(chan os.Signal, 1)